@@ -2,7 +2,9 @@ package v1beta1
 
 import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type ComponentType string
@@ -49,6 +51,8 @@ type ComponentSpec struct {
 	// +kubebuilder:validation:Optional
 	Paused bool `json:"paused"`
 
+	// PodLabels are added to the pod template of the component, for the selectors of monitoring or cost allocation.
+	// the component ones are merged with the global ones, the labels managed by the operator can't be overridden
 	// +kubebuilder:validation:Optional
 	PodLabels map[string]string `json:"podLabels,omitempty"`
 
@@ -67,6 +71,10 @@ type ComponentSpec struct {
 	// +kubebuilder:validation:Optional
 	Commands []string `json:"commands,omitempty"`
 
+	// ExtraArgs are appended to the default args of the container, unlike commands which replaces them
+	// +kubebuilder:validation:Optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
 	// RunWithSubProcess whether to run milvus with flag --run-with-subprocess
 	// note: supported in 2.2.15, 2.3.2+
 	// +kubebuilder:validation:Optional
@@ -85,6 +93,7 @@ type ComponentSpec struct {
 	// +kubebuilder:validation:Optional
 	SchedulerName string `json:"schedulerName,omitempty"`
 
+	// NodeSelector of the pods, `${name}` and `${namespace}` in the values are substituted with the milvus instance's name and namespace
 	// +kubebuilder:validation:Optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
@@ -97,14 +106,26 @@ type ComponentSpec struct {
 	// +kubebuilder:validation:Optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// SecurityContext of the milvus container, it overrides the one set by securityProfile
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// VolumeMounts are extra volume mounts of the milvus container, appended to the ones managed by the operator
+	// component's volumeMounts are merged with the global ones by subPath
 	// +kubebuilder:validation:Optional
 	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
 
 	// Volumes is same as corev1.Volume, we use a Values here to avoid the CRD become too large
+	// they're appended to the volumes managed by the operator, component's volumes override the global ones
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Volumes []Values `json:"volumes,omitempty"`
 
+	// SecretMounts mount the keys of secrets as files into the milvus container, e.g. the API keys of embedding services
+	// component's secretMounts override the global ones
+	// +kubebuilder:validation:Optional
+	SecretMounts []SecretMount `json:"secretMounts,omitempty"`
+
 	// ServiceAccountName usually used for situations like accessing s3 with IAM role
 	// +kubebuilder:validation:Optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
@@ -113,6 +134,11 @@ type ComponentSpec struct {
 	// +kubebuilder:validation:Optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// Lifecycle of the milvus container, e.g. a preStop hook to flush before termination.
+	// it overrides the default preStop hook of proxy & standalone
+	// +kubebuilder:validation:Optional
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+
 	// HostNetwork indicates whether to use host network
 	// +kubebuilder:validation:Optional
 	HostNetwork bool `json:"hostNetwork,omitempty"`
@@ -121,6 +147,16 @@ type ComponentSpec struct {
 	// +kubebuilder:validation:Optional
 	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
 
+	// HostAliases are the entries added to the pod's /etc/hosts, e.g. to resolve the object storage in air-gapped clusters
+	// +kubebuilder:validation:Optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DeploymentStrategyType overrides the strategy type of the component's deployment decided by operator
+	// Recreate is useful for single replica components mounting ReadWriteOnce volumes
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:={"Recreate", "RollingUpdate"}
+	DeploymentStrategyType appsv1.DeploymentStrategyType `json:"deploymentStrategyType,omitempty"`
+
 	// Probes has fields startupProbe, livenessProbe, readinessProbe
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -128,6 +164,15 @@ type ComponentSpec struct {
 	Probes Values `json:"probes,omitempty"`
 }
 
+// SecretMount mounts a secret into the milvus container
+type SecretMount struct {
+	// SecretName is the name of the secret in the namespace of milvus
+	SecretName string `json:"secretName"`
+
+	// MountPath is the directory where the keys of the secret are mounted as files
+	MountPath string `json:"mountPath"`
+}
+
 // Probes is the actual struct for the Probes field in ComponentSpec
 type Probes struct {
 	StartupProbe   *corev1.Probe `json:"startupProbe,omitempty"`
@@ -150,6 +195,48 @@ const (
 	ImageUpdateModeForce ImageUpdateMode = "force"
 )
 
+// UpdateWindow is the time window in which the image updates are allowed
+// the window is open when the latest start time is after the latest end time
+type UpdateWindow struct {
+	// Start is the cron expression of the time the window opens, e.g. "0 2 * * 6"
+	Start string `json:"start"`
+
+	// End is the cron expression of the time the window closes, e.g. "0 6 * * 6"
+	End string `json:"end"`
+
+	// TimeZone of the cron expressions in IANA format, e.g. "Asia/Shanghai", default to UTC
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// LivenessPolicy is the policy of the default liveness probe of milvus components
+type LivenessPolicy string
+
+const (
+	// LivenessPolicyStrict uses the default liveness probe, pods are restarted soon after liveness probe fails
+	LivenessPolicyStrict LivenessPolicy = "strict"
+	// LivenessPolicyLenient uses a liveness probe with much higher failure threshold
+	LivenessPolicyLenient LivenessPolicy = "lenient"
+)
+
+// SecurityProfile is a shortcut of security context settings for milvus pods
+type SecurityProfile string
+
+const (
+	// SecurityProfileRestricted complies with the restricted pod security standard for the containers rendered by the operator
+	SecurityProfileRestricted SecurityProfile = "restricted"
+)
+
+// PostUpgradeAction is an action the operator performs after milvus is upgraded
+// +kubebuilder:validation:Enum:={"triggerCompaction"}
+type PostUpgradeAction string
+
+const (
+	// PostUpgradeActionTriggerCompaction triggers a manual compaction of every collection, it requires milvus v2.5+.
+	// the operator connects to milvus with spec.clientCredentialsSecretRef if authorization is enabled
+	PostUpgradeActionTriggerCompaction PostUpgradeAction = "triggerCompaction"
+)
+
 type MilvusComponents struct {
 	ComponentSpec `json:",inline"`
 
@@ -165,6 +252,11 @@ type MilvusComponents struct {
 	// +kubebuilder:validation:Optional
 	ImageUpdateMode ImageUpdateMode `json:"imageUpdateMode,omitempty"`
 
+	// UpdateWindow limits the image updates of the existing deployments within a time window,
+	// the image changes are deferred until the window opens
+	// +kubebuilder:validation:Optional
+	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty"`
+
 	// Note: it's still in beta, do not use for production. EnableRollingUpdate whether to enable rolling update for milvus component
 	// there is nearly zero downtime for rolling update
 	// +kubebuilder:validation:Optional
@@ -181,7 +273,25 @@ type MilvusComponents struct {
 	// +kubebuilder:validation:Optional
 	MetricLabels map[string]string `json:"metricLabels,omitempty"`
 
-	// ToolImage specify tool image to merge milvus config to original one in image, default uses same image as milvus-operator
+	// LivenessPolicy is the policy of the default liveness probe of milvus components, default to strict
+	// lenient: the liveness probe tolerates much more failures, so that pods won't be restarted during long GC pauses.
+	// unready pods are still removed from service by readiness probe, and reported in the MilvusReady condition.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:={"strict", "lenient"}
+	LivenessPolicy LivenessPolicy `json:"livenessPolicy,omitempty"`
+
+	// ServiceMonitor creates a ServiceMonitor for the milvus services when enabled
+	// it's skipped if the ServiceMonitor CRD is not installed
+	// +kubebuilder:validation:Optional
+	ServiceMonitor *MilvusServiceMonitor `json:"serviceMonitor,omitempty"`
+
+	// NetworkPolicy creates a NetworkPolicy restricting the ingress traffic of milvus pods when enabled,
+	// it's deleted when disabled. The namespace of the operator is always allowed to access the milvus & metric ports
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *MilvusNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// ToolImage specify tool image to merge milvus config to original one in image, default uses same image as milvus-operator.
+	// the config container is updated to it whenever it runs another image
 	// +kubebuilder:validation:Optional
 	ToolImage string `json:"toolImage,omitempty"`
 
@@ -195,6 +305,34 @@ type MilvusComponents struct {
 	// +kubebuilder:validation:Optional
 	UpdateToolImage bool `json:"updateToolImage,omitempty"`
 
+	// ConfigContainerArgs overrides the args of the config init container, which defaults to ["/init.sh"]
+	// the container still uses the image by ToolImage & UpdateToolImage
+	// +kubebuilder:validation:Optional
+	ConfigContainerArgs []string `json:"configContainerArgs,omitempty"`
+
+	// ConfigSidecarMode when enabled, the config init container is run as a native sidecar (restartPolicy: Always),
+	// so that it keeps running along with milvus. It takes effect on kubernetes 1.29+, ignored on the older clusters.
+	// the config container should be kept running by ConfigContainerArgs, otherwise it's restarted after it exits
+	// +kubebuilder:validation:Optional
+	ConfigSidecarMode bool `json:"configSidecarMode,omitempty"`
+
+	// AutoGOMAXPROCS when enabled, the GOMAXPROCS env of the milvus container is set to its CPU limit rounded up,
+	// to avoid CPU throttling. It's not set for the containers without CPU limit
+	// +kubebuilder:validation:Optional
+	AutoGOMAXPROCS bool `json:"autoGOMAXPROCS,omitempty"`
+
+	// ResourceBudget is the CPU & memory of one pod of each component in total, it's divided across the components by their weights.
+	// The replicas are not counted, so scaling a component doesn't change the resources of the others.
+	// It only applies to the components whose CPU or memory isn't set in resources
+	// +kubebuilder:validation:Optional
+	ResourceBudget *MilvusResourceBudget `json:"resourceBudget,omitempty"`
+
+	// Arch constrains the pods to the nodes of the CPU architecture by the kubernetes.io/arch node selector,
+	// it should match the architecture of the image. The scheduling is unconstrained if not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"amd64","arm64"}
+	Arch string `json:"arch,omitempty"`
+
 	// UpdateConfigMapOnly when enabled, will not rollout pods. By default pods will be restarted when configmap changed
 	// +kubebuilder:validation:Optional
 	UpdateConfigMapOnly bool `json:"updateConfigMapOnly,omitempty"`
@@ -223,6 +361,52 @@ type MilvusComponents struct {
 	// +kubebuilder:validation:Optional
 	RunAsNonRoot bool `json:"runAsNonRoot,omitempty"`
 
+	// PodSecurityContext of milvus pods, it overrides the one set by runAsNonRoot or securityProfile
+	// +kubebuilder:validation:Optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// SecurityProfile is a shortcut for hardened security context settings
+	// restricted: the milvus & config containers run as non-root user with privilege escalation disallowed & all capabilities dropped,
+	// with the RuntimeDefault seccomp profile and a read-only root filesystem. writable emptyDirs are mounted at /tmp & /milvus/configs,
+	// the default config files of the image are copied into the latter by an init container.
+	// the sidecars & init containers defined by user are not changed
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:={"restricted"}
+	SecurityProfile SecurityProfile `json:"securityProfile,omitempty"`
+
+	// DegradedMinReadyPercent enables the degraded but serving state of the MilvusReady condition.
+	// a component not fully ready is considered serving when its ready replicas reach the percentage of its desired replicas,
+	// and the MilvusReady condition stays true with reason MilvusDegraded. disabled if not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	DegradedMinReadyPercent *int32 `json:"degradedMinReadyPercent,omitempty"`
+
+	// PostUpgradeActions are performed once after each image upgrade completes and milvus is healthy
+	// +kubebuilder:validation:Optional
+	PostUpgradeActions []PostUpgradeAction `json:"postUpgradeActions,omitempty"`
+
+	// UpgradeDeadlineSeconds is the max seconds a rolling upgrade can stay without progress.
+	// when it's exceeded, the operator rolls back to status.currentImage by switching imageUpdateMode to rollingDowngrade.
+	// disabled if not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	UpgradeDeadlineSeconds *int32 `json:"upgradeDeadlineSeconds,omitempty"`
+
+	// InitContainerFailureThreshold is the number of restarts of the config init container,
+	// after which the InitContainerFailed condition is set with the failed pods.
+	// disabled if not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	InitContainerFailureThreshold *int32 `json:"initContainerFailureThreshold,omitempty"`
+
+	// StartupTimeoutSeconds is the max seconds the deployment of a component can stay without ready replicas after it's created,
+	// before milvus ever becomes healthy. when it's exceeded, milvus is marked unhealthy with reason StartupTimeout.
+	// disabled if not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	StartupTimeoutSeconds *int32 `json:"startupTimeoutSeconds,omitempty"`
+
 	// StreamingMode whether to enable streaming mode by default
 	// +kubebuilder:validation:Optional
 	// +nullable
@@ -262,6 +446,35 @@ type MilvusComponents struct {
 	Standalone *MilvusStandalone `json:"standalone,omitempty"`
 }
 
+// MilvusServiceMonitor is the config of the ServiceMonitor for milvus services
+type MilvusServiceMonitor struct {
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval the interval of ServiceMonitor metric scraping in string, default to 30s
+	// +kubebuilder:validation:Optional
+	Interval monitoringv1.Duration `json:"interval,omitempty"`
+
+	// Labels of the ServiceMonitor, usually used to be selected by prometheus
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MilvusNetworkPolicy is the config of the NetworkPolicy for milvus pods.
+// The traffic between milvus components is always allowed
+type MilvusNetworkPolicy struct {
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllowedNamespaces the pods in these namespaces are allowed to access milvus
+	// +kubebuilder:validation:Optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowedCIDRs the IP blocks allowed to access milvus, like 10.0.0.0/16
+	// +kubebuilder:validation:Optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
 type Component struct {
 	ComponentSpec `json:",inline"`
 
@@ -270,6 +483,50 @@ type Component struct {
 	// when replicas is -1, it means the replicas should be managed by HPA
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Enabled default to true, when set to false, the component is scaled to 0 replicas
+	// while its deployment is kept, so it can be enabled again without changing replicas
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// SpreadAcross is a topology key like kubernetes.io/hostname or topology.kubernetes.io/zone,
+	// when set, a preferred pod anti-affinity is added to spread the component's pods across the topology domains
+	// +kubebuilder:validation:Optional
+	SpreadAcross string `json:"spreadAcross,omitempty"`
+
+	// HeadlessService when set to true, a headless service selecting the component's pods is created,
+	// so the pods can be discovered by DNS. It only takes effect on coordinators
+	// +kubebuilder:validation:Optional
+	HeadlessService bool `json:"headlessService,omitempty"`
+
+	// ExtraConfig is merged onto the rendered milvus config for this component only.
+	// The merged config is written to a configmap for the component and mounted on its pods
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	ExtraConfig Values `json:"extraConfig,omitempty"`
+
+	// LogLevel overrides the log.level of milvus config for this component only
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"debug","info","warn","error"}
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// LogFormat overrides the log.format of milvus config for this component only
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"text","json"}
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// ScratchSizeLimit limits the size of the emptyDir the component writes its local data to,
+	// so that the pods don't use up the local scratch of the node & get evicted.
+	// it's set on the operator managed data emptyDir if there's one, otherwise an emptyDir is added for the local storage path
+	// +kubebuilder:validation:Optional
+	ScratchSizeLimit *resource.Quantity `json:"scratchSizeLimit,omitempty"`
+
+	// Ports override the container ports of the component by name, like `milvus` for the grpc port of proxy & standalone,
+	// the component name for the other components, `metrics` and `restful`. ports with other names are added to the container.
+	// the services of the component follow the overridden ports, the listening ports in config should be changed accordingly
+	// +kubebuilder:validation:Optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
 	// SideCars is same as []corev1.Container, we use a Values here to avoid the CRD become too large
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -283,6 +540,11 @@ type Component struct {
 
 type MilvusQueryNode struct {
 	Component `json:",inline"`
+
+	// RollingBatch is the number of querynode pods rolled in a batch during the rolling update, default to 1
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	RollingBatch int32 `json:"rollingBatch,omitempty"`
 }
 
 type MilvusDataNode struct {
@@ -295,11 +557,23 @@ type MilvusIndexNode struct {
 
 type MilvusProxy struct {
 	ServiceComponent `json:",inline"`
+
+	// CoordReadinessGate adds a readiness gate to the proxy pods in cluster mode,
+	// so that they don't accept traffic until all the coordinators are ready.
+	// The operator opens the gate of a pod once, it's not closed when the coordinators become unready later
+	// +kubebuilder:validation:Optional
+	CoordReadinessGate bool `json:"coordReadinessGate,omitempty"`
 }
 
 // MilvusMixCoord is a mixture of rootCoord, indexCoord, queryCoord & dataCoord
 type MilvusMixCoord struct {
 	Component `json:",inline"`
+
+	// StandbyReplicas is the number of warm-standby mixcoord replicas added to the replicas,
+	// active-standby mode is enabled by env when it's greater than 0
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	StandbyReplicas *int32 `json:"standbyReplicas,omitempty"`
 }
 
 type MilvusRootCoord struct {
@@ -320,6 +594,28 @@ type MilvusIndexCoord struct {
 
 type MilvusStreamingNode struct {
 	Component `json:",inline"`
+
+	// Groups splits the streaming nodes into multiple deployments, e.g. by channel range.
+	// Each group is deployed as {instance}-milvus-streamingnode-{group}, and rolled before mixcoord when upgrading to 2.6.
+	// It's not supported in rolling mode v3
+	// +kubebuilder:validation:Optional
+	Groups []StreamingNodeGroup `json:"groups,omitempty"`
+}
+
+// StreamingNodeGroup is a group of streaming nodes in its own deployment
+type StreamingNodeGroup struct {
+	// Name of the group
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Replicas of the group, default to the replicas of streamingNode.
+	// All groups are stopped when the replicas of streamingNode is 0
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ComponentSpec of the group overrides the spec of streamingNode, e.g. env to assign the channel range
+	ComponentSpec `json:",inline"`
 }
 
 type MilvusStandalone struct {
@@ -347,9 +643,49 @@ type ServiceComponent struct {
 	// +kubebuilder:validation:Optional
 	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
 
+	// ServiceAnnotations are merged onto the annotations of the milvus service,
+	// it's usually used to configure the cloud load balancer, like internal LB, NLB or idle timeout
 	// +kubebuilder:validation:Optional
 	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
 
+	// ServiceSessionAffinity of the service, ClientIP keeps the connections from a client to the same pod, default to None
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"None", "ClientIP"}
+	ServiceSessionAffinity corev1.ServiceAffinity `json:"serviceSessionAffinity,omitempty"`
+
+	// ServiceSessionAffinityConfig of the service, works only when serviceSessionAffinity is ClientIP
+	// +kubebuilder:validation:Optional
+	ServiceSessionAffinityConfig *corev1.SessionAffinityConfig `json:"serviceSessionAffinityConfig,omitempty"`
+
+	// ServiceExternalTrafficPolicy of the service, works only when serviceType is LoadBalancer or NodePort.
+	// Local preserves the client source IP, default to Cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"Cluster", "Local"}
+	ServiceExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"serviceExternalTrafficPolicy,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	Ingress *MilvusIngress `json:"ingress,omitempty"`
+
+	// InternalService generates an extra ClusterIP service named {instance}-milvus-internal for the in-cluster clients
+	// +kubebuilder:validation:Optional
+	InternalService *MilvusInternalService `json:"internalService,omitempty"`
+}
+
+// MilvusResourceBudget is the total resources of the milvus
+type MilvusResourceBudget struct {
+	// CPU budget of all the pods
+	// +kubebuilder:validation:Optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory budget of all the pods
+	// +kubebuilder:validation:Optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// MilvusInternalService is the internal-only service of milvus
+type MilvusInternalService struct {
+	// SelectUpdatedPodsOnRollout makes the internal service select only the pods of the new version during a rollout.
+	// It works when the component is deployed by 2 deployments, otherwise the service selects all pods
+	// +kubebuilder:validation:Optional
+	SelectUpdatedPodsOnRollout bool `json:"selectUpdatedPodsOnRollout,omitempty"`
 }
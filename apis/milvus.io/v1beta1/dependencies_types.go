@@ -1,6 +1,11 @@
 package v1beta1
 
-import "github.com/zilliztech/milvus-operator/pkg/helm/values"
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/zilliztech/milvus-operator/pkg/helm/values"
+)
 
 type DependencyDeletionPolicy string
 
@@ -16,6 +21,11 @@ const (
 )
 
 type MilvusDependencies struct {
+	// ManageDependencies whether the operator deploys & deletes the in-cluster dependencies, default to true.
+	// if false, the dependencies are managed externally, the operator only checks their status by the configured endpoints
+	// +kubebuilder:validation:Optional
+	ManageDependencies *bool `json:"manageDependencies,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	Etcd MilvusEtcd `json:"etcd"`
 
@@ -54,6 +64,11 @@ type MilvusDependencies struct {
 	CustomMsgStream Values `json:"customMsgStream,omitempty"`
 }
 
+// IsManageDependencies returns true if the operator manages the in-cluster dependencies
+func (m *MilvusDependencies) IsManageDependencies() bool {
+	return m.ManageDependencies == nil || *m.ManageDependencies
+}
+
 func (m *MilvusDependencies) GetMilvusBuiltInMQ() *MilvusBuiltInMQ {
 	switch m.MsgStreamType {
 	case MsgStreamTypePulsar, MsgStreamTypeKafka, MsgStreamTypeCustom:
@@ -90,6 +105,36 @@ type MilvusEtcd struct {
 
 	// +kubebuilder:validation:Optional
 	InCluster *InClusterConfig `json:"inCluster,omitempty"`
+
+	// Backup snapshots the managed etcd into the object storage of milvus periodically,
+	// it's ignored for the external etcd
+	// +kubebuilder:validation:Optional
+	Backup *EtcdBackup `json:"backup,omitempty"`
+}
+
+// EtcdBackup is the config of the cronjob snapshotting the managed etcd into the object storage
+type EtcdBackup struct {
+	// Schedule is the cron schedule of the backup, like "0 2 * * *"
+	Schedule string `json:"schedule"`
+
+	// Destination of the snapshots in the object storage
+	// +kubebuilder:validation:Optional
+	Destination EtcdBackupDestination `json:"destination,omitempty"`
+
+	// Image of the upload container, it should contain the minio client `mc`, default to minio/mc
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
+// EtcdBackupDestination is the location of the etcd snapshots in the object storage
+type EtcdBackupDestination struct {
+	// Bucket to store the snapshots, default to the bucket of milvus
+	// +kubebuilder:validation:Optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Path of the snapshots in the bucket, default to etcd-backup/<instance>
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
 }
 
 type InClusterConfig struct {
@@ -98,6 +143,11 @@ type InClusterConfig struct {
 	// +nullable
 	Values Values `json:"values,omitempty"`
 
+	// ValuesFrom is the source of user maintained helm values, which are merged over the chart defaults.
+	// the values set in spec.values take precedence over it
+	// +kubebuilder:validation:Optional
+	ValuesFrom *HelmValuesFrom `json:"valuesFrom,omitempty"`
+
 	// ChartVersion is the pulsar chart version to be installed
 	// For now only pulsar uses this field
 	// pulsar-v2 (v2.7.8) & pulsar-v3 (v3.3.0) can be used
@@ -107,6 +157,24 @@ type InClusterConfig struct {
 	// +kubebuilder:validation:Optional
 	ChartVersion values.ChartVersion `json:"chartVersion,omitempty"`
 
+	// DefragSchedule is the cron schedule of the job defragmenting the etcd, like "0 3 * * 0"
+	// For now only etcd uses this field
+	// the job is not created if it's empty
+	// +kubebuilder:validation:Optional
+	DefragSchedule string `json:"defragSchedule,omitempty"`
+
+	// Mode is the deployment mode of minio, it overrides the mode in values
+	// For now only minio uses this field
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:={"standalone", "distributed"}
+	Mode MinioMode `json:"mode,omitempty"`
+
+	// Replicas is the number of the minio pods, it overrides the replicas in values
+	// For now only minio uses this field
+	// distributed mode requires at least 4 replicas
+	// +kubebuilder:validation:Optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum:={"Delete", "Retain"}
 	// +kubebuilder:default:="Retain"
@@ -116,6 +184,17 @@ type InClusterConfig struct {
 	PVCDeletion bool `json:"pvcDeletion,omitempty"`
 }
 
+// HelmValuesFrom is the source of helm values in yaml, only one of the fields should be set
+type HelmValuesFrom struct {
+	// ConfigMapKeyRef refers to the key of a configmap in the same namespace of milvus
+	// +kubebuilder:validation:Optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// Inline is the helm values in yaml
+	// +kubebuilder:validation:Optional
+	Inline string `json:"inline,omitempty"`
+}
+
 type ChartVersion string
 
 const (
@@ -123,6 +202,16 @@ const (
 	ChartVersionPulsarV3 ChartVersion = "pulsar-v3"
 )
 
+type MinioMode string
+
+const (
+	MinioModeStandalone  MinioMode = "standalone"
+	MinioModeDistributed MinioMode = "distributed"
+)
+
+// MinioDistributedMinReplicas is the minimum replicas of minio in distributed mode
+const MinioDistributedMinReplicas = 4
+
 type MilvusStorage struct {
 	// +kubebuilder:default:="MinIO"
 	// +kubebuilder:validation:Enum:={"MinIO", "S3", "Azure", ""}
@@ -135,6 +224,19 @@ type MilvusStorage struct {
 	// +kubebuilder:validation:Optional
 	Endpoint string `json:"endpoint"`
 
+	// Region of the bucket, it's set as minio.region in the milvus config & used by the storage probe
+	// +kubebuilder:validation:Optional
+	Region string `json:"region,omitempty"`
+
+	// UseSSL overrides minio.useSSL in the milvus config if set
+	// +kubebuilder:validation:Optional
+	UseSSL *bool `json:"useSSL,omitempty"`
+
+	// UseVirtualHost overrides minio.useVirtualHost in the milvus config if set,
+	// the bucket is accessed in virtual-hosted style if true, in path style if false
+	// +kubebuilder:validation:Optional
+	UseVirtualHost *bool `json:"useVirtualHost,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	InCluster *InClusterConfig `json:"inCluster,omitempty"`
 
@@ -145,6 +247,12 @@ type MilvusStorage struct {
 	// SSL configuration for secure storage connections
 	// +kubebuilder:validation:Optional
 	SSL *MilvusStorageSSLConfig `json:"ssl,omitempty"`
+
+	// CapacityWarningThreshold when set, the operator queries the usage of the bucket,
+	// and sets the StorageCapacityWarning condition if the usage exceeds the threshold.
+	// Only MinIO reports the bucket usage, it's ignored for other storage types
+	// +kubebuilder:validation:Optional
+	CapacityWarningThreshold *resource.Quantity `json:"capacityWarningThreshold,omitempty"`
 }
 
 // MilvusStorageSSLConfig defines SSL configuration for storage connections
@@ -193,6 +301,11 @@ type MilvusKafka struct {
 
 	// +kubebuilder:validation:Optional
 	BrokerList []string `json:"brokerList,omitempty"`
+
+	// BrokerListFromSecret refers to a secret key whose value is the comma separated broker list.
+	// it overrides brokerList when set, and is re-read when the secret changes
+	// +kubebuilder:validation:Optional
+	BrokerListFromSecret *corev1.SecretKeySelector `json:"brokerListFromSecret,omitempty"`
 }
 
 // MilvusTei configuration
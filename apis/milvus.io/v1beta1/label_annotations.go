@@ -24,6 +24,8 @@ const (
 	StoppedAtAnnotation                    = MilvusIO + "stopped-at"
 	PodAnnotationUsingConfigMap            = MilvusIO + "using-configmap"
 	AnnotationMilvusGeneration             = MilvusIO + "generation"
+	// AnnotationOperatorVersion records the version of the operator a resource is rendered by
+	AnnotationOperatorVersion = MilvusIO + "rendered-by-operator-version"
 
 	// PodServiceLabelAddedAnnotation is to indicate whether the milvus.io/service=true label is added to proxy & standalone pods
 	// previously, we use milvus.io/component: proxy / standalone; to select the service pods
@@ -33,6 +35,14 @@ const (
 	// ServiceLabel is the label to indicate whether the pod is a service pod
 	ServiceLabel                         = MilvusIO + "service"
 	OldAnnotationCurrentQueryNodeGroupID = MilvusIO + "current-querynode-group-id"
+
+	// AllowMsgStreamSwitchAnnotation allows changing the msgStreamType of a running milvus,
+	// the in-flight messages in the old message queue will be lost
+	AllowMsgStreamSwitchAnnotation = MilvusIO + "allow-mq-switch"
+
+	// AllowIncompatibleDowngradeAnnotation allows downgrading the image of a milvus across incompatible versions,
+	// the data written by the newer version may not be readable by the older one
+	AllowIncompatibleDowngradeAnnotation = MilvusIO + "allow-incompatible-downgrade"
 )
 
 // +kubebuilder:object:generate=false
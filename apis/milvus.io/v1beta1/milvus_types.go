@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"sort"
 	"strings"
 	"time"
 
@@ -32,6 +33,16 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// ConfMergeStrategy is how the user config overlays the default config
+type ConfMergeStrategy string
+
+const (
+	// ConfMergeStrategyDeepMerge merges the user config into the default config recursively
+	ConfMergeStrategyDeepMerge ConfMergeStrategy = "deepMerge"
+	// ConfMergeStrategyReplaceSection replaces the top level sections of the default config with the ones in user config
+	ConfMergeStrategyReplaceSection ConfMergeStrategy = "replaceSection"
+)
+
 // MilvusSpec defines the desired state of Milvus
 type MilvusSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -52,10 +63,194 @@ type MilvusSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Conf Values `json:"config,omitempty"`
 
+	// ConfMergeStrategy is how the config overlays the default config rendered by the operator, default to deepMerge
+	// replaceSection replaces the whole top level sections set in config
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:={"deepMerge", "replaceSection"}
+	ConfMergeStrategy ConfMergeStrategy `json:"configMergeStrategy,omitempty"`
+
+	// ConfReplacePaths are dot separated paths in config like "queryNode.segcore",
+	// the values at which replace the default ones instead of being deep merged
+	// +kubebuilder:validation:Optional
+	ConfReplacePaths []string `json:"configReplacePaths,omitempty"`
+
+	// FeatureGates toggles the experimental milvus features by name, e.g. ClusteringCompaction: true
+	// they're rendered into the config keys of the gates over spec.config. unknown gates are ignored
+	// +kubebuilder:validation:Optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// ExportRenderedConf when enabled, the fully rendered milvus config is written to a dedicated configmap for debugging,
+	// the values of the secret-like keys are redacted
+	// +kubebuilder:validation:Optional
+	ExportRenderedConf bool `json:"exportRenderedConfig,omitempty"`
+
+	// ConfProjectedSources are extra sources projected into the config volume alongside the operator managed configmap,
+	// the config volume becomes a projected volume if set
+	// +kubebuilder:validation:Optional
+	ConfProjectedSources []corev1.VolumeProjection `json:"configProjectedSources,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +nullable
 	HookConf Values `json:"hookConfig,omitempty"`
+
+	// HealthGateWebhook is the URL of an external health gate, e.g. a smoke test service.
+	// when set, milvus becomes Healthy only after a GET request to the URL returns 200
+	// +kubebuilder:validation:Optional
+	HealthGateWebhook string `json:"healthGateWebhook,omitempty"`
+
+	// RestoreFrom seeds a new milvus from a backup of the milvus-backup tool.
+	// It only takes effect when set at creation, the restore job runs once after milvus is ready
+	// +kubebuilder:validation:Optional
+	RestoreFrom *MilvusRestoreSource `json:"restoreFrom,omitempty"`
+
+	// Schedule stops & starts the milvus automatically by cron expressions
+	// +kubebuilder:validation:Optional
+	Schedule *MilvusSchedule `json:"schedule,omitempty"`
+
+	// GracefulTermination stops the components in order to flush the data before the milvus is deleted
+	// +kubebuilder:validation:Optional
+	GracefulTermination *MilvusGracefulTermination `json:"gracefulTermination,omitempty"`
+
+	// DeletePolicy of the milvus, BackupThenDelete backs up the milvus by a job before it's deleted, default to Delete
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"Delete", "BackupThenDelete"}
+	DeletePolicy MilvusDeletePolicy `json:"deletePolicy,omitempty"`
+
+	// BackupBeforeDelete configures the backup job when deletePolicy is BackupThenDelete
+	// +kubebuilder:validation:Optional
+	BackupBeforeDelete *MilvusBackupBeforeDelete `json:"backupBeforeDelete,omitempty"`
+
+	// HealthCheck configures the extra checks on whether the milvus is healthy
+	// +kubebuilder:validation:Optional
+	HealthCheck *MilvusHealthCheck `json:"healthCheck,omitempty"`
+
+	// ClientCredentialsSecretRef refers to the secret of the milvus user that the operator & its jobs connect as
+	// when authorization is enabled. The secret in the namespace of milvus should have the keys `username` & `password`,
+	// optionally `ca.crt` to verify the server certificate, and `tls.crt` & `tls.key` for mutual tls.
+	// If it's not set, the root user with common.security.defaultRootPassword is used, which only works until the password is changed
+	// +kubebuilder:validation:Optional
+	ClientCredentialsSecretRef *corev1.LocalObjectReference `json:"clientCredentialsSecretRef,omitempty"`
+}
+
+// MilvusHealthCheck is the config of the milvus health check
+type MilvusHealthCheck struct {
+	// Deep when enabled, the operator connects to the milvus as a client and lists the collections,
+	// the milvus is healthy only if the call succeeds besides all the components are ready.
+	// It connects with spec.clientCredentialsSecretRef if authorization is enabled, and in tls by common.security.tlsMode
+	// +kubebuilder:validation:Optional
+	Deep bool `json:"deep,omitempty"`
+}
+
+// IsDeepHealthCheckEnabled returns whether the deep health check is enabled
+func (ms MilvusSpec) IsDeepHealthCheckEnabled() bool {
+	return ms.HealthCheck != nil && ms.HealthCheck.Deep
+}
+
+// DefaultGracefulTerminationTimeoutSeconds is the default deadline of the graceful termination
+const DefaultGracefulTerminationTimeoutSeconds = 600
+
+// MilvusGracefulTermination is the config of the graceful termination on milvus deletion
+type MilvusGracefulTermination struct {
+	// Enabled adds a finalizer to the milvus, so that when it's deleted,
+	// the proxies, nodes and coords are stopped one tier after another before the resources are deleted
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TimeoutSeconds is the deadline of the graceful termination since the deletion,
+	// after which the milvus is deleted anyway, default to 600
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// IsGracefulTerminationEnabled returns true if the graceful termination on deletion is enabled
+func (ms MilvusSpec) IsGracefulTerminationEnabled() bool {
+	return ms.GracefulTermination != nil && ms.GracefulTermination.Enabled
+}
+
+// GetGracefulTerminationTimeout returns the deadline of the graceful termination
+func (ms MilvusSpec) GetGracefulTerminationTimeout() time.Duration {
+	if ms.GracefulTermination == nil || ms.GracefulTermination.TimeoutSeconds < 1 {
+		return DefaultGracefulTerminationTimeoutSeconds * time.Second
+	}
+	return time.Duration(ms.GracefulTermination.TimeoutSeconds) * time.Second
+}
+
+// MilvusDeletePolicy is the policy of what to do before the milvus is deleted
+type MilvusDeletePolicy string
+
+const (
+	// DeletePolicyDelete deletes the milvus directly
+	DeletePolicyDelete MilvusDeletePolicy = "Delete"
+	// DeletePolicyBackupThenDelete holds the milvus on deletion until a backup job succeeds
+	DeletePolicyBackupThenDelete MilvusDeletePolicy = "BackupThenDelete"
+)
+
+// MilvusBackupBeforeDelete is the config of the backup job before the milvus is deleted
+type MilvusBackupBeforeDelete struct {
+	// Image of the backup job, it should be the milvus-backup tool, default to milvusdb/milvus-backup of the version tested with the operator
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Bucket to store the backup, default to the bucket of milvus
+	// +kubebuilder:validation:Optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Path of the backup in the bucket, default to backup
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+}
+
+// IsBackupBeforeDeleteEnabled returns true if the milvus should be backed up before it's deleted
+func (ms MilvusSpec) IsBackupBeforeDeleteEnabled() bool {
+	return ms.DeletePolicy == DeletePolicyBackupThenDelete
+}
+
+// MilvusRestoreSource is the backup created by the milvus-backup tool to restore from
+type MilvusRestoreSource struct {
+	// BackupName is the name of the backup
+	BackupName string `json:"backupName"`
+
+	// Bucket where the backups are stored, default to the bucket of milvus
+	// +kubebuilder:validation:Optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Path where the backups are stored in the bucket, default to backup
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// Image of the restore job, it should be the milvus-backup tool, default to milvusdb/milvus-backup of the version tested with the operator
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
+// MilvusSchedule is the schedule to stop & start milvus automatically
+// milvus is stopped when the latest stop time is after the latest start time
+type MilvusSchedule struct {
+	// Stop is the cron expression of the time to stop milvus, e.g. "0 22 * * 1-5"
+	Stop string `json:"stop"`
+
+	// Start is the cron expression of the time to start milvus, e.g. "0 8 * * 1-5"
+	Start string `json:"start"`
+
+	// TimeZone of the cron expressions in IANA format, e.g. "Asia/Shanghai", default to UTC
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// IsStopping returns true if milvus is stopped by spec or by spec.schedule
+func (m Milvus) IsStopping() bool {
+	return m.Spec.IsStopping() || m.IsStoppedBySchedule()
+}
+
+// IsStoppedBySchedule returns true if milvus is within the stop window of spec.schedule
+func (m Milvus) IsStoppedBySchedule() bool {
+	if m.Spec.Schedule == nil {
+		return false
+	}
+	cond := GetMilvusConditionByType(&m.Status, ScheduledStop)
+	return cond != nil && cond.Status == corev1.ConditionTrue
 }
 
 // IsStopping returns true if the MilvusSpec has replicas serving
@@ -116,7 +311,7 @@ func (ms MilvusSpec) IsVersionGreaterThan2_6() bool {
 }
 
 func isImageVersionGreaterThan2_6(version, image string) bool {
-	if version != "" {
+	if version != "" && version != UnknownVersion {
 		semanticVersion, err := semver.ParseTolerant(version)
 		if err != nil {
 			return false
@@ -124,13 +319,11 @@ func isImageVersionGreaterThan2_6(version, image string) bool {
 		return semanticVersion.GT(sermanticVersion2_5_Max)
 	}
 
-	// use tag if version is not set, parse format: registry/namespace/image:tag
-	parts := strings.Split(image, ":")
-
-	if len(parts) != 2 {
+	// use tag if version is not set, parse format: registry/namespace/image:tag[@digest]
+	_, imageTag, _ := SplitImage(image)
+	if imageTag == "" {
 		return false
 	}
-	imageTag := parts[1]
 	if strings.HasPrefix(imageTag, "master-") {
 		return true
 	}
@@ -143,15 +336,88 @@ func isImageVersionGreaterThan2_6(version, image string) bool {
 
 // GetMilvusVersionByImage returns the version of Milvus by ms.Com.ComponentSpec.Image
 func (ms MilvusSpec) GetMilvusVersionByImage() (semver.Version, error) {
-	// parse format: registry/namespace/image:tag
-	splited := strings.Split(ms.Com.Image, ":")
-	if len(splited) != 2 {
-		return semver.Version{}, errors.Errorf("unknown version of image[%s]", splited[0])
+	return getMilvusVersionByImage(ms.Com.Image)
+}
+
+// UnknownVersion is the version of milvus whose image tag isn't a semantic version
+const UnknownVersion = "unknown"
+
+// GetMilvusVersion returns the semantic version of milvus by ms.Com.Version, or by the image tag if version not set.
+// it returns UnknownVersion if neither can be parsed, e.g. the image is pinned by digest or tagged by a branch
+func (ms MilvusSpec) GetMilvusVersion() string {
+	if ms.Com.Version != "" {
+		if version, err := semver.ParseTolerant(ms.Com.Version); err == nil {
+			return version.String()
+		}
+	}
+	version, err := getMilvusVersionByImage(ms.Com.Image)
+	if err != nil {
+		return UnknownVersion
+	}
+	return version.String()
+}
+
+// FeatureGateConfigKeys are the milvus config keys of the known feature gates
+var FeatureGateConfigKeys = map[string]string{
+	"ClusteringCompaction": "dataCoord.compaction.clustering.enable",
+	"JSONKeyStats":         "common.enabledJSONKeyStats",
+	"StorageV2":            "common.storage.enablev2",
+	"InterimIndex":         "queryNode.segcore.interimIndex.enableIndex",
+	"MmapVectorField":      "queryNode.mmap.vectorField",
+	"MmapScalarField":      "queryNode.mmap.scalarField",
+	"GrowingMmap":          "queryNode.mmap.growingMmapEnabled",
+}
+
+// GetUnknownFeatureGates returns the sorted names of the feature gates not in FeatureGateConfigKeys
+func (ms MilvusSpec) GetUnknownFeatureGates() []string {
+	var ret []string
+	for gate := range ms.FeatureGates {
+		if _, ok := FeatureGateConfigKeys[gate]; !ok {
+			ret = append(ret, gate)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func getMilvusVersionByImage(image string) (semver.Version, error) {
+	// parse format: registry/namespace/image:tag[@digest]
+	repository, imageTag, _ := SplitImage(image)
+	if imageTag == "" {
+		return semver.Version{}, errors.Errorf("unknown version of image[%s]", repository)
 	}
-	imageTag := splited[1]
 	return semver.ParseTolerant(imageTag)
 }
 
+// SplitImage splits the image in format [registry/]repository[:tag][@digest] into the repository, tag & digest
+func SplitImage(image string) (repository, tag, digest string) {
+	repository = image
+	if idx := strings.Index(repository, "@"); idx >= 0 {
+		digest = repository[idx+1:]
+		repository = repository[:idx]
+	}
+	// the colon before the last slash belongs to the registry port
+	if idx := strings.LastIndex(repository, ":"); idx > strings.LastIndex(repository, "/") {
+		tag = repository[idx+1:]
+		repository = repository[:idx]
+	}
+	return repository, tag, digest
+}
+
+// IsSameImage returns whether the 2 images refer to the same image,
+// images pinned by digest are compared by the repository & digest regardless of the tags
+func IsSameImage(a, b string) bool {
+	if a == b {
+		return true
+	}
+	repoA, _, digestA := SplitImage(a)
+	repoB, _, digestB := SplitImage(b)
+	if digestA == "" || digestB == "" {
+		return false
+	}
+	return repoA == repoB && digestA == digestB
+}
+
 func (ms *MilvusSpec) GetPersistenceConfig() *Persistence {
 	switch ms.Dep.MsgStreamType {
 	case MsgStreamTypeRocksMQ:
@@ -218,6 +484,10 @@ type MilvusStatus struct {
 	// IngressStatus of the ingress created by milvus
 	IngressStatus networkv1.IngressStatus `json:"ingress,omitempty"`
 
+	// DependencyEndpoints are the endpoints of the dependencies resolved by the operator
+	// +optional
+	DependencyEndpoints *DependencyEndpoints `json:"dependencyEndpoints,omitempty"`
+
 	// ComponentsDeployStatus contains the map of component's name to the status of each component deployment
 	// it is used to check the status of rolling update of each component
 	// +optional
@@ -238,9 +508,67 @@ type MilvusStatus struct {
 	// +optional
 	CurrentImage string `json:"currentImage,omitempty"`
 
-	// CurrentVersion is the current version of the milvus cluster
+	// CurrentVersion is the semantic version of the running milvus, parsed from the version or the image tag.
+	// it's unknown if neither is a semantic version
 	// +optional
 	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// PendingPostUpgradeActions are the post upgrade actions of the last upgrade not performed yet
+	// +optional
+	PendingPostUpgradeActions []PostUpgradeAction `json:"pendingPostUpgradeActions,omitempty"`
+
+	// PostUpgradeCompactionCursor is the last collection compacted by the pending triggerCompaction action, in format <database>/<collection>.
+	// the compaction is triggered in batches across the syncs, and continues after the cursor
+	// +optional
+	PostUpgradeCompactionCursor string `json:"postUpgradeCompactionCursor,omitempty"`
+
+	// LastReconcileTime is the last time the milvus is reconciled successfully, it's updated at most once a minute
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// RollingUpdateProgress is the percent of the replicas updated to the target image across the components,
+	// it's 100 only when all the components are updated and ready
+	// +optional
+	RollingUpdateProgress int `json:"rollingUpdateProgress"`
+
+	// ManagedReleases are the helm releases of the in-cluster dependencies installed by the operator.
+	// a release is uninstalled when its dependency is changed to external
+	// +optional
+	ManagedReleases []string `json:"managedReleases,omitempty"`
+
+	// DependencyReleases are the chart & app versions of the deployed helm releases in ManagedReleases
+	// +optional
+	DependencyReleases []DependencyReleaseStatus `json:"dependencyReleases,omitempty"`
+
+	// RenderedConfigMap is the name of the configmap containing the fully rendered milvus config with secrets redacted,
+	// it's set when spec.exportRenderedConfig is enabled
+	// +optional
+	RenderedConfigMap string `json:"renderedConfigMap,omitempty"`
+}
+
+// DependencyReleaseStatus is the deployed helm release of an in-cluster dependency
+type DependencyReleaseStatus struct {
+	// Name of the helm release
+	Name string `json:"name"`
+	// ChartVersion is the version of the chart deployed
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// AppVersion is the version of the app in the chart
+	// +optional
+	AppVersion string `json:"appVersion,omitempty"`
+}
+
+// DependencyEndpoints are the endpoints of milvus dependencies
+// for in-cluster dependencies, it's the in-cluster service address
+// for external dependencies, it's the configured address
+type DependencyEndpoints struct {
+	// +optional
+	Etcd []string `json:"etcd,omitempty"`
+	// +optional
+	Storage string `json:"storage,omitempty"`
+	// MsgStream is empty for the msgStreams embedded in milvus, like rocksmq, natsmq & woodpecker
+	// +optional
+	MsgStream []string `json:"msgStream,omitempty"`
 }
 
 // RollingMode we have changed our rolling mode several times, so we use this enum to track the version of rolling mode the milvus CR is using
@@ -277,6 +605,9 @@ type ComponentDeployStatus struct {
 	Image string `json:"image"`
 	// Status of the deployment
 	Status appsv1.DeploymentStatus `json:"status"`
+	// CreationTimestamp of the deployment
+	// +optional
+	CreationTimestamp *metav1.Time `json:"creationTimestamp,omitempty"`
 }
 
 // DeploymentState is defined according to https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#deployment-status
@@ -371,6 +702,28 @@ type MilvusIngress struct {
 	// TLSSecretRefs is a map of TLS secret to hosts
 	// +kubebuilder:validation:Optional
 	TLSSecretRefs map[string][]string `json:"tlsSecretRefs,omitempty"`
+
+	// TLSCertExpiryWarningWindow is the window before the expiry of the certificates in TLSSecretRefs
+	// within which the TLSCertificateValid condition turns false. Default is 720h (30 days)
+	// +kubebuilder:validation:Optional
+	TLSCertExpiryWarningWindow *metav1.Duration `json:"tlsCertExpiryWarningWindow,omitempty"`
+
+	// RESTful when set, a separate ingress named <instance>-milvus-restful routes to the RESTful port (9091) of the proxy,
+	// so that it can have backend protocol annotations different from the gRPC one.
+	// it shares the labels, ingressClassName & tlsSecretRefs above
+	// +kubebuilder:validation:Optional
+	RESTful *MilvusIngressBackend `json:"restful,omitempty"`
+}
+
+// MilvusIngressBackend defines the ingress of a port other than the gRPC port
+type MilvusIngressBackend struct {
+	// Annotations of the ingress, the annotations of the gRPC ingress are not inherited
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Hosts default to the hosts of the gRPC ingress
+	// +kubebuilder:validation:Optional
+	Hosts []string `json:"hosts,omitempty"`
 }
 
 // MilvusCondition contains details for the current condition of this milvus/milvus cluster instance
@@ -446,13 +799,63 @@ const (
 	MilvusReady MilvusConditionType = "MilvusReady"
 	// MilvusUpdated means the Milvus has updated according to its spec.
 	MilvusUpdated MilvusConditionType = "MilvusUpdated"
+	// TLSCertificateValid means the TLS certificates referenced by the ingress are not expiring within the warning window.
+	TLSCertificateValid MilvusConditionType = "TLSCertificateValid"
+	// GenerationObserved means the latest generation of the spec has been observed by the operator.
+	GenerationObserved MilvusConditionType = "GenerationObserved"
+	// HealthGatePassed means the external health gate webhook accepts milvus to be healthy.
+	HealthGatePassed MilvusConditionType = "HealthGatePassed"
+	// DependencyConflict means another milvus uses the same external etcd root path or storage bucket.
+	DependencyConflict MilvusConditionType = "DependencyConflict"
+	// ConfigurationDrift means the operator reverted manual changes of the managed deployments.
+	ConfigurationDrift MilvusConditionType = "ConfigurationDrift"
+	// RestoreCompleted means milvus has been restored from spec.restoreFrom.
+	RestoreCompleted MilvusConditionType = "RestoreCompleted"
+	// StorageCapacityWarning means the usage of the storage bucket exceeds the capacity warning threshold.
+	StorageCapacityWarning MilvusConditionType = "StorageCapacityWarning"
+	// ProxyTrafficGated means the proxy pods are held unready by the readiness gate until the coordinators are ready.
+	ProxyTrafficGated MilvusConditionType = "ProxyTrafficGated"
+	// RolloutPaused means the image update of the components is paused by annotation,
+	// it's set false when the annotation is cleared
+	RolloutPaused MilvusConditionType = "RolloutPaused"
+	// InitContainerFailed means the config init container of some pods restarted beyond spec.components.initContainerFailureThreshold.
+	InitContainerFailed MilvusConditionType = "InitContainerFailed"
+	// SlowReconcile means the last reconcile of milvus took longer than the slow reconcile threshold of the operator,
+	// which usually indicates a wedged dependency
+	SlowReconcile MilvusConditionType = "SlowReconcile"
+	// OptionalResourceUnavailable means some optional resources like the PodMonitor are skipped,
+	// because their kinds are not registered in the scheme of the operator or their CRDs are not installed
+	OptionalResourceUnavailable MilvusConditionType = "OptionalResourceUnavailable"
+	// ReplicasConflict means some components are scaled by a HorizontalPodAutoscaler while their replicas in spec are not -1,
+	// the replicas in spec are ignored for them and the HPA takes effect
+	ReplicasConflict MilvusConditionType = "ReplicasConflict"
+	// BackupBeforeDeleteFailed means the backup job before deletion failed, the deletion is blocked until
+	// the job is fixed & deleted to retry, or deletePolicy is changed to Delete
+	BackupBeforeDeleteFailed MilvusConditionType = "BackupBeforeDeleteFailed"
+	// ScheduledStop means milvus is within the stop window of spec.schedule, its components are scaled to 0
+	// without changing the replicas in spec
+	ScheduledStop MilvusConditionType = "ScheduledStop"
 
 	// ReasonEndpointsHealthy means the endpoint is healthy
 	ReasonEndpointsHealthy string = "EndpointsHealthy"
 	// ReasonMilvusHealthy means milvus cluster is healthy
 	ReasonMilvusHealthy string = "ReasonMilvusHealthy"
+	// ReasonMilvusQueryFailed means all milvus components are ready, but the deep health check call failed
+	ReasonMilvusQueryFailed string = "MilvusQueryFailed"
+	// ReasonMilvusDegraded means some milvus components are not fully ready, but enough replicas are serving
+	ReasonMilvusDegraded string = "MilvusDegraded"
 	// ReasonMilvusComponentNotHealthy means at least one of milvus component is not healthy
 	ReasonMilvusComponentNotHealthy string = "MilvusComponentNotHealthy"
+	// ReasonStartupTimeout means some milvus components have no ready replicas beyond the startup timeout since they're created
+	ReasonStartupTimeout string = "StartupTimeout"
+	// ReasonImagePullFailed means at least one of milvus component failed to pull image
+	ReasonImagePullFailed string = "ImagePullFailed"
+	// ReasonInsufficientCapacity means at least one of milvus component's pod can't be scheduled
+	ReasonInsufficientCapacity string = "InsufficientCapacity"
+	// ReasonContainerCrashLooping means at least one of milvus component's container is in CrashLoopBackOff
+	ReasonContainerCrashLooping string = "ContainerCrashLooping"
+	// ReasonContainerOOMKilled means at least one of milvus component's container is in CrashLoopBackOff because of OOMKilled
+	ReasonContainerOOMKilled string = "ContainerOOMKilled"
 	// ReasonMilvusStopped means milvus cluster is stopped
 	ReasonMilvusStopped string = "MilvusStopped"
 	// ReasonMilvusStopping means milvus cluster is stopping
@@ -461,10 +864,14 @@ const (
 	ReasonMilvusComponentsUpdated string = "MilvusComponentsUpdated"
 	// ReasonMilvusComponentsUpdating means some milvus components are not updated
 	ReasonMilvusComponentsUpdating string = "MilvusComponentsUpdating"
+	// ReasonWaitingForUpdateWindow means the image update is deferred until the update window opens
+	ReasonWaitingForUpdateWindow string = "WaitingForUpdateWindow"
 	// ReasonMilvusUpgradingImage means milvus is upgrading image
 	ReasonMilvusUpgradingImage string = "MilvusUpgradingImage"
 	// ReasonMilvusDowngradingImage means milvus is downgrading image
 	ReasonMilvusDowngradingImage string = "MilvusDowngradingImage"
+	// ReasonMilvusUpgradeRollback means milvus rolls back a stalled upgrade
+	ReasonMilvusUpgradeRollback string = "MilvusUpgradeRollback"
 
 	ReasonEtcdReady          = "EtcdReady"
 	ReasonEtcdNotReady       = "EtcdNotReady"
@@ -479,6 +886,40 @@ const (
 	ReasonClientErr          = "ClientError"
 	ReasonDependencyNotReady = "DependencyNotReady"
 
+	ReasonTLSCertificateValid    = "TLSCertificateValid"
+	ReasonTLSCertificateExpiring = "TLSCertificateExpiring"
+	ReasonTLSCertificateExpired  = "TLSCertificateExpired"
+	ReasonTLSCertificateInvalid  = "TLSCertificateInvalid"
+
+	ReasonGenerationObserved = "GenerationObserved"
+	ReasonGenerationLagging  = "GenerationLagging"
+
+	ReasonHealthGatePassed = "HealthGatePassed"
+	ReasonHealthGateFailed = "HealthGateFailed"
+
+	ReasonDependencyConflict   = "DependencyConflict"
+	ReasonNoDependencyConflict = "NoDependencyConflict"
+
+	ReasonDeploymentDriftCorrected = "DeploymentDriftCorrected"
+	ReasonNoDeploymentDrift        = "NoDeploymentDrift"
+
+	ReasonRestoring        = "Restoring"
+	ReasonRestoreCompleted = "RestoreCompleted"
+	ReasonRestoreFailed    = "RestoreFailed"
+
+	ReasonStorageCapacityExceeded = "StorageCapacityExceeded"
+	ReasonWaitingForCoordinators  = "WaitingForCoordinators"
+	ReasonRolloutPaused           = "RolloutPaused"
+	ReasonRolloutResumed          = "RolloutResumed"
+	ReasonConfigInitFailed        = "ConfigInitFailed"
+	ReasonReconcileSlow           = "ReconcileSlow"
+	ReasonKindUnavailable         = "KindUnavailable"
+	ReasonHPAReplicasConflict     = "HPAReplicasConflict"
+	ReasonBackupJobFailed         = "BackupJobFailed"
+
+	ReasonInStopWindow    = "InStopWindow"
+	ReasonOutOfStopWindow = "OutOfStopWindow"
+
 	MsgMilvusHasTerminatingPods = "Milvus has terminating pods"
 )
 
@@ -491,6 +932,7 @@ const (
 // +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Milvus mode"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.status",description="Milvus status"
 // +kubebuilder:printcolumn:name="Updated",type="string",JSONPath=".status.conditions[?(@.type==\"MilvusUpdated\")].status",description="Milvus updated"
+// +kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.rollingUpdateProgress",description="Percent of replicas updated"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // Milvus is the Schema for the milvus API
 type Milvus struct {
@@ -501,6 +943,15 @@ type Milvus struct {
 	Status MilvusStatus `json:"status,omitempty"`
 }
 
+// GetCurrentVersion returns the semantic version of the running milvus by status.currentVersion,
+// or by the tag of status.currentImage if the version is unknown
+func (m *Milvus) GetCurrentVersion() (semver.Version, error) {
+	if m.Status.CurrentVersion != "" && m.Status.CurrentVersion != UnknownVersion {
+		return semver.ParseTolerant(m.Status.CurrentVersion)
+	}
+	return getMilvusVersionByImage(m.Status.CurrentImage)
+}
+
 func (m *Milvus) IsCurrentImageVersionGreaterThan2_6() bool {
 	return isImageVersionGreaterThan2_6(m.Status.CurrentVersion, m.Status.CurrentImage)
 }
@@ -217,6 +217,85 @@ func TestGetMilvusVersionByGlobalImage(t *testing.T) {
 	m.Spec.Com.Image = "harbor.milvus.io/milvus/milvus:latest"
 	_, err = m.Spec.GetMilvusVersionByImage()
 	assert.Error(t, err)
+
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	m.Spec.Com.Image = "harbor.milvus.io:8443/milvus/milvus:v2.6.0@" + digest
+	ver, err = m.Spec.GetMilvusVersionByImage()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), ver.Minor)
+	assert.True(t, m.Spec.IsVersionGreaterThan2_6())
+
+	m.Spec.Com.Image = "milvusdb/milvus@" + digest
+	_, err = m.Spec.GetMilvusVersionByImage()
+	assert.Error(t, err)
+}
+
+func TestMilvusSpec_GetMilvusVersion(t *testing.T) {
+	m := Milvus{}
+	m.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+	assert.Equal(t, "2.6.0", m.Spec.GetMilvusVersion())
+
+	m.Spec.Com.Image = "my-registry/milvus:nightly-build"
+	assert.Equal(t, UnknownVersion, m.Spec.GetMilvusVersion())
+	// the unknown version falls back to the image
+	assert.False(t, isImageVersionGreaterThan2_6(UnknownVersion, m.Spec.Com.Image))
+	assert.True(t, isImageVersionGreaterThan2_6(UnknownVersion, "milvusdb/milvus:master-20250101"))
+
+	m.Spec.Com.Version = "v2.5.4"
+	assert.Equal(t, "2.5.4", m.Spec.GetMilvusVersion())
+
+	m.Spec.Com.Version = "weird"
+	m.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+	assert.Equal(t, "2.6.0", m.Spec.GetMilvusVersion())
+}
+
+func TestMilvus_GetCurrentVersion(t *testing.T) {
+	m := Milvus{}
+	m.Status.CurrentImage = "milvusdb/milvus:v2.6.1"
+	m.Status.CurrentVersion = "2.5.4"
+	ver, err := m.GetCurrentVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "2.5.4", ver.String())
+
+	m.Status.CurrentVersion = UnknownVersion
+	ver, err = m.GetCurrentVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "2.6.1", ver.String())
+
+	m.Status.CurrentImage = "my-registry/milvus:nightly-build"
+	_, err = m.GetCurrentVersion()
+	assert.Error(t, err)
+}
+
+func TestSplitImage(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	cases := []struct {
+		image, repository, tag, digest string
+	}{
+		{"milvusdb/milvus", "milvusdb/milvus", "", ""},
+		{"milvusdb/milvus:v2.5.10", "milvusdb/milvus", "v2.5.10", ""},
+		{"milvusdb/milvus@" + digest, "milvusdb/milvus", "", digest},
+		{"milvusdb/milvus:v2.5.10@" + digest, "milvusdb/milvus", "v2.5.10", digest},
+		{"registry:5000/milvusdb/milvus", "registry:5000/milvusdb/milvus", "", ""},
+		{"registry:5000/milvusdb/milvus:v2.5.10@" + digest, "registry:5000/milvusdb/milvus", "v2.5.10", digest},
+	}
+	for _, c := range cases {
+		repository, tag, d := SplitImage(c.image)
+		assert.Equal(t, c.repository, repository, c.image)
+		assert.Equal(t, c.tag, tag, c.image)
+		assert.Equal(t, c.digest, d, c.image)
+	}
+}
+
+func TestIsSameImage(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	otherDigest := "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	assert.True(t, IsSameImage("milvusdb/milvus:v2.5.10", "milvusdb/milvus:v2.5.10"))
+	assert.False(t, IsSameImage("milvusdb/milvus:v2.5.10", "milvusdb/milvus:v2.5.11"))
+	assert.True(t, IsSameImage("milvusdb/milvus:v2.5.10@"+digest, "milvusdb/milvus@"+digest))
+	assert.False(t, IsSameImage("milvusdb/milvus:v2.5.10@"+digest, "milvusdb/milvus:v2.5.10@"+otherDigest))
+	assert.False(t, IsSameImage("milvusdb/milvus:v2.5.10@"+digest, "milvusdb/milvus:v2.5.10"))
+	assert.False(t, IsSameImage("milvusdb/milvus@"+digest, "other/milvus@"+digest))
 }
 
 func TestGetPersistenceConfig(t *testing.T) {
@@ -19,14 +19,16 @@ package v1beta1
 import (
 	"fmt"
 	"reflect"
+	"time"
 
+	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -35,12 +37,6 @@ import (
 	"github.com/zilliztech/milvus-operator/pkg/util"
 )
 
-func (r *Milvus) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
-		Complete()
-}
-
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
 //+kubebuilder:webhook:path=/mutate-milvus-io-v1beta1-milvus,mutating=true,failurePolicy=fail,sideEffects=None,groups=milvus.io,resources=milvuses,verbs=create;update,versions=v1beta1,name=mmilvus.kb.io,admissionReviewVersions=v1
@@ -72,11 +68,12 @@ func (r *Milvus) ValidateCreate() (admission.Warnings, error) {
 		allErrs = append(allErrs, errs...)
 	}
 
+	warnings := r.getFeatureGateWarnings()
 	if len(allErrs) == 0 {
-		return nil, nil
+		return warnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "Milvus"}, r.Name, allErrs)
+	return warnings, apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "Milvus"}, r.Name, allErrs)
 }
 
 func (r *Milvus) validateCommon() *field.Error {
@@ -86,9 +83,27 @@ func (r *Milvus) validateCommon() *field.Error {
 		fp := field.NewPath("spec").Child("components").Child("rollingMode")
 		return field.Invalid(fp, r.Spec.Com.RollingMode, "rollingMode should be 2 or 3")
 	}
+	if err := r.validateMsgStreamType(); err != nil {
+		return err
+	}
 	if err := r.validateEnableRolingUpdate(); err != nil {
 		return err
 	}
+	if err := r.validateSchedule(); err != nil {
+		return err
+	}
+	if err := r.validateUpdateWindow(); err != nil {
+		return err
+	}
+	if err := r.validateServiceExternalTrafficPolicy(); err != nil {
+		return err
+	}
+	if err := r.validateStreamingNodeGroups(); err != nil {
+		return err
+	}
+	if err := r.validateMinioMode(); err != nil {
+		return err
+	}
 	// examine values
 	if err := r.validatePersistConfig(); err != nil {
 		return err
@@ -96,6 +111,17 @@ func (r *Milvus) validateCommon() *field.Error {
 	return nil
 }
 
+func (r *Milvus) validateMsgStreamType() *field.Error {
+	if r.Spec.Mode != MilvusModeCluster {
+		return nil
+	}
+	if r.Spec.Dep.MsgStreamType != MsgStreamTypeRocksMQ {
+		return nil
+	}
+	fp := field.NewPath("spec").Child("dependencies").Child("msgStreamType")
+	return field.Invalid(fp, r.Spec.Dep.MsgStreamType, "msgStreamType rocksmq is only supported in standalone mode. Set spec.dependencies.msgStreamType to pulsar/kafka/woodpecker")
+}
+
 func (r *Milvus) validateEnableRolingUpdate() *field.Error {
 	if r.Spec.Com.EnableRollingUpdate == nil {
 		return nil
@@ -115,6 +141,100 @@ func (r *Milvus) validateEnableRolingUpdate() *field.Error {
 	return field.Invalid(fp, r.Spec.Com.EnableRollingUpdate, "enableRollingUpdate is not supported for msgStream rocksmq or natsmq. Set it to false or set spec.msgStreamType to kafka/pulsar")
 }
 
+func (r *Milvus) validateUpdateWindow() *field.Error {
+	window := r.Spec.Com.UpdateWindow
+	if window == nil {
+		return nil
+	}
+	fp := field.NewPath("spec").Child("components").Child("updateWindow")
+	if _, err := cron.ParseStandard(window.Start); err != nil {
+		return field.Invalid(fp.Child("start"), window.Start, err.Error())
+	}
+	if _, err := cron.ParseStandard(window.End); err != nil {
+		return field.Invalid(fp.Child("end"), window.End, err.Error())
+	}
+	if _, err := time.LoadLocation(window.TimeZone); err != nil {
+		return field.Invalid(fp.Child("timeZone"), window.TimeZone, err.Error())
+	}
+	return nil
+}
+
+func (r *Milvus) validateSchedule() *field.Error {
+	if r.Spec.Schedule == nil {
+		return nil
+	}
+	fp := field.NewPath("spec").Child("schedule")
+	if _, err := cron.ParseStandard(r.Spec.Schedule.Stop); err != nil {
+		return field.Invalid(fp.Child("stop"), r.Spec.Schedule.Stop, err.Error())
+	}
+	if _, err := cron.ParseStandard(r.Spec.Schedule.Start); err != nil {
+		return field.Invalid(fp.Child("start"), r.Spec.Schedule.Start, err.Error())
+	}
+	if _, err := time.LoadLocation(r.Spec.Schedule.TimeZone); err != nil {
+		return field.Invalid(fp.Child("timeZone"), r.Spec.Schedule.TimeZone, err.Error())
+	}
+	return nil
+}
+
+func (r *Milvus) validateServiceExternalTrafficPolicy() *field.Error {
+	var serviceComponent *ServiceComponent
+	fp := field.NewPath("spec").Child("components")
+	if r.Spec.Mode == MilvusModeCluster {
+		if r.Spec.Com.Proxy == nil {
+			return nil
+		}
+		serviceComponent = &r.Spec.Com.Proxy.ServiceComponent
+		fp = fp.Child("proxy")
+	} else {
+		if r.Spec.Com.Standalone == nil {
+			return nil
+		}
+		serviceComponent = &r.Spec.Com.Standalone.ServiceComponent
+		fp = fp.Child("standalone")
+	}
+	if serviceComponent.ServiceExternalTrafficPolicy == "" {
+		return nil
+	}
+	switch serviceComponent.ServiceType {
+	case corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+		return nil
+	}
+	return field.Invalid(fp.Child("serviceExternalTrafficPolicy"), serviceComponent.ServiceExternalTrafficPolicy, "serviceExternalTrafficPolicy is only supported for serviceType LoadBalancer or NodePort")
+}
+
+func (r *Milvus) validateStreamingNodeGroups() *field.Error {
+	if r.Spec.Com.StreamingNode == nil || len(r.Spec.Com.StreamingNode.Groups) < 1 {
+		return nil
+	}
+	fp := field.NewPath("spec").Child("components").Child("streamingNode").Child("groups")
+	if r.Spec.Com.RollingMode == RollingModeV3 {
+		return field.Invalid(fp, len(r.Spec.Com.StreamingNode.Groups), "streamingNode groups are not supported in rolling mode v3")
+	}
+	names := map[string]bool{}
+	for i, group := range r.Spec.Com.StreamingNode.Groups {
+		if names[group.Name] {
+			return field.Duplicate(fp.Index(i).Child("name"), group.Name)
+		}
+		names[group.Name] = true
+	}
+	return nil
+}
+
+func (r *Milvus) validateMinioMode() *field.Error {
+	inCluster := r.Spec.Dep.Storage.InCluster
+	if r.Spec.Dep.Storage.External || inCluster == nil {
+		return nil
+	}
+	if inCluster.Mode != MinioModeDistributed || inCluster.Replicas == nil {
+		return nil
+	}
+	if *inCluster.Replicas < MinioDistributedMinReplicas {
+		fp := field.NewPath("spec").Child("dependencies").Child("storage").Child("inCluster").Child("replicas")
+		return field.Invalid(fp, *inCluster.Replicas, fmt.Sprintf("minio distributed mode requires at least %d replicas", MinioDistributedMinReplicas))
+	}
+	return nil
+}
+
 func (r *Milvus) validatePersistConfig() *field.Error {
 	persistconfig := r.Spec.GetPersistenceConfig()
 	if persistconfig == nil {
@@ -129,7 +249,7 @@ func (r *Milvus) validatePersistConfig() *field.Error {
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Milvus) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
-	_, ok := old.(*Milvus)
+	oldMilvus, ok := old.(*Milvus)
 	if !ok {
 		return nil, errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
 	}
@@ -139,15 +259,139 @@ func (r *Milvus) ValidateUpdate(old runtime.Object) (admission.Warnings, error)
 		allErrs = append(allErrs, err)
 	}
 
+	if err := r.validateMsgStreamTypeSwitch(oldMilvus); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := r.validateImageDowngrade(oldMilvus); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if errs := r.validateExternal(); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
+	warnings := r.getServiceTypeChangeWarnings(oldMilvus)
+	warnings = append(warnings, r.getFeatureGateWarnings()...)
 	if len(allErrs) == 0 {
-		return nil, nil
+		return warnings, nil
+	}
+
+	return warnings, apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "Milvus"}, r.Name, allErrs)
+}
+
+// getServiceTypeChangeWarnings warns when the service type change makes the milvus service unreachable from outside the cluster
+func (r *Milvus) getServiceTypeChangeWarnings(old *Milvus) admission.Warnings {
+	if r.Spec.Mode != old.Spec.Mode {
+		return nil
+	}
+	oldType := getServiceType(old.Spec)
+	newType := getServiceType(r.Spec)
+	if oldType == newType || !isExternalServiceType(oldType) {
+		return nil
+	}
+	if newType == corev1.ServiceTypeLoadBalancer {
+		// the node ports are kept for the LoadBalancer service
+		return nil
+	}
+	return admission.Warnings{
+		fmt.Sprintf("changing service type from %s to %s, the clients connecting from outside the cluster through the %s service will be disconnected",
+			oldType, newType, oldType),
 	}
+}
+
+// getFeatureGateWarnings warns the unknown feature gates, which are not rendered into the config
+func (r *Milvus) getFeatureGateWarnings() admission.Warnings {
+	unknownGates := r.Spec.GetUnknownFeatureGates()
+	if len(unknownGates) < 1 {
+		return nil
+	}
+	return admission.Warnings{
+		fmt.Sprintf("unknown feature gates %v are ignored, known gates are the keys of %v", unknownGates, FeatureGateConfigKeys),
+	}
+}
+
+// getServiceType returns the type of the milvus service, it's empty if the component's not set
+func getServiceType(spec MilvusSpec) corev1.ServiceType {
+	if spec.Mode == MilvusModeCluster {
+		if spec.Com.Proxy == nil {
+			return ""
+		}
+		return spec.Com.Proxy.ServiceType
+	}
+	if spec.Com.Standalone == nil {
+		return ""
+	}
+	return spec.Com.Standalone.ServiceType
+}
 
-	return nil, apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "Milvus"}, r.Name, allErrs)
+func isExternalServiceType(serviceType corev1.ServiceType) bool {
+	return serviceType == corev1.ServiceTypeLoadBalancer || serviceType == corev1.ServiceTypeNodePort
+}
+
+// validateMsgStreamTypeSwitch blocks changing the msgStreamType of a running milvus without the allow annotation,
+// because the in-flight messages in the old message queue will be lost
+func (r *Milvus) validateMsgStreamTypeSwitch(old *Milvus) *field.Error {
+	if old.Status.Status == "" {
+		return nil
+	}
+	if old.Spec.Dep.MsgStreamType == "" || old.Spec.Dep.MsgStreamType == r.Spec.Dep.MsgStreamType {
+		return nil
+	}
+	if r.Annotations[AllowMsgStreamSwitchAnnotation] == TrueStr {
+		return nil
+	}
+	fp := field.NewPath("spec").Child("dependencies").Child("msgStreamType")
+	return field.Forbidden(fp, fmt.Sprintf("changing msgStreamType from %s to %s loses the in-flight messages, set annotation %s=true to allow it",
+		old.Spec.Dep.MsgStreamType, r.Spec.Dep.MsgStreamType, AllowMsgStreamSwitchAnnotation))
+}
+
+// incompatibleDowngradeBoundaries are the versions whose data can't be read by the versions before them,
+// e.g. the streaming service migration in 2.6
+var incompatibleDowngradeBoundaries = []semver.Version{
+	semver.MustParse("2.6.0"),
+}
+
+// validateImageDowngrade blocks downgrading the image across incompatible versions without the allow annotation
+func (r *Milvus) validateImageDowngrade(old *Milvus) *field.Error {
+	if old.Status.CurrentImage == "" || old.Status.CurrentImage == r.Spec.Com.Image {
+		return nil
+	}
+	if r.Annotations[AllowIncompatibleDowngradeAnnotation] == TrueStr {
+		return nil
+	}
+	current, err := old.GetCurrentVersion()
+	if err != nil {
+		return nil
+	}
+	target, err := r.Spec.GetMilvusVersionByImage()
+	if err != nil {
+		return nil
+	}
+	if !isIncompatibleDowngrade(current, target) {
+		return nil
+	}
+	fp := field.NewPath("spec").Child("components").Child("image")
+	return field.Forbidden(fp, fmt.Sprintf("downgrading from %s to %s is incompatible and may corrupt data, set annotation %s=true to allow it",
+		current, target, AllowIncompatibleDowngradeAnnotation))
+}
+
+func isIncompatibleDowngrade(current, target semver.Version) bool {
+	// ignore the pre-release & build metadata, e.g. v2.6.0-rc1
+	current = semver.Version{Major: current.Major, Minor: current.Minor, Patch: current.Patch}
+	target = semver.Version{Major: target.Major, Minor: target.Minor, Patch: target.Patch}
+	if target.GTE(current) {
+		return false
+	}
+	if target.Major < current.Major {
+		return true
+	}
+	for _, boundary := range incompatibleDowngradeBoundaries {
+		if current.GTE(boundary) && target.LT(boundary) {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -169,7 +413,8 @@ func (r *Milvus) validateExternal() field.ErrorList {
 
 	switch r.Spec.Dep.MsgStreamType {
 	case MsgStreamTypeKafka:
-		if r.Spec.Dep.Kafka.External && len(r.Spec.Dep.Kafka.BrokerList) == 0 {
+		if r.Spec.Dep.Kafka.External && len(r.Spec.Dep.Kafka.BrokerList) == 0 &&
+			r.Spec.Dep.Kafka.BrokerListFromSecret == nil {
 			allErrs = append(allErrs, required(fp.Child("kafka").Child("brokerList")))
 		}
 	case MsgStreamTypePulsar:
@@ -564,6 +809,21 @@ func (r *Milvus) DefaultConf() {
 	if *r.Spec.Com.EnableRollingUpdate {
 		setEnableActiveStandby(&r.Spec, true)
 	}
+	setStorageConf(&r.Spec)
+}
+
+// setStorageConf sets the storage options in spec.dependencies.storage into the minio section of the config
+func setStorageConf(spec *MilvusSpec) {
+	storage := spec.Dep.Storage
+	if storage.Region != "" {
+		util.SetValue(spec.Conf.Data, storage.Region, "minio", "region")
+	}
+	if storage.UseSSL != nil {
+		util.SetValue(spec.Conf.Data, *storage.UseSSL, "minio", "useSSL")
+	}
+	if storage.UseVirtualHost != nil {
+		util.SetValue(spec.Conf.Data, *storage.UseVirtualHost, "minio", "useVirtualHost")
+	}
 }
 
 var rollingUpdateConfigFields = []string{
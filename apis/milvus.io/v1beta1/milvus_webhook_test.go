@@ -3,8 +3,10 @@ package v1beta1
 import (
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
@@ -239,6 +241,27 @@ func TestMilvus_Default_DeleteUnSetableOK(t *testing.T) {
 	assert.Equal(t, conf.Data["minio"], mc.Spec.Conf.Data["minio"])
 }
 
+func TestMilvus_Default_StorageConf(t *testing.T) {
+	mc := Milvus{}
+	mc.Spec.Conf.Data = map[string]interface{}{
+		"minio": map[string]interface{}{
+			"useSSL": true,
+		},
+	}
+	mc.Default()
+	assert.Equal(t, map[string]interface{}{"useSSL": true}, mc.Spec.Conf.Data["minio"])
+
+	mc.Spec.Dep.Storage.Region = "us-west-2"
+	mc.Spec.Dep.Storage.UseSSL = util.BoolPtr(false)
+	mc.Spec.Dep.Storage.UseVirtualHost = util.BoolPtr(true)
+	mc.Default()
+	assert.Equal(t, map[string]interface{}{
+		"region":         "us-west-2",
+		"useSSL":         false,
+		"useVirtualHost": true,
+	}, mc.Spec.Conf.Data["minio"])
+}
+
 func TestMilvus_ValidateCreate_NoError(t *testing.T) {
 	mc := Milvus{}
 	mc.Default()
@@ -246,6 +269,25 @@ func TestMilvus_ValidateCreate_NoError(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMilvus_ValidateCreate_FeatureGateWarnings(t *testing.T) {
+	mc := Milvus{}
+	mc.Default()
+	mc.Spec.FeatureGates = map[string]bool{"ClusteringCompaction": true}
+	warnings, err := mc.ValidateCreate()
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	mc.Spec.FeatureGates["NotAGate"] = true
+	warnings, err = mc.ValidateCreate()
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "NotAGate")
+
+	warnings, err = mc.ValidateUpdate(mc.DeepCopy())
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+}
+
 func TestMilvus_ValidateCreate_Invalid1(t *testing.T) {
 	mc := Milvus{
 		Spec: MilvusSpec{
@@ -302,6 +344,131 @@ func TestMilvus_ValidateUpdate_Invalid(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMilvus_ValidateUpdate_MsgStreamTypeSwitch(t *testing.T) {
+	old := Milvus{}
+	old.Spec.Dep.MsgStreamType = MsgStreamTypePulsar
+	old.Status.Status = StatusHealthy
+	new := old.DeepCopy()
+	new.Spec.Dep.MsgStreamType = MsgStreamTypeKafka
+	new.Spec.Dep.Kafka.External = true
+	new.Spec.Dep.Kafka.BrokerList = []string{"kafka:9092"}
+
+	t.Run("blocked without annotation", func(t *testing.T) {
+		_, err := new.ValidateUpdate(&old)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), AllowMsgStreamSwitchAnnotation)
+	})
+
+	t.Run("allowed with annotation", func(t *testing.T) {
+		allowed := new.DeepCopy()
+		allowed.Annotations = map[string]string{AllowMsgStreamSwitchAnnotation: TrueStr}
+		_, err := allowed.ValidateUpdate(&old)
+		assert.NoError(t, err)
+	})
+
+	t.Run("allowed before milvus has status", func(t *testing.T) {
+		fresh := old.DeepCopy()
+		fresh.Status.Status = ""
+		_, err := new.ValidateUpdate(fresh)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMilvus_ValidateUpdate_ServiceTypeChangeWarnings(t *testing.T) {
+	old := Milvus{}
+	old.Spec.Mode = MilvusModeCluster
+	old.Spec.Com.Proxy = &MilvusProxy{}
+	old.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+
+	t.Run("LoadBalancer to ClusterIP warns", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeClusterIP
+		warnings, err := new.ValidateUpdate(&old)
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "from LoadBalancer to ClusterIP")
+	})
+
+	t.Run("NodePort to LoadBalancer no warning", func(t *testing.T) {
+		old := old.DeepCopy()
+		old.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeNodePort
+		new := old.DeepCopy()
+		new.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+		warnings, err := new.ValidateUpdate(old)
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("unchanged no warning", func(t *testing.T) {
+		warnings, err := old.ValidateUpdate(&old)
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+}
+
+func TestMilvus_ValidateUpdate_ImageDowngrade(t *testing.T) {
+	old := Milvus{}
+	old.Spec.Com.Image = "milvusdb/milvus:v2.6.1"
+	old.Status.CurrentImage = "milvusdb/milvus:v2.6.1"
+
+	t.Run("blocked cross major downgrade", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Com.Image = "milvusdb/milvus:v2.5.10"
+		_, err := new.ValidateUpdate(&old)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), AllowIncompatibleDowngradeAnnotation)
+	})
+
+	t.Run("allowed with annotation", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Com.Image = "milvusdb/milvus:v2.5.10"
+		new.Annotations = map[string]string{AllowIncompatibleDowngradeAnnotation: TrueStr}
+		_, err := new.ValidateUpdate(&old)
+		assert.NoError(t, err)
+	})
+
+	t.Run("blocked by current version of custom image", func(t *testing.T) {
+		old := old.DeepCopy()
+		old.Spec.Com.Image = "my-registry/milvus:custom"
+		old.Status.CurrentImage = "my-registry/milvus:custom"
+		old.Status.CurrentVersion = "2.6.1"
+		new := old.DeepCopy()
+		new.Spec.Com.Image = "milvusdb/milvus:v2.5.10"
+		_, err := new.ValidateUpdate(old)
+		assert.Error(t, err)
+	})
+
+	t.Run("allowed patch downgrade", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+		_, err := new.ValidateUpdate(&old)
+		assert.NoError(t, err)
+	})
+
+	t.Run("allowed upgrade", func(t *testing.T) {
+		old := old.DeepCopy()
+		old.Status.CurrentImage = "milvusdb/milvus:v2.5.10"
+		new := old.DeepCopy()
+		new.Spec.Com.Image = "milvusdb/milvus:v2.6.1"
+		_, err := new.ValidateUpdate(old)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown version not blocked", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Com.Image = "milvusdb/milvus:master-latest"
+		_, err := new.ValidateUpdate(&old)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIsIncompatibleDowngrade(t *testing.T) {
+	assert.True(t, isIncompatibleDowngrade(semver.MustParse("3.0.0"), semver.MustParse("2.6.5")))
+	assert.True(t, isIncompatibleDowngrade(semver.MustParse("2.6.0-rc1"), semver.MustParse("2.5.3")))
+	assert.False(t, isIncompatibleDowngrade(semver.MustParse("2.5.3"), semver.MustParse("2.4.9")))
+	assert.False(t, isIncompatibleDowngrade(semver.MustParse("2.6.2"), semver.MustParse("2.6.2")))
+}
+
 func TestMilvus_ValidateUpdate_KindAssertionFailed(t *testing.T) {
 	new := Milvus{}
 	old := appsv1.Deployment{}
@@ -384,3 +551,138 @@ func TestMilvus_validateCommon(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestMilvus_validateServiceExternalTrafficPolicy(t *testing.T) {
+	mc := Milvus{}
+	assert.Nil(t, mc.validateServiceExternalTrafficPolicy())
+
+	mc.Spec.Mode = MilvusModeCluster
+	mc.Default()
+	assert.Nil(t, mc.validateServiceExternalTrafficPolicy())
+
+	mc.Spec.Com.Proxy.ServiceExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+	mc.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeClusterIP
+	assert.NotNil(t, mc.validateServiceExternalTrafficPolicy())
+
+	mc.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+	assert.Nil(t, mc.validateServiceExternalTrafficPolicy())
+
+	mc.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeNodePort
+	assert.Nil(t, mc.validateServiceExternalTrafficPolicy())
+}
+
+func TestMilvus_validateStreamingNodeGroups(t *testing.T) {
+	mc := Milvus{}
+	assert.Nil(t, mc.validateStreamingNodeGroups())
+
+	mc.Spec.Com.StreamingNode = &MilvusStreamingNode{
+		Groups: []StreamingNodeGroup{{Name: "a"}, {Name: "b"}},
+	}
+	assert.Nil(t, mc.validateStreamingNodeGroups())
+
+	mc.Spec.Com.StreamingNode.Groups[1].Name = "a"
+	assert.NotNil(t, mc.validateStreamingNodeGroups())
+
+	mc.Spec.Com.StreamingNode.Groups[1].Name = "b"
+	mc.Spec.Com.RollingMode = RollingModeV3
+	assert.NotNil(t, mc.validateStreamingNodeGroups())
+}
+
+func TestMilvus_validateMinioMode(t *testing.T) {
+	mc := Milvus{}
+	assert.Nil(t, mc.validateMinioMode())
+
+	mc.Spec.Dep.Storage.InCluster = &InClusterConfig{Mode: MinioModeDistributed}
+	assert.Nil(t, mc.validateMinioMode())
+
+	replicas := int32(2)
+	mc.Spec.Dep.Storage.InCluster.Replicas = &replicas
+	err := mc.validateMinioMode()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "at least 4 replicas")
+	_, err2 := mc.ValidateCreate()
+	assert.Error(t, err2)
+
+	replicas = 4
+	assert.Nil(t, mc.validateMinioMode())
+
+	mc.Spec.Dep.Storage.InCluster.Mode = MinioModeStandalone
+	replicas = 1
+	assert.Nil(t, mc.validateMinioMode())
+}
+
+func TestMilvus_validateMsgStreamType(t *testing.T) {
+	t.Run("rocksmq in cluster mode rejected", func(t *testing.T) {
+		mc := Milvus{}
+		mc.Spec.Mode = MilvusModeCluster
+		mc.Spec.Dep.MsgStreamType = MsgStreamTypeRocksMQ
+		err := mc.validateMsgStreamType()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "pulsar/kafka/woodpecker")
+
+		_, err2 := mc.ValidateCreate()
+		assert.Error(t, err2)
+	})
+
+	t.Run("rocksmq in standalone mode allowed", func(t *testing.T) {
+		mc := Milvus{}
+		mc.Spec.Mode = MilvusModeStandalone
+		mc.Spec.Dep.MsgStreamType = MsgStreamTypeRocksMQ
+		err := mc.validateMsgStreamType()
+		assert.Nil(t, err)
+	})
+
+	t.Run("pulsar in cluster mode allowed", func(t *testing.T) {
+		mc := Milvus{}
+		mc.Spec.Mode = MilvusModeCluster
+		mc.Spec.Dep.MsgStreamType = MsgStreamTypePulsar
+		err := mc.validateMsgStreamType()
+		assert.Nil(t, err)
+	})
+}
+
+func TestMilvus_validateSchedule(t *testing.T) {
+	mc := Milvus{}
+	assert.Nil(t, mc.validateSchedule())
+
+	mc.Spec.Schedule = &MilvusSchedule{
+		Stop:     "0 22 * * 1-5",
+		Start:    "0 8 * * 1-5",
+		TimeZone: "Asia/Shanghai",
+	}
+	assert.Nil(t, mc.validateSchedule())
+
+	mc.Spec.Schedule.Stop = "bad"
+	assert.NotNil(t, mc.validateSchedule())
+
+	mc.Spec.Schedule.Stop = "0 22 * * 1-5"
+	mc.Spec.Schedule.Start = ""
+	assert.NotNil(t, mc.validateSchedule())
+
+	mc.Spec.Schedule.Start = "0 8 * * 1-5"
+	mc.Spec.Schedule.TimeZone = "Mars/Olympus"
+	assert.NotNil(t, mc.validateSchedule())
+}
+
+func TestMilvus_validateUpdateWindow(t *testing.T) {
+	mc := Milvus{}
+	assert.Nil(t, mc.validateUpdateWindow())
+
+	mc.Spec.Com.UpdateWindow = &UpdateWindow{
+		Start:    "0 2 * * 6",
+		End:      "0 6 * * 6",
+		TimeZone: "Asia/Shanghai",
+	}
+	assert.Nil(t, mc.validateUpdateWindow())
+
+	mc.Spec.Com.UpdateWindow.Start = "bad"
+	assert.NotNil(t, mc.validateUpdateWindow())
+
+	mc.Spec.Com.UpdateWindow.Start = "0 2 * * 6"
+	mc.Spec.Com.UpdateWindow.End = ""
+	assert.NotNil(t, mc.validateUpdateWindow())
+
+	mc.Spec.Com.UpdateWindow.End = "0 6 * * 6"
+	mc.Spec.Com.UpdateWindow.TimeZone = "Mars/Olympus"
+	assert.NotNil(t, mc.validateUpdateWindow())
+}
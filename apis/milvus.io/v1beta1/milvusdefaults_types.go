@@ -0,0 +1,61 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MilvusDefaultsName is the name of the MilvusDefaults taking effect, the others are ignored
+const MilvusDefaultsName = "default"
+
+// MilvusDefaultsSpec defines the cluster-wide defaults of Milvus
+type MilvusDefaultsSpec struct {
+	// Spec is merged into the spec of each Milvus when it's created, the fields set in the Milvus take precedence.
+	// it's same as the spec of Milvus, we use a Values here so that it can be partial & the CRD doesn't become too large
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec Values `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// MilvusDefaults is the Schema for the cluster-wide defaults of Milvus
+type MilvusDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MilvusDefaultsSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MilvusDefaultsList contains a list of MilvusDefaults
+type MilvusDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MilvusDefaults `json:"items"`
+}
+
+var MilvusDefaultsKind = reflect.TypeOf(MilvusDefaults{}).Name()
+
+func init() {
+	SchemeBuilder.Register(&MilvusDefaults{}, &MilvusDefaultsList{})
+}
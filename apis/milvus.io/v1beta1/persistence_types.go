@@ -35,6 +35,11 @@ type PersistentVolumeClaim struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 
+	// StorageClassName of the pvc created by milvus-operator, it overrides the storageClassName in Spec
+	// it's ignored when ExistingClaim is set
+	// +kubebuilder:validation:Optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
 	// Spec defines the desired characteristics of a volume requested by a pod author.
 	// It's same as corev1.PersistentVolumeClaimSpec, we use a Values here to avoid the CRD become too large
 	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#persistentvolumeclaims
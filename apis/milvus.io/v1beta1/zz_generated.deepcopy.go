@@ -35,6 +35,22 @@ func (in *Component) DeepCopyInto(out *Component) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	in.ExtraConfig.DeepCopyInto(&out.ExtraConfig)
+	if in.ScratchSizeLimit != nil {
+		in, out := &in.ScratchSizeLimit, &out.ScratchSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]v1.ContainerPort, len(*in))
+		copy(*out, *in)
+	}
 	if in.SideCars != nil {
 		in, out := &in.SideCars, &out.SideCars
 		*out = make([]Values, len(*in))
@@ -65,6 +81,10 @@ func (in *Component) DeepCopy() *Component {
 func (in *ComponentDeployStatus) DeepCopyInto(out *ComponentDeployStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.CreationTimestamp != nil {
+		in, out := &in.CreationTimestamp, &out.CreationTimestamp
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentDeployStatus.
@@ -99,6 +119,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.RunWithSubProcess != nil {
 		in, out := &in.RunWithSubProcess, &out.RunWithSubProcess
 		*out = new(bool)
@@ -145,6 +170,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]v1.VolumeMount, len(*in))
@@ -159,6 +189,23 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecretMounts != nil {
+		in, out := &in.SecretMounts, &out.SecretMounts
+		*out = make([]SecretMount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(v1.Lifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Probes.DeepCopyInto(&out.Probes)
 }
 
@@ -172,10 +219,111 @@ func (in *ComponentSpec) DeepCopy() *ComponentSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyEndpoints) DeepCopyInto(out *DependencyEndpoints) {
+	*out = *in
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MsgStream != nil {
+		in, out := &in.MsgStream, &out.MsgStream
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyEndpoints.
+func (in *DependencyEndpoints) DeepCopy() *DependencyEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyReleaseStatus) DeepCopyInto(out *DependencyReleaseStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyReleaseStatus.
+func (in *DependencyReleaseStatus) DeepCopy() *DependencyReleaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyReleaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackup) DeepCopyInto(out *EtcdBackup) {
+	*out = *in
+	out.Destination = in.Destination
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackup.
+func (in *EtcdBackup) DeepCopy() *EtcdBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupDestination) DeepCopyInto(out *EtcdBackupDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupDestination.
+func (in *EtcdBackupDestination) DeepCopy() *EtcdBackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmValuesFrom) DeepCopyInto(out *HelmValuesFrom) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmValuesFrom.
+func (in *HelmValuesFrom) DeepCopy() *HelmValuesFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmValuesFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InClusterConfig) DeepCopyInto(out *InClusterConfig) {
 	*out = *in
 	in.Values.DeepCopyInto(&out.Values)
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = new(HelmValuesFrom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InClusterConfig.
@@ -215,6 +363,21 @@ func (in *Milvus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusBackupBeforeDelete) DeepCopyInto(out *MilvusBackupBeforeDelete) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusBackupBeforeDelete.
+func (in *MilvusBackupBeforeDelete) DeepCopy() *MilvusBackupBeforeDelete {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusBackupBeforeDelete)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusBuiltInMQ) DeepCopyInto(out *MilvusBuiltInMQ) {
 	*out = *in
@@ -235,6 +398,11 @@ func (in *MilvusBuiltInMQ) DeepCopy() *MilvusBuiltInMQ {
 func (in *MilvusComponents) DeepCopyInto(out *MilvusComponents) {
 	*out = *in
 	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindow)
+		**out = **in
+	}
 	if in.EnableRollingUpdate != nil {
 		in, out := &in.EnableRollingUpdate, &out.EnableRollingUpdate
 		*out = new(bool)
@@ -247,6 +415,56 @@ func (in *MilvusComponents) DeepCopyInto(out *MilvusComponents) {
 			(*out)[key] = val
 		}
 	}
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(MilvusServiceMonitor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(MilvusNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigContainerArgs != nil {
+		in, out := &in.ConfigContainerArgs, &out.ConfigContainerArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceBudget != nil {
+		in, out := &in.ResourceBudget, &out.ResourceBudget
+		*out = new(MilvusResourceBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DegradedMinReadyPercent != nil {
+		in, out := &in.DegradedMinReadyPercent, &out.DegradedMinReadyPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PostUpgradeActions != nil {
+		in, out := &in.PostUpgradeActions, &out.PostUpgradeActions
+		*out = make([]PostUpgradeAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeDeadlineSeconds != nil {
+		in, out := &in.UpgradeDeadlineSeconds, &out.UpgradeDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitContainerFailureThreshold != nil {
+		in, out := &in.InitContainerFailureThreshold, &out.InitContainerFailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartupTimeoutSeconds != nil {
+		in, out := &in.StartupTimeoutSeconds, &out.StartupTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	if in.StreamingMode != nil {
 		in, out := &in.StreamingMode, &out.StreamingMode
 		*out = new(bool)
@@ -370,9 +588,88 @@ func (in *MilvusDataNode) DeepCopy() *MilvusDataNode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusDefaults) DeepCopyInto(out *MilvusDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusDefaults.
+func (in *MilvusDefaults) DeepCopy() *MilvusDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MilvusDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusDefaultsList) DeepCopyInto(out *MilvusDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MilvusDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusDefaultsList.
+func (in *MilvusDefaultsList) DeepCopy() *MilvusDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MilvusDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusDefaultsSpec) DeepCopyInto(out *MilvusDefaultsSpec) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusDefaultsSpec.
+func (in *MilvusDefaultsSpec) DeepCopy() *MilvusDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusDependencies) DeepCopyInto(out *MilvusDependencies) {
 	*out = *in
+	if in.ManageDependencies != nil {
+		in, out := &in.ManageDependencies, &out.ManageDependencies
+		*out = new(bool)
+		**out = **in
+	}
 	in.Etcd.DeepCopyInto(&out.Etcd)
 	in.Pulsar.DeepCopyInto(&out.Pulsar)
 	in.Kafka.DeepCopyInto(&out.Kafka)
@@ -407,6 +704,11 @@ func (in *MilvusEtcd) DeepCopyInto(out *MilvusEtcd) {
 		*out = new(InClusterConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(EtcdBackup)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusEtcd.
@@ -419,6 +721,36 @@ func (in *MilvusEtcd) DeepCopy() *MilvusEtcd {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusGracefulTermination) DeepCopyInto(out *MilvusGracefulTermination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusGracefulTermination.
+func (in *MilvusGracefulTermination) DeepCopy() *MilvusGracefulTermination {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusGracefulTermination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusHealthCheck) DeepCopyInto(out *MilvusHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusHealthCheck.
+func (in *MilvusHealthCheck) DeepCopy() *MilvusHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusIndexCoord) DeepCopyInto(out *MilvusIndexCoord) {
 	*out = *in
@@ -494,6 +826,16 @@ func (in *MilvusIngress) DeepCopyInto(out *MilvusIngress) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.TLSCertExpiryWarningWindow != nil {
+		in, out := &in.TLSCertExpiryWarningWindow, &out.TLSCertExpiryWarningWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RESTful != nil {
+		in, out := &in.RESTful, &out.RESTful
+		*out = new(MilvusIngressBackend)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusIngress.
@@ -506,6 +848,48 @@ func (in *MilvusIngress) DeepCopy() *MilvusIngress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusIngressBackend) DeepCopyInto(out *MilvusIngressBackend) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusIngressBackend.
+func (in *MilvusIngressBackend) DeepCopy() *MilvusIngressBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusIngressBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusInternalService) DeepCopyInto(out *MilvusInternalService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusInternalService.
+func (in *MilvusInternalService) DeepCopy() *MilvusInternalService {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusInternalService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusKafka) DeepCopyInto(out *MilvusKafka) {
 	*out = *in
@@ -519,6 +903,11 @@ func (in *MilvusKafka) DeepCopyInto(out *MilvusKafka) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BrokerListFromSecret != nil {
+		in, out := &in.BrokerListFromSecret, &out.BrokerListFromSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusKafka.
@@ -567,6 +956,11 @@ func (in *MilvusList) DeepCopyObject() runtime.Object {
 func (in *MilvusMixCoord) DeepCopyInto(out *MilvusMixCoord) {
 	*out = *in
 	in.Component.DeepCopyInto(&out.Component)
+	if in.StandbyReplicas != nil {
+		in, out := &in.StandbyReplicas, &out.StandbyReplicas
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusMixCoord.
@@ -579,6 +973,31 @@ func (in *MilvusMixCoord) DeepCopy() *MilvusMixCoord {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusNetworkPolicy) DeepCopyInto(out *MilvusNetworkPolicy) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusNetworkPolicy.
+func (in *MilvusNetworkPolicy) DeepCopy() *MilvusNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusProxy) DeepCopyInto(out *MilvusProxy) {
 	*out = *in
@@ -662,6 +1081,46 @@ func (in *MilvusReplicas) DeepCopy() *MilvusReplicas {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusResourceBudget) DeepCopyInto(out *MilvusResourceBudget) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusResourceBudget.
+func (in *MilvusResourceBudget) DeepCopy() *MilvusResourceBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusResourceBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusRestoreSource) DeepCopyInto(out *MilvusRestoreSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusRestoreSource.
+func (in *MilvusRestoreSource) DeepCopy() *MilvusRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusRootCoord) DeepCopyInto(out *MilvusRootCoord) {
 	*out = *in
@@ -678,13 +1137,99 @@ func (in *MilvusRootCoord) DeepCopy() *MilvusRootCoord {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusSchedule) DeepCopyInto(out *MilvusSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusSchedule.
+func (in *MilvusSchedule) DeepCopy() *MilvusSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MilvusServiceMonitor) DeepCopyInto(out *MilvusServiceMonitor) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusServiceMonitor.
+func (in *MilvusServiceMonitor) DeepCopy() *MilvusServiceMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(MilvusServiceMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusSpec) DeepCopyInto(out *MilvusSpec) {
 	*out = *in
 	in.Com.DeepCopyInto(&out.Com)
 	in.Dep.DeepCopyInto(&out.Dep)
 	in.Conf.DeepCopyInto(&out.Conf)
+	if in.ConfReplacePaths != nil {
+		in, out := &in.ConfReplacePaths, &out.ConfReplacePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfProjectedSources != nil {
+		in, out := &in.ConfProjectedSources, &out.ConfProjectedSources
+		*out = make([]v1.VolumeProjection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.HookConf.DeepCopyInto(&out.HookConf)
+	if in.RestoreFrom != nil {
+		in, out := &in.RestoreFrom, &out.RestoreFrom
+		*out = new(MilvusRestoreSource)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(MilvusSchedule)
+		**out = **in
+	}
+	if in.GracefulTermination != nil {
+		in, out := &in.GracefulTermination, &out.GracefulTermination
+		*out = new(MilvusGracefulTermination)
+		**out = **in
+	}
+	if in.BackupBeforeDelete != nil {
+		in, out := &in.BackupBeforeDelete, &out.BackupBeforeDelete
+		*out = new(MilvusBackupBeforeDelete)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(MilvusHealthCheck)
+		**out = **in
+	}
+	if in.ClientCredentialsSecretRef != nil {
+		in, out := &in.ClientCredentialsSecretRef, &out.ClientCredentialsSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusSpec.
@@ -724,6 +1269,11 @@ func (in *MilvusStatus) DeepCopyInto(out *MilvusStatus) {
 		}
 	}
 	in.IngressStatus.DeepCopyInto(&out.IngressStatus)
+	if in.DependencyEndpoints != nil {
+		in, out := &in.DependencyEndpoints, &out.DependencyEndpoints
+		*out = new(DependencyEndpoints)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ComponentsDeployStatus != nil {
 		in, out := &in.ComponentsDeployStatus, &out.ComponentsDeployStatus
 		*out = make(map[string]ComponentDeployStatus, len(*in))
@@ -731,6 +1281,25 @@ func (in *MilvusStatus) DeepCopyInto(out *MilvusStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.PendingPostUpgradeActions != nil {
+		in, out := &in.PendingPostUpgradeActions, &out.PendingPostUpgradeActions
+		*out = make([]PostUpgradeAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ManagedReleases != nil {
+		in, out := &in.ManagedReleases, &out.ManagedReleases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependencyReleases != nil {
+		in, out := &in.DependencyReleases, &out.DependencyReleases
+		*out = make([]DependencyReleaseStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusStatus.
@@ -746,6 +1315,16 @@ func (in *MilvusStatus) DeepCopy() *MilvusStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MilvusStorage) DeepCopyInto(out *MilvusStorage) {
 	*out = *in
+	if in.UseSSL != nil {
+		in, out := &in.UseSSL, &out.UseSSL
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseVirtualHost != nil {
+		in, out := &in.UseVirtualHost, &out.UseVirtualHost
+		*out = new(bool)
+		**out = **in
+	}
 	if in.InCluster != nil {
 		in, out := &in.InCluster, &out.InCluster
 		*out = new(InClusterConfig)
@@ -756,6 +1335,11 @@ func (in *MilvusStorage) DeepCopyInto(out *MilvusStorage) {
 		*out = new(MilvusStorageSSLConfig)
 		**out = **in
 	}
+	if in.CapacityWarningThreshold != nil {
+		in, out := &in.CapacityWarningThreshold, &out.CapacityWarningThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusStorage.
@@ -787,6 +1371,13 @@ func (in *MilvusStorageSSLConfig) DeepCopy() *MilvusStorageSSLConfig {
 func (in *MilvusStreamingNode) DeepCopyInto(out *MilvusStreamingNode) {
 	*out = *in
 	in.Component.DeepCopyInto(&out.Component)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]StreamingNodeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MilvusStreamingNode.
@@ -969,6 +1560,11 @@ func (in *PersistentVolumeClaim) DeepCopyInto(out *PersistentVolumeClaim) {
 			(*out)[key] = val
 		}
 	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
@@ -1012,6 +1608,21 @@ func (in *Probes) DeepCopy() *Probes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretMount) DeepCopyInto(out *SecretMount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretMount.
+func (in *SecretMount) DeepCopy() *SecretMount {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceComponent) DeepCopyInto(out *ServiceComponent) {
 	*out = *in
@@ -1030,11 +1641,21 @@ func (in *ServiceComponent) DeepCopyInto(out *ServiceComponent) {
 			(*out)[key] = val
 		}
 	}
+	if in.ServiceSessionAffinityConfig != nil {
+		in, out := &in.ServiceSessionAffinityConfig, &out.ServiceSessionAffinityConfig
+		*out = new(v1.SessionAffinityConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Ingress != nil {
 		in, out := &in.Ingress, &out.Ingress
 		*out = new(MilvusIngress)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InternalService != nil {
+		in, out := &in.InternalService, &out.InternalService
+		*out = new(MilvusInternalService)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceComponent.
@@ -1047,6 +1668,42 @@ func (in *ServiceComponent) DeepCopy() *ServiceComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamingNodeGroup) DeepCopyInto(out *StreamingNodeGroup) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamingNodeGroup.
+func (in *StreamingNodeGroup) DeepCopy() *StreamingNodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamingNodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateWindow) DeepCopyInto(out *UpdateWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateWindow.
+func (in *UpdateWindow) DeepCopy() *UpdateWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Values.
 func (in *Values) DeepCopy() *Values {
 	if in == nil {
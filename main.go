@@ -20,7 +20,6 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
-	_ "net/http/pprof"
 	"os"
 	"strings"
 
@@ -46,11 +45,13 @@ func main() {
 	var enableLeaderElection bool
 	var stopReconcilers string
 	var enablePprof bool
+	var pprofAddr string
 	var probeAddr string
 	var workDir string
 	var k8sQps = 100
 	var k8sBurst = 100
 	var enableWebhook bool
+	var reconcileEventLog bool
 	showVersion := flag.Bool("version", false, "Show version")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -65,10 +66,15 @@ func main() {
 	flag.IntVar(&config.MaxConcurrentReconcile, "concurrent-reconcile", config.MaxConcurrentReconcile, "The max concurrent reconcile")
 	flag.IntVar(&config.MaxConcurrentHealthCheck, "concurrent-healthcheck", config.MaxConcurrentHealthCheck, "The max concurrent healthcheck")
 	flag.IntVar(&config.SyncIntervalSec, "sync-interval", config.SyncIntervalSec, "The interval of sync milvus")
-	flag.BoolVar(&enablePprof, "pprof", enablePprof, "Enable pprof")
+	flag.BoolVar(&enablePprof, "pprof", enablePprof, "Enable pprof and the /debug/reconcile-stats endpoint")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", controllers.DefaultDebugBindAddress, "The address the pprof & debug endpoints bind to.")
 	flag.IntVar(&k8sQps, "k8s-qps", k8sQps, "The qps of k8s client")
 	flag.IntVar(&k8sBurst, "k8s-burst", k8sQps, "The burst of k8s client")
 	flag.BoolVar(&controllers.Debug, "debug", controllers.Debug, "Enable debug")
+	flag.DurationVar(&controllers.RequeueBaseInterval, "requeue-interval", controllers.RequeueBaseInterval, "The base interval to requeue a reconciled milvus, backed off for healthy & updated ones, 0 disables it")
+	flag.DurationVar(&controllers.SlowReconcileThreshold, "slow-reconcile-threshold", controllers.SlowReconcileThreshold, "The duration beyond which a reconcile of milvus sets the SlowReconcile condition, 0 disables it")
+	flag.DurationVar(&controllers.DiscoveryCacheTTL, "discovery-cache-ttl", controllers.DiscoveryCacheTTL, "The TTL of the cached discovery client used for dependency helm releases")
+	flag.BoolVar(&reconcileEventLog, "reconcile-event-log", reconcileEventLog, "Write the outcome of each reconcile of milvus to stdout as JSON lines, besides the logs")
 	flag.BoolVar(&enableWebhook, "webhook", false, "Enable webhook for support of v1alpha1 crd & validation")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
@@ -80,10 +86,14 @@ func main() {
 	}
 
 	if enablePprof {
+		controllers.EnableReconcileStats = true
 		go func() {
-			setupLog.Error(http.ListenAndServe(":6060", nil), "serve pprof")
+			setupLog.Error(http.ListenAndServe(pprofAddr, controllers.NewDebugHandler()), "serve pprof")
 		}()
 	}
+	if reconcileEventLog {
+		controllers.ReconcileEventWriter = os.Stdout
+	}
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
 	util.SetLogger(logger)
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// BackupBeforeDeleteFinalizer holds the milvus on deletion until the backup job succeeds
+const BackupBeforeDeleteFinalizer = "milvus.milvus.io/backup-before-delete"
+
+const (
+	backupContainerName     = "backup"
+	defaultBackupImage      = "milvusdb/milvus-backup:v0.5.3"
+	defaultBackupPath       = "backup"
+	defaultMinioPort        = "9000"
+	backupJobBackoffLimit   = 3
+	backupJobTTLAfterFinish = 24 * 3600
+)
+
+// updateBackupBeforeDeleteFinalizer adds or removes the finalizer by spec, returns true if it's changed
+func updateBackupBeforeDeleteFinalizer(mc *v1beta1.Milvus) bool {
+	if mc.Spec.IsBackupBeforeDeleteEnabled() {
+		return controllerutil.AddFinalizer(mc, BackupBeforeDeleteFinalizer)
+	}
+	return controllerutil.RemoveFinalizer(mc, BackupBeforeDeleteFinalizer)
+}
+
+// newBackupJobLabels returns the labels of the backup job & its pods,
+// they're not selected as the pods of milvus
+func newBackupJobLabels(instance string) map[string]string {
+	return map[string]string{
+		AppLabelInstance:  instance,
+		AppLabelName:      "milvus-backup",
+		AppLabelManagedBy: ManagerName,
+	}
+}
+
+func getBackupBeforeDeleteJobName(instance string) string {
+	return instance + "-backup-before-delete"
+}
+
+// getBackupBeforeDeleteName returns the name of the backup, which only contains letters, numbers & underscores
+func getBackupBeforeDeleteName(mc v1beta1.Milvus) string {
+	return fmt.Sprintf("%s_%s", strings.ReplaceAll(mc.Name, "-", "_"),
+		mc.DeletionTimestamp.UTC().Format("20060102150405"))
+}
+
+// finalizeBackupBeforeDelete holds the deleting milvus until it's backed up, then removes the finalizer.
+// it returns true if the finalizer is removed
+func (r *MilvusReconciler) finalizeBackupBeforeDelete(ctx context.Context, mc *v1beta1.Milvus) (bool, error) {
+	if mc.Spec.IsBackupBeforeDeleteEnabled() {
+		backedUp, err := r.backupBeforeDelete(ctx, mc)
+		if err != nil || !backedUp {
+			return false, err
+		}
+	}
+	controllerutil.RemoveFinalizer(mc, BackupBeforeDeleteFinalizer)
+	return true, errors.Wrap(r.Update(ctx, mc), "remove backup finalizer")
+}
+
+// backupBeforeDelete runs the backup job of the milvus,
+// it returns true when the job succeeds
+func (r *MilvusReconciler) backupBeforeDelete(ctx context.Context, mc *v1beta1.Milvus) (bool, error) {
+	logger := ctrl.LoggerFrom(ctx)
+	job := &batchv1.Job{}
+	key := NamespacedName(mc.Namespace, getBackupBeforeDeleteJobName(mc.Name))
+	err := r.Get(ctx, key, job)
+	if k8sErrors.IsNotFound(err) {
+		job = renderBackupBeforeDeleteJob(*mc)
+		logger.Info("backup before delete: create backup job", "job", job.Name)
+		return false, errors.Wrap(r.Create(ctx, job), "create backup job")
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "get backup job")
+	}
+	if job.Status.Succeeded > 0 {
+		logger.Info("backup before delete: backup job succeeded", "job", job.Name)
+		return true, nil
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			// keep holding the milvus, users can check the job & set deletePolicy to Delete to give up the backup
+			logger.Info("backup before delete: backup job failed, deletion blocked", "job", job.Name, "message", cond.Message)
+			return false, r.setBackupBeforeDeleteFailed(ctx, mc, job.Name, cond.Message)
+		}
+	}
+	return false, nil
+}
+
+// setBackupBeforeDeleteFailed sets the BackupBeforeDeleteFailed condition, so that the blocked deletion is visible in status
+func (r *MilvusReconciler) setBackupBeforeDeleteFailed(ctx context.Context, mc *v1beta1.Milvus, jobName, message string) error {
+	condition := v1beta1.MilvusCondition{
+		Type:    v1beta1.BackupBeforeDeleteFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonBackupJobFailed,
+		Message: fmt.Sprintf("backup job[%s] failed: %s; delete the job to retry, or set deletePolicy to Delete to skip the backup", jobName, message),
+	}
+	if existing := GetMilvusConditionByType(mc.Status.Conditions, condition.Type); existing != nil &&
+		existing.Status == condition.Status && existing.Message == condition.Message {
+		return nil
+	}
+	UpdateCondition(&mc.Status, condition)
+	return errors.Wrap(r.Status().Update(ctx, mc), "update backup failed condition")
+}
+
+// renderBackupBeforeDeleteJob renders the job to back up the milvus by the milvus-backup tool.
+// The job isn't owned by the milvus, otherwise it will be collected on foreground deletion
+func renderBackupBeforeDeleteJob(mc v1beta1.Milvus) *batchv1.Job {
+	backup := mc.Spec.BackupBeforeDelete
+	if backup == nil {
+		backup = &v1beta1.MilvusBackupBeforeDelete{}
+	}
+	return renderMilvusBackupJob(mc, milvusBackupJobConfig{
+		name:   getBackupBeforeDeleteJobName(mc.Name),
+		image:  backup.Image,
+		bucket: backup.Bucket,
+		path:   backup.Path,
+		args:   []string{"create", "-n", getBackupBeforeDeleteName(mc)},
+	})
+}
+
+// milvusBackupJobConfig is the config of a job running the milvus-backup tool
+type milvusBackupJobConfig struct {
+	name string
+	// image of the tool, default to defaultBackupImage
+	image string
+	// bucket & path where the backups are stored, default to the bucket of milvus & defaultBackupPath
+	bucket string
+	path   string
+	args   []string
+}
+
+// renderMilvusBackupJob renders the job running the milvus-backup tool against the milvus
+func renderMilvusBackupJob(mc v1beta1.Milvus, cfg milvusBackupJobConfig) *batchv1.Job {
+	image := cfg.image
+	if image == "" {
+		image = defaultBackupImage
+	}
+	bucket := GetMinioBucket(mc.Spec.Conf.Data)
+	backupBucket := cfg.bucket
+	if backupBucket == "" {
+		backupBucket = bucket
+	}
+	backupPath := cfg.path
+	if backupPath == "" {
+		backupPath = defaultBackupPath
+	}
+	minioHost, minioPort, err := net.SplitHostPort(mc.Spec.Dep.Storage.Endpoint)
+	if err != nil {
+		minioHost, minioPort = mc.Spec.Dep.Storage.Endpoint, defaultMinioPort
+	}
+	serviceComponent := MilvusStandalone
+	if mc.Spec.Mode == v1beta1.MilvusModeCluster {
+		serviceComponent = Proxy
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "MILVUS_ADDRESS", Value: GetServiceInstanceName(mc.Name)},
+		{Name: "MILVUS_PORT", Value: strconv.Itoa(int(serviceComponent.GetComponentPort(mc.Spec)))},
+		{Name: "MINIO_ADDRESS", Value: minioHost},
+		{Name: "MINIO_PORT", Value: minioPort},
+		{Name: "MINIO_USE_SSL", Value: strconv.FormatBool(GetMinioSecure(mc.Spec.Conf.Data))},
+		{Name: "MINIO_BUCKET_NAME", Value: bucket},
+		{Name: "MINIO_ROOT_PATH", Value: GetStringValueWithDefault(mc.Spec.Conf.Data, defaultMinioRootPath, "minio", "rootPath")},
+		{Name: "MINIO_BACKUP_BUCKET_NAME", Value: backupBucket},
+		{Name: "MINIO_BACKUP_ROOT_PATH", Value: path.Clean(backupPath)},
+	}
+	env = append(env, GetStorageSecretRefEnv(mc.Spec.Dep.Storage.SecretRef)...)
+	env = append(env, getMilvusClientEnv(mc)...)
+	container := corev1.Container{
+		Name:  backupContainerName,
+		Image: image,
+		Args:  cfg.args,
+		Env:   env,
+	}
+	fillContainerDefaultValues(&container)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfg.name,
+			Namespace: mc.Namespace,
+			Labels:    newBackupJobLabels(mc.Name),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            int32Ptr(backupJobBackoffLimit),
+			TTLSecondsAfterFinished: int32Ptr(backupJobTTLAfterFinish),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: newBackupJobLabels(mc.Name),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// getMilvusClientEnv returns the env of the milvus-backup tool to connect to the milvus,
+// the user & password are referred from spec.clientCredentialsSecretRef if it's set
+func getMilvusClientEnv(mc v1beta1.Milvus) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "MILVUS_TLS_MODE", Value: strconv.Itoa(getMilvusTLSMode(mc.Spec))},
+	}
+	if !isMilvusAuthorizationEnabled(mc.Spec) {
+		return env
+	}
+	env = append(env, corev1.EnvVar{Name: "MILVUS_AUTHORIZATION_ENABLED", Value: "true"})
+	secretRef := mc.Spec.ClientCredentialsSecretRef
+	if secretRef == nil {
+		return append(env,
+			corev1.EnvVar{Name: "MILVUS_USER", Value: defaultMilvusRootUser},
+			corev1.EnvVar{Name: "MILVUS_PASSWORD", Value: getDefaultRootPassword(mc.Spec)},
+		)
+	}
+	secretKeyEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: *secretRef,
+					Key:                  key,
+				},
+			},
+		}
+	}
+	return append(env,
+		secretKeyEnv("MILVUS_USER", MilvusClientUsernameKey),
+		secretKeyEnv("MILVUS_PASSWORD", MilvusClientPasswordKey),
+	)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestUpdateBackupBeforeDeleteFinalizer(t *testing.T) {
+	mc := &v1beta1.Milvus{}
+	assert.False(t, updateBackupBeforeDeleteFinalizer(mc))
+
+	mc.Spec.DeletePolicy = v1beta1.DeletePolicyBackupThenDelete
+	assert.True(t, updateBackupBeforeDeleteFinalizer(mc))
+	assert.Equal(t, []string{BackupBeforeDeleteFinalizer}, mc.Finalizers)
+	assert.False(t, updateBackupBeforeDeleteFinalizer(mc))
+
+	mc.Spec.DeletePolicy = v1beta1.DeletePolicyDelete
+	assert.True(t, updateBackupBeforeDeleteFinalizer(mc))
+	assert.Empty(t, mc.Finalizers)
+}
+
+func TestMilvusReconciler_finalizeBackupBeforeDelete(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Dep.Storage.Endpoint = "minio:9000"
+	mc.Spec.DeletePolicy = v1beta1.DeletePolicyBackupThenDelete
+	mc.Spec.BackupBeforeDelete = &v1beta1.MilvusBackupBeforeDelete{Bucket: "backup-bucket"}
+	mc.Finalizers = []string{BackupBeforeDeleteFinalizer}
+	mc.DeletionTimestamp = &metav1.Time{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mc).
+		WithStatusSubresource(&batchv1.Job{}, &v1beta1.Milvus{}).Build()
+	r.Client = cli
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(mc), mc))
+
+	// backup job created, deletion blocked
+	finalized, err := r.finalizeBackupBeforeDelete(ctx, mc)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+	assert.True(t, controllerutil.ContainsFinalizer(mc, BackupBeforeDeleteFinalizer))
+	job := &batchv1.Job{}
+	jobKey := client.ObjectKey{Namespace: mc.Namespace, Name: getBackupBeforeDeleteJobName(mc.Name)}
+	assert.NoError(t, cli.Get(ctx, jobKey, job))
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, defaultBackupImage, container.Image)
+	assert.Equal(t, []string{"create", "-n", "mc_20260102030405"}, container.Args)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "MILVUS_ADDRESS", Value: "mc-milvus"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "MINIO_ADDRESS", Value: "minio"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "MINIO_BACKUP_BUCKET_NAME", Value: "backup-bucket"})
+	assert.Empty(t, job.OwnerReferences)
+	assert.NotEqual(t, NewAppLabels(mc.Name), job.Spec.Template.Labels)
+
+	// job running, deletion blocked
+	finalized, err = r.finalizeBackupBeforeDelete(ctx, mc)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+
+	// job failed, deletion blocked
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.NoError(t, cli.Status().Update(ctx, job))
+	finalized, err = r.finalizeBackupBeforeDelete(ctx, mc)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+	assert.True(t, controllerutil.ContainsFinalizer(mc, BackupBeforeDeleteFinalizer))
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(mc), mc))
+	failedCond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.BackupBeforeDeleteFailed)
+	assert.NotNil(t, failedCond)
+	assert.Equal(t, corev1.ConditionTrue, failedCond.Status)
+	assert.Equal(t, v1beta1.ReasonBackupJobFailed, failedCond.Reason)
+
+	// job succeeded, finalizer removed
+	job.Status.Conditions = nil
+	job.Status.Succeeded = 1
+	assert.NoError(t, cli.Status().Update(ctx, job))
+	finalized, err = r.finalizeBackupBeforeDelete(ctx, mc)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+	assert.False(t, controllerutil.ContainsFinalizer(mc, BackupBeforeDeleteFinalizer))
+
+	t.Run("delete policy changed to delete", func(t *testing.T) {
+		mc := env.Inst.DeepCopy()
+		mc.Finalizers = []string{BackupBeforeDeleteFinalizer, MilvusFinalizerName}
+		cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mc).Build()
+		r.Client = cli
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(mc), mc))
+		finalized, err := r.finalizeBackupBeforeDelete(ctx, mc)
+		assert.NoError(t, err)
+		assert.True(t, finalized)
+		assert.Equal(t, []string{MilvusFinalizerName}, mc.Finalizers)
+	})
+}
+
+func TestGetMilvusClientEnv(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "MILVUS_TLS_MODE", Value: "0"},
+	}, getMilvusClientEnv(mc))
+
+	mc.Spec.Conf.Data = map[string]interface{}{
+		"common": map[string]interface{}{
+			"security": map[string]interface{}{
+				"authorizationEnabled": true,
+				"defaultRootPassword":  "pwd",
+				"tlsMode":              int64(1),
+			},
+		},
+	}
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "MILVUS_TLS_MODE", Value: "1"},
+		{Name: "MILVUS_AUTHORIZATION_ENABLED", Value: "true"},
+		{Name: "MILVUS_USER", Value: "root"},
+		{Name: "MILVUS_PASSWORD", Value: "pwd"},
+	}, getMilvusClientEnv(mc))
+
+	mc.Spec.ClientCredentialsSecretRef = &corev1.LocalObjectReference{Name: "creds"}
+	env := getMilvusClientEnv(mc)
+	assert.Len(t, env, 4)
+	assert.Equal(t, "MILVUS_PASSWORD", env[3].Name)
+	assert.Equal(t, "creds", env[3].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, MilvusClientPasswordKey, env[3].ValueFrom.SecretKeyRef.Key)
+}
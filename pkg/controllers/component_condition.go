@@ -24,7 +24,7 @@ type ComponentConditionGetter interface {
 type ComponentConditionGetterImpl struct{}
 
 func (c ComponentConditionGetterImpl) GetMilvusInstanceCondition(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (v1beta1.MilvusCondition, error) {
-	if mc.Spec.IsStopping() {
+	if mc.IsStopping() {
 		return c.getStoppingCondition(ctx, cli, mc)
 	}
 
@@ -42,21 +42,29 @@ func (c ComponentConditionGetterImpl) GetMilvusInstanceCondition(ctx context.Con
 		return v1beta1.MilvusCondition{}, err
 	}
 
-	allComponents := GetComponentsBySpec(mc.Spec)
+	allComponents := GetDeploymentComponentsBySpec(mc.Spec)
 	var notReadyComponents []string
+	var degradedComponents []string
 	var errDetail *ComponentErrorDetail
 	var err error
 	componentDeploy := makeComponentDeploymentMap(mc, deployList.Items)
 	hasEntryReplicas := false
 	for _, component := range allComponents {
-		deployment := componentDeploy[component.Name]
+		deployment := componentDeploy[component.GetName()]
 		if deployment != nil && DeploymentReady(deployment.Status) {
 			if component.IsService() && deployment.Status.ReadyReplicas > 0 {
 				hasEntryReplicas = true
 			}
 			continue
 		}
-		notReadyComponents = append(notReadyComponents, component.Name)
+		if isDeploymentDegradedServing(deployment, mc.Spec.Com.DegradedMinReadyPercent) {
+			if component.IsService() {
+				hasEntryReplicas = true
+			}
+			degradedComponents = append(degradedComponents, component.GetName())
+			continue
+		}
+		notReadyComponents = append(notReadyComponents, component.GetName())
 		if errDetail == nil {
 			errDetail, err = getComponentErrorDetail(ctx, cli, component.Name, deployment)
 			if err != nil {
@@ -76,15 +84,56 @@ func (c ComponentConditionGetterImpl) GetMilvusInstanceCondition(ctx context.Con
 		cond.Status = corev1.ConditionTrue
 		cond.Reason = v1beta1.ReasonMilvusHealthy
 		cond.Message = MessageMilvusHealthy
+		if len(degradedComponents) > 0 {
+			cond.Reason = v1beta1.ReasonMilvusDegraded
+			cond.Message = fmt.Sprintf("%s not fully ready, but serving", degradedComponents)
+		}
+		if mc.Spec.IsDeepHealthCheckEnabled() {
+			if err := deepHealthCheck(ctx, cli, mc); err != nil {
+				cond.Status = corev1.ConditionFalse
+				cond.Reason = v1beta1.ReasonMilvusQueryFailed
+				cond.Message = fmt.Sprintf("components ready, but deep health check failed: %s", err.Error())
+				ctrl.LoggerFrom(ctx).Info("milvus unhealthy", "reason", cond.Reason, "msg", cond.Message)
+			}
+		}
 	} else {
 		cond.Status = corev1.ConditionFalse
 		cond.Reason = v1beta1.ReasonMilvusComponentNotHealthy
 		cond.Message = fmt.Sprintf("%s not ready, detail: %s", notReadyComponents, errDetail)
+		if errDetail != nil {
+			if image, failed := errDetail.GetImagePullFailedImage(); failed {
+				cond.Reason = v1beta1.ReasonImagePullFailed
+				cond.Message = fmt.Sprintf("failed to pull image[%s], %s", image, cond.Message)
+			} else if schedulerMsg, unschedulable := errDetail.GetUnschedulableMessage(); unschedulable {
+				cond.Reason = v1beta1.ReasonInsufficientCapacity
+				cond.Message = fmt.Sprintf("insufficient cluster capacity: %s, %s", schedulerMsg, cond.Message)
+			} else if terminated, crashLooping := errDetail.GetCrashLoopTermination(); crashLooping {
+				cond.Reason = v1beta1.ReasonContainerCrashLooping
+				if isOOMKilled(terminated) {
+					cond.Reason = v1beta1.ReasonContainerOOMKilled
+				}
+				cond.Message = fmt.Sprintf("%s, %s", GetCrashLoopMessage(errDetail.Container.Name, terminated), cond.Message)
+			}
+		}
 		ctrl.LoggerFrom(ctx).Info("milvus unhealthy", "reason", cond.Reason, "msg", cond.Message)
 	}
 
 	return cond, nil
 }
+
+// isDeploymentDegradedServing returns true if the ready replicas of the deployment reach the minReadyPercent of its desired replicas
+func isDeploymentDegradedServing(deployment *appsv1.Deployment, minReadyPercent *int32) bool {
+	if deployment == nil || minReadyPercent == nil {
+		return false
+	}
+	desired := getDeployReplicas(deployment)
+	ready := int(deployment.Status.ReadyReplicas)
+	if desired < 1 || ready < 1 {
+		return false
+	}
+	return ready*100 >= desired*int(*minReadyPercent)
+}
+
 func (c ComponentConditionGetterImpl) logReasonMsgIfDependencyNotReady(ctx context.Context, mc v1beta1.Milvus) {
 	if !IsDependencyReady(mc.Status.Conditions) {
 		notReadyConditions := GetNotReadyDependencyConditions(mc.Status.Conditions)
@@ -155,7 +204,10 @@ var getComponentErrorDetail = func(ctx context.Context, cli client.Client, compo
 			}
 			ret.PodName = pod.Name
 			ret.Pod = podCondition
-			ret.Container = getFirstNotReadyContainerStatus(pod.Status.ContainerStatuses)
+			ret.Container = getImagePullFailedContainerStatus(pod)
+			if ret.Container == nil {
+				ret.Container = getFirstNotReadyContainerStatus(pod.Status.ContainerStatuses)
+			}
 			return ret, nil
 		}
 	}
@@ -177,8 +177,129 @@ func TestComponentConditionGetter_GetMilvusInstanceCondition(t *testing.T) {
 		ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
 		assert.NoError(t, err)
 		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonMilvusComponentNotHealthy, ret.Reason)
 	})
 
+	t.Run(("cluster degraded"), func(t *testing.T) {
+		mockListWithQueryNodeReady := func(readyReplicas int32) {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).
+				Do(func(ctx interface{}, list *appsv1.DeploymentList, opts interface{}) {
+					list.Items = make([]appsv1.Deployment, 9)
+					for i := 0; i < 9; i++ {
+						list.Items[i].Labels = map[string]string{
+							AppLabelComponent: MilvusComponents[i].Name,
+						}
+						list.Items[i].OwnerReferences = []metav1.OwnerReference{
+							{Controller: &trueVal, UID: "uid"},
+						}
+						list.Items[i].Status = readyDeployStatus
+						if MilvusComponents[i].Name == QueryNodeName {
+							list.Items[i].Spec.Replicas = int32Ptr(4)
+							list.Items[i].Status = appsv1.DeploymentStatus{
+								Conditions: []appsv1.DeploymentCondition{
+									{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse},
+								},
+								Replicas:      4,
+								ReadyReplicas: readyReplicas,
+							}
+						}
+					}
+				})
+		}
+		defer func() { milvus.Spec.Com.DegradedMinReadyPercent = nil }()
+
+		t.Run("disabled", func(t *testing.T) {
+			mockListWithQueryNodeReady(3)
+			ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+			assert.NoError(t, err)
+			assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		})
+
+		milvus.Spec.Com.DegradedMinReadyPercent = int32Ptr(50)
+		t.Run("degraded but serving", func(t *testing.T) {
+			mockListWithQueryNodeReady(2)
+			ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+			assert.NoError(t, err)
+			assert.Equal(t, corev1.ConditionTrue, ret.Status)
+			assert.Equal(t, v1beta1.ReasonMilvusDegraded, ret.Reason)
+			assert.Contains(t, ret.Message, QueryNodeName)
+		})
+
+		t.Run("not serving", func(t *testing.T) {
+			mockListWithQueryNodeReady(1)
+			ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+			assert.NoError(t, err)
+			assert.Equal(t, corev1.ConditionFalse, ret.Status)
+			assert.Equal(t, v1beta1.ReasonMilvusComponentNotHealthy, ret.Reason)
+		})
+
+		t.Run("fully ready", func(t *testing.T) {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).
+				Do(func(ctx interface{}, list *appsv1.DeploymentList, opts interface{}) {
+					list.Items = make([]appsv1.Deployment, 9)
+					for i := 0; i < 9; i++ {
+						list.Items[i].Labels = map[string]string{
+							AppLabelComponent: MilvusComponents[i].Name,
+						}
+						list.Items[i].OwnerReferences = []metav1.OwnerReference{
+							{Controller: &trueVal, UID: "uid"},
+						}
+						list.Items[i].Status = readyDeployStatus
+					}
+				})
+			ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+			assert.NoError(t, err)
+			assert.Equal(t, corev1.ConditionTrue, ret.Status)
+			assert.Equal(t, v1beta1.ReasonMilvusHealthy, ret.Reason)
+		})
+	})
+
+	t.Run(("cluster unready by image pull failure"), func(t *testing.T) {
+		stubs := gostub.Stub(&getComponentErrorDetail, func(ctx context.Context, cli client.Client, component string, deploy *appsv1.Deployment) (*ComponentErrorDetail, error) {
+			return &ComponentErrorDetail{
+				ComponentName: component,
+				PodName:       "pod1",
+				Pod:           &corev1.PodCondition{},
+				Container: &corev1.ContainerStatus{
+					Name:  component,
+					Image: "milvusdb/milvus:v2.3.mistyped",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+					},
+				},
+			}, nil
+		})
+		defer stubs.Reset()
+		mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonImagePullFailed, ret.Reason)
+		assert.Contains(t, ret.Message, "milvusdb/milvus:v2.3.mistyped")
+	})
+
+	t.Run(("cluster unready by unschedulable pod"), func(t *testing.T) {
+		const schedulerMsg = "0/3 nodes are available: 3 Insufficient cpu."
+		stubs := gostub.Stub(&getComponentErrorDetail, func(ctx context.Context, cli client.Client, component string, deploy *appsv1.Deployment) (*ComponentErrorDetail, error) {
+			return &ComponentErrorDetail{
+				ComponentName: component,
+				PodName:       "pod1",
+				Pod: &corev1.PodCondition{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: schedulerMsg,
+				},
+			}, nil
+		})
+		defer stubs.Reset()
+		mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonInsufficientCapacity, ret.Reason)
+		assert.Contains(t, ret.Message, schedulerMsg)
+	})
 }
 
 func TestGetComponentErrorDetail(t *testing.T) {
@@ -235,6 +356,41 @@ func TestGetComponentErrorDetail(t *testing.T) {
 		assert.Equal(t, "scheduling", ret.Pod.Message)
 	})
 
+	t.Run("pod image pull backoff", func(t *testing.T) {
+		pod := &corev1.Pod{}
+		pod.Name = "test"
+		pod.Namespace = "ns"
+		pod.Status.Conditions = []corev1.PodCondition{
+			{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionFalse,
+			},
+		}
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+			{Name: "config", Ready: true},
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name:  "proxy",
+				Image: "milvusdb/milvus:bad",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				},
+			},
+		}
+		cli.EXPECT().List(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list interface{}, opts ...interface{}) error {
+				podList := list.(*corev1.PodList)
+				podList.Items = append(podList.Items, *pod)
+				return nil
+			})
+		ret, err := getComponentErrorDetail(ctx, cli, component, deploy)
+		assert.NoError(t, err)
+		image, failed := ret.GetImagePullFailedImage()
+		assert.True(t, failed)
+		assert.Equal(t, "milvusdb/milvus:bad", image)
+	})
+
 	t.Run("creating, all pods ready", func(t *testing.T) {
 		pod := &corev1.Pod{}
 		pod.Name = "test"
@@ -300,3 +456,153 @@ func TestExecKillIfTerminatingTooLong(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestComponentConditionGetter_GetMilvusInstanceCondition_CrashLoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := NewMockK8sClient(ctrl)
+	ctx := context.TODO()
+
+	milvus := &v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+			UID:       "uid",
+		},
+	}
+	milvus.Default()
+
+	deploy := appsv1.Deployment{}
+	deploy.Namespace = "ns"
+	deploy.Name = MilvusStandalone.GetDeploymentName(milvus.Name)
+	deploy.Labels = NewComponentAppLabels(milvus.Name, StandaloneName)
+	deploy.OwnerReferences = []metav1.OwnerReference{{UID: milvus.UID, Controller: boolPtr(true)}}
+	deploy.Spec.Selector = &metav1.LabelSelector{MatchLabels: deploy.Labels}
+	deploy.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue},
+		{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse},
+	}
+
+	pod := corev1.Pod{}
+	pod.Name = "pod1"
+	pod.Namespace = "ns"
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+		{Type: corev1.ContainersReady, Status: corev1.ConditionFalse},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         StandaloneName,
+			RestartCount: 3,
+			State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+			},
+		},
+	}
+
+	mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DeploymentList{}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, list interface{}, opts ...interface{}) error {
+			list.(*appsv1.DeploymentList).Items = []appsv1.Deployment{deploy}
+			return nil
+		})
+	mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, list interface{}, opts ...interface{}) error {
+			list.(*corev1.PodList).Items = []corev1.Pod{pod}
+			return nil
+		})
+	ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ConditionFalse, ret.Status)
+	assert.Equal(t, v1beta1.ReasonContainerOOMKilled, ret.Reason)
+	assert.Contains(t, ret.Message, "reason[OOMKilled] exitCode[137] oomKilled[true]")
+
+	t.Run("crash loop with exit code", func(t *testing.T) {
+		detail := ComponentErrorDetail{
+			Container: &corev1.ContainerStatus{
+				Name: StandaloneName,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+				},
+			},
+		}
+		terminated, crashLooping := detail.GetCrashLoopTermination()
+		assert.True(t, crashLooping)
+		assert.Equal(t, "container[standalone] crash looping, last terminated: reason[Error] exitCode[1] oomKilled[false]",
+			GetCrashLoopMessage(StandaloneName, terminated))
+
+		detail.Container.State.Waiting.Reason = "ContainerCreating"
+		_, crashLooping = detail.GetCrashLoopTermination()
+		assert.False(t, crashLooping)
+	})
+}
+
+func TestComponentConditionGetter_GetMilvusInstanceCondition_DeepHealthCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := NewMockK8sClient(ctrl)
+	mockQueryClient := NewMockMilvusQueryClient(ctrl)
+	ctx := context.TODO()
+
+	bak := newMilvusQueryClient
+	defer func() { newMilvusQueryClient = bak }()
+	newMilvusQueryClient = func(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (MilvusQueryClient, error) {
+		return mockQueryClient, nil
+	}
+
+	milvus := &v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+			UID:       "uid",
+		},
+	}
+	milvus.Default()
+	milvus.Spec.HealthCheck = &v1beta1.MilvusHealthCheck{Deep: true}
+
+	mockListReady := func() {
+		mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DeploymentList{}), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list interface{}, opts ...interface{}) error {
+				deploy := appsv1.Deployment{}
+				deploy.Labels = NewComponentAppLabels(milvus.Name, StandaloneName)
+				deploy.OwnerReferences = []metav1.OwnerReference{{UID: milvus.UID, Controller: boolPtr(true)}}
+				deploy.Status = readyDeployStatus
+				list.(*appsv1.DeploymentList).Items = []appsv1.Deployment{deploy}
+				return nil
+			})
+	}
+
+	t.Run("deep check succeeded", func(t *testing.T) {
+		mockListReady()
+		mockQueryClient.EXPECT().ListCollections(gomock.Any(), defaultMilvusDatabase).Return(nil, nil)
+		ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionTrue, ret.Status)
+		assert.Equal(t, v1beta1.ReasonMilvusHealthy, ret.Reason)
+	})
+
+	t.Run("deep check failed", func(t *testing.T) {
+		mockListReady()
+		mockQueryClient.EXPECT().ListCollections(gomock.Any(), defaultMilvusDatabase).Return(nil, errors.New("connection refused"))
+		ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonMilvusQueryFailed, ret.Reason)
+		assert.Contains(t, ret.Message, "connection refused")
+	})
+
+	t.Run("deep check disabled", func(t *testing.T) {
+		mc := milvus.DeepCopy()
+		mc.Spec.HealthCheck = nil
+		mockListReady()
+		ret, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, mockClient, *mc)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionTrue, ret.Status)
+	})
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// ExpectedComponents returns the names of the components which should have a deployment for the milvus,
+// regarding the mode, the use of mixcoord / streamingnode and the streamingnode groups.
+// The names are the same as the keys of status.componentsDeployStatus
+func ExpectedComponents(mc v1beta1.Milvus) []string {
+	components := GetDeploymentComponentsBySpec(mc.Spec)
+	ret := make([]string, 0, len(components))
+	for _, component := range components {
+		ret = append(ret, component.GetName())
+	}
+	return ret
+}
+
+// ComponentsDiff is the difference between the expected components and the actual deployments of a milvus
+type ComponentsDiff struct {
+	// Missing components have no deployment yet
+	Missing []string
+	// Orphaned components have a deployment but are no longer expected
+	Orphaned []string
+}
+
+// IsEmpty returns true if the actual deployments match the expected components
+func (d ComponentsDiff) IsEmpty() bool {
+	return len(d.Missing) == 0 && len(d.Orphaned) == 0
+}
+
+func (d ComponentsDiff) String() string {
+	if d.IsEmpty() {
+		return "no difference"
+	}
+	var parts []string
+	if len(d.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing: %s", strings.Join(d.Missing, ",")))
+	}
+	if len(d.Orphaned) > 0 {
+		parts = append(parts, fmt.Sprintf("orphaned: %s", strings.Join(d.Orphaned, ",")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DiffComponents compares the expected components of the milvus with the deployments controlled by it
+func DiffComponents(mc v1beta1.Milvus, deploys []appsv1.Deployment) ComponentsDiff {
+	expected := sets.New(ExpectedComponents(mc)...)
+	actual := sets.New[string]()
+	for i := range deploys {
+		if !metav1.IsControlledBy(&deploys[i], &mc) {
+			continue
+		}
+		component := getDeploymentComponentName(&deploys[i])
+		if component == "" {
+			continue
+		}
+		actual.Insert(component)
+	}
+	return ComponentsDiff{
+		Missing:  sets.List(expected.Difference(actual)),
+		Orphaned: sets.List(actual.Difference(expected)),
+	}
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestExpectedComponents(t *testing.T) {
+	t.Run("standalone", func(t *testing.T) {
+		mc := v1beta1.Milvus{}
+		mc.Spec.Mode = v1beta1.MilvusModeStandalone
+		assert.Equal(t, []string{StandaloneName}, ExpectedComponents(mc))
+	})
+
+	t.Run("cluster with mixcoord", func(t *testing.T) {
+		mc := v1beta1.Milvus{}
+		mc.Spec.Mode = v1beta1.MilvusModeCluster
+		mc.Spec.Com.Image = "milvusdb/milvus:v2.5.0"
+		mc.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		assert.ElementsMatch(t, []string{
+			MixCoordName, DataNodeName, QueryNodeName, IndexNodeName, ProxyName, StandaloneName,
+		}, ExpectedComponents(mc))
+	})
+
+	t.Run("cluster with split coords and streamingnode", func(t *testing.T) {
+		mc := v1beta1.Milvus{}
+		mc.Spec.Mode = v1beta1.MilvusModeCluster
+		mc.Spec.Com.Image = "milvusdb/milvus:v2.5.0"
+		mc.Spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{}
+		assert.ElementsMatch(t, []string{
+			RootCoordName, DataCoordName, QueryCoordName, IndexCoordName,
+			DataNodeName, QueryNodeName, IndexNodeName, ProxyName, StandaloneName, StreamingNodeName,
+		}, ExpectedComponents(mc))
+	})
+}
+
+func TestDiffComponents(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	mc.Name = "mc"
+	mc.Namespace = "ns"
+	mc.UID = "uid"
+	mc.Spec.Mode = v1beta1.MilvusModeStandalone
+	newDeploy := func(component string, controlled bool) appsv1.Deployment {
+		deploy := appsv1.Deployment{}
+		deploy.Labels = NewComponentAppLabels(mc.Name, component)
+		if controlled {
+			deploy.OwnerReferences = []metav1.OwnerReference{{UID: mc.UID, Controller: boolPtr(true)}}
+		}
+		return deploy
+	}
+
+	diff := DiffComponents(mc, nil)
+	assert.Equal(t, []string{StandaloneName}, diff.Missing)
+	assert.Empty(t, diff.Orphaned)
+	assert.Equal(t, "missing: standalone", diff.String())
+
+	diff = DiffComponents(mc, []appsv1.Deployment{
+		newDeploy(StandaloneName, true),
+		newDeploy(ProxyName, true),
+		newDeploy(QueryNodeName, false),
+	})
+	assert.Empty(t, diff.Missing)
+	assert.Equal(t, []string{ProxyName}, diff.Orphaned)
+	assert.Equal(t, "orphaned: proxy", diff.String())
+
+	diff = DiffComponents(mc, []appsv1.Deployment{newDeploy(StandaloneName, true)})
+	assert.True(t, diff.IsEmpty())
+	assert.Equal(t, "no difference", diff.String())
+}
@@ -36,6 +36,9 @@ const (
 	StreamingNodeName = v1beta1.StreamingNodeName
 	MilvusName        = "milvus"
 
+	// StreamingNodeGroupLabel is the label of the streaming node group deployment & pods
+	StreamingNodeGroupLabel = v1beta1.MilvusIO + "streamingnode-group"
+
 	MixCoordFieldName      = "MixCoord"
 	RootCoordFieldName     = "RootCoord"
 	DataCoordFieldName     = "DataCoord"
@@ -69,23 +72,25 @@ type MilvusComponent struct {
 	Name        string
 	FieldName   string
 	DefaultPort int32
+	// Group is the name of the streaming node group, it's empty for other components
+	Group string
 }
 
 // define MilvusComponents
 var (
-	MixCoord      = MilvusComponent{MixCoordName, MixCoordFieldName, MultiplePorts}
-	RootCoord     = MilvusComponent{RootCoordName, RootCoordFieldName, RootCoordPort}
-	DataCoord     = MilvusComponent{DataCoordName, DataCoordFieldName, DataCoordPort}
-	QueryCoord    = MilvusComponent{QueryCoordName, QueryCoordFieldName, QueryCoordPort}
-	IndexCoord    = MilvusComponent{IndexCoordName, IndexCoordFieldName, IndexCoordPort}
-	DataNode      = MilvusComponent{DataNodeName, DataNodeFieldName, DataNodePort}
-	QueryNode     = MilvusComponent{QueryNodeName, QueryNodeFieldName, QueryNodePort}
-	IndexNode     = MilvusComponent{IndexNodeName, IndexNodeFieldName, IndexNodePort}
-	StreamingNode = MilvusComponent{StreamingNodeName, StreamingNodeFieldName, StreamingNodePort}
-	Proxy         = MilvusComponent{ProxyName, ProxyFieldName, ProxyPort}
+	MixCoord      = MilvusComponent{MixCoordName, MixCoordFieldName, MultiplePorts, ""}
+	RootCoord     = MilvusComponent{RootCoordName, RootCoordFieldName, RootCoordPort, ""}
+	DataCoord     = MilvusComponent{DataCoordName, DataCoordFieldName, DataCoordPort, ""}
+	QueryCoord    = MilvusComponent{QueryCoordName, QueryCoordFieldName, QueryCoordPort, ""}
+	IndexCoord    = MilvusComponent{IndexCoordName, IndexCoordFieldName, IndexCoordPort, ""}
+	DataNode      = MilvusComponent{DataNodeName, DataNodeFieldName, DataNodePort, ""}
+	QueryNode     = MilvusComponent{QueryNodeName, QueryNodeFieldName, QueryNodePort, ""}
+	IndexNode     = MilvusComponent{IndexNodeName, IndexNodeFieldName, IndexNodePort, ""}
+	StreamingNode = MilvusComponent{StreamingNodeName, StreamingNodeFieldName, StreamingNodePort, ""}
+	Proxy         = MilvusComponent{ProxyName, ProxyFieldName, ProxyPort, ""}
 
 	// Milvus standalone
-	MilvusStandalone = MilvusComponent{StandaloneName, StandaloneFieldName, StandalonePort}
+	MilvusStandalone = MilvusComponent{StandaloneName, StandaloneFieldName, StandalonePort, ""}
 
 	MixtureComponents = []MilvusComponent{
 		MixCoord, DataNode, QueryNode, IndexNode, Proxy, MilvusStandalone,
@@ -117,16 +122,59 @@ var (
 )
 
 func IsMilvusDeploymentsComplete(m *v1beta1.Milvus) bool {
-	components := GetComponentsBySpec(m.Spec)
+	components := GetDeploymentComponentsBySpec(m.Spec)
 	status := m.Status.ComponentsDeployStatus
 	for _, component := range components {
-		if status[component.Name].GetState() != v1beta1.DeploymentComplete {
+		if status[component.GetName()].GetState() != v1beta1.DeploymentComplete {
 			return false
 		}
 	}
 	return true
 }
 
+// GetDeploymentComponentsBySpec returns the components by the spec, with the streaming node
+// expanded into its groups, so that each component has exactly one deployment in rolling mode v2
+func GetDeploymentComponentsBySpec(spec v1beta1.MilvusSpec) []MilvusComponent {
+	return expandStreamingNodeGroups(spec, GetComponentsBySpec(spec))
+}
+
+// expandStreamingNodeGroups replaces the streaming node with its groups if groups are set
+func expandStreamingNodeGroups(spec v1beta1.MilvusSpec, components []MilvusComponent) []MilvusComponent {
+	if spec.Com.StreamingNode == nil || len(spec.Com.StreamingNode.Groups) < 1 {
+		return components
+	}
+	ret := make([]MilvusComponent, 0, len(components)+len(spec.Com.StreamingNode.Groups))
+	for _, component := range components {
+		if component != StreamingNode {
+			ret = append(ret, component)
+			continue
+		}
+		for _, group := range spec.Com.StreamingNode.Groups {
+			ret = append(ret, StreamingNode.WithGroup(group.Name))
+		}
+	}
+	return ret
+}
+
+// WithGroup returns the component of the streaming node group
+func (c MilvusComponent) WithGroup(group string) MilvusComponent {
+	c.Group = group
+	return c
+}
+
+// getStreamingNodeGroup returns the group spec of the component, nil if it's not a streaming node group
+func (c MilvusComponent) getStreamingNodeGroup(spec v1beta1.MilvusSpec) *v1beta1.StreamingNodeGroup {
+	if c.Group == "" || spec.Com.StreamingNode == nil {
+		return nil
+	}
+	for i := range spec.Com.StreamingNode.Groups {
+		if spec.Com.StreamingNode.Groups[i].Name == c.Group {
+			return &spec.Com.StreamingNode.Groups[i]
+		}
+	}
+	return nil
+}
+
 // GetComponentsBySpec returns the components by the spec
 func GetComponentsBySpec(spec v1beta1.MilvusSpec) []MilvusComponent {
 	if spec.Mode != v1beta1.MilvusModeCluster {
@@ -185,9 +233,127 @@ func (c MilvusComponent) GetReplicas(spec v1beta1.MilvusSpec) *int32 {
 	replicas, _ := componentField.Elem().
 		FieldByName("Component").
 		FieldByName("Replicas").Interface().(*int32)
+	group := c.getStreamingNodeGroup(spec)
+	if group != nil && group.Replicas != nil && ReplicasValue(replicas) != 0 {
+		return group.Replicas
+	}
+	return replicas
+}
+
+// IsEnabled returns false if the component is disabled by spec.components.<component>.enabled
+func (c MilvusComponent) IsEnabled(spec v1beta1.MilvusSpec) bool {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return true
+	}
+	enabled, _ := componentField.Elem().
+		FieldByName("Component").
+		FieldByName("Enabled").Interface().(*bool)
+	return enabled == nil || *enabled
+}
+
+// GetSpreadAcross returns the topology key to spread the component's pods across
+func (c MilvusComponent) GetSpreadAcross(spec v1beta1.MilvusSpec) string {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return ""
+	}
+	return componentField.Elem().
+		FieldByName("Component").
+		FieldByName("SpreadAcross").String()
+}
+
+// IsHeadlessServiceEnabled returns whether a headless service should be created for the component
+func (c MilvusComponent) IsHeadlessServiceEnabled(spec v1beta1.MilvusSpec) bool {
+	if !c.IsCoord() {
+		return false
+	}
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return false
+	}
+	return componentField.Elem().
+		FieldByName("Component").
+		FieldByName("HeadlessService").Bool()
+}
+
+// GetExtraConfig returns the config merged onto the rendered milvus config for the component only
+func (c MilvusComponent) GetExtraConfig(spec v1beta1.MilvusSpec) map[string]interface{} {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return nil
+	}
+	extraConfig, _ := componentField.Elem().
+		FieldByName("Component").
+		FieldByName("ExtraConfig").Interface().(v1beta1.Values)
+	return extraConfig.Data
+}
+
+// GetLogConfig returns the log level & format set for the component only
+func (c MilvusComponent) GetLogConfig(spec v1beta1.MilvusSpec) (level, format string) {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return "", ""
+	}
+	component := componentField.Elem().FieldByName("Component").Interface().(v1beta1.Component)
+	return component.LogLevel, component.LogFormat
+}
+
+// GetScratchSizeLimit returns the size limit of the scratch emptyDir set for the component
+func (c MilvusComponent) GetScratchSizeLimit(spec v1beta1.MilvusSpec) *resource.Quantity {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return nil
+	}
+	component := componentField.Elem().FieldByName("Component").Interface().(v1beta1.Component)
+	return component.ScratchSizeLimit
+}
+
+// GetConfigMapName returns the name of the configmap mounted on the component's pods
+func (c MilvusComponent) GetConfigMapName(mc v1beta1.Milvus) string {
+	if len(c.GetExtraConfig(mc.Spec)) == 0 {
+		return mc.GetActiveConfigMap()
+	}
+	return mc.GetActiveConfigMap() + "-" + c.GetName()
+}
+
+// GetHeadlessServiceName returns the name of the component headless service
+func (c MilvusComponent) GetHeadlessServiceName(instance string) string {
+	return c.GetDeploymentName(instance) + "-headless"
+}
+
+// GetDesiredReplicas returns the replicas the component's deployment should have,
+// it's 0 when the component is disabled
+func (c MilvusComponent) GetDesiredReplicas(spec v1beta1.MilvusSpec) *int32 {
+	if !c.IsEnabled(spec) {
+		return int32Ptr(0)
+	}
+	replicas := c.GetReplicas(spec)
+	standbyReplicas := c.GetStandbyReplicas(spec)
+	if standbyReplicas > 0 && ReplicasValue(replicas) > 0 {
+		desiredReplicas := ReplicasValue(replicas) + standbyReplicas
+		return &desiredReplicas
+	}
 	return replicas
 }
 
+// GetRollingBatch returns the number of pods rolled in a batch, only querynode supports more than 1
+func (c MilvusComponent) GetRollingBatch(spec v1beta1.MilvusSpec) int32 {
+	if c.Name != QueryNodeName || spec.Com.QueryNode == nil || spec.Com.QueryNode.RollingBatch < 1 {
+		return 1
+	}
+	return spec.Com.QueryNode.RollingBatch
+}
+
+// GetStandbyReplicas returns the warm-standby replicas of the component, only mixcoord supports it
+func (c MilvusComponent) GetStandbyReplicas(spec v1beta1.MilvusSpec) int32 {
+	if c.Name != MixCoordName || spec.Com.MixCoord == nil ||
+		spec.Com.MixCoord.StandbyReplicas == nil {
+		return 0
+	}
+	return *spec.Com.MixCoord.StandbyReplicas
+}
+
 // GetReplicas returns the replicas for the component
 func (c MilvusComponent) SetReplicas(spec v1beta1.MilvusSpec, replicas *int32) error {
 	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
@@ -213,14 +379,17 @@ func (c MilvusComponent) GetRunCommands() []string {
 	return []string{c.Name}
 }
 
-// String returns the name of the component
+// String returns the name of the component, with the group name if it's a streaming node group
 func (c MilvusComponent) GetName() string {
+	if c.Group != "" {
+		return c.Name + "-" + c.Group
+	}
 	return c.Name
 }
 
 // GetDeploymentName returns the name of the component deployment
 func (c MilvusComponent) GetDeploymentName(instance string) string {
-	return fmt.Sprintf("%s-milvus-%s", instance, c.Name)
+	return fmt.Sprintf("%s-milvus-%s", instance, c.GetName())
 }
 
 // GetServiceInstanceName returns the name of the component service
@@ -228,6 +397,11 @@ func GetServiceInstanceName(instance string) string {
 	return instance + "-milvus"
 }
 
+// GetInternalServiceName returns the name of the internal-only service
+func GetInternalServiceName(instance string) string {
+	return GetServiceInstanceName(instance) + "-internal"
+}
+
 // GetContainerName returns the name of the component container
 func (c MilvusComponent) GetContainerName() string {
 	return c.Name
@@ -281,12 +455,12 @@ func (c MilvusComponent) GetServicePorts(spec v1beta1.MilvusSpec) []corev1.Servi
 
 	targetPort = intstr.FromString(MetricPortName)
 	if spec.Com.TargetPortType == v1beta1.ServiceTargetPortTypInteger {
-		targetPort = intstr.FromInt32(MetricPort)
+		targetPort = intstr.FromInt32(c.GetMetricPort(spec))
 	}
 	servicePorts = append(servicePorts, corev1.ServicePort{
 		Name:       MetricPortName,
 		Protocol:   corev1.ProtocolTCP,
-		Port:       MetricPort,
+		Port:       c.GetMetricPort(spec),
 		TargetPort: targetPort,
 	})
 
@@ -326,8 +500,33 @@ func (c MilvusComponent) GetServicePorts(spec v1beta1.MilvusSpec) []corev1.Servi
 	return servicePorts
 }
 
+// GetPorts returns the container ports of the component in spec
+func (c MilvusComponent) GetPorts(spec v1beta1.MilvusSpec) []corev1.ContainerPort {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
+	if componentField.IsNil() {
+		return nil
+	}
+	ports, _ := componentField.Elem().
+		FieldByName("Component").
+		FieldByName("Ports").Interface().([]corev1.ContainerPort)
+	return ports
+}
+
+// getPortOverride returns the port of given name in spec, 0 if it's not overridden
+func (c MilvusComponent) getPortOverride(spec v1beta1.MilvusSpec, name string) int32 {
+	for _, port := range c.GetPorts(spec) {
+		if port.Name == name {
+			return port.ContainerPort
+		}
+	}
+	return 0
+}
+
 // GetComponentPort returns the port of the component
 func (c MilvusComponent) GetComponentPort(spec v1beta1.MilvusSpec) int32 {
+	if port := c.getPortOverride(spec, c.GetPortName()); port > 0 {
+		return port
+	}
 	if c == Proxy || c == MilvusStandalone {
 		svcPort := spec.GetServiceComponent().Port
 		if svcPort > 0 {
@@ -337,14 +536,56 @@ func (c MilvusComponent) GetComponentPort(spec v1beta1.MilvusSpec) int32 {
 	return c.DefaultPort
 }
 
-// GetComponentPort returns the port of the component
+// GetMetricPort returns the metric port of the component
+func (c MilvusComponent) GetMetricPort(spec v1beta1.MilvusSpec) int32 {
+	if port := c.getPortOverride(spec, MetricPortName); port > 0 {
+		return port
+	}
+	return MetricPort
+}
+
+// GetRestfulPort returns the restful port of the component, 0 if it's not exposed
 func (c MilvusComponent) GetRestfulPort(spec v1beta1.MilvusSpec) int32 {
 	if c == Proxy || c == MilvusStandalone {
+		if port := c.getPortOverride(spec, RestfulPortName); port > 0 {
+			return port
+		}
 		return spec.GetServiceComponent().ServiceRestfulPort
 	}
 	return 0
 }
 
+// GetContainerPorts returns the ports of the component container, the ports in spec override the default ones by name
+func (c MilvusComponent) GetContainerPorts(spec v1beta1.MilvusSpec) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{}
+	if c == Proxy || c == MilvusStandalone {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          c.GetPortName(),
+			ContainerPort: c.GetComponentPort(spec),
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	ports = append(ports, corev1.ContainerPort{
+		Name:          MetricPortName,
+		ContainerPort: c.GetMetricPort(spec),
+		Protocol:      corev1.ProtocolTCP,
+	})
+	if restfulPort := c.GetRestfulPort(spec); restfulPort != 0 {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          RestfulPortName,
+			ContainerPort: restfulPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	ret := MergeContainerPort(ports, c.GetPorts(spec))
+	for i := range ret {
+		if ret[i].Protocol == "" {
+			ret[i].Protocol = corev1.ProtocolTCP
+		}
+	}
+	return ret
+}
+
 // GetSideCars returns the component sidecar containers
 func (c MilvusComponent) GetSideCars(spec v1beta1.MilvusSpec) []corev1.Container {
 	componentField := reflect.ValueOf(spec.Com).FieldByName(c.FieldName)
@@ -393,6 +634,9 @@ func (c MilvusComponent) GetInitContainers(spec v1beta1.MilvusSpec) []corev1.Con
 func (c MilvusComponent) GetComponentSpec(spec v1beta1.MilvusSpec) v1beta1.ComponentSpec {
 	value := reflect.ValueOf(spec.Com).FieldByName(c.FieldName).Elem().FieldByName("ComponentSpec")
 	comSpec, _ := value.Interface().(v1beta1.ComponentSpec)
+	if group := c.getStreamingNodeGroup(spec); group != nil {
+		return MergeComponentSpec(group.ComponentSpec, comSpec)
+	}
 	return comSpec
 }
 
@@ -400,7 +644,9 @@ func (c MilvusComponent) GetDependenciesFor2_6Upgrade(spec v1beta1.MilvusSpec) [
 	if spec.Mode != v1beta1.MilvusModeCluster {
 		return []MilvusComponent{}
 	}
-	return upgrade26ClusterDependencyGraph.GetDependencies(c)
+	// all groups of streaming node share the dependencies of streaming node
+	deps := upgrade26ClusterDependencyGraph.GetDependencies(c.WithGroup(""))
+	return expandStreamingNodeGroups(spec, deps)
 }
 
 func (c MilvusComponent) GetDependencies(spec v1beta1.MilvusSpec) []MilvusComponent {
@@ -418,9 +664,9 @@ func (c MilvusComponent) GetDependencies(spec v1beta1.MilvusSpec) []MilvusCompon
 		depGraph = streamingNodeClusterDependencyGraph
 	}
 	if isDowngrade {
-		return depGraph.GetReversedDependencies(c)
+		return expandStreamingNodeGroups(spec, depGraph.GetReversedDependencies(c.WithGroup("")))
 	}
-	return depGraph.GetDependencies(c)
+	return expandStreamingNodeGroups(spec, depGraph.GetDependencies(c.WithGroup("")))
 }
 
 // IsImageUpdated returns whether the image of the component is updated
@@ -429,7 +675,7 @@ func (c MilvusComponent) IsImageUpdated(m *v1beta1.Milvus) bool {
 		return false
 	}
 	deployStatus := m.Status.ComponentsDeployStatus[c.GetName()]
-	if m.Spec.Com.Image != deployStatus.Image {
+	if !v1beta1.IsSameImage(m.Spec.Com.Image, deployStatus.Image) {
 		return false
 	}
 
@@ -446,6 +692,7 @@ func GetConfCheckSum(spec v1beta1.MilvusSpec) string {
 	conf["conf"] = spec.Conf.Data
 	conf["etcd-endpoints"] = spec.Dep.Etcd.Endpoints
 	conf["pulsar-endpoint"] = spec.Dep.Pulsar.Endpoint
+	// resolved from the secret in reconcile if brokerListFromSecret is set
 	conf["kafka-brokerList"] = spec.Dep.Kafka.BrokerList
 	conf["storage-endpoint"] = spec.Dep.Storage.Endpoint
 
@@ -457,6 +704,19 @@ func GetConfCheckSum(spec v1beta1.MilvusSpec) string {
 	return util.CheckSum(b)
 }
 
+// GetComponentConfCheckSum returns the checksum of the configuration including the component's extra config
+func GetComponentConfCheckSum(spec v1beta1.MilvusSpec, component MilvusComponent) string {
+	extraConfig := component.GetExtraConfig(spec)
+	if len(extraConfig) == 0 {
+		return GetConfCheckSum(spec)
+	}
+	b, err := json.Marshal(extraConfig)
+	if err != nil {
+		return ""
+	}
+	return util.CheckSum(append([]byte(GetConfCheckSum(spec)), b...))
+}
+
 // GetMilvusConfCheckSum returns the checksum of the component configuration
 func GetMilvusConfCheckSum(spec v1beta1.MilvusSpec) string {
 	conf := map[string]interface{}{}
@@ -500,6 +760,19 @@ func GetDefaultLivenessProbe() *corev1.Probe {
 	}
 }
 
+// LenientLivenessFailureThreshold is the liveness probe failure threshold for LivenessPolicyLenient
+// 5 minutes with the default 15s period
+const LenientLivenessFailureThreshold = 20
+
+// GetLivenessProbeByPolicy returns the default liveness probe according to the policy
+func GetLivenessProbeByPolicy(policy v1beta1.LivenessPolicy) *corev1.Probe {
+	probe := GetDefaultLivenessProbe()
+	if policy == v1beta1.LivenessPolicyLenient {
+		probe.FailureThreshold = LenientLivenessFailureThreshold
+	}
+	return probe
+}
+
 func GetDefaultReadinessProbe() *corev1.Probe {
 	return &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
@@ -536,19 +809,23 @@ func (c MilvusComponent) GetDeploymentStrategy(configs map[string]interface{}) a
 	}
 
 	if useRollingUpdate {
-		return appsv1.DeploymentStrategy{
-			Type: appsv1.RollingUpdateDeploymentStrategyType,
-			RollingUpdate: &appsv1.RollingUpdateDeployment{
-				MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
-				MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
-			},
-		}
+		return newRollingUpdateStrategy()
 	}
 	return appsv1.DeploymentStrategy{
 		Type: appsv1.RecreateDeploymentStrategyType,
 	}
 }
 
+func newRollingUpdateStrategy() appsv1.DeploymentStrategy {
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+			MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+		},
+	}
+}
+
 type ComponentSpec = v1beta1.ComponentSpec
 
 const (
@@ -570,7 +847,8 @@ var (
 // MergeComponentSpec merges the src ComponentSpec to dst
 func MergeComponentSpec(src, dst ComponentSpec) ComponentSpec {
 	dst.PodLabels = MergeLabels(src.PodLabels, dst.PodLabels)
-	dst.PodAnnotations = MergeAnnotations(src.PodAnnotations, dst.PodAnnotations)
+	// the component's annotations override the global ones like other fields
+	dst.PodAnnotations = MergeAnnotations(dst.PodAnnotations, src.PodAnnotations)
 
 	if src.Paused {
 		dst.Paused = src.Paused
@@ -584,6 +862,10 @@ func MergeComponentSpec(src, dst ComponentSpec) ComponentSpec {
 		dst.Commands = src.Commands
 	}
 
+	if len(src.ExtraArgs) > 0 {
+		dst.ExtraArgs = src.ExtraArgs
+	}
+
 	if src.ImagePullPolicy != nil {
 		dst.ImagePullPolicy = src.ImagePullPolicy
 	}
@@ -622,6 +904,10 @@ func MergeComponentSpec(src, dst ComponentSpec) ComponentSpec {
 		dst.Resources = &corev1.ResourceRequirements{}
 	}
 
+	if src.SecurityContext != nil {
+		dst.SecurityContext = src.SecurityContext
+	}
+
 	if src.VolumeMounts != nil {
 		if dst.VolumeMounts == nil {
 			dst.VolumeMounts = []corev1.VolumeMount{}
@@ -640,6 +926,10 @@ func MergeComponentSpec(src, dst ComponentSpec) ComponentSpec {
 		dst.Volumes = src.Volumes
 	}
 
+	if src.SecretMounts != nil {
+		dst.SecretMounts = src.SecretMounts
+	}
+
 	if len(src.ServiceAccountName) > 0 {
 		dst.ServiceAccountName = src.ServiceAccountName
 	}
@@ -648,6 +938,10 @@ func MergeComponentSpec(src, dst ComponentSpec) ComponentSpec {
 		dst.PriorityClassName = src.PriorityClassName
 	}
 
+	if src.Lifecycle != nil {
+		dst.Lifecycle = src.Lifecycle
+	}
+
 	if src.RunWithSubProcess != nil {
 		dst.RunWithSubProcess = src.RunWithSubProcess
 	}
@@ -660,6 +954,14 @@ func MergeComponentSpec(src, dst ComponentSpec) ComponentSpec {
 		dst.DNSPolicy = src.DNSPolicy
 	}
 
+	if src.HostAliases != nil {
+		dst.HostAliases = src.HostAliases
+	}
+
+	if len(src.DeploymentStrategyType) > 0 {
+		dst.DeploymentStrategyType = src.DeploymentStrategyType
+	}
+
 	if src.Probes.Data != nil {
 		dst.Probes = src.Probes
 	}
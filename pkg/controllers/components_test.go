@@ -74,6 +74,11 @@ func TestMergeComponentSpec(t *testing.T) {
 			"c": "2",
 		}
 		assert.Equal(t, expect, ret.PodLabels)
+		expect = map[string]string{
+			"a": "1",
+			"b": "1",
+			"c": "2",
+		}
 		assert.Equal(t, expect, ret.PodAnnotations)
 	})
 
@@ -217,6 +222,15 @@ func TestMergeComponentSpec(t *testing.T) {
 		assert.Equal(t, "b", merged)
 	})
 
+	t.Run("merge extraArgs", func(t *testing.T) {
+		dst.ExtraArgs = []string{"a"}
+		merged := MergeComponentSpec(src, dst).ExtraArgs
+		assert.Equal(t, []string{"a"}, merged)
+		src.ExtraArgs = []string{"b"}
+		merged = MergeComponentSpec(src, dst).ExtraArgs
+		assert.Equal(t, []string{"b"}, merged)
+	})
+
 	t.Run("merge priorityClassName", func(t *testing.T) {
 		dst.PriorityClassName = "a"
 		merged := MergeComponentSpec(src, dst).PriorityClassName
@@ -248,6 +262,15 @@ func TestMergeComponentSpec(t *testing.T) {
 		assert.Equal(t, "c", merged[1].Name)
 	})
 
+	t.Run("merge securityContext", func(t *testing.T) {
+		dst.SecurityContext = &corev1.SecurityContext{RunAsUser: int64Ptr(1000)}
+		merged := MergeComponentSpec(src, dst).SecurityContext
+		assert.Equal(t, int64(1000), *merged.RunAsUser)
+		src.SecurityContext = &corev1.SecurityContext{RunAsUser: int64Ptr(2000)}
+		merged = MergeComponentSpec(src, dst).SecurityContext
+		assert.Equal(t, int64(2000), *merged.RunAsUser)
+	})
+
 	t.Run("merge runWithSubProcess", func(t *testing.T) {
 		merged := MergeComponentSpec(src, dst).RunWithSubProcess
 		assert.Nil(t, merged)
@@ -271,6 +294,15 @@ func TestMergeComponentSpec(t *testing.T) {
 		assert.Equal(t, true, merged)
 	})
 
+	t.Run("merge HostAliases", func(t *testing.T) {
+		dst.HostAliases = []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"a"}}}
+		merged := MergeComponentSpec(src, dst).HostAliases
+		assert.Equal(t, dst.HostAliases, merged)
+		src.HostAliases = []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"b"}}}
+		merged = MergeComponentSpec(src, dst).HostAliases
+		assert.Equal(t, src.HostAliases, merged)
+	})
+
 	t.Run("merge DNSPolicy", func(t *testing.T) {
 		dst.DNSPolicy = corev1.DNSPolicy("Default")
 		merged := MergeComponentSpec(src, dst).DNSPolicy
@@ -307,6 +339,22 @@ func TestMilvusComponent_GetReplicas(t *testing.T) {
 	assert.Equal(t, &replica, com.GetReplicas(spec))
 }
 
+func TestMilvusComponent_GetDesiredReplicas(t *testing.T) {
+	spec := newSpecCluster()
+	spec.Com.IndexNode.Replicas = int32Ptr(3)
+	assert.True(t, IndexNode.IsEnabled(spec))
+	assert.Equal(t, int32(3), *IndexNode.GetDesiredReplicas(spec))
+
+	spec.Com.IndexNode.Enabled = boolPtr(false)
+	assert.False(t, IndexNode.IsEnabled(spec))
+	assert.Equal(t, int32(0), *IndexNode.GetDesiredReplicas(spec))
+	assert.Equal(t, int32(3), *IndexNode.GetReplicas(spec))
+
+	// nil component is enabled
+	spec.Com.QueryNode = nil
+	assert.True(t, QueryNode.IsEnabled(spec))
+}
+
 func TestMilvusComponent_GetRunCommands(t *testing.T) {
 	com := QueryNode
 	assert.Equal(t, []string{com.Name}, com.GetRunCommands())
@@ -440,6 +488,23 @@ func TestMilvusComponent_GetComponentPort(t *testing.T) {
 	spec = newSpec()
 	spec.Com.Standalone.Port = 19533
 	assert.Equal(t, spec.Com.Standalone.Port, com.GetComponentPort(spec))
+
+	// overridden by ports
+	spec.Com.Standalone.Ports = []corev1.ContainerPort{
+		{Name: MilvusName, ContainerPort: 29530},
+		{Name: MetricPortName, ContainerPort: 29091},
+		{Name: RestfulPortName, ContainerPort: 28080},
+	}
+	assert.Equal(t, int32(29530), com.GetComponentPort(spec))
+	assert.Equal(t, int32(29091), com.GetMetricPort(spec))
+	assert.Equal(t, int32(28080), com.GetRestfulPort(spec))
+
+	com = QueryNode
+	spec = newSpecCluster()
+	spec.Com.QueryNode.Ports = []corev1.ContainerPort{{Name: QueryNodeName, ContainerPort: 31123}}
+	assert.Equal(t, int32(31123), com.GetComponentPort(spec))
+	assert.Equal(t, int32(MetricPort), com.GetMetricPort(spec))
+	assert.Equal(t, int32(0), com.GetRestfulPort(spec))
 }
 
 func TestMilvusComponent_GetComponentSpec(t *testing.T) {
@@ -654,6 +719,42 @@ func TestMilvusComponent_GetDependencies(t *testing.T) {
 
 }
 
+func TestGetDeploymentComponentsBySpec(t *testing.T) {
+	spec := v1beta1.MilvusSpec{}
+	spec.Mode = v1beta1.MilvusModeCluster
+	spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+	spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{}
+	assert.Equal(t, GetComponentsBySpec(spec), GetDeploymentComponentsBySpec(spec))
+
+	spec.Com.StreamingNode.Groups = []v1beta1.StreamingNodeGroup{{Name: "a"}, {Name: "b"}}
+	components := GetDeploymentComponentsBySpec(spec)
+	assert.Contains(t, components, StreamingNode.WithGroup("a"))
+	assert.Contains(t, components, StreamingNode.WithGroup("b"))
+	assert.NotContains(t, components, StreamingNode)
+
+	// all groups are updated before mixcoord
+	deps := MixCoord.GetDependenciesFor2_6Upgrade(spec)
+	assert.Equal(t, []MilvusComponent{StreamingNode.WithGroup("a"), StreamingNode.WithGroup("b")}, deps)
+}
+
+func TestMilvusComponent_Group(t *testing.T) {
+	spec := v1beta1.MilvusSpec{}
+	spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{}
+	spec.Com.StreamingNode.Replicas = int32Ptr(3)
+	spec.Com.StreamingNode.Groups = []v1beta1.StreamingNodeGroup{{Name: "a", Replicas: int32Ptr(2)}, {Name: "b"}}
+	groupA := StreamingNode.WithGroup("a")
+	groupB := StreamingNode.WithGroup("b")
+	assert.Equal(t, "streamingnode-a", groupA.GetName())
+	assert.Equal(t, "mc-milvus-streamingnode-a", groupA.GetDeploymentName("mc"))
+	assert.Equal(t, StreamingNodeName, groupA.GetContainerName())
+	assert.Equal(t, int32(2), *groupA.GetReplicas(spec))
+	assert.Equal(t, int32(3), *groupB.GetReplicas(spec))
+
+	// stopped streaming node stops all groups
+	spec.Com.StreamingNode.Replicas = int32Ptr(0)
+	assert.Equal(t, int32(0), *groupA.GetReplicas(spec))
+}
+
 func TestMilvusComponent_IsImageUpdated(t *testing.T) {
 	m := &v1beta1.Milvus{}
 	assert.False(t, MilvusStandalone.IsImageUpdated(m))
@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,6 +18,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -91,6 +95,7 @@ type StorageConditionInfo struct {
 	// StorageAccount of azure
 	StorageAccount string
 	UseVirtualHost bool
+	Region         string
 }
 
 type checkMinIOFunc = func(args external.CheckMinIOArgs) error
@@ -98,24 +103,30 @@ type checkMinIOFunc = func(args external.CheckMinIOArgs) error
 // checkMinIO wraps minio.New for test mock convenience
 var checkMinIO = external.CheckMinIO
 
-func GetMinioCondition(ctx context.Context, logger logr.Logger, cli client.Client, info StorageConditionInfo) v1beta1.MilvusCondition {
+// getCheckMinIOArgs reads the credentials & the CA certificate of the storage from the secrets,
+// the returned condition is not nil if they can't be read
+func getCheckMinIOArgs(ctx context.Context, cli client.Client, info StorageConditionInfo) (external.CheckMinIOArgs, *v1beta1.MilvusCondition) {
+	failed := func(reason, msg string) (external.CheckMinIOArgs, *v1beta1.MilvusCondition) {
+		cond := newErrStorageCondResult(reason, msg)
+		return external.CheckMinIOArgs{}, &cond
+	}
 	var accesskey, secretkey []byte
 	if !info.UseIAM {
 		secret := &corev1.Secret{}
 		key := types.NamespacedName{Namespace: info.Namespace, Name: info.Storage.SecretRef}
 		err := cli.Get(ctx, key, secret)
 		if err != nil && !k8sErrors.IsNotFound(err) {
-			return newErrStorageCondResult(v1beta1.ReasonClientErr, err.Error())
+			return failed(v1beta1.ReasonClientErr, err.Error())
 		}
 
 		if k8sErrors.IsNotFound(err) {
-			return newErrStorageCondResult(v1beta1.ReasonSecretNotExist, MessageSecretNotExist)
+			return failed(v1beta1.ReasonSecretNotExist, MessageSecretNotExist)
 		}
 		var exist1, exist2 bool
 		accesskey, exist1 = secret.Data[AccessKey]
 		secretkey, exist2 = secret.Data[SecretKey]
 		if !exist1 || !exist2 {
-			return newErrStorageCondResult(v1beta1.ReasonSecretNotExist, MessageKeyNotExist)
+			return failed(v1beta1.ReasonSecretNotExist, MessageKeyNotExist)
 		}
 	}
 	ak := string(accesskey)
@@ -137,20 +148,20 @@ func GetMinioCondition(ctx context.Context, logger logr.Logger, cli client.Clien
 			err := cli.Get(ctx, caKey, caSecret)
 			if err != nil {
 				if k8sErrors.IsNotFound(err) {
-					return newErrStorageCondResult(v1beta1.ReasonSecretNotExist, MessageStorageSSLCertSecretNotExist)
+					return failed(v1beta1.ReasonSecretNotExist, MessageStorageSSLCertSecretNotExist)
 				}
-				return newErrStorageCondResult(v1beta1.ReasonClientErr, MessageStorageSSLCertLoadFailed+": "+err.Error())
+				return failed(v1beta1.ReasonClientErr, MessageStorageSSLCertLoadFailed+": "+err.Error())
 			}
 
 			var exists bool
 			caCertificate, exists = caSecret.Data["ca.crt"]
 			if !exists {
-				return newErrStorageCondResult(v1beta1.ReasonClientErr, MessageStorageSSLCertKeyNotExist)
+				return failed(v1beta1.ReasonClientErr, MessageStorageSSLCertKeyNotExist)
 			}
 		}
 	}
 
-	err := checkMinIO(external.CheckMinIOArgs{
+	return external.CheckMinIOArgs{
 		Type:               info.Storage.Type,
 		AK:                 ak,
 		SK:                 string(secretkey),
@@ -160,10 +171,18 @@ func GetMinioCondition(ctx context.Context, logger logr.Logger, cli client.Clien
 		UseIAM:             info.UseIAM,
 		IAMEndpoint:        info.IAMEndpoint,
 		UseVirtualHost:     info.UseVirtualHost,
+		Region:             info.Region,
 		CACertificate:      caCertificate,
 		InsecureSkipVerify: insecureSkipVerify,
-	})
-	if err != nil {
+	}, nil
+}
+
+func GetMinioCondition(ctx context.Context, logger logr.Logger, cli client.Client, info StorageConditionInfo) v1beta1.MilvusCondition {
+	args, failedCond := getCheckMinIOArgs(ctx, cli, info)
+	if failedCond != nil {
+		return *failedCond
+	}
+	if err := checkMinIO(args); err != nil {
 		return newErrStorageCondResult(v1beta1.ReasonClientErr, err.Error())
 	}
 
@@ -174,6 +193,32 @@ func GetMinioCondition(ctx context.Context, logger logr.Logger, cli client.Clien
 	}
 }
 
+// getStorageBucketUsage wraps external.GetMinIOBucketUsage for test mock convenience
+var getStorageBucketUsage = external.GetMinIOBucketUsage
+
+// GetStorageCapacityCondition returns the StorageCapacityWarning condition if the bucket usage exceeds the threshold,
+// it returns nil if the usage is under the threshold
+func GetStorageCapacityCondition(ctx context.Context, cli client.Client, info StorageConditionInfo, threshold resource.Quantity) (*v1beta1.MilvusCondition, error) {
+	args, failedCond := getCheckMinIOArgs(ctx, cli, info)
+	if failedCond != nil {
+		return nil, errors.New(failedCond.Message)
+	}
+	usage, err := getStorageBucketUsage(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "get bucket usage")
+	}
+	if usage <= uint64(threshold.Value()) {
+		return nil, nil
+	}
+	return &v1beta1.MilvusCondition{
+		Type:   v1beta1.StorageCapacityWarning,
+		Status: corev1.ConditionTrue,
+		Reason: v1beta1.ReasonStorageCapacityExceeded,
+		Message: fmt.Sprintf("bucket[%s] usage %s exceeds the threshold %s",
+			info.Bucket, resource.NewQuantity(int64(usage), resource.BinarySI).String(), threshold.String()),
+	}, nil
+}
+
 type EtcdConditionInfo struct {
 	Endpoints []string
 }
@@ -290,6 +335,15 @@ func newErrStorageCondResult(reason, message string) v1beta1.MilvusCondition {
 	}
 }
 
+func newErrTLSCertCondResult(reason, message string) v1beta1.MilvusCondition {
+	return v1beta1.MilvusCondition{
+		Type:    v1beta1.TLSCertificateValid,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
 func newErrMsgStreamCondResult(reason, message string) v1beta1.MilvusCondition {
 	return v1beta1.MilvusCondition{
 		Type:    v1beta1.MsgStreamReady,
@@ -329,6 +383,16 @@ func GetMilvusEndpoint(ctx context.Context, logger logr.Logger, client client.Cl
 	return ""
 }
 
+// getDeploymentComponentName returns the name of the component by the labels of the deployment,
+// which equals to the MilvusComponent.GetName()
+func getDeploymentComponentName(deploy *appsv1.Deployment) string {
+	component := deploy.Labels[AppLabelComponent]
+	if group := deploy.Labels[StreamingNodeGroupLabel]; group != "" {
+		return component + "-" + group
+	}
+	return component
+}
+
 func makeComponentDeploymentMap(mc v1beta1.Milvus, deploys []appsv1.Deployment) map[string]*appsv1.Deployment {
 	m := make(map[string]*appsv1.Deployment)
 	labelHelper := v1beta1.Labels()
@@ -351,7 +415,7 @@ func makeComponentDeploymentMap(mc v1beta1.Milvus, deploys []appsv1.Deployment)
 				Message: fmt.Sprintf("rolling id %s", labelHelper.GetComponentRollingId(mc, component)),
 			})
 		}
-		m[deploy.Labels[AppLabelComponent]] = &deploy
+		m[getDeploymentComponentName(&deploy)] = &deploy
 
 	}
 	return m
@@ -373,3 +437,68 @@ func IsMilvusConditionTrueByType(conditions []v1beta1.MilvusCondition, t v1beta1
 	}
 	return cond.Status == corev1.ConditionTrue
 }
+
+// DefaultTLSCertExpiryWarningWindow is the default window before the certificate expires to turn TLSCertificateValid false
+var DefaultTLSCertExpiryWarningWindow = 30 * 24 * time.Hour
+
+// GetTLSCertificateCondition checks the certificates of the TLS secrets referenced by the ingress,
+// it returns a false condition if any of them is invalid, expired or expiring within the warning window
+func GetTLSCertificateCondition(ctx context.Context, cli client.Client, namespace string, ingress v1beta1.MilvusIngress, now time.Time) v1beta1.MilvusCondition {
+	window := DefaultTLSCertExpiryWarningWindow
+	if ingress.TLSCertExpiryWarningWindow != nil {
+		window = ingress.TLSCertExpiryWarningWindow.Duration
+	}
+	secretNames := make([]string, 0, len(ingress.TLSSecretRefs))
+	for name := range ingress.TLSSecretRefs {
+		secretNames = append(secretNames, name)
+	}
+	sort.Strings(secretNames)
+
+	var earliestSecret string
+	var earliestNotAfter time.Time
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		err := cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return newErrTLSCertCondResult(v1beta1.ReasonSecretNotExist, fmt.Sprintf("%s: %s", MessageSecretNotExist, name))
+			}
+			return newErrTLSCertCondResult(v1beta1.ReasonClientErr, err.Error())
+		}
+		notAfter, err := getCertificateNotAfter(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			return newErrTLSCertCondResult(v1beta1.ReasonTLSCertificateInvalid, fmt.Sprintf("secret[%s]: %s", name, err.Error()))
+		}
+		if earliestNotAfter.IsZero() || notAfter.Before(earliestNotAfter) {
+			earliestSecret = name
+			earliestNotAfter = notAfter
+		}
+	}
+
+	message := fmt.Sprintf("certificate in secret[%s] expires at %s", earliestSecret, earliestNotAfter.Format(time.RFC3339))
+	switch {
+	case !now.Before(earliestNotAfter):
+		return newErrTLSCertCondResult(v1beta1.ReasonTLSCertificateExpired, message)
+	case earliestNotAfter.Sub(now) < window:
+		return newErrTLSCertCondResult(v1beta1.ReasonTLSCertificateExpiring, message)
+	}
+	return v1beta1.MilvusCondition{
+		Type:    v1beta1.TLSCertificateValid,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonTLSCertificateValid,
+		Message: message,
+	}
+}
+
+// getCertificateNotAfter returns the NotAfter of the first certificate in the PEM data
+func getCertificateNotAfter(pemData []byte) (time.Time, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, errors.Errorf("no PEM certificate found in %s", corev1.TLSCertKey)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parse certificate")
+	}
+	return cert.NotAfter, nil
+}
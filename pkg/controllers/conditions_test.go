@@ -2,8 +2,15 @@ package controllers
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/prashantv/gostub"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +21,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -188,6 +197,72 @@ func TestGetMinioCondition(t *testing.T) {
 		assert.Equal(t, corev1.ConditionTrue, ret.Status)
 		assert.Equal(t, v1beta1.ReasonStorageReady, ret.Reason)
 	})
+
+	t.Run("probe honors region & path style in spec", func(t *testing.T) {
+		var probeArgs external.CheckMinIOArgs
+		stubs := gostub.Stub(&checkMinIO, func(args external.CheckMinIOArgs) error {
+			probeArgs = args
+			return nil
+		})
+		defer stubs.Reset()
+		mockK8sCli.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(ctx interface{}, key interface{}, secret *corev1.Secret, opt ...any) {
+				secret.Data = map[string][]byte{
+					AccessKey: []byte("accessKeyID"),
+					SecretKey: []byte("secretAccessKey"),
+				}
+			})
+		mc := v1beta1.Milvus{}
+		mc.Spec.Dep.Storage.Type = v1beta1.StorageTypeS3
+		mc.Spec.Dep.Storage.Region = "eu-central-1"
+		mc.Spec.Dep.Storage.UseSSL = util.BoolPtr(true)
+		mc.Spec.Dep.Storage.UseVirtualHost = util.BoolPtr(false)
+		mc.Default()
+		ret := GetMinioCondition(ctx, logger, mockK8sCli, newStorageConditionInfo(mc))
+		assert.Equal(t, corev1.ConditionTrue, ret.Status)
+		assert.Equal(t, "eu-central-1", probeArgs.Region)
+		assert.True(t, probeArgs.UseSSL)
+		assert.False(t, probeArgs.UseVirtualHost)
+	})
+}
+
+func TestGetStorageCapacityCondition(t *testing.T) {
+	ctx := context.TODO()
+	info := StorageConditionInfo{UseIAM: true, Bucket: "milvus-bucket"}
+	threshold := resource.MustParse("1Gi")
+
+	t.Run("under threshold", func(t *testing.T) {
+		stubs := gostub.Stub(&getStorageBucketUsage, func(external.CheckMinIOArgs) (uint64, error) {
+			return 512 * 1024 * 1024, nil
+		})
+		defer stubs.Reset()
+		cond, err := GetStorageCapacityCondition(ctx, nil, info, threshold)
+		assert.NoError(t, err)
+		assert.Nil(t, cond)
+	})
+
+	t.Run("over threshold", func(t *testing.T) {
+		stubs := gostub.Stub(&getStorageBucketUsage, func(args external.CheckMinIOArgs) (uint64, error) {
+			assert.Equal(t, "milvus-bucket", args.Bucket)
+			return 2 * 1024 * 1024 * 1024, nil
+		})
+		defer stubs.Reset()
+		cond, err := GetStorageCapacityCondition(ctx, nil, info, threshold)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.StorageCapacityWarning, cond.Type)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+		assert.Equal(t, v1beta1.ReasonStorageCapacityExceeded, cond.Reason)
+		assert.Contains(t, cond.Message, "2Gi")
+	})
+
+	t.Run("get usage failed", func(t *testing.T) {
+		stubs := gostub.Stub(&getStorageBucketUsage, func(external.CheckMinIOArgs) (uint64, error) {
+			return 0, errors.New("test")
+		})
+		defer stubs.Reset()
+		_, err := GetStorageCapacityCondition(ctx, nil, info, threshold)
+		assert.Error(t, err)
+	})
 }
 
 func getMockNewEtcdClient(cli EtcdClient, err error) NewEtcdClientFunc {
@@ -324,3 +399,78 @@ func TestIsMilvusConditionTrueByType(t *testing.T) {
 	ret = IsMilvusConditionTrueByType(conds, v1beta1.StorageReady)
 	assert.True(t, ret)
 }
+
+func newTestCertPEM(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGetTLSCertificateCondition(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newTLSSecret := func(name string, cert []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Data:       map[string][]byte{corev1.TLSCertKey: cert},
+		}
+	}
+	validSecret := newTLSSecret("valid", newTestCertPEM(t, now.Add(365*24*time.Hour)))
+	expiringSecret := newTLSSecret("expiring", newTestCertPEM(t, now.Add(7*24*time.Hour)))
+	expiredSecret := newTLSSecret("expired", newTestCertPEM(t, now.Add(-time.Hour)))
+	invalidSecret := newTLSSecret("invalid", []byte("not a cert"))
+	cli := fake.NewClientBuilder().WithObjects(validSecret, expiringSecret, expiredSecret, invalidSecret).Build()
+
+	t.Run("valid cert", func(t *testing.T) {
+		ingress := v1beta1.MilvusIngress{TLSSecretRefs: map[string][]string{"valid": {"host1"}}}
+		ret := GetTLSCertificateCondition(ctx, cli, "ns", ingress, now)
+		assert.Equal(t, v1beta1.TLSCertificateValid, ret.Type)
+		assert.Equal(t, corev1.ConditionTrue, ret.Status)
+		assert.Equal(t, v1beta1.ReasonTLSCertificateValid, ret.Reason)
+		assert.Contains(t, ret.Message, "valid")
+	})
+
+	t.Run("soon to expire cert", func(t *testing.T) {
+		ingress := v1beta1.MilvusIngress{TLSSecretRefs: map[string][]string{
+			"valid":    {"host1"},
+			"expiring": {"host2"},
+		}}
+		ret := GetTLSCertificateCondition(ctx, cli, "ns", ingress, now)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonTLSCertificateExpiring, ret.Reason)
+		assert.Contains(t, ret.Message, "secret[expiring]")
+
+		// valid with a shorter warning window
+		ingress.TLSCertExpiryWarningWindow = &metav1.Duration{Duration: 24 * time.Hour}
+		ret = GetTLSCertificateCondition(ctx, cli, "ns", ingress, now)
+		assert.Equal(t, corev1.ConditionTrue, ret.Status)
+	})
+
+	t.Run("expired cert", func(t *testing.T) {
+		ingress := v1beta1.MilvusIngress{TLSSecretRefs: map[string][]string{"expired": {"host1"}}}
+		ret := GetTLSCertificateCondition(ctx, cli, "ns", ingress, now)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonTLSCertificateExpired, ret.Reason)
+	})
+
+	t.Run("invalid cert", func(t *testing.T) {
+		ingress := v1beta1.MilvusIngress{TLSSecretRefs: map[string][]string{"invalid": {"host1"}}}
+		ret := GetTLSCertificateCondition(ctx, cli, "ns", ingress, now)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonTLSCertificateInvalid, ret.Reason)
+	})
+
+	t.Run("secret not exist", func(t *testing.T) {
+		ingress := v1beta1.MilvusIngress{TLSSecretRefs: map[string][]string{"notexist": {"host1"}}}
+		ret := GetTLSCertificateCondition(ctx, cli, "ns", ingress, now)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonSecretNotExist, ret.Reason)
+	})
+}
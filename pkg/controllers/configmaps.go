@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"strings"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,23 +36,80 @@ func (r *MilvusReconciler) getMinioAccessInfo(ctx context.Context, mc v1beta1.Mi
 
 }
 
-func (r *MilvusReconciler) updateConfigMap(ctx context.Context, mc v1beta1.Milvus, configmap *corev1.ConfigMap) error {
+// mergeUserConf overlays the user config on the default config by the merge strategy, then the feature gates
+func mergeUserConf(conf map[string]interface{}, spec v1beta1.MilvusSpec) {
+	userConf := spec.Conf.Data
+	util.MergeValues(conf, userConf)
+	if spec.ConfMergeStrategy == v1beta1.ConfMergeStrategyReplaceSection {
+		for section := range userConf {
+			util.ReplaceValues(conf, userConf, section)
+		}
+	}
+	for _, path := range spec.ConfReplacePaths {
+		util.ReplaceValues(conf, userConf, strings.Split(path, ".")...)
+	}
+	for gate, enabled := range spec.FeatureGates {
+		if key, ok := v1beta1.FeatureGateConfigKeys[gate]; ok {
+			util.SetValue(conf, enabled, strings.Split(key, ".")...)
+		}
+	}
+}
+
+// renderedConfCache carries the milvus config rendered in a reconcile,
+// so that the configmaps written in the same reconcile share one rendering
+type renderedConfCache struct {
+	mu   sync.Mutex
+	conf map[string]interface{}
+}
+
+type renderedConfCacheKey struct{}
+
+// withRenderedConfCache returns a context in which the milvus config is rendered at most once
+func withRenderedConfCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, renderedConfCacheKey{}, &renderedConfCache{})
+}
+
+// renderMilvusConf renders the milvus config, or returns a copy of the one rendered before in the same reconcile
+func (r *MilvusReconciler) renderMilvusConf(ctx context.Context, mc v1beta1.Milvus) (map[string]interface{}, error) {
+	cache, ok := ctx.Value(renderedConfCacheKey{}).(*renderedConfCache)
+	if !ok {
+		return r.doRenderMilvusConf(ctx, mc)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.conf == nil {
+		conf, err := r.doRenderMilvusConf(ctx, mc)
+		if err != nil {
+			return nil, err
+		}
+		cache.conf = conf
+	}
+	return util.DeepCopyValues(cache.conf), nil
+}
+
+// doRenderMilvusConf renders the milvus config from the defaults, the user config & the dependencies
+func (r *MilvusReconciler) doRenderMilvusConf(ctx context.Context, mc v1beta1.Milvus) (map[string]interface{}, error) {
 	confYaml, err := util.GetTemplatedValues(config.GetMilvusConfigTemplate(), mc)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	conf := map[string]interface{}{}
 	if err := yaml.Unmarshal(confYaml, &conf); err != nil {
 		r.logger.Error(err, "yaml Unmarshal conf error")
-		return err
+		return nil, err
 	}
 
+	mergeUserConf(conf, mc.Spec)
+	// set after the merge so that the keys aren't wiped when the minio section is replaced,
+	// while the ones set in the user config still take precedence
 	key, secret := r.getMinioAccessInfo(ctx, mc)
-	util.SetValue(conf, key, "minio", "accessKeyID")
-	util.SetValue(conf, secret, "minio", "secretAccessKey")
-
-	util.MergeValues(conf, mc.Spec.Conf.Data)
+	if _, found := util.GetStringValue(mc.Spec.Conf.Data, "minio", "accessKeyID"); !found {
+		util.SetValue(conf, key, "minio", "accessKeyID")
+	}
+	if _, found := util.GetStringValue(mc.Spec.Conf.Data, "minio", "secretAccessKey"); !found {
+		util.SetValue(conf, secret, "minio", "secretAccessKey")
+	}
 	util.SetStringSlice(conf, mc.Spec.Dep.Etcd.Endpoints, "etcd", "endpoints")
 
 	host, port := util.GetHostPort(mc.Spec.Dep.Storage.Endpoint)
@@ -59,7 +118,11 @@ func (r *MilvusReconciler) updateConfigMap(ctx context.Context, mc v1beta1.Milvu
 
 	switch mc.Spec.Dep.MsgStreamType {
 	case v1beta1.MsgStreamTypeKafka:
-		util.SetStringSlice(conf, mc.Spec.Dep.Kafka.BrokerList, "kafka", "brokerList")
+		brokerList, err := ResolveKafkaBrokerList(ctx, r.Client, mc)
+		if err != nil {
+			return nil, err
+		}
+		util.SetStringSlice(conf, brokerList, "kafka", "brokerList")
 		// delete other mq config to make milvus use kafka
 		delete(conf, "pulsar")
 		delete(conf, "rocksmq")
@@ -89,6 +152,14 @@ func (r *MilvusReconciler) updateConfigMap(ctx context.Context, mc v1beta1.Milvu
 		conf["mq"].(map[string]interface{})["type"] = mc.Spec.Dep.MsgStreamType
 		conf[util.MqTypeConfigKey] = mc.Spec.Dep.MsgStreamType
 	}
+	return conf, nil
+}
+
+func (r *MilvusReconciler) updateConfigMap(ctx context.Context, mc v1beta1.Milvus, configmap *corev1.ConfigMap) error {
+	conf, err := r.renderMilvusConf(ctx, mc)
+	if err != nil {
+		return err
+	}
 
 	milvusYaml, err := yaml.Marshal(conf)
 	if err != nil {
@@ -185,3 +256,80 @@ var reconcileOneConfigMap = func(r *MilvusReconciler, ctx context.Context, mc v1
 	r.logger.Info("Update Configmap", "name", cur.Name, "namespace", cur.Namespace)
 	return r.Update(ctx, cur)
 }
+
+// newComponentConfigMapLabels differs from the app labels, so that it's not treated as a shared milvus configmap
+func newComponentConfigMapLabels(instance, component string) map[string]string {
+	return map[string]string{
+		AppLabelInstance:  instance,
+		AppLabelComponent: component,
+		AppLabelName:      "milvus-component-config",
+		AppLabelManagedBy: ManagerName,
+	}
+}
+
+// updateComponentConfigMap renders the shared config with the component's extra config merged on top
+func (r *MilvusReconciler) updateComponentConfigMap(ctx context.Context, mc v1beta1.Milvus, component MilvusComponent, configmap *corev1.ConfigMap) error {
+	if err := r.updateConfigMap(ctx, mc, configmap); err != nil {
+		return err
+	}
+	conf := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(configmap.Data[UserYaml]), &conf); err != nil {
+		return errors.Wrap(err, "unmarshal rendered config")
+	}
+	util.MergeValues(conf, util.DeepCopyValues(component.GetExtraConfig(mc.Spec)))
+	milvusYaml, err := yaml.Marshal(conf)
+	if err != nil {
+		return errors.Wrap(err, "marshal component config")
+	}
+	configmap.Data[UserYaml] = string(milvusYaml)
+	// overrides the app name label, so that it's not listed as a shared configmap
+	configmap.Labels = MergeLabels(configmap.Labels, newComponentConfigMapLabels(mc.Name, component.GetName()))
+	return nil
+}
+
+// ReconcileComponentConfigMaps reconciles the configmaps of the components with extra config
+func (r *MilvusReconciler) ReconcileComponentConfigMaps(ctx context.Context, mc v1beta1.Milvus) error {
+	for _, component := range GetComponentsBySpec(mc.Spec) {
+		if len(component.GetExtraConfig(mc.Spec)) == 0 {
+			continue
+		}
+		if err := r.reconcileComponentConfigMap(ctx, mc, component); err != nil {
+			return errors.Wrapf(err, "reconcile configmap of %s", component.GetName())
+		}
+	}
+	return nil
+}
+
+func (r *MilvusReconciler) reconcileComponentConfigMap(ctx context.Context, mc v1beta1.Milvus, component MilvusComponent) error {
+	namespacedName := NamespacedName(mc.Namespace, component.GetConfigMapName(mc))
+	old := &corev1.ConfigMap{}
+	err := r.Get(ctx, namespacedName, old)
+	if kerrors.IsNotFound(err) {
+		new := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := r.updateComponentConfigMap(ctx, mc, component, new); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create Configmap", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	} else if err != nil {
+		return err
+	}
+
+	cur := old.DeepCopy()
+	if err := r.updateComponentConfigMap(ctx, mc, component, cur); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update Configmap", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
@@ -240,3 +240,168 @@ func TestReconcileOneConfigMap_Existed(t *testing.T) {
 		}, mc.Spec.Conf.Data["rocksmq"])
 	})
 }
+
+func TestMilvusReconciler_updateConfigMap_ConfMergeStrategy(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	render := func(mc v1beta1.Milvus) map[string]interface{} {
+		// get secret of minio
+		env.MockClient.EXPECT().
+			Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+		cm := &corev1.ConfigMap{}
+		cm.Namespace = mc.Namespace
+		assert.NoError(t, r.updateConfigMap(ctx, mc, cm))
+		conf := map[string]interface{}{}
+		assert.NoError(t, yaml.Unmarshal([]byte(cm.Data[UserYaml]), &conf))
+		return conf
+	}
+	newMilvus := func() v1beta1.Milvus {
+		mc := *env.Inst.DeepCopy()
+		mc.Spec.Conf.Data = map[string]interface{}{
+			"minio": map[string]interface{}{
+				"useSSL": true,
+			},
+			"msgChannel": map[string]interface{}{
+				"chanNamePrefix": map[string]interface{}{
+					"search": "mysearch",
+				},
+			},
+		}
+		return mc
+	}
+
+	t.Run("deepMerge by default", func(t *testing.T) {
+		conf := render(newMilvus())
+		minio := conf["minio"].(map[string]interface{})
+		assert.Equal(t, true, minio["useSSL"])
+		assert.Equal(t, "mc", minio["bucketName"])
+		prefix := conf["msgChannel"].(map[string]interface{})["chanNamePrefix"].(map[string]interface{})
+		assert.Equal(t, "mysearch", prefix["search"])
+		assert.Equal(t, "mc", prefix["cluster"])
+	})
+
+	t.Run("replaceSection", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Spec.ConfMergeStrategy = v1beta1.ConfMergeStrategyReplaceSection
+		conf := render(mc)
+		minio := conf["minio"].(map[string]interface{})
+		assert.Equal(t, true, minio["useSSL"])
+		assert.NotContains(t, minio, "bucketName")
+		// operator managed values still set
+		assert.Contains(t, minio, "address")
+		assert.Contains(t, minio, "accessKeyID")
+		assert.Contains(t, minio, "secretAccessKey")
+		prefix := conf["msgChannel"].(map[string]interface{})["chanNamePrefix"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"search": "mysearch"}, prefix)
+		// sections not in user config are kept
+		assert.Contains(t, conf, "etcd")
+	})
+
+	t.Run("deepMerge with replace paths", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Spec.ConfReplacePaths = []string{"msgChannel.chanNamePrefix", "not.exist"}
+		conf := render(mc)
+		minio := conf["minio"].(map[string]interface{})
+		assert.Equal(t, "mc", minio["bucketName"])
+		prefix := conf["msgChannel"].(map[string]interface{})["chanNamePrefix"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"search": "mysearch"}, prefix)
+		assert.NotContains(t, conf, "not")
+	})
+
+	t.Run("feature gates override user config", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Spec.Conf.Data["queryNode"] = map[string]interface{}{
+			"mmap": map[string]interface{}{
+				"vectorField": true,
+			},
+		}
+		mc.Spec.FeatureGates = map[string]bool{
+			"ClusteringCompaction": true,
+			"MmapVectorField":      false,
+			"Unknown":              true,
+		}
+		conf := render(mc)
+		clustering := conf["dataCoord"].(map[string]interface{})["compaction"].(map[string]interface{})["clustering"].(map[string]interface{})
+		assert.Equal(t, true, clustering["enable"])
+		mmap := conf["queryNode"].(map[string]interface{})["mmap"].(map[string]interface{})
+		assert.Equal(t, false, mmap["vectorField"])
+		assert.NotContains(t, conf, "Unknown")
+	})
+}
+
+func TestMilvusReconciler_ReconcileComponentConfigMaps(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	mockClient := env.MockClient
+
+	mc := *env.Inst.DeepCopy()
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Conf.Data = map[string]interface{}{
+		"queryNode": map[string]interface{}{
+			"gracefulTime": 1000,
+		},
+	}
+
+	t.Run("no extra config", func(t *testing.T) {
+		assert.NoError(t, r.ReconcileComponentConfigMaps(ctx, mc))
+	})
+
+	mc.Spec.Com.QueryNode.ExtraConfig.Data = map[string]interface{}{
+		"queryNode": map[string]interface{}{
+			"cache": map[string]interface{}{
+				"memoryLimit": 1024,
+			},
+		},
+	}
+
+	t.Run("create for querynode only", func(t *testing.T) {
+		mockClient.EXPECT().
+			Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr")).AnyTimes()
+		mockClient.EXPECT().
+			Get(gomock.Any(), NamespacedName(mc.Namespace, "mc-querynode"), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+		var created *corev1.ConfigMap
+		mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, obj interface{}, _ ...interface{}) error {
+				created = obj.(*corev1.ConfigMap)
+				return nil
+			})
+		assert.NoError(t, r.ReconcileComponentConfigMaps(ctx, mc))
+
+		assert.Equal(t, "mc-querynode", created.Name)
+		assert.Equal(t, newComponentConfigMapLabels(mc.Name, QueryNodeName), created.Labels)
+		conf := map[string]interface{}{}
+		assert.NoError(t, yaml.Unmarshal([]byte(created.Data[UserYaml]), &conf))
+		queryNode := conf["queryNode"].(map[string]interface{})
+		// composed with the shared config
+		assert.Equal(t, 1000.0, queryNode["gracefulTime"])
+		assert.Equal(t, 1024.0, queryNode["cache"].(map[string]interface{})["memoryLimit"])
+		assert.Contains(t, conf, "etcd")
+	})
+
+	t.Run("mounted on querynode only", func(t *testing.T) {
+		getConfigMapName := func(component MilvusComponent) string {
+			updater := newMilvusDeploymentUpdater(mc, r.Scheme, component)
+			template := &corev1.PodTemplateSpec{}
+			template.Annotations = map[string]string{}
+			updateBuiltInVolumes(template, updater)
+			for _, volume := range template.Spec.Volumes {
+				if volume.Name == MilvusConfigVolumeName {
+					return volume.ConfigMap.Name
+				}
+			}
+			return ""
+		}
+		assert.Equal(t, "mc-querynode", getConfigMapName(QueryNode))
+		assert.Equal(t, "mc", getConfigMapName(DataNode))
+		assert.Equal(t, "mc", getConfigMapName(Proxy))
+	})
+}
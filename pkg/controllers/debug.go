@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultDebugBindAddress is the default address of the pprof & debug endpoints, only reachable in the pod
+const DefaultDebugBindAddress = "localhost:6060"
+
+// ReconcileStat is the statistics of the reconciles of a resource
+type ReconcileStat struct {
+	Kind                 string    `json:"kind"`
+	Namespace            string    `json:"namespace"`
+	Name                 string    `json:"name"`
+	Count                int64     `json:"count"`
+	ErrorCount           int64     `json:"errorCount"`
+	TotalDurationSeconds float64   `json:"totalDurationSeconds"`
+	MaxDurationSeconds   float64   `json:"maxDurationSeconds"`
+	LastDurationSeconds  float64   `json:"lastDurationSeconds"`
+	LastReconcileTime    time.Time `json:"lastReconcileTime"`
+}
+
+// ReconcileStats records the reconcile statistics per resource in memory
+type ReconcileStats struct {
+	mu    sync.Mutex
+	stats map[string]*ReconcileStat
+}
+
+func NewReconcileStats() *ReconcileStats {
+	return &ReconcileStats{stats: map[string]*ReconcileStat{}}
+}
+
+var defaultReconcileStats = NewReconcileStats()
+
+// EnableReconcileStats enables the statistics of the reconciles served by /debug/reconcile-stats,
+// it's set before the reconcilers are set up
+var EnableReconcileStats = false
+
+// Observe records a reconcile of the resource
+func (s *ReconcileStats) Observe(kind string, key types.NamespacedName, start time.Time, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statKey := kind + "/" + key.String()
+	stat, ok := s.stats[statKey]
+	if !ok {
+		stat = &ReconcileStat{Kind: kind, Namespace: key.Namespace, Name: key.Name}
+		s.stats[statKey] = stat
+	}
+	seconds := duration.Seconds()
+	stat.Count++
+	if err != nil {
+		stat.ErrorCount++
+	}
+	stat.TotalDurationSeconds += seconds
+	stat.LastDurationSeconds = seconds
+	if seconds > stat.MaxDurationSeconds {
+		stat.MaxDurationSeconds = seconds
+	}
+	stat.LastReconcileTime = start
+}
+
+// Forget removes the statistics of the deleted resource
+func (s *ReconcileStats) Forget(kind string, key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.stats, kind+"/"+key.String())
+}
+
+// List returns a copy of the statistics sorted by kind, namespace & name
+func (s *ReconcileStats) List() []ReconcileStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]ReconcileStat, 0, len(s.stats))
+	for _, stat := range s.stats {
+		ret = append(ret, *stat)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Kind != ret[j].Kind {
+			return ret[i].Kind < ret[j].Kind
+		}
+		if ret[i].Namespace != ret[j].Namespace {
+			return ret[i].Namespace < ret[j].Namespace
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// ServeHTTP writes the statistics in JSON
+func (s *ReconcileStats) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"reconciles": s.List(),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// reconcilerWithStats records the statistics of each reconcile of the wrapped reconciler
+type reconcilerWithStats struct {
+	kind       string
+	stats      *ReconcileStats
+	reconciler reconcile.Reconciler
+}
+
+// withReconcileStats wraps the reconciler to record its statistics if EnableReconcileStats is set
+func withReconcileStats(kind string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if !EnableReconcileStats {
+		return reconciler
+	}
+	return &reconcilerWithStats{kind: kind, stats: defaultReconcileStats, reconciler: reconciler}
+}
+
+type forgetReconcileStatsKey struct{}
+
+// forgetReconcileStats drops the statistics of the resource reconciled with the ctx after the reconcile,
+// the reconcilers call it when the resource is deleted
+func forgetReconcileStats(ctx context.Context) {
+	if forget, ok := ctx.Value(forgetReconcileStatsKey{}).(*bool); ok {
+		*forget = true
+	}
+}
+
+func (r *reconcilerWithStats) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	start := time.Now()
+	var forget bool
+	ret, err := r.reconciler.Reconcile(context.WithValue(ctx, forgetReconcileStatsKey{}, &forget), req)
+	if forget {
+		r.stats.Forget(r.kind, req.NamespacedName)
+		return ret, err
+	}
+	r.stats.Observe(r.kind, req.NamespacedName, start, time.Since(start), err)
+	return ret, err
+}
+
+// NewDebugHandler returns the handler of the pprof endpoints & /debug/reconcile-stats
+func NewDebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/reconcile-stats", defaultReconcileStats)
+	return mux
+}
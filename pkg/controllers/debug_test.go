@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileStats(t *testing.T) {
+	stats := NewReconcileStats()
+	key := types.NamespacedName{Namespace: "ns", Name: "mc"}
+	now := time.Now()
+	stats.Observe("Milvus", key, now, time.Second, nil)
+	stats.Observe("Milvus", key, now.Add(time.Minute), 3*time.Second, errors.New("failed"))
+	stats.Observe("MilvusUpgrade", key, now, time.Second, nil)
+
+	list := stats.List()
+	assert.Len(t, list, 2)
+	assert.Equal(t, "Milvus", list[0].Kind)
+	assert.Equal(t, int64(2), list[0].Count)
+	assert.Equal(t, int64(1), list[0].ErrorCount)
+	assert.Equal(t, 4.0, list[0].TotalDurationSeconds)
+	assert.Equal(t, 3.0, list[0].MaxDurationSeconds)
+	assert.Equal(t, 3.0, list[0].LastDurationSeconds)
+	assert.True(t, now.Add(time.Minute).Equal(list[0].LastReconcileTime))
+	assert.Equal(t, "MilvusUpgrade", list[1].Kind)
+
+	stats.Forget("Milvus", key)
+	list = stats.List()
+	assert.Len(t, list, 1)
+	assert.Equal(t, "MilvusUpgrade", list[0].Kind)
+}
+
+func TestWithReconcileStats(t *testing.T) {
+	bak := defaultReconcileStats
+	defer func() { defaultReconcileStats = bak }()
+	defaultReconcileStats = NewReconcileStats()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mc"}}
+	deleted := false
+	inner := reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if deleted {
+			forgetReconcileStats(ctx)
+		}
+		return ctrl.Result{}, nil
+	})
+
+	t.Run("not wrapped when disabled", func(t *testing.T) {
+		r := withReconcileStats("Milvus", inner)
+		_, err := r.Reconcile(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Empty(t, defaultReconcileStats.List())
+	})
+
+	EnableReconcileStats = true
+	defer func() { EnableReconcileStats = false }()
+	r := withReconcileStats("Milvus", inner)
+
+	t.Run("observed when enabled", func(t *testing.T) {
+		_, err := r.Reconcile(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Len(t, defaultReconcileStats.List(), 1)
+	})
+
+	t.Run("forgotten when deleted", func(t *testing.T) {
+		deleted = true
+		_, err := r.Reconcile(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Empty(t, defaultReconcileStats.List())
+	})
+}
+
+func TestNewDebugHandler_ReconcileStats(t *testing.T) {
+	bak := defaultReconcileStats
+	defer func() { defaultReconcileStats = bak }()
+	defaultReconcileStats = NewReconcileStats()
+	EnableReconcileStats = true
+	defer func() { EnableReconcileStats = false }()
+
+	r := withReconcileStats("Milvus", reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		return ctrl.Result{}, nil
+	}))
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mc"}})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(NewDebugHandler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/debug/reconcile-stats")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	body := map[string][]map[string]interface{}{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body["reconciles"], 1)
+	stat := body["reconciles"][0]
+	for _, field := range []string{"kind", "namespace", "name", "count", "errorCount",
+		"totalDurationSeconds", "maxDurationSeconds", "lastDurationSeconds", "lastReconcileTime"} {
+		assert.Contains(t, stat, field)
+	}
+	assert.Equal(t, "Milvus", stat["kind"])
+	assert.Equal(t, 1.0, stat["count"])
+
+	resp, err = http.Get(server.URL + "/debug/pprof/")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -8,9 +8,13 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/disk"
@@ -19,10 +23,12 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/yaml"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
 	"github.com/zilliztech/milvus-operator/pkg/helm"
 	"github.com/zilliztech/milvus-operator/pkg/helm/values"
+	"github.com/zilliztech/milvus-operator/pkg/util"
 )
 
 //go:generate mockgen -package=controllers -source=dependencies.go -destination=dependencies_mock.go HelmReconciler
@@ -46,18 +52,26 @@ type HelmReconciler interface {
 type Chart = string
 type Values = map[string]interface{}
 
+// DiscoveryCacheTTL is the default TTL of the cached discovery client used by helm
+var DiscoveryCacheTTL = 45 * time.Minute
+
+// newCachedDiscoveryClientForConfig creates the disk cached discovery client, it's a variable for testing
+var newCachedDiscoveryClientForConfig = disk.NewCachedDiscoveryClientForConfig
+
 // LocalHelmReconciler implements HelmReconciler at local
 type LocalHelmReconciler struct {
-	helmSettings *cli.EnvSettings
-	logger       logr.Logger
-	mgr          manager.Manager
+	helmSettings      *cli.EnvSettings
+	logger            logr.Logger
+	mgr               manager.Manager
+	discoveryCacheTTL time.Duration
 }
 
 func MustNewLocalHelmReconciler(helmSettings *cli.EnvSettings, logger logr.Logger, mgr manager.Manager) *LocalHelmReconciler {
 	return &LocalHelmReconciler{
-		helmSettings: helmSettings,
-		logger:       logger,
-		mgr:          mgr,
+		helmSettings:      helmSettings,
+		logger:            logger,
+		mgr:               mgr,
+		discoveryCacheTTL: DiscoveryCacheTTL,
 	}
 }
 
@@ -69,7 +83,7 @@ func (l LocalHelmReconciler) NewHelmCfg(namespace string) *action.Configuration
 
 	// cfg.Init will never return err, only panic if bad driver
 	_ = cfg.Init(
-		getRESTClientGetterFromClient(l.helmSettings, namespace, l.mgr),
+		getRESTClientGetterFromClient(l.helmSettings, namespace, l.mgr, l.discoveryCacheTTL),
 		namespace,
 		os.Getenv("HELM_DRIVER"),
 		helmLogger,
@@ -78,18 +92,20 @@ func (l LocalHelmReconciler) NewHelmCfg(namespace string) *action.Configuration
 	return cfg
 }
 
-func getRESTClientGetterFromClient(env *cli.EnvSettings, namespace string, mgr manager.Manager) genericclioptions.RESTClientGetter {
+func getRESTClientGetterFromClient(env *cli.EnvSettings, namespace string, mgr manager.Manager, discoveryCacheTTL time.Duration) genericclioptions.RESTClientGetter {
 	return &clientRESTClientGetter{
-		namespace:  namespace,
-		kubeConfig: env.KubeConfig,
-		mgr:        mgr,
+		namespace:         namespace,
+		kubeConfig:        env.KubeConfig,
+		mgr:               mgr,
+		discoveryCacheTTL: discoveryCacheTTL,
 	}
 }
 
 type clientRESTClientGetter struct {
-	namespace  string
-	kubeConfig string
-	mgr        manager.Manager
+	namespace         string
+	kubeConfig        string
+	mgr               manager.Manager
+	discoveryCacheTTL time.Duration
 }
 
 func (c *clientRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
@@ -103,11 +119,11 @@ func (c *clientRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryI
 		return nil, err
 	}
 
-	return disk.NewCachedDiscoveryClientForConfig(
+	return newCachedDiscoveryClientForConfig(
 		config,
 		"",
 		"",
-		45*time.Minute,
+		c.discoveryCacheTTL,
 	)
 }
 
@@ -135,7 +151,45 @@ func (c *clientRESTClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig
 }
 
 func IsPulsarChartPath(chartPath string) bool {
-	return chartPath == helm.GetChartPathByName(Pulsar)
+	return chartPath == helm.GetChartPathByName(Pulsar) ||
+		chartPath == helm.GetChartPathByName(values.PulsarV3)
+}
+
+// chartInitializeFields are the value fields of the flag to initialize the dependency cluster, by chart path.
+// the flag is set true on install and false on subsequent updates
+var chartInitializeFields = map[string][]string{
+	helm.GetChartPathByName(Pulsar):          {"initialize"},
+	helm.GetChartPathByName(values.PulsarV3): {"components", "initialize"},
+}
+
+// GetChartInitializeFields returns the initialize flag fields of the chart, nil if the chart has no such flag
+func GetChartInitializeFields(chartPath string) []string {
+	return chartInitializeFields[chartPath]
+}
+
+func setInitializeFlag(request *helm.ChartRequest, fields []string, initialize bool) {
+	if len(fields) < 1 {
+		return
+	}
+	if request.Values == nil {
+		request.Values = Values{}
+	}
+	util.SetValue(request.Values, initialize, fields...)
+}
+
+// removeInitializeFlag removes the initialize flag and the parent values left empty by the removal
+func removeInitializeFlag(vals Values, fields []string) {
+	if len(fields) < 1 {
+		return
+	}
+	util.DeleteValue(vals, fields...)
+	for i := len(fields) - 1; i > 0; i-- {
+		parent, found, _ := unstructured.NestedMap(vals, fields[:i]...)
+		if !found || len(parent) > 0 {
+			return
+		}
+		util.DeleteValue(vals, fields[:i]...)
+	}
 }
 
 // ReconcileHelm reconciles Helm releases
@@ -147,11 +201,11 @@ func (l LocalHelmReconciler) Reconcile(ctx context.Context, request helm.ChartRe
 		return err
 	}
 
+	initializeFields := GetChartInitializeFields(request.Chart)
 	if !exist {
-		if request.Chart == helm.GetChartPathByName(Pulsar) {
-			request.Values["initialize"] = true
-		}
+		setInitializeFlag(&request, initializeFields, true)
 		l.logger.Info("helm install values", "values", request.Values)
+		recordDependencyAction(ctx, request.ReleaseName, DependencyActionInstall)
 		return helm.Install(cfg, request)
 	}
 
@@ -165,9 +219,7 @@ func (l LocalHelmReconciler) Reconcile(ctx context.Context, request helm.ChartRe
 		return err
 	}
 
-	if request.Chart == helm.GetChartPathByName(Pulsar) {
-		delete(vals, "initialize")
-	}
+	removeInitializeFlag(vals, initializeFields)
 
 	deepEqual := reflect.DeepEqual(vals, request.Values)
 	needUpdate := helm.NeedUpdate(status)
@@ -175,15 +227,14 @@ func (l LocalHelmReconciler) Reconcile(ctx context.Context, request helm.ChartRe
 		return nil
 	}
 
-	if request.Chart == helm.GetChartPathByName(Pulsar) {
-		request.Values["initialize"] = false
-	}
+	setInitializeFlag(&request, initializeFields, false)
 
 	l.logger.Info("update helm", "namespace", request.Namespace, "release", request.ReleaseName, "needUpdate", needUpdate, "deepEqual", deepEqual)
 	if !deepEqual {
 		l.logger.Info("update helm values", "old", vals, "new", request.Values)
 	}
 
+	recordDependencyAction(ctx, request.ReleaseName, DependencyActionUpgrade)
 	return helm.Update(cfg, request)
 }
 
@@ -199,16 +250,95 @@ func (l *LocalHelmReconciler) GetValues(namespace, release string) (map[string]i
 	return helm.GetValues(cfg, release)
 }
 
+// getHelmValuesFrom reads the helm values from the valuesFrom source
+func (r *MilvusReconciler) getHelmValuesFrom(ctx context.Context, namespace string, valuesFrom v1beta1.HelmValuesFrom) (map[string]interface{}, error) {
+	data := valuesFrom.Inline
+	if valuesFrom.ConfigMapKeyRef != nil {
+		configmap := &corev1.ConfigMap{}
+		key := types.NamespacedName{Namespace: namespace, Name: valuesFrom.ConfigMapKeyRef.Name}
+		err := r.Get(ctx, key, configmap)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get values configmap[%s]", valuesFrom.ConfigMapKeyRef.Name)
+		}
+		var found bool
+		data, found = configmap.Data[valuesFrom.ConfigMapKeyRef.Key]
+		if !found {
+			return nil, errors.Errorf("key[%s] not found in values configmap[%s]", valuesFrom.ConfigMapKeyRef.Key, valuesFrom.ConfigMapKeyRef.Name)
+		}
+	}
+	ret := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(data), &ret)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal values")
+	}
+	return ret, nil
+}
+
+// removeDefaultValues returns a copy of values without the fields equal to the default values
+func removeDefaultValues(values, defaults map[string]interface{}) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for k, v := range values {
+		defaultV, exist := defaults[k]
+		if !exist {
+			ret[k] = v
+			continue
+		}
+		subValues, isMap := v.(map[string]interface{})
+		subDefaults, isDefaultMap := defaultV.(map[string]interface{})
+		if isMap && isDefaultMap {
+			if sub := removeDefaultValues(subValues, subDefaults); len(sub) > 0 {
+				ret[k] = sub
+			}
+			continue
+		}
+		if !reflect.DeepEqual(v, defaultV) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// mergeHelmValuesFrom merges the values from valuesFrom into the request if it's set.
+// the precedence is: spec.values > valuesFrom > chart defaults.
+// the chart defaults are merged into spec.values by the webhook, so a spec value equal to the chart default is considered not set
+func (r *MilvusReconciler) mergeHelmValuesFrom(ctx context.Context, mc v1beta1.Milvus, dep values.DependencyKind, request *helm.ChartRequest) error {
+	inCluster := reflect.ValueOf(mc.Spec.Dep).FieldByName(string(dep)).
+		FieldByName("InCluster").Interface().(*v1beta1.InClusterConfig)
+	if inCluster == nil || inCluster.ValuesFrom == nil {
+		return nil
+	}
+	valuesFrom, err := r.getHelmValuesFrom(ctx, mc.Namespace, *inCluster.ValuesFrom)
+	if err != nil {
+		return errors.Wrapf(err, "get %s values from source", dep)
+	}
+	defaults := values.GetDefaultValuesProvider().GetDefaultValues(dep, inCluster.ChartVersion)
+	merged := util.DeepCopyValues(defaults)
+	if merged == nil {
+		merged = map[string]interface{}{}
+	}
+	util.MergeValues(merged, valuesFrom)
+	util.MergeValues(merged, removeDefaultValues(util.DeepCopyValues(request.Values), defaults))
+	request.Values = merged
+	return nil
+}
+
 func (r *MilvusReconciler) ReconcileEtcd(ctx context.Context, mc v1beta1.Milvus) error {
-	if mc.Spec.Dep.Etcd.External {
+	if !mc.Spec.Dep.IsManageDependencies() || mc.Spec.Dep.Etcd.External {
 		return nil
 	}
 	request := helm.GetChartRequest(mc, values.DependencyKindEtcd, Etcd)
+	err := r.mergeHelmValuesFrom(ctx, mc, values.DependencyKindEtcd, &request)
+	if err != nil {
+		return err
+	}
 
 	return r.helmReconciler.Reconcile(ctx, request)
 }
 
 func (r *MilvusReconciler) ReconcileMsgStream(ctx context.Context, mc v1beta1.Milvus) error {
+	if !mc.Spec.Dep.IsManageDependencies() {
+		return nil
+	}
 	switch mc.Spec.Dep.MsgStreamType {
 	case v1beta1.MsgStreamTypeKafka:
 		return r.ReconcileKafka(ctx, mc)
@@ -225,6 +355,10 @@ func (r *MilvusReconciler) ReconcileKafka(ctx context.Context, mc v1beta1.Milvus
 		return nil
 	}
 	request := helm.GetChartRequest(mc, values.DependencyKindKafka, Kafka)
+	err := r.mergeHelmValuesFrom(ctx, mc, values.DependencyKindKafka, &request)
+	if err != nil {
+		return err
+	}
 
 	return r.helmReconciler.Reconcile(ctx, request)
 }
@@ -234,24 +368,57 @@ func (r *MilvusReconciler) ReconcilePulsar(ctx context.Context, mc v1beta1.Milvu
 		return nil
 	}
 	request := helm.GetChartRequest(mc, values.DependencyKindPulsar, Pulsar)
+	err := r.mergeHelmValuesFrom(ctx, mc, values.DependencyKindPulsar, &request)
+	if err != nil {
+		return err
+	}
 
-	return r.helmReconciler.Reconcile(ctx, request)
+	if err := r.helmReconciler.Reconcile(ctx, request); err != nil {
+		return err
+	}
+	return r.cleanupPulsarInitJobs(ctx, mc, request.ReleaseName)
 }
 
 func (r *MilvusReconciler) ReconcileMinio(ctx context.Context, mc v1beta1.Milvus) error {
-	if mc.Spec.Dep.Storage.External {
+	if !mc.Spec.Dep.IsManageDependencies() || mc.Spec.Dep.Storage.External {
 		return nil
 	}
 	request := helm.GetChartRequest(mc, values.DependencyKindStorage, Minio)
+	err := r.mergeHelmValuesFrom(ctx, mc, values.DependencyKindStorage, &request)
+	if err != nil {
+		return err
+	}
+	setMinioModeValues(mc.Spec.Dep.Storage.InCluster, &request)
 
 	return r.helmReconciler.Reconcile(ctx, request)
 }
 
+// setMinioModeValues sets the mode & replicas in spec over the values of the request
+func setMinioModeValues(inCluster *v1beta1.InClusterConfig, request *helm.ChartRequest) {
+	if inCluster == nil || (inCluster.Mode == "" && inCluster.Replicas == nil) {
+		return
+	}
+	request.Values = util.DeepCopyValues(request.Values)
+	if request.Values == nil {
+		request.Values = map[string]interface{}{}
+	}
+	if inCluster.Mode != "" {
+		request.Values["mode"] = string(inCluster.Mode)
+	}
+	if inCluster.Replicas != nil {
+		request.Values["replicas"] = int64(*inCluster.Replicas)
+	}
+}
+
 func (r *MilvusReconciler) ReconcileTei(ctx context.Context, mc v1beta1.Milvus) error {
-	if !mc.Spec.Dep.Tei.Enabled {
+	if !mc.Spec.Dep.IsManageDependencies() || !mc.Spec.Dep.Tei.Enabled {
 		return nil
 	}
 	request := helm.GetChartRequest(mc, values.DependencyKindTei, Tei)
+	err := r.mergeHelmValuesFrom(ctx, mc, values.DependencyKindTei, &request)
+	if err != nil {
+		return err
+	}
 
 	return r.helmReconciler.Reconcile(ctx, request)
 }
@@ -5,16 +5,24 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/prashantv/gostub"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
 	"github.com/zilliztech/milvus-operator/pkg/helm"
+	"github.com/zilliztech/milvus-operator/pkg/helm/values"
+	"github.com/zilliztech/milvus-operator/pkg/util"
 )
 
 func TestLocalHelmReconciler_ReconcilePanic(t *testing.T) {
@@ -40,6 +48,37 @@ func TestLocalHelmReconciler_ReconcilePanic(t *testing.T) {
 	assert.Panics(t, func() { rec.Reconcile(ctx, request) })
 }
 
+func TestClientRESTClientGetter_ToDiscoveryClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockManager := NewMockManager(mockCtrl)
+	config := &rest.Config{Host: "localhost"}
+	mockManager.EXPECT().GetConfig().Return(config).AnyTimes()
+
+	var gotTTL time.Duration
+	stubs := gostub.Stub(&newCachedDiscoveryClientForConfig, func(c *rest.Config, discoveryCacheDir, httpCacheDir string, ttl time.Duration) (*disk.CachedDiscoveryClient, error) {
+		assert.Equal(t, config, c)
+		gotTTL = ttl
+		return nil, nil
+	})
+	defer stubs.Reset()
+
+	t.Run("default ttl", func(t *testing.T) {
+		rec := MustNewLocalHelmReconciler(cli.New(), ctrl.Log.WithName("test"), mockManager)
+		getter := getRESTClientGetterFromClient(rec.helmSettings, "ns", rec.mgr, rec.discoveryCacheTTL)
+		_, err := getter.ToDiscoveryClient()
+		assert.NoError(t, err)
+		assert.Equal(t, 45*time.Minute, gotTTL)
+	})
+
+	t.Run("custom ttl", func(t *testing.T) {
+		getter := getRESTClientGetterFromClient(cli.New(), "ns", mockManager, time.Minute)
+		_, err := getter.ToDiscoveryClient()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, gotTTL)
+	})
+}
+
 func TestLocalHelmReconciler_Reconcile(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -136,6 +175,97 @@ func TestLocalHelmReconciler_Reconcile(t *testing.T) {
 		err := rec.Reconcile(ctx, request)
 		assert.NoError(t, err)
 	})
+
+	t.Run("not existed, install pulsar-v3", func(t *testing.T) {
+		request.Chart = helm.GetChartPathByName(values.PulsarV3)
+		request.Values = make(map[string]interface{})
+		mockHelm.EXPECT().
+			ReleaseExist(gomock.Any(), gomock.Any()).
+			Return(false, nil)
+		mockHelm.EXPECT().
+			Install(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(cfg *action.Configuration, request helm.ChartRequest) error {
+				initialize, found := util.GetBoolValue(request.Values, "components", "initialize")
+				assert.True(t, found)
+				assert.True(t, initialize)
+				assert.NotContains(t, request.Values, "initialize")
+				return nil
+			})
+		err := rec.Reconcile(ctx, request)
+		assert.NoError(t, err)
+	})
+
+	t.Run("existed, pulsar-v3 not need update", func(t *testing.T) {
+		request.Chart = helm.GetChartPathByName(values.PulsarV3)
+		request.Values = map[string]interface{}{"val2": true}
+		mockHelm.EXPECT().
+			ReleaseExist(gomock.Any(), gomock.Any()).
+			Return(true, nil)
+		mockHelm.EXPECT().GetValues(gomock.Any(), gomock.Any()).Return(map[string]interface{}{
+			"val2": true,
+			"components": map[string]interface{}{
+				"initialize": true,
+			},
+		}, nil)
+		mockHelm.EXPECT().GetStatus(gomock.Any(), gomock.Any()).Return(release.StatusDeployed, nil)
+		err := rec.Reconcile(ctx, request)
+		assert.NoError(t, err)
+	})
+
+	t.Run("existed, pulsar-v3 update", func(t *testing.T) {
+		request.Chart = helm.GetChartPathByName(values.PulsarV3)
+		request.Values = map[string]interface{}{
+			"val2": true,
+			"components": map[string]interface{}{
+				"zookeeper": true,
+			},
+		}
+		mockHelm.EXPECT().
+			ReleaseExist(gomock.Any(), gomock.Any()).
+			Return(true, nil)
+		mockHelm.EXPECT().GetValues(gomock.Any(), gomock.Any()).Return(map[string]interface{}{
+			"components": map[string]interface{}{
+				"initialize": true,
+				"zookeeper":  true,
+			},
+		}, nil)
+		mockHelm.EXPECT().GetStatus(gomock.Any(), gomock.Any()).Return(release.StatusDeployed, nil)
+		mockHelm.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(cfg *action.Configuration, request helm.ChartRequest) error {
+				initialize, found := util.GetBoolValue(request.Values, "components", "initialize")
+				assert.True(t, found)
+				assert.False(t, initialize)
+				zookeeper, _ := util.GetBoolValue(request.Values, "components", "zookeeper")
+				assert.True(t, zookeeper)
+				assert.NotContains(t, request.Values, "initialize")
+				return nil
+			})
+		err := rec.Reconcile(ctx, request)
+		assert.NoError(t, err)
+	})
+
+	t.Run("existed, other chart not toggled", func(t *testing.T) {
+		request.Chart = helm.GetChartPathByName(Etcd)
+		request.Values = map[string]interface{}{"val2": true}
+		mockHelm.EXPECT().
+			ReleaseExist(gomock.Any(), gomock.Any()).
+			Return(true, nil)
+		mockHelm.EXPECT().GetValues(gomock.Any(), gomock.Any()).Return(map[string]interface{}{}, nil)
+		mockHelm.EXPECT().GetStatus(gomock.Any(), gomock.Any()).Return(release.StatusDeployed, nil)
+		mockHelm.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(cfg *action.Configuration, request helm.ChartRequest) error {
+				assert.Equal(t, map[string]interface{}{"val2": true}, request.Values)
+				return nil
+			})
+		err := rec.Reconcile(ctx, request)
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetChartInitializeFields(t *testing.T) {
+	assert.Equal(t, []string{"initialize"}, GetChartInitializeFields(helm.GetChartPathByName(Pulsar)))
+	assert.Equal(t, []string{"components", "initialize"}, GetChartInitializeFields(helm.GetChartPathByName(values.PulsarV3)))
+	assert.Nil(t, GetChartInitializeFields(helm.GetChartPathByName(Kafka)))
 }
 
 func TestClusterReconciler_ReconcileDeps(t *testing.T) {
@@ -200,3 +330,170 @@ func TestClusterReconciler_ReconcileDeps(t *testing.T) {
 		m.Spec.Dep.Tei.Enabled = false
 	})
 }
+
+func TestClusterReconciler_ReconcileDeps_NotManaged(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	m := env.Inst
+	mockHelm := NewMockHelmReconciler(env.Ctrl)
+	r.helmReconciler = mockHelm
+	icc := new(v1beta1.InClusterConfig)
+
+	m.Spec.Dep.ManageDependencies = boolPtr(false)
+	m.Spec.Dep.Etcd.InCluster = icc
+	m.Spec.Dep.Storage.InCluster = icc
+	m.Spec.Dep.Pulsar.InCluster = icc
+	m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+	m.Spec.Dep.Tei.Enabled = true
+	m.Spec.Dep.Tei.InCluster = icc
+
+	// no helm reconcile expected
+	mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).Times(0)
+	assert.NoError(t, r.ReconcileEtcd(ctx, m))
+	assert.NoError(t, r.ReconcileMinio(ctx, m))
+	assert.NoError(t, r.ReconcileMsgStream(ctx, m))
+	assert.NoError(t, r.ReconcileTei(ctx, m))
+}
+
+func TestMilvusReconciler_ReconcileMinio_Mode(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	m := env.Inst
+	mockHelm := NewMockHelmReconciler(env.Ctrl)
+	r.helmReconciler = mockHelm
+
+	m.Spec.Dep.Storage.InCluster.Mode = v1beta1.MinioModeDistributed
+	m.Spec.Dep.Storage.InCluster.Replicas = int32Ptr(4)
+	mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request helm.ChartRequest) error {
+			assert.Equal(t, "distributed", request.Values["mode"])
+			assert.Equal(t, int64(4), request.Values["replicas"])
+			return nil
+		})
+	assert.NoError(t, r.ReconcileMinio(ctx, m))
+	// spec values not changed
+	assert.Equal(t, "standalone", m.Spec.Dep.Storage.InCluster.Values.Data["mode"])
+
+	t.Run("mode not set", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Dep.Storage.InCluster.Mode = ""
+		m.Spec.Dep.Storage.InCluster.Replicas = nil
+		mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request helm.ChartRequest) error {
+				assert.Equal(t, "standalone", request.Values["mode"])
+				return nil
+			})
+		assert.NoError(t, r.ReconcileMinio(ctx, m))
+	})
+}
+
+func TestMilvusReconciler_ReconcilePulsar_ValuesFrom(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	m := env.Inst
+	mockHelm := NewMockHelmReconciler(env.Ctrl)
+	r.helmReconciler = mockHelm
+
+	m.Spec.Dep.Pulsar.InCluster = &v1beta1.InClusterConfig{
+		Values: v1beta1.Values{
+			Data: map[string]interface{}{
+				"broker": map[string]interface{}{
+					"replicaCount": 2,
+				},
+			},
+		},
+	}
+	const valuesYaml = `
+broker:
+  replicaCount: 3
+  configData:
+    maxMessageSize: "10485760"
+bookkeeper:
+  replicaCount: 4
+`
+	expectValues := map[string]interface{}{
+		"broker": map[string]interface{}{
+			"replicaCount": float64(2),
+			"configData": map[string]interface{}{
+				"maxMessageSize": "10485760",
+			},
+		},
+		"bookkeeper": map[string]interface{}{
+			"replicaCount": float64(4),
+		},
+	}
+
+	t.Run("inline values merged, spec takes precedence", func(t *testing.T) {
+		m.Spec.Dep.Pulsar.InCluster.ValuesFrom = &v1beta1.HelmValuesFrom{Inline: valuesYaml}
+		mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request helm.ChartRequest) error {
+				assert.Equal(t, expectValues, request.Values)
+				return nil
+			})
+		assert.NoError(t, r.ReconcilePulsar(ctx, m))
+	})
+
+	m.Spec.Dep.Pulsar.InCluster.ValuesFrom = &v1beta1.HelmValuesFrom{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "pulsar-values"},
+			Key:                  "values.yaml",
+		},
+	}
+	t.Run("configmap values merged", func(t *testing.T) {
+		env.MockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				assert.Equal(t, "pulsar-values", key.Name)
+				obj.(*corev1.ConfigMap).Data = map[string]string{"values.yaml": valuesYaml}
+				return nil
+			})
+		mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request helm.ChartRequest) error {
+				assert.Equal(t, expectValues, request.Values)
+				return nil
+			})
+		assert.NoError(t, r.ReconcilePulsar(ctx, m))
+	})
+
+	t.Run("configmap key not found", func(t *testing.T) {
+		env.MockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		assert.Error(t, r.ReconcilePulsar(ctx, m))
+	})
+}
+
+func TestRemoveDefaultValues(t *testing.T) {
+	defaults := map[string]interface{}{
+		"broker": map[string]interface{}{
+			"replicaCount": float64(1),
+			"image":        "pulsar",
+		},
+		"zookeeper": map[string]interface{}{
+			"replicaCount": float64(3),
+		},
+	}
+	values := map[string]interface{}{
+		"broker": map[string]interface{}{
+			"replicaCount": float64(2),
+			"image":        "pulsar",
+		},
+		"zookeeper": map[string]interface{}{
+			"replicaCount": float64(3),
+		},
+		"proxy": map[string]interface{}{
+			"enabled": false,
+		},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"broker": map[string]interface{}{
+			"replicaCount": float64(2),
+		},
+		"proxy": map[string]interface{}{
+			"enabled": false,
+		},
+	}, removeDefaultValues(values, defaults))
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+const defaultMinioRootPath = "files"
+
+// getEtcdRootPath returns the etcd root path of milvus, it defaults to the name of milvus
+func getEtcdRootPath(mc v1beta1.Milvus) string {
+	return GetStringValueWithDefault(mc.Spec.Conf.Data, mc.Name, "etcd", "rootPath")
+}
+
+func trimEndpointScheme(endpoint string) string {
+	endpoint = strings.TrimPrefix(endpoint, "http://")
+	return strings.TrimPrefix(endpoint, "https://")
+}
+
+// isSharingEtcd returns true if both milvus use the same root path of an external etcd
+func isSharingEtcd(a, b v1beta1.Milvus) bool {
+	if !a.Spec.Dep.Etcd.External || !b.Spec.Dep.Etcd.External {
+		return false
+	}
+	if getEtcdRootPath(a) != getEtcdRootPath(b) {
+		return false
+	}
+	endpoints := map[string]bool{}
+	for _, endpoint := range a.Spec.Dep.Etcd.Endpoints {
+		endpoints[trimEndpointScheme(endpoint)] = true
+	}
+	for _, endpoint := range b.Spec.Dep.Etcd.Endpoints {
+		if endpoints[trimEndpointScheme(endpoint)] {
+			return true
+		}
+	}
+	return false
+}
+
+// isSharingStorage returns true if both milvus use the same root path in the same bucket of an external storage
+func isSharingStorage(a, b v1beta1.Milvus) bool {
+	if !a.Spec.Dep.Storage.External || !b.Spec.Dep.Storage.External {
+		return false
+	}
+	if trimEndpointScheme(a.Spec.Dep.Storage.Endpoint) != trimEndpointScheme(b.Spec.Dep.Storage.Endpoint) {
+		return false
+	}
+	if GetMinioBucket(a.Spec.Conf.Data) != GetMinioBucket(b.Spec.Conf.Data) {
+		return false
+	}
+	rootPathA := GetStringValueWithDefault(a.Spec.Conf.Data, defaultMinioRootPath, "minio", "rootPath")
+	rootPathB := GetStringValueWithDefault(b.Spec.Conf.Data, defaultMinioRootPath, "minio", "rootPath")
+	return rootPathA == rootPathB
+}
+
+// GetDependencyConflictCondition lists the milvus in the cluster to detect
+// if any other milvus shares the same external etcd root path or storage bucket with mc
+func GetDependencyConflictCondition(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (v1beta1.MilvusCondition, error) {
+	milvusList := &v1beta1.MilvusList{}
+	err := cli.List(ctx, milvusList)
+	if err != nil {
+		return v1beta1.MilvusCondition{}, errors.Wrap(err, "list milvus failed")
+	}
+	var conflicts []string
+	for _, sibling := range milvusList.Items {
+		if sibling.Namespace == mc.Namespace && sibling.Name == mc.Name {
+			continue
+		}
+		if isSharingEtcd(mc, sibling) {
+			conflicts = append(conflicts, fmt.Sprintf("etcd shared with %s/%s", sibling.Namespace, sibling.Name))
+		}
+		if isSharingStorage(mc, sibling) {
+			conflicts = append(conflicts, fmt.Sprintf("storage shared with %s/%s", sibling.Namespace, sibling.Name))
+		}
+	}
+	if len(conflicts) > 0 {
+		return v1beta1.MilvusCondition{
+			Type:    v1beta1.DependencyConflict,
+			Status:  corev1.ConditionTrue,
+			Reason:  v1beta1.ReasonDependencyConflict,
+			Message: strings.Join(conflicts, "; "),
+		}, nil
+	}
+	return v1beta1.MilvusCondition{
+		Type:    v1beta1.DependencyConflict,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1beta1.ReasonNoDependencyConflict,
+		Message: "No other milvus shares the dependencies",
+	}, nil
+}
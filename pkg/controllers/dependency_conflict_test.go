@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestGetDependencyConflictCondition(t *testing.T) {
+	ctx := context.Background()
+	newExternalMilvus := func(namespace, name string) *v1beta1.Milvus {
+		mc := &v1beta1.Milvus{}
+		mc.Namespace = namespace
+		mc.Name = name
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.Etcd.Endpoints = []string{"etcd-0.etcd:2379", "etcd-1.etcd:2379"}
+		mc.Spec.Dep.Storage.External = true
+		mc.Spec.Dep.Storage.Endpoint = "minio.storage:9000"
+		mc.Spec.Conf.Data = map[string]interface{}{
+			"etcd": map[string]interface{}{
+				"rootPath": name,
+			},
+			"minio": map[string]interface{}{
+				"bucketName": name,
+			},
+		}
+		return mc
+	}
+
+	t.Run("conflicting pair", func(t *testing.T) {
+		mc1 := newExternalMilvus("ns1", "mc1")
+		mc2 := newExternalMilvus("ns2", "mc2")
+		mc2.Spec.Dep.Etcd.Endpoints = []string{"http://etcd-1.etcd:2379"}
+		mc2.Spec.Conf.Data = mc1.Spec.Conf.DeepCopy().Data
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc1, mc2).Build()
+
+		cond, err := GetDependencyConflictCondition(ctx, cli, *mc1)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.DependencyConflict, cond.Type)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+		assert.Equal(t, v1beta1.ReasonDependencyConflict, cond.Reason)
+		assert.Contains(t, cond.Message, "etcd shared with ns2/mc2")
+		assert.Contains(t, cond.Message, "storage shared with ns2/mc2")
+	})
+
+	t.Run("non-conflicting pair", func(t *testing.T) {
+		mc1 := newExternalMilvus("ns1", "mc1")
+		mc2 := newExternalMilvus("ns2", "mc2")
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc1, mc2).Build()
+
+		cond, err := GetDependencyConflictCondition(ctx, cli, *mc1)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonNoDependencyConflict, cond.Reason)
+	})
+
+	t.Run("in-cluster dependencies not conflicting", func(t *testing.T) {
+		mc1 := &v1beta1.Milvus{}
+		mc1.Namespace = "ns"
+		mc1.Name = "mc"
+		mc2 := mc1.DeepCopy()
+		mc2.Namespace = "ns2"
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc1, mc2).Build()
+
+		cond, err := GetDependencyConflictCondition(ctx, cli, *mc1)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	})
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/helm"
+)
+
+// dependencyRelease is the helm release of a dependency which can be changed to external
+type dependencyRelease struct {
+	name string
+	// external is true when the release is no longer needed, i.e. the dependency is external or disabled
+	external bool
+	// inCluster is true when the release is currently installed & managed by the operator
+	inCluster bool
+	// inClusterConfig decides whether the release & its pvcs are deleted when it's no longer needed
+	inClusterConfig *v1beta1.InClusterConfig
+}
+
+func getDependencyReleases(mc v1beta1.Milvus) []dependencyRelease {
+	dep := mc.Spec.Dep
+	return []dependencyRelease{
+		{name: mc.Name + "-" + Etcd, external: dep.Etcd.External, inCluster: !dep.Etcd.External,
+			inClusterConfig: dep.Etcd.InCluster},
+		{name: mc.Name + "-" + Minio, external: dep.Storage.External, inCluster: !dep.Storage.External,
+			inClusterConfig: dep.Storage.InCluster},
+		{name: mc.Name + "-" + Kafka, external: dep.Kafka.External,
+			inCluster:       dep.MsgStreamType == v1beta1.MsgStreamTypeKafka && !dep.Kafka.External,
+			inClusterConfig: dep.Kafka.InCluster},
+		{name: mc.Name + "-" + Pulsar, external: dep.Pulsar.External,
+			inCluster:       dep.MsgStreamType == v1beta1.MsgStreamTypePulsar && !dep.Pulsar.External,
+			inClusterConfig: dep.Pulsar.InCluster},
+		{name: mc.Name + "-" + Tei, external: !dep.Tei.Enabled, inCluster: dep.Tei.Enabled,
+			inClusterConfig: dep.Tei.InCluster},
+	}
+}
+
+// getInClusterDependencyReleases returns the names of the releases currently managed by the operator
+func getInClusterDependencyReleases(mc v1beta1.Milvus) []string {
+	var ret []string
+	for _, release := range getDependencyReleases(mc) {
+		if release.inCluster {
+			ret = append(ret, release.name)
+		}
+	}
+	return ret
+}
+
+// ReconcileExternalizedDependencies records the releases of the in-cluster dependencies in status,
+// and stops managing the recorded ones whose dependency is changed to external or disabled.
+// like Finalize, they're only uninstalled with DeletionPolicy Delete, and their pvcs deleted with PVCDeletion.
+// releases not recorded, e.g. installed by the user, are never uninstalled
+func (r *MilvusReconciler) ReconcileExternalizedDependencies(ctx context.Context, mc *v1beta1.Milvus) error {
+	if !mc.Spec.Dep.IsManageDependencies() {
+		return nil
+	}
+	recorded := sets.New(mc.Status.ManagedReleases...)
+	updated := recorded.Clone().Insert(getInClusterDependencyReleases(*mc)...)
+	for _, release := range getDependencyReleases(*mc) {
+		if !release.external || !recorded.Has(release.name) {
+			continue
+		}
+		inCluster := release.inClusterConfig
+		if inCluster == nil || inCluster.DeletionPolicy != v1beta1.DeletionPolicyDelete {
+			r.logger.Info("retain release of dependency changed to external", "namespace", mc.Namespace, "release", release.name)
+			updated.Delete(release.name)
+			continue
+		}
+		r.logger.Info("uninstall release of dependency changed to external", "namespace", mc.Namespace, "release", release.name, "pvcDeletion", inCluster.PVCDeletion)
+		recordDependencyAction(ctx, release.name, DependencyActionUninstall)
+		err := r.deleteDependencyRelease(ctx, r.helmReconciler.NewHelmCfg(mc.Namespace), mc.Namespace, release.name, inCluster.PVCDeletion)
+		if err != nil {
+			return errors.Wrapf(err, "uninstall release %s", release.name)
+		}
+		updated.Delete(release.name)
+	}
+	releases, err := r.getDependencyReleasesStatus(mc.Namespace, sets.List(updated))
+	if err != nil {
+		return err
+	}
+	if updated.Equal(recorded) && reflect.DeepEqual(releases, mc.Status.DependencyReleases) {
+		return nil
+	}
+	mc.Status.ManagedReleases = sets.List(updated)
+	mc.Status.DependencyReleases = releases
+	return errors.Wrap(r.Client.Status().Update(ctx, mc), "update managed releases")
+}
+
+// getDependencyReleasesStatus returns the chart & app versions of the releases, the ones not installed yet are skipped
+func (r *MilvusReconciler) getDependencyReleasesStatus(namespace string, names []string) ([]v1beta1.DependencyReleaseStatus, error) {
+	var ret []v1beta1.DependencyReleaseStatus
+	for _, name := range names {
+		rel, err := helm.GetRelease(r.helmReconciler.NewHelmCfg(namespace), name)
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "get release %s", name)
+		}
+		status := v1beta1.DependencyReleaseStatus{Name: name}
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			status.ChartVersion = rel.Chart.Metadata.Version
+			status.AppVersion = rel.Chart.Metadata.AppVersion
+		}
+		ret = append(ret, status)
+	}
+	return ret, nil
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/helm"
+)
+
+func TestMilvusReconciler_ReconcileExternalizedDependencies(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	mockHelm := helm.NewMockClient(env.Ctrl)
+	helm.SetDefaultClient(mockHelm)
+	mockStatusCli := NewMockK8sStatusClient(env.Ctrl)
+	mockHelm.EXPECT().GetRelease(gomock.Any(), gomock.Any()).Return(nil, driver.ErrReleaseNotFound).AnyTimes()
+
+	newMilvus := func() *v1beta1.Milvus {
+		mc := env.Inst.DeepCopy()
+		mc.Spec.Mode = v1beta1.MilvusModeCluster
+		mc.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+		return mc
+	}
+
+	t.Run("record in-cluster releases", func(t *testing.T) {
+		mc := newMilvus()
+		env.MockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mc-etcd", "mc-minio", "mc-pulsar"}, mc.Status.ManagedReleases)
+
+		// no change, no update
+		err = r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("retain release when changed to external by default", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Status.ManagedReleases = []string{"mc-etcd", "mc-minio", "mc-pulsar"}
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.Etcd.Endpoints = []string{"etcd:2379"}
+		mc.Spec.Dep.Etcd.InCluster = &v1beta1.InClusterConfig{DeletionPolicy: v1beta1.DeletionPolicyRetain}
+		env.MockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, obj interface{}, _ ...client.SubResourceUpdateOption) error {
+				assert.Equal(t, []string{"mc-minio", "mc-pulsar"}, obj.(*v1beta1.Milvus).Status.ManagedReleases)
+				return nil
+			})
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("uninstall release & delete pvcs with delete policy", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Status.ManagedReleases = []string{"mc-etcd", "mc-minio", "mc-pulsar"}
+		mc.Spec.Dep.Pulsar.External = true
+		mc.Spec.Dep.Pulsar.Endpoint = "pulsar:6650"
+		mc.Spec.Dep.Pulsar.InCluster = &v1beta1.InClusterConfig{DeletionPolicy: v1beta1.DeletionPolicyDelete, PVCDeletion: true}
+		mockHelm.EXPECT().Uninstall(gomock.Any(), "mc-pulsar").Return(nil)
+		env.MockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaimList{}), gomock.Any()).
+			DoAndReturn(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				list.(*corev1.PersistentVolumeClaimList).Items = []corev1.PersistentVolumeClaim{{}}
+				return nil
+			}).Times(2)
+		env.MockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PersistentVolumeClaim{})).Return(nil).Times(2)
+		env.MockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, obj interface{}, _ ...client.SubResourceUpdateOption) error {
+				assert.Equal(t, []string{"mc-etcd", "mc-minio"}, obj.(*v1beta1.Milvus).Status.ManagedReleases)
+				return nil
+			})
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("uninstall tei when disabled", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Status.ManagedReleases = []string{"mc-etcd", "mc-minio", "mc-pulsar", "mc-tei"}
+		mc.Spec.Dep.Tei.InCluster = &v1beta1.InClusterConfig{DeletionPolicy: v1beta1.DeletionPolicyDelete}
+		mockHelm.EXPECT().Uninstall(gomock.Any(), "mc-tei").Return(nil)
+		env.MockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mc-etcd", "mc-minio", "mc-pulsar"}, mc.Status.ManagedReleases)
+	})
+
+	t.Run("uninstall release when changed to external", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Status.ManagedReleases = []string{"mc-etcd", "mc-minio", "mc-pulsar"}
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.Etcd.Endpoints = []string{"etcd:2379"}
+		mc.Spec.Dep.Etcd.InCluster = &v1beta1.InClusterConfig{DeletionPolicy: v1beta1.DeletionPolicyDelete}
+		mockHelm.EXPECT().Uninstall(gomock.Any(), "mc-etcd").Return(nil)
+		env.MockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, obj interface{}, _ ...client.SubResourceUpdateOption) error {
+				assert.Equal(t, []string{"mc-minio", "mc-pulsar"}, obj.(*v1beta1.Milvus).Status.ManagedReleases)
+				return nil
+			})
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("uninstall failed keeps the record", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Status.ManagedReleases = []string{"mc-etcd", "mc-minio", "mc-pulsar"}
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.Etcd.InCluster = &v1beta1.InClusterConfig{DeletionPolicy: v1beta1.DeletionPolicyDelete}
+		mockHelm.EXPECT().Uninstall(gomock.Any(), "mc-etcd").Return(errMock)
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.Error(t, err)
+		assert.Contains(t, mc.Status.ManagedReleases, "mc-etcd")
+	})
+
+	t.Run("not touch releases not installed by operator", func(t *testing.T) {
+		mc := newMilvus()
+		// e.g. created with external etcd, the user owns release mc-etcd
+		mc.Status.ManagedReleases = []string{"mc-minio", "mc-pulsar"}
+		mc.Spec.Dep.Etcd.External = true
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("dependencies not managed", func(t *testing.T) {
+		mc := newMilvus()
+		mc.Status.ManagedReleases = []string{"mc-etcd"}
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.ManageDependencies = boolPtr(false)
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMilvusReconciler_ReconcileExternalizedDependencies_ReleaseVersions(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	mockHelm := helm.NewMockClient(env.Ctrl)
+	helm.SetDefaultClient(mockHelm)
+	mockStatusCli := NewMockK8sStatusClient(env.Ctrl)
+
+	mc := env.Inst.DeepCopy()
+	mc.Status.ManagedReleases = []string{"mc-etcd", "mc-minio"}
+	etcdRelease := &release.Release{
+		Name:  "mc-etcd",
+		Chart: &chart.Chart{Metadata: &chart.Metadata{Version: "6.3.3", AppVersion: "3.5.5"}},
+	}
+	mockHelm.EXPECT().GetRelease(gomock.Any(), "mc-etcd").Return(etcdRelease, nil).Times(2)
+	// not installed yet
+	mockHelm.EXPECT().GetRelease(gomock.Any(), "mc-minio").Return(nil, driver.ErrReleaseNotFound).Times(2)
+	env.MockClient.EXPECT().Status().Return(mockStatusCli)
+	mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	err := r.ReconcileExternalizedDependencies(ctx, mc)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1beta1.DependencyReleaseStatus{
+		{Name: "mc-etcd", ChartVersion: "6.3.3", AppVersion: "3.5.5"},
+	}, mc.Status.DependencyReleases)
+
+	// no change, no update
+	err = r.ReconcileExternalizedDependencies(ctx, mc)
+	assert.NoError(t, err)
+
+	t.Run("get release failed", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mockHelm.EXPECT().GetRelease(gomock.Any(), "mc-etcd").Return(nil, errMock)
+		err := r.ReconcileExternalizedDependencies(ctx, mc)
+		assert.Error(t, err)
+	})
+}
@@ -101,7 +101,7 @@ func (c *DeployControllerImpl) Reconcile(ctx context.Context, mc v1beta1.Milvus,
 		return biz.HandleManualMode(ctx, mc)
 	}
 
-	if ReplicasValue(component.GetReplicas(mc.Spec)) == 0 {
+	if ReplicasValue(component.GetDesiredReplicas(mc.Spec)) == 0 {
 		return biz.HandleStop(ctx, mc)
 	}
 
@@ -196,7 +196,7 @@ func (c *DeployControllerBizImpl) IsUpdating(ctx context.Context, mc v1beta1.Mil
 	if v1beta1.Labels().IsChangingMode(mc, c.component.Name) {
 		return false, nil
 	}
-	if mc.Spec.IsStopping() {
+	if mc.IsStopping() {
 		return false, nil
 	}
 	if mc.Status.ObservedGeneration < mc.Generation {
@@ -270,10 +270,17 @@ func (c *DeployControllerBizImpl) HandleScaling(ctx context.Context, mc v1beta1.
 	if err != nil {
 		return errors.Wrap(err, "get querynode deploys")
 	}
+	if isRolloutPaused(mc) && lastDeploy != nil && getDeployReplicas(lastDeploy) > 0 {
+		// a rollout is in progress, hold the replicas of both deployments
+		return nil
+	}
 	return c.util.ScaleDeployments(ctx, mc, currentDeploy, lastDeploy)
 }
 
 func (c *DeployControllerBizImpl) HandleRolling(ctx context.Context, mc v1beta1.Milvus) error {
+	if isRolloutPaused(mc) {
+		return nil
+	}
 	currentDeploy, lastDeploy, err := c.util.GetDeploys(ctx, mc)
 	if err != nil {
 		return errors.Wrapf(err, "get [%s] deploys", c.component.Name)
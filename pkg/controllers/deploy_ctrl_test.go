@@ -428,6 +428,29 @@ func TestDeployControllerBizImpl_HandleScaling(t *testing.T) {
 		err := bizImpl.HandleScaling(ctx, mc)
 		assert.NoError(t, err)
 	})
+
+	t.Run("rollout paused, replicas held during rollout", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+		deploy := appsv1.Deployment{}
+		deploy.Spec.Replicas = int32Ptr(2)
+		mockUtil.EXPECT().GetDeploys(ctx, mc).Return(&deploy, &deploy, nil)
+		err := bizImpl.HandleScaling(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rollout paused, scaling ok without rollout", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+		deploy := appsv1.Deployment{}
+		deploy.Spec.Replicas = int32Ptr(2)
+		lastDeploy := appsv1.Deployment{}
+		lastDeploy.Spec.Replicas = int32Ptr(0)
+		mockUtil.EXPECT().GetDeploys(ctx, mc).Return(&deploy, &lastDeploy, nil)
+		mockUtil.EXPECT().ScaleDeployments(ctx, mc, &deploy, &lastDeploy).Return(nil)
+		err := bizImpl.HandleScaling(ctx, mc)
+		assert.NoError(t, err)
+	})
 }
 
 func TestDeployControllerBizImpl_HandleRolling(t *testing.T) {
@@ -493,6 +516,13 @@ func TestDeployControllerBizImpl_HandleRolling(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("rollout paused, no new rollout", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+		err := bizImpl.HandleRolling(ctx, mc)
+		assert.NoError(t, err)
+	})
+
 	t.Run("new rollout & requeue", func(t *testing.T) {
 		mockUtil.EXPECT().GetDeploys(ctx, mc).Return(&deploy, &deploy2, nil)
 		mockUtil.EXPECT().RenderPodTemplateWithoutGroupID(mc, gomock.Any(), QueryNode, false).Return(nil)
@@ -3,7 +3,6 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"strconv"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -98,7 +97,7 @@ func (c *DeployControllerBizUtilImpl) RenderPodTemplateWithoutGroupID(mc v1beta1
 	appLabels := NewComponentAppLabels(updater.GetIntanceName(), updater.GetComponent().Name)
 	if !forceUpdateAll {
 		isCreating := currentTemplate == nil
-		isStopped := ReplicasValue(component.GetReplicas(mc.Spec)) == 0
+		isStopped := ReplicasValue(component.GetDesiredReplicas(mc.Spec)) == 0
 		forceUpdateAll = isCreating || isStopped
 	}
 	updatePodTemplate(updater, ret, appLabels, forceUpdateAll)
@@ -320,7 +319,7 @@ func (c *DeployControllerBizUtilImpl) checkCanScaleNow(ctx context.Context, mc v
 
 func (c *DeployControllerBizUtilImpl) planScaleForForceUpgrade(mc v1beta1.Milvus, currentDeployment, lastDeployment *appsv1.Deployment) scaleAction {
 	currentDeployReplicas := getDeployReplicas(currentDeployment)
-	expectedReplicas := int(ReplicasValue(c.component.GetReplicas(mc.Spec)))
+	expectedReplicas := int(ReplicasValue(c.component.GetDesiredReplicas(mc.Spec)))
 	if currentDeployReplicas != expectedReplicas {
 		return scaleAction{deploy: currentDeployment, replicaChange: expectedReplicas - currentDeployReplicas}
 	}
@@ -335,7 +334,7 @@ func (c *DeployControllerBizUtilImpl) planScaleForForceUpgrade(mc v1beta1.Milvus
 type scaleAction struct {
 	// deploy shall not be nil
 	deploy *appsv1.Deployment
-	// 0: no change, 1: scale up, -1: scale down
+	// 0: no change, positive: scale up, negative: scale down
 	replicaChange int
 }
 
@@ -370,7 +369,7 @@ func (c *DeployControllerBizUtilImpl) checkScaleKind(mc v1beta1.Milvus) scaleKin
 	if mc.Spec.Com.ImageUpdateMode == v1beta1.ImageUpdateModeForce {
 		return scaleKindForce
 	}
-	expectedReplicas := int(ReplicasValue(c.component.GetReplicas(mc.Spec)))
+	expectedReplicas := int(ReplicasValue(c.component.GetDesiredReplicas(mc.Spec)))
 	isHpa := expectedReplicas < 0
 	if isHpa {
 		return scaleKindHPA
@@ -430,13 +429,14 @@ func (c *DeployControllerBizUtilImpl) planScaleForRollout(mc v1beta1.Milvus, cur
 	lastDeployReplicas := getDeployReplicas(lastDeployment)
 
 	currentReplicas := currentDeployReplicas + lastDeployReplicas
-	expectedReplicas := int(ReplicasValue(c.component.GetReplicas(mc.Spec)))
+	expectedReplicas := int(ReplicasValue(c.component.GetDesiredReplicas(mc.Spec)))
+	batch := int(c.component.GetRollingBatch(mc.Spec))
 	if compareDeployResourceLimitEqual(currentDeployment, lastDeployment) {
 		switch {
 		case currentReplicas > expectedReplicas:
 			if lastDeployReplicas > 0 {
 				// continue rollout by scale in last deployment
-				return scaleAction{deploy: lastDeployment, replicaChange: -1}
+				return scaleAction{deploy: lastDeployment, replicaChange: -min(batch, lastDeployReplicas)}
 			}
 			// scale in is not allowed during a rollout
 			return noScaleAction
@@ -446,7 +446,7 @@ func (c *DeployControllerBizUtilImpl) planScaleForRollout(mc v1beta1.Milvus, cur
 				return noScaleAction
 			}
 			// continue rollout by scale out last deployment
-			return scaleAction{deploy: currentDeployment, replicaChange: 1}
+			return scaleAction{deploy: currentDeployment, replicaChange: min(batch, lastDeployReplicas)}
 		default:
 			// case currentReplicas < expectedReplicas
 			// scale out
@@ -467,7 +467,7 @@ func (c *DeployControllerBizUtilImpl) planScaleForRollout(mc v1beta1.Milvus, cur
 				return scaleAction{deploy: currentDeployment, replicaChange: lastDeployReplicas - currentDeployReplicas}
 			}
 			// continue rollout by scale in last deployment
-			return scaleAction{deploy: lastDeployment, replicaChange: -1}
+			return scaleAction{deploy: lastDeployment, replicaChange: -min(batch, lastDeployReplicas)}
 		}
 		if currentDeployReplicas > expectedReplicas {
 			// scale current deploy replica to expected
@@ -484,7 +484,7 @@ func (c *DeployControllerBizUtilImpl) planScaleForRollout(mc v1beta1.Milvus, cur
 func (c *DeployControllerBizUtilImpl) planScaleForNormalState(mc v1beta1.Milvus, currentDeployment *appsv1.Deployment) scaleAction {
 	currentDeployReplicas := getDeployReplicas(currentDeployment)
 	currentReplicas := currentDeployReplicas
-	expectedReplicas := int(ReplicasValue(c.component.GetReplicas(mc.Spec)))
+	expectedReplicas := int(ReplicasValue(c.component.GetDesiredReplicas(mc.Spec)))
 	switch {
 	case currentReplicas > expectedReplicas:
 		// scale in one by one
@@ -555,14 +555,5 @@ func (c *DeployControllerBizUtilImpl) PrepareNewRollout(ctx context.Context, mc
 
 // RenewDeployAnnotation returns true if annotation is updated
 func (c *DeployControllerBizUtilImpl) RenewDeployAnnotation(ctx context.Context, mc v1beta1.Milvus, currentDeploy *appsv1.Deployment) bool {
-	if currentDeploy.Annotations == nil {
-		currentDeploy.Annotations = map[string]string{}
-	}
-	currentGen := currentDeploy.Annotations[AnnotationMilvusGeneration]
-	expectedGen := strconv.FormatInt(mc.GetGeneration(), 10)
-	if currentGen == expectedGen {
-		return false
-	}
-	currentDeploy.Annotations[AnnotationMilvusGeneration] = expectedGen
-	return true
+	return renewDeployGenerationAnnotation(mc, currentDeploy)
 }
@@ -1417,6 +1417,57 @@ func TestDeployControllerBizUtilImpl_planNextScaleAction(t *testing.T) {
 	})
 }
 
+func TestDeployControllerBizUtilImpl_planScaleForRollout_RollingBatch(t *testing.T) {
+	bizUtil := DeployControllerBizUtilImpl{
+		component: QueryNode,
+	}
+	mc := v1beta1.Milvus{}
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Spec.Com.QueryNode = &v1beta1.MilvusQueryNode{RollingBatch: 3}
+	mc.Spec.Com.QueryNode.Replicas = int32Ptr(10)
+
+	currentDeploy := new(appsv1.Deployment)
+	lastDeploy := new(appsv1.Deployment)
+
+	t.Run("scale out current by batch", func(t *testing.T) {
+		currentDeploy.Spec.Replicas = int32Ptr(2)
+		lastDeploy.Spec.Replicas = int32Ptr(8)
+		action := bizUtil.planScaleForRollout(mc, currentDeploy, lastDeploy)
+		assert.Equal(t, scaleAction{deploy: currentDeploy, replicaChange: 3}, action)
+	})
+
+	t.Run("scale in last by batch", func(t *testing.T) {
+		currentDeploy.Spec.Replicas = int32Ptr(5)
+		lastDeploy.Spec.Replicas = int32Ptr(8)
+		action := bizUtil.planScaleForRollout(mc, currentDeploy, lastDeploy)
+		assert.Equal(t, scaleAction{deploy: lastDeploy, replicaChange: -3}, action)
+	})
+
+	t.Run("last batch smaller", func(t *testing.T) {
+		currentDeploy.Spec.Replicas = int32Ptr(9)
+		lastDeploy.Spec.Replicas = int32Ptr(1)
+		action := bizUtil.planScaleForRollout(mc, currentDeploy, lastDeploy)
+		assert.Equal(t, scaleAction{deploy: currentDeploy, replicaChange: 1}, action)
+
+		currentDeploy.Spec.Replicas = int32Ptr(10)
+		action = bizUtil.planScaleForRollout(mc, currentDeploy, lastDeploy)
+		assert.Equal(t, scaleAction{deploy: lastDeploy, replicaChange: -1}, action)
+	})
+
+	t.Run("other components roll one by one", func(t *testing.T) {
+		bizUtil := DeployControllerBizUtilImpl{
+			component: DataNode,
+		}
+		mc := mc.DeepCopy()
+		mc.Spec.Com.DataNode = &v1beta1.MilvusDataNode{}
+		mc.Spec.Com.DataNode.Replicas = int32Ptr(10)
+		currentDeploy.Spec.Replicas = int32Ptr(2)
+		lastDeploy.Spec.Replicas = int32Ptr(8)
+		action := bizUtil.planScaleForRollout(*mc, currentDeploy, lastDeploy)
+		assert.Equal(t, scaleAction{deploy: currentDeploy, replicaChange: 1}, action)
+	})
+}
+
 func TestDeployControllerBizUtilImpl_PrepareNewRollout(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -1469,11 +1520,24 @@ func TestDeployControllerBizUtilImpl_RenewDeployAnnotation(t *testing.T) {
 	})
 
 	t.Run("annotation exists, not renewed", func(t *testing.T) {
-		deploy.Annotations = map[string]string{AnnotationMilvusGeneration: "1"}
+		deploy.Annotations = map[string]string{
+			AnnotationMilvusGeneration: "1",
+			AnnotationOperatorVersion:  v1beta1.Version,
+		}
 		renewed := bizUtil.RenewDeployAnnotation(ctx, mc, deploy)
 		assert.False(t, renewed)
 	})
 
+	t.Run("rendered by another operator version, renewed", func(t *testing.T) {
+		deploy.Annotations = map[string]string{
+			AnnotationMilvusGeneration: "1",
+			AnnotationOperatorVersion:  "old-version",
+		}
+		renewed := bizUtil.RenewDeployAnnotation(ctx, mc, deploy)
+		assert.True(t, renewed)
+		assert.Equal(t, v1beta1.Version, deploy.Annotations[AnnotationOperatorVersion])
+	})
+
 	mc.Generation = 2
 	t.Run("annotation exists, renewed", func(t *testing.T) {
 		renewed := bizUtil.RenewDeployAnnotation(ctx, mc, deploy)
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// renewDeployGenerationAnnotation records the milvus generation & the operator version the deployment is rendered by,
+// returns true if annotation is updated
+func renewDeployGenerationAnnotation(mc v1beta1.Milvus, deploy *appsv1.Deployment) bool {
+	if deploy.Annotations == nil {
+		deploy.Annotations = map[string]string{}
+	}
+	expectedGen := strconv.FormatInt(mc.GetGeneration(), 10)
+	if deploy.Annotations[AnnotationMilvusGeneration] == expectedGen &&
+		deploy.Annotations[AnnotationOperatorVersion] == v1beta1.Version {
+		return false
+	}
+	deploy.Annotations[AnnotationMilvusGeneration] = expectedGen
+	deploy.Annotations[AnnotationOperatorVersion] = v1beta1.Version
+	return true
+}
+
+// isDeploymentDrifted returns true if the deployment was already rendered from the current milvus generation
+// by the current operator version, but the managed fields differ from the rendered ones,
+// which means it's edited by someone else. An upgraded operator may render differently, so it's not drift.
+// The image & config checksum are ignored, because they can change within a generation during rolling upgrade
+// or when the dependencies change. The replicas are ignored when HPA manages them.
+func isDeploymentDrifted(mc v1beta1.Milvus, old, cur *appsv1.Deployment, hpaEnabled bool) bool {
+	if old.Annotations[AnnotationMilvusGeneration] != strconv.FormatInt(mc.GetGeneration(), 10) ||
+		old.Annotations[AnnotationOperatorVersion] != v1beta1.Version {
+		return false
+	}
+	expected := cur.DeepCopy()
+	if checksum, ok := old.Spec.Template.Annotations[AnnotationCheckSum]; ok && expected.Spec.Template.Annotations != nil {
+		expected.Spec.Template.Annotations[AnnotationCheckSum] = checksum
+	}
+	restoreContainerImages(expected.Spec.Template.Spec.Containers, old.Spec.Template.Spec.Containers)
+	restoreContainerImages(expected.Spec.Template.Spec.InitContainers, old.Spec.Template.Spec.InitContainers)
+	if hpaEnabled {
+		expected.Spec.Replicas = old.Spec.Replicas
+	}
+	return !IsEqual(old, expected)
+}
+
+func restoreContainerImages(containers, oldContainers []corev1.Container) {
+	for i := range containers {
+		for _, oldContainer := range oldContainers {
+			if oldContainer.Name == containers[i].Name {
+				containers[i].Image = oldContainer.Image
+				break
+			}
+		}
+	}
+}
+
+// recordConfigurationDrift sets the ConfigurationDrift condition of milvus when a drifted deployment is corrected
+func recordConfigurationDrift(ctx context.Context, cli client.Client, mc v1beta1.Milvus, component MilvusComponent) error {
+	latest := &v1beta1.Milvus{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(&mc), latest); err != nil {
+		return errors.Wrap(err, "get milvus")
+	}
+	UpdateCondition(&latest.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.ConfigurationDrift,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonDeploymentDriftCorrected,
+		Message: getConfigurationDriftMessage(mc, component),
+	})
+	return errors.Wrap(cli.Status().Update(ctx, latest), "update configuration drift condition")
+}
+
+func getConfigurationDriftMessage(mc v1beta1.Milvus, component MilvusComponent) string {
+	return fmt.Sprintf("Reverted manual changes of deployment %s", component.GetDeploymentName(mc.Name))
+}
+
+// clearConfigurationDrift sets the ConfigurationDrift condition to false
+// when the deployment it's reported for is found without drift
+func clearConfigurationDrift(ctx context.Context, cli client.Client, mc v1beta1.Milvus, component MilvusComponent) error {
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ConfigurationDrift)
+	if cond == nil || cond.Status != corev1.ConditionTrue ||
+		cond.Message != getConfigurationDriftMessage(mc, component) {
+		return nil
+	}
+	latest := &v1beta1.Milvus{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(&mc), latest); err != nil {
+		return errors.Wrap(err, "get milvus")
+	}
+	UpdateCondition(&latest.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.ConfigurationDrift,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1beta1.ReasonNoDeploymentDrift,
+		Message: fmt.Sprintf("Deployment %s matches the milvus spec", component.GetDeploymentName(mc.Name)),
+	})
+	return errors.Wrap(cli.Status().Update(ctx, latest), "update configuration drift condition")
+}
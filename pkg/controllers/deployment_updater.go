@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	pkgErrs "github.com/pkg/errors"
@@ -60,6 +63,9 @@ func updateDeploymentReplicas(deployment *appsv1.Deployment, updater deploymentU
 
 func updateDeployment(deployment *appsv1.Deployment, updater deploymentUpdater) error {
 	appLabels := NewComponentAppLabels(updater.GetIntanceName(), updater.GetComponent().Name)
+	if group := updater.GetComponent().Group; group != "" {
+		appLabels[StreamingNodeGroupLabel] = group
+	}
 	deployment.Labels = MergeLabels(deployment.Labels, appLabels)
 	if err := SetControllerReference(updater.GetControllerRef(), deployment, updater.GetScheme()); err != nil {
 		return pkgErrs.Wrap(err, "set controller reference")
@@ -93,16 +99,12 @@ func updatePodTemplate(
 	updateInitContainers(template, updater)
 	updateUserDefinedVolumes(template, updater)
 	updateScheduleSpec(template, updater)
+	updateReadinessGates(template, updater)
 	updateMilvusContainer(template, updater, forceUpdateAll)
 	updateSidecars(template, updater)
 	updateNetworkSettings(template, updater)
-
-	if updater.GetMilvus().Spec.Com.RunAsNonRoot {
-		template.Spec.SecurityContext = &corev1.PodSecurityContext{
-			RunAsNonRoot: &updater.GetMilvus().Spec.Com.RunAsNonRoot,
-			RunAsUser:    int64Ptr(1000),
-		}
-	}
+	updateSecurityContext(template, updater)
+	updateScratchVolume(template, updater)
 
 	var hasUpdates = !IsEqual(currentTemplate, template)
 	switch {
@@ -129,6 +131,202 @@ func updateNetworkSettings(template *corev1.PodTemplateSpec, updater deploymentU
 	if len(mergedComSpec.DNSPolicy) > 0 {
 		template.Spec.DNSPolicy = mergedComSpec.DNSPolicy
 	}
+	template.Spec.HostAliases = mergedComSpec.HostAliases
+}
+
+// the writable volumes of the read-only root filesystem with the restricted profile
+const (
+	TmpVolumeName = "milvus-tmp"
+	TmpMountPath  = "/tmp"
+	// WritableConfigVolumeName is mounted at MilvusConfigRootPath, where run.sh links & merges the config files
+	WritableConfigVolumeName = "milvus-writable-config"
+	// configCopyContainerName is the init container copying the config files in the milvus image to the writable config volume
+	configCopyContainerName = "copy-config"
+	configCopyMountPath     = "/milvus/writable-configs"
+)
+
+const (
+	ScratchVolumeName = "milvus-scratch"
+	// ScratchMountPath is the default localStorage.path of milvus
+	ScratchMountPath = "/var/lib/milvus/data"
+)
+
+func updateSecurityContext(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
+	spec := updater.GetMilvus().Spec
+	isRestricted := spec.Com.SecurityProfile == v1beta1.SecurityProfileRestricted
+	switch {
+	case spec.Com.PodSecurityContext != nil:
+		template.Spec.SecurityContext = spec.Com.PodSecurityContext
+	case isRestricted:
+		template.Spec.SecurityContext = &corev1.PodSecurityContext{
+			RunAsNonRoot: boolPtr(true),
+			RunAsUser:    int64Ptr(1000),
+			FSGroup:      int64Ptr(1000),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		}
+	case spec.Com.RunAsNonRoot:
+		template.Spec.SecurityContext = &corev1.PodSecurityContext{
+			RunAsNonRoot: &spec.Com.RunAsNonRoot,
+			RunAsUser:    int64Ptr(1000),
+		}
+	case template.Spec.SecurityContext != nil:
+		// reset to the one defaulted by the apiserver
+		template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	containerIdx := GetContainerIndex(template.Spec.Containers, updater.GetComponent().Name)
+	if containerIdx < 0 {
+		return
+	}
+	container := &template.Spec.Containers[containerIdx]
+	containerSecurityContext := updater.GetMergedComponentSpec().SecurityContext
+	switch {
+	case containerSecurityContext != nil:
+		container.SecurityContext = containerSecurityContext
+	case isRestricted:
+		container.SecurityContext = restrictedSecurityContext()
+	default:
+		container.SecurityContext = nil
+	}
+	updateWritableVolumes(template, container, isRestricted)
+}
+
+// restrictedSecurityContext returns the container security context complies with the restricted pod security standard
+func restrictedSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		RunAsNonRoot:             boolPtr(true),
+		ReadOnlyRootFilesystem:   boolPtr(true),
+		AllowPrivilegeEscalation: boolPtr(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// updateConfigContainerSecurityContext hardens the rendered config container with the restricted profile.
+// toggling the profile always changes the milvus container, so the config container is re-rendered with the rolling update
+func updateConfigContainerSecurityContext(template *corev1.PodTemplateSpec, isRestricted bool) {
+	configContainerIdx := GetContainerIndex(template.Spec.InitContainers, configContainerName)
+	if configContainerIdx < 0 || !isRestricted {
+		return
+	}
+	container := &template.Spec.InitContainers[configContainerIdx]
+	container.SecurityContext = restrictedSecurityContext()
+	container.SecurityContext.RunAsUser = int64Ptr(1000)
+}
+
+// updateWritableVolumes adds the writable emptyDirs needed by run.sh & milvus with the read-only root filesystem
+// of the restricted profile, or removes them when the profile is unset
+func updateWritableVolumes(template *corev1.PodTemplateSpec, container *corev1.Container, isRestricted bool) {
+	if !isRestricted {
+		removeVolume(template, TmpVolumeName)
+		removeVolume(template, WritableConfigVolumeName)
+		if idx := GetContainerIndex(template.Spec.InitContainers, configCopyContainerName); idx >= 0 {
+			template.Spec.InitContainers = append(template.Spec.InitContainers[:idx], template.Spec.InitContainers[idx+1:]...)
+		}
+		return
+	}
+	addVolume(&template.Spec.Volumes, corev1.Volume{
+		Name: TmpVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	addVolumeMount(&container.VolumeMounts, corev1.VolumeMount{
+		Name:      TmpVolumeName,
+		MountPath: TmpMountPath,
+	})
+	addVolume(&template.Spec.Volumes, corev1.Volume{
+		Name: WritableConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	addVolumeMount(&container.VolumeMounts, corev1.VolumeMount{
+		Name:      WritableConfigVolumeName,
+		MountPath: MilvusConfigRootPath,
+	})
+
+	// the writable config volume hides the default config files of the image, copy them into it first
+	copyContainer := corev1.Container{
+		Name:    configCopyContainerName,
+		Image:   container.Image,
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{fmt.Sprintf("cp -r %s/. %s/", MilvusConfigRootPath, configCopyMountPath)},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      WritableConfigVolumeName,
+				MountPath: configCopyMountPath,
+			},
+		},
+		SecurityContext: restrictedSecurityContext(),
+	}
+	fillContainerDefaultValues(&copyContainer)
+	if idx := GetContainerIndex(template.Spec.InitContainers, configCopyContainerName); idx >= 0 {
+		template.Spec.InitContainers[idx] = copyContainer
+	} else {
+		template.Spec.InitContainers = append(template.Spec.InitContainers, copyContainer)
+	}
+}
+
+// removeVolume removes the volume & its mounts in all the containers of the pod
+func removeVolume(template *corev1.PodTemplateSpec, volumeName string) {
+	volumeIdx := GetVolumeIndex(template.Spec.Volumes, volumeName)
+	if volumeIdx < 0 {
+		return
+	}
+	template.Spec.Volumes = append(template.Spec.Volumes[:volumeIdx], template.Spec.Volumes[volumeIdx+1:]...)
+	for i := range template.Spec.Containers {
+		removeVolumeMounts(&template.Spec.Containers[i].VolumeMounts, volumeName)
+	}
+	for i := range template.Spec.InitContainers {
+		removeVolumeMounts(&template.Spec.InitContainers[i].VolumeMounts, volumeName)
+	}
+}
+
+// updateScratchVolume sets the scratch size limit of the component on the data emptyDir,
+// or on an added emptyDir for the local storage path if there's no data volume
+func updateScratchVolume(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
+	sizeLimit := updater.GetComponent().GetScratchSizeLimit(updater.GetMilvus().Spec)
+	containerIdx := GetContainerIndex(template.Spec.Containers, updater.GetComponent().Name)
+	container := &template.Spec.Containers[containerIdx]
+
+	dataVolumeIdx := GetVolumeIndex(template.Spec.Volumes, MilvusDataVolumeName)
+	if dataVolumeIdx >= 0 || sizeLimit == nil {
+		removeScratchVolume(template, container)
+	}
+	if dataVolumeIdx >= 0 {
+		// the persistent data volume is not limited
+		if emptyDir := template.Spec.Volumes[dataVolumeIdx].EmptyDir; emptyDir != nil {
+			emptyDir.SizeLimit = sizeLimit
+		}
+		return
+	}
+	if sizeLimit == nil {
+		return
+	}
+	addVolume(&template.Spec.Volumes, corev1.Volume{
+		Name: ScratchVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				SizeLimit: sizeLimit,
+			},
+		},
+	})
+	addVolumeMount(&container.VolumeMounts, corev1.VolumeMount{
+		Name:      ScratchVolumeName,
+		MountPath: ScratchMountPath,
+	})
+}
+
+func removeScratchVolume(template *corev1.PodTemplateSpec, container *corev1.Container) {
+	removeVolumeMounts(&container.VolumeMounts, ScratchVolumeName)
+	volumeIdx := GetVolumeIndex(template.Spec.Volumes, ScratchVolumeName)
+	if volumeIdx >= 0 {
+		template.Spec.Volumes = append(template.Spec.Volumes[:volumeIdx], template.Spec.Volumes[volumeIdx+1:]...)
+	}
 }
 
 func updatePodMeta(template *corev1.PodTemplateSpec, appLabels map[string]string, updater deploymentUpdater) {
@@ -151,9 +349,12 @@ func updatePodMeta(template *corev1.PodTemplateSpec, appLabels map[string]string
 func updateInitContainers(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
 	configContainerIdx := GetContainerIndex(template.Spec.InitContainers, configContainerName)
 	spec := updater.GetMilvus().Spec
-	if configContainerIdx < 0 || spec.Com.UpdateToolImage {
+	if configContainerIdx < 0 || spec.Com.UpdateToolImage ||
+		isToolImageChanged(template.Spec.InitContainers[configContainerIdx], spec.Com.ToolImage) {
 		updateConfigContainer(template, updater)
 	}
+	updateConfigContainerArgs(template, spec.Com.ConfigContainerArgs)
+	updateConfigContainerRestartPolicy(template, spec.Com.ConfigSidecarMode)
 
 	initContainers := updater.GetInitContainers()
 	if len(initContainers) > 0 {
@@ -180,6 +381,31 @@ func updateConfigContainer(template *corev1.PodTemplateSpec, updater deploymentU
 	} else {
 		renderInitContainer(&template.Spec.InitContainers[configContainerIdx], spec.Com.ToolImage)
 	}
+	updateConfigContainerArgs(template, spec.Com.ConfigContainerArgs)
+	updateConfigContainerSecurityContext(template, spec.Com.SecurityProfile == v1beta1.SecurityProfileRestricted)
+}
+
+// isToolImageChanged returns true if the tool image is set & the config container runs another image.
+// the operator image used by default is only updated with UpdateToolImage, to avoid restarting milvus on operator upgrade
+func isToolImageChanged(configContainer corev1.Container, toolImage string) bool {
+	return toolImage != "" && configContainer.Image != toolImage
+}
+
+// updateConfigContainerArgs applies the user defined args to the config container
+func updateConfigContainerArgs(template *corev1.PodTemplateSpec, args []string) {
+	configContainerIdx := GetContainerIndex(template.Spec.InitContainers, configContainerName)
+	if configContainerIdx < 0 {
+		return
+	}
+	container := &template.Spec.InitContainers[configContainerIdx]
+	if len(args) < 1 {
+		// only reset the args overridden before
+		if len(container.Args) < 1 || slices.Equal(container.Args, defaultConfigContainerArgs) {
+			return
+		}
+		args = defaultConfigContainerArgs
+	}
+	container.Args = append([]string{}, args...)
 }
 
 func updateScheduleSpec(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
@@ -187,14 +413,74 @@ func updateScheduleSpec(template *corev1.PodTemplateSpec, updater deploymentUpda
 	if len(mergedComSpec.SchedulerName) > 0 {
 		template.Spec.SchedulerName = mergedComSpec.SchedulerName
 	}
-	template.Spec.Affinity = mergedComSpec.Affinity
+	component := updater.GetComponent()
+	spreadAcross := component.GetSpreadAcross(updater.GetMilvus().Spec)
+	template.Spec.Affinity = addSpreadAntiAffinity(mergedComSpec.Affinity, spreadAcross,
+		NewComponentAppLabels(updater.GetIntanceName(), component.Name))
 	template.Spec.Tolerations = mergedComSpec.Tolerations
-	template.Spec.NodeSelector = mergedComSpec.NodeSelector
+	template.Spec.NodeSelector = addArchNodeSelector(
+		renderNodeSelector(mergedComSpec.NodeSelector, updater.GetMilvus()),
+		updater.GetMilvus().Spec.Com.Arch)
 	template.Spec.ImagePullSecrets = mergedComSpec.ImagePullSecrets
 	template.Spec.ServiceAccountName = mergedComSpec.ServiceAccountName
 	template.Spec.PriorityClassName = mergedComSpec.PriorityClassName
 }
 
+// addSpreadAntiAffinity returns a copy of the affinity with a preferred pod anti-affinity term
+// of the topology key selecting the pods by labels
+func addSpreadAntiAffinity(affinity *corev1.Affinity, topologyKey string, podLabels map[string]string) *corev1.Affinity {
+	if len(topologyKey) < 1 {
+		return affinity
+	}
+	ret := affinity.DeepCopy()
+	if ret == nil {
+		ret = &corev1.Affinity{}
+	}
+	if ret.PodAntiAffinity == nil {
+		ret.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	ret.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		ret.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels},
+				TopologyKey:   topologyKey,
+			},
+		})
+	return ret
+}
+
+// renderNodeSelector substitutes the `${name}` & `${namespace}` in the node selector values with the milvus instance's
+func renderNodeSelector(nodeSelector map[string]string, mc *v1beta1.Milvus) map[string]string {
+	if nodeSelector == nil {
+		return nil
+	}
+	replacer := strings.NewReplacer("${name}", mc.Name, "${namespace}", mc.Namespace)
+	ret := make(map[string]string, len(nodeSelector))
+	for k, v := range nodeSelector {
+		ret[k] = replacer.Replace(v)
+	}
+	return ret
+}
+
+// addArchNodeSelector adds the kubernetes.io/arch node selector if arch is set,
+// the one already in the node selector takes precedence
+func addArchNodeSelector(nodeSelector map[string]string, arch string) map[string]string {
+	if arch == "" {
+		return nodeSelector
+	}
+	if _, ok := nodeSelector[corev1.LabelArchStable]; ok {
+		return nodeSelector
+	}
+	ret := make(map[string]string, len(nodeSelector)+1)
+	for k, v := range nodeSelector {
+		ret[k] = v
+	}
+	ret[corev1.LabelArchStable] = arch
+	return ret
+}
+
 func updateUserDefinedVolumes(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
 	userDefinedVolumes := []corev1.Volume{}
 	volumesInCRSpec := updater.GetMergedComponentSpec().Volumes
@@ -218,15 +504,25 @@ func updateUserDefinedVolumes(template *corev1.PodTemplateSpec, updater deployme
 		}
 	}
 
+	secretVolumes, _ := secretMountVolumes(updater.GetMergedComponentSpec().SecretMounts)
+	removeSecretMountVolumes(&template.Spec.Volumes)
+	userDefinedVolumes = append(userDefinedVolumes, secretVolumes...)
+
 	for _, volume := range userDefinedVolumes {
 		addVolume(&template.Spec.Volumes, volume)
 	}
 }
 
 func updateBuiltInVolumes(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
-	template.Annotations[v1beta1.PodAnnotationUsingConfigMap] = updater.GetMilvus().GetActiveConfigMap()
+	activeConfigMap := updater.GetMilvus().GetActiveConfigMap()
+	template.Annotations[v1beta1.PodAnnotationUsingConfigMap] = activeConfigMap
+	componentConfigMap := updater.GetComponent().GetConfigMapName(*updater.GetMilvus())
+	configVolume := configVolumeByName(componentConfigMap)
+	if extraSources := updater.GetMilvus().Spec.ConfProjectedSources; len(extraSources) > 0 {
+		configVolume = projectedConfigVolume(componentConfigMap, extraSources)
+	}
 	builtInVolumes := []corev1.Volume{
-		configVolumeByName(updater.GetMilvus().GetActiveConfigMap()),
+		configVolume,
 		toolVolume,
 	}
 	for _, volume := range builtInVolumes {
@@ -247,52 +543,77 @@ func updateMilvusContainer(template *corev1.PodTemplateSpec, updater deploymentU
 	}
 	container := &template.Spec.Containers[containerIdx]
 	container.Args = updater.GetArgs()
-	env := mergedComSpec.Env
-	env = append(env, GetStorageSecretRefEnv(updater.GetSecretRef())...)
-	container.Env = MergeEnvVar(container.Env, env)
-	metricPort := corev1.ContainerPort{
-		Name:          MetricPortName,
-		ContainerPort: MetricPort,
-		Protocol:      corev1.ProtocolTCP,
-	}
-	componentName := updater.GetComponent().Name
-	if componentName == ProxyName || componentName == StandaloneName {
-		container.Ports = []corev1.ContainerPort{
-			{
-				Name:          updater.GetComponent().GetPortName(),
-				ContainerPort: updater.GetComponent().GetComponentPort(updater.GetMilvus().Spec),
-				Protocol:      corev1.ProtocolTCP,
-			},
-			metricPort,
+	operatorEnv := GetStorageSecretRefEnv(updater.GetSecretRef())
+	operatorEnv = append(operatorEnv, GetStreamingServiceEnv(updater.GetMilvus().Spec)...)
+	activeStandbyEnv := GetActiveStandbyEnv(updater.GetMilvus().Spec, updater.GetComponent())
+	operatorEnv = append(operatorEnv, activeStandbyEnv...)
+	gomaxprocsEnv := GetGOMAXPROCSEnv(updater.GetMilvus().Spec, mergedComSpec.Resources)
+	operatorEnv = append(operatorEnv, gomaxprocsEnv...)
+	operatorEnv = append(operatorEnv, GetLogEnv(updater.GetMilvus().Spec, updater.GetComponent())...)
+	env := mergeUserDefinedEnv(operatorEnv, mergedComSpec.Env, updater)
+	for _, name := range []string{LogLevelEnvName, LogFormatEnvName} {
+		// log config removed from the component
+		if !hasEnvVar(env, name) {
+			container.Env = removeEnvVar(container.Env, name)
 		}
-		restfulPort := updater.GetRestfulPort()
-		if restfulPort != 0 {
-			container.Ports = append(container.Ports, corev1.ContainerPort{
-				Name:          RestfulPortName,
-				ContainerPort: restfulPort,
-				Protocol:      corev1.ProtocolTCP,
-			})
+	}
+	if len(gomaxprocsEnv) < 1 && !hasEnvVar(env, GOMAXPROCSEnvName) {
+		// AutoGOMAXPROCS disabled or CPU limit removed
+		container.Env = removeEnvVar(container.Env, GOMAXPROCSEnvName)
+	}
+	if len(activeStandbyEnv) < 1 {
+		// standby replicas removed
+		for _, name := range activeStandbyEnvNames {
+			if !hasEnvVar(env, name) {
+				container.Env = removeEnvVar(container.Env, name)
+			}
 		}
-	} else {
-		container.Ports = []corev1.ContainerPort{metricPort}
 	}
+	container.Env = MergeEnvVar(container.Env, env)
+	container.Ports = updater.GetComponent().GetContainerPorts(updater.GetMilvus().Spec)
 
+	removeSecretMountVolumeMounts(&container.VolumeMounts)
 	for _, volumeMount := range getUserDefinedVolumeMounts(updater) {
 		addVolumeMount(&container.VolumeMounts, volumeMount)
 	}
 
 	container.ImagePullPolicy = *mergedComSpec.ImagePullPolicy
-	if forceUpdateImage ||
+	// the existing image is held at the current step of rollout when paused
+	holdImage := isRolloutPaused(*updater.GetMilvus()) && container.Image != ""
+	if !holdImage && (forceUpdateImage ||
 		!updater.GetMilvus().IsRollingUpdateEnabled() || // rolling update is disabled
 		updater.GetMilvus().Spec.Com.ImageUpdateMode == v1beta1.ImageUpdateModeAll || // image update mode is update all
 		updater.GetMilvus().Spec.Com.ImageUpdateMode == v1beta1.ImageUpdateModeForce ||
-		updater.RollingUpdateImageDependencyReady() {
-		container.Image = mergedComSpec.Image
+		updater.RollingUpdateImageDependencyReady()) {
+		// new container always uses the spec image,
+		// the same digest pinned with another tag needs no rollout
+		if container.Image == "" ||
+			(isUpdateWindowOpen(updater.GetMilvus().Spec.Com.UpdateWindow, scheduleNow()) &&
+				!v1beta1.IsSameImage(container.Image, mergedComSpec.Image)) {
+			container.Image = mergedComSpec.Image
+		}
 	}
 
 	container.Resources = *mergedComSpec.Resources
 }
 
+// mergeUserDefinedEnv merges user defined env into the env set by operator, user's one wins on conflict
+func mergeUserDefinedEnv(operatorEnv, userEnv []corev1.EnvVar, updater deploymentUpdater) []corev1.EnvVar {
+	operatorEnvNames := map[string]bool{}
+	for _, envVar := range operatorEnv {
+		operatorEnvNames[envVar.Name] = true
+	}
+	for _, envVar := range userEnv {
+		if operatorEnvNames[envVar.Name] {
+			podTemplateLogger.WithValues(
+				"namespace", updater.GetMilvus().Namespace,
+				"milvus", updater.GetMilvus().Name).
+				Info("user defined env overrides the one set by operator", "component", updater.GetComponent().Name, "env", envVar.Name)
+		}
+	}
+	return MergeEnvVar(operatorEnv, userEnv)
+}
+
 func updateBuiltInVolumeMounts(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
 	containerIdx := GetContainerIndex(template.Spec.Containers, updater.GetComponent().Name)
 	if containerIdx < 0 {
@@ -315,7 +636,8 @@ func getUserDefinedVolumeMounts(updater deploymentUpdater) []corev1.VolumeMount
 	if builtInMq != nil {
 		ret = append(ret, dataVolumeMount())
 	}
-	return ret
+	_, secretVolumeMounts := secretMountVolumes(updater.GetMergedComponentSpec().SecretMounts)
+	return append(ret, secretVolumeMounts...)
 }
 
 func updateSomeFieldsOnlyWhenRolling(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
@@ -330,8 +652,10 @@ func updateSomeFieldsOnlyWhenRolling(template *corev1.PodTemplateSpec, updater d
 	if componentName == ProxyName || componentName == StandaloneName {
 		template.Labels[v1beta1.ServiceLabel] = v1beta1.TrueStr
 	}
-	updateProbes(container, updater.GetMergedComponentSpec())
-	if componentName == ProxyName || componentName == StandaloneName {
+	updateProbes(container, updater.GetMergedComponentSpec(), updater.GetMilvus().Spec.Com.LivenessPolicy)
+	if lifecycle := updater.GetMergedComponentSpec().Lifecycle; lifecycle != nil {
+		container.Lifecycle = lifecycle.DeepCopy()
+	} else if componentName == ProxyName || componentName == StandaloneName {
 		// When the proxy or standalone receives a SIGTERM,
 		// will stop handling new requests immediately
 		// but it maybe still not removed from the load balancer.
@@ -355,7 +679,7 @@ func updateSomeFieldsOnlyWhenRolling(template *corev1.PodTemplateSpec, updater d
 	template.Spec.TerminationGracePeriodSeconds = int64Ptr(int64(oneMonthSeconds))
 }
 
-func updateProbes(container *corev1.Container, spec ComponentSpec) {
+func updateProbes(container *corev1.Container, spec ComponentSpec, livenessPolicy v1beta1.LivenessPolicy) {
 	probes := v1beta1.Probes{}
 	if spec.Probes.Data != nil {
 		spec.Probes.MustAsObj(&probes)
@@ -364,7 +688,7 @@ func updateProbes(container *corev1.Container, spec ComponentSpec) {
 		probes.StartupProbe = GetDefaultStartupProbe()
 	}
 	if probes.LivenessProbe == nil {
-		probes.LivenessProbe = GetDefaultLivenessProbe()
+		probes.LivenessProbe = GetLivenessProbeByPolicy(livenessPolicy)
 	}
 	if probes.ReadinessProbe == nil {
 		probes.ReadinessProbe = GetDefaultReadinessProbe()
@@ -433,12 +757,12 @@ func (m milvusDeploymentUpdater) GetScheme() *runtime.Scheme {
 }
 
 func (m milvusDeploymentUpdater) GetReplicas() *int32 {
-	return m.component.GetReplicas(m.Spec)
+	return m.component.GetDesiredReplicas(m.Spec)
 }
 
 // when replicas is -1, HPA is enabled
 func (m milvusDeploymentUpdater) IsHPAEnabled() bool {
-	replicas := m.component.GetReplicas(m.Spec)
+	replicas := m.component.GetDesiredReplicas(m.Spec)
 	return replicas != nil && *replicas < 0
 }
 
@@ -465,6 +789,13 @@ func (m milvusDeploymentUpdater) GetDeploymentStrategy() appsv1.DeploymentStrate
 }
 
 func GetDeploymentStrategy(milvus *v1beta1.Milvus, component MilvusComponent) appsv1.DeploymentStrategy {
+	mergedComSpec := MergeComponentSpec(component.GetComponentSpec(milvus.Spec), milvus.Spec.Com.ComponentSpec)
+	if mergedComSpec.DeploymentStrategyType == appsv1.RecreateDeploymentStrategyType {
+		// rollingUpdate must not be set for Recreate
+		return appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		}
+	}
 	if milvus.Spec.Com.ImageUpdateMode == v1beta1.ImageUpdateModeForce ||
 		milvus.Spec.Com.EnableManualMode {
 		all := intstr.FromString("100%")
@@ -476,18 +807,34 @@ func GetDeploymentStrategy(milvus *v1beta1.Milvus, component MilvusComponent) ap
 			},
 		}
 	}
-	return component.GetDeploymentStrategy(milvus.Spec.Conf.Data)
+	if component.GetStandbyReplicas(milvus.Spec) > 0 {
+		// active-standby is enabled by env, the standby replicas take over during the rolling update
+		return newRollingUpdateStrategy()
+	}
+	strategy := component.GetDeploymentStrategy(milvus.Spec.Conf.Data)
+	if mergedComSpec.DeploymentStrategyType == appsv1.RollingUpdateDeploymentStrategyType &&
+		strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+		strategy = newRollingUpdateStrategy()
+	}
+	if batch := component.GetRollingBatch(milvus.Spec); batch > 1 && strategy.RollingUpdate != nil {
+		// surge the batch of new pods, the old pods are removed as the new ones become ready
+		maxSurge := intstr.FromInt32(batch)
+		strategy.RollingUpdate.MaxSurge = &maxSurge
+	}
+	return strategy
 }
 
 func (m milvusDeploymentUpdater) GetConfCheckSum() string {
-	return GetConfCheckSum(m.Spec)
+	return GetComponentConfCheckSum(m.Spec, m.component)
 }
 
 func (m milvusDeploymentUpdater) GetMergedComponentSpec() ComponentSpec {
-	return MergeComponentSpec(
+	mergedComSpec := MergeComponentSpec(
 		m.component.GetComponentSpec(m.Spec),
 		m.Spec.Com.ComponentSpec,
 	)
+	mergedComSpec.Resources = applyResourceBudget(m.Spec, m.component, mergedComSpec.Resources)
+	return mergedComSpec
 }
 
 func (m milvusDeploymentUpdater) GetArgs() []string {
@@ -497,11 +844,11 @@ func (m milvusDeploymentUpdater) GetArgs() []string {
 	} else {
 		ret = append([]string{RunScriptPath, "milvus", "run"}, m.component.GetRunCommands()...)
 	}
-	if m.GetMergedComponentSpec().RunWithSubProcess == nil ||
-		!*m.GetMergedComponentSpec().RunWithSubProcess {
-		return ret
+	if m.GetMergedComponentSpec().RunWithSubProcess != nil &&
+		*m.GetMergedComponentSpec().RunWithSubProcess {
+		ret = append(ret, "--run-with-subprocess")
 	}
-	return append(ret, "--run-with-subprocess")
+	return append(ret, m.GetMergedComponentSpec().ExtraArgs...)
 }
 func (m milvusDeploymentUpdater) GetSecretRef() string {
 	return m.Spec.Dep.Storage.SecretRef
@@ -515,7 +862,12 @@ func (m milvusDeploymentUpdater) RollingUpdateImageDependencyReady() bool {
 	if m.Status.ObservedGeneration < m.Generation {
 		return false
 	}
+	return len(m.RollingUpdateImageDependencyBlockers()) == 0
+}
 
+// RollingUpdateImageDependencyBlockers returns the names of the dependencies whose image is not updated,
+// which blocks the component's image update in rolling upgrade
+func (m milvusDeploymentUpdater) RollingUpdateImageDependencyBlockers() []string {
 	var deps []MilvusComponent
 	if m.IsUpgradingTo26() {
 		podTemplateLogger.Info("using upgrading to 2.6 dependency graph", "component", m.component.Name)
@@ -524,12 +876,13 @@ func (m milvusDeploymentUpdater) RollingUpdateImageDependencyReady() bool {
 		deps = m.component.GetDependencies(m.Spec)
 	}
 
+	var blockers []string
 	for _, dep := range deps {
 		if !dep.IsImageUpdated(m.GetMilvus()) {
-			return false
+			blockers = append(blockers, dep.GetName())
 		}
 	}
-	return true
+	return blockers
 }
 
 func (m milvusDeploymentUpdater) HasHookConfig() bool {
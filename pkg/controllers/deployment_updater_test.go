@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
 	"github.com/zilliztech/milvus-operator/pkg/util"
@@ -35,6 +38,16 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 		assert.Equal(t, []string{"/milvus/tools/run.sh", "milvus", "run", "mycomponent"}, deployment.Spec.Template.Spec.Containers[0].Args)
 	})
 
+	t.Run("extra args appended", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.GetServiceComponent().ExtraArgs = []string{"--log.level", "debug"}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/milvus/tools/run.sh", "milvus", "run", "standalone", "--log.level", "debug"}, deployment.Spec.Template.Spec.Containers[0].Args)
+	})
+
 	t.Run("test replicas", func(t *testing.T) {
 		int32Ptr := func(i int32) *int32 {
 			return &i
@@ -135,6 +148,107 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 		assert.Equal(t, DefaultOperatorImageInfo.Image, deployment.Spec.Template.Spec.InitContainers[0].Image)
 	})
 
+	t.Run("configContainer uses custom tool image", func(t *testing.T) {
+		const toolImage = "my-registry/milvus-config-tool:v1"
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.ToolImage = toolImage
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, toolImage, deployment.Spec.Template.Spec.InitContainers[0].Image)
+
+		// rotate tool image
+		const newToolImage = "my-registry/milvus-config-tool:v2"
+		inst.Spec.Com.ToolImage = newToolImage
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, newToolImage, deployment.Spec.Template.Spec.InitContainers[0].Image)
+
+		// kept when unset, like the operator image on operator upgrade
+		inst.Spec.Com.ToolImage = ""
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, newToolImage, deployment.Spec.Template.Spec.InitContainers[0].Image)
+
+		// fallback to operator image with updateToolImage
+		inst.Spec.Com.UpdateToolImage = true
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultOperatorImageInfo.Image, deployment.Spec.Template.Spec.InitContainers[0].Image)
+	})
+
+	t.Run("configContainer uses custom args", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.ConfigContainerArgs = []string{"/init.sh", "--extra-template"}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		configContainer := deployment.Spec.Template.Spec.InitContainers[0]
+		assert.Equal(t, configContainerName, configContainer.Name)
+		assert.Equal(t, []string{"/init.sh", "--extra-template"}, configContainer.Args)
+		assert.Equal(t, DefaultOperatorImageInfo.Image, configContainer.Image)
+
+		// custom args with custom tool image
+		const toolImage = "my-registry/milvus-config-tool:v1"
+		inst.Spec.Com.ToolImage = toolImage
+		inst.Spec.Com.UpdateToolImage = true
+		inst.Spec.Com.ConfigContainerArgs = []string{"/init.sh", "--other"}
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, toolImage, deployment.Spec.Template.Spec.InitContainers[0].Image)
+		assert.Equal(t, []string{"/init.sh", "--other"}, deployment.Spec.Template.Spec.InitContainers[0].Args)
+
+		// reset to default
+		inst.Spec.Com.ConfigContainerArgs = nil
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/init.sh"}, deployment.Spec.Template.Spec.InitContainers[0].Args)
+	})
+
+	t.Run("configContainer sidecar mode", func(t *testing.T) {
+		bak := nativeSidecarSupported
+		defer func() { nativeSidecarSupported = bak }()
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.ConfigSidecarMode = true
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+
+		nativeSidecarSupported = true
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		configContainer := deployment.Spec.Template.Spec.InitContainers[0]
+		assert.Equal(t, configContainerName, configContainer.Name)
+		if assert.NotNil(t, configContainer.RestartPolicy) {
+			assert.Equal(t, corev1.ContainerRestartPolicyAlways, *configContainer.RestartPolicy)
+		}
+		if assert.NotNil(t, configContainer.StartupProbe) {
+			assert.Equal(t, []string{"/bin/sh", "-c", "test -f /milvus/tools/run.sh && test -f /milvus/tools/merge && test -f /milvus/tools/iam-verify"},
+				configContainer.StartupProbe.Exec.Command)
+		}
+
+		// fallback on older clusters
+		nativeSidecarSupported = false
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Nil(t, deployment.Spec.Template.Spec.InitContainers[0].RestartPolicy)
+		assert.Nil(t, deployment.Spec.Template.Spec.InitContainers[0].StartupProbe)
+
+		// disabled
+		nativeSidecarSupported = true
+		inst.Spec.Com.ConfigSidecarMode = false
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Nil(t, deployment.Spec.Template.Spec.InitContainers[0].RestartPolicy)
+	})
+
 	t.Run("update configContainer when podTemplate updated", func(t *testing.T) {
 		inst := env.Inst.DeepCopy()
 		inst.Spec.GetServiceComponent().Commands = []string{"milvus", "run", "mycomponent"}
@@ -188,6 +302,132 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 		assert.LessOrEqual(t, 0, idx)
 	})
 
+	t.Run("user defined volumes coexist with persistence volume", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Dep.RocksMQ.Persistence.Enabled = true
+		inst.Spec.Com.Volumes = []v1beta1.Values{
+			{Data: map[string]interface{}{
+				"name": "jvm-options",
+				"configMap": map[string]interface{}{
+					"name": "jvm-options",
+				},
+			}},
+		}
+		inst.Spec.Com.VolumeMounts = []corev1.VolumeMount{
+			{Name: "jvm-options", MountPath: "/opt/jvm", SubPath: "jvm.options"},
+		}
+		inst.Spec.Com.Standalone.VolumeMounts = []corev1.VolumeMount{
+			{Name: "jvm-options", MountPath: "/opt/gpu", SubPath: "gpu.conf"},
+		}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		volumes := deployment.Spec.Template.Spec.Volumes
+		assert.Len(t, volumes, 4)
+		assert.LessOrEqual(t, 0, GetVolumeIndex(volumes, MilvusDataVolumeName))
+		assert.LessOrEqual(t, 0, GetVolumeIndex(volumes, MilvusConfigVolumeName))
+		idx := GetVolumeIndex(volumes, "jvm-options")
+		assert.LessOrEqual(t, 0, idx)
+		assert.Equal(t, "jvm-options", volumes[idx].ConfigMap.Name)
+
+		volumeMounts := deployment.Spec.Template.Spec.Containers[0].VolumeMounts
+		assert.Len(t, volumeMounts, 5)
+		assert.LessOrEqual(t, 0, GetVolumeMountIndex(volumeMounts, v1beta1.RocksMQPersistPath))
+		assert.LessOrEqual(t, 0, GetVolumeMountIndex(volumeMounts, "/opt/jvm"))
+		assert.LessOrEqual(t, 0, GetVolumeMountIndex(volumeMounts, "/opt/gpu"))
+	})
+
+	t.Run("restricted security profile", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+		inst.Spec.Com.SecurityProfile = v1beta1.SecurityProfileRestricted
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		podSpec := deployment.Spec.Template.Spec
+		assert.True(t, *podSpec.SecurityContext.RunAsNonRoot)
+		container := podSpec.Containers[0]
+		assert.True(t, *container.SecurityContext.RunAsNonRoot)
+		assert.True(t, *container.SecurityContext.ReadOnlyRootFilesystem)
+		assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+		assert.Equal(t, []corev1.Capability{"ALL"}, container.SecurityContext.Capabilities.Drop)
+
+		tmpIdx := GetVolumeIndex(podSpec.Volumes, TmpVolumeName)
+		assert.LessOrEqual(t, 0, tmpIdx)
+		assert.NotNil(t, podSpec.Volumes[tmpIdx].EmptyDir)
+		assert.LessOrEqual(t, 0, GetVolumeMountIndex(container.VolumeMounts, TmpMountPath))
+		configIdx := GetVolumeIndex(podSpec.Volumes, WritableConfigVolumeName)
+		assert.LessOrEqual(t, 0, configIdx)
+		assert.NotNil(t, podSpec.Volumes[configIdx].EmptyDir)
+		assert.LessOrEqual(t, 0, GetVolumeMountIndex(container.VolumeMounts, MilvusConfigRootPath))
+
+		configContainer := podSpec.InitContainers[GetContainerIndex(podSpec.InitContainers, configContainerName)]
+		assert.True(t, *configContainer.SecurityContext.RunAsNonRoot)
+		assert.True(t, *configContainer.SecurityContext.ReadOnlyRootFilesystem)
+		assert.False(t, *configContainer.SecurityContext.AllowPrivilegeEscalation)
+		assert.Equal(t, []corev1.Capability{"ALL"}, configContainer.SecurityContext.Capabilities.Drop)
+
+		copyIdx := GetContainerIndex(podSpec.InitContainers, configCopyContainerName)
+		assert.LessOrEqual(t, 0, copyIdx)
+		copyContainer := podSpec.InitContainers[copyIdx]
+		assert.Equal(t, container.Image, copyContainer.Image)
+		assert.True(t, *copyContainer.SecurityContext.ReadOnlyRootFilesystem)
+		assert.Equal(t, configCopyMountPath, copyContainer.VolumeMounts[0].MountPath)
+
+		// idempotent
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, podSpec.InitContainers, deployment.Spec.Template.Spec.InitContainers)
+		assert.Equal(t, podSpec.Volumes, deployment.Spec.Template.Spec.Volumes)
+
+		// reset when the profile removed
+		inst.Spec.Com.SecurityProfile = ""
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		podSpec = deployment.Spec.Template.Spec
+		assert.Equal(t, &corev1.PodSecurityContext{}, podSpec.SecurityContext)
+		assert.Nil(t, podSpec.Containers[0].SecurityContext)
+		configContainer = podSpec.InitContainers[GetContainerIndex(podSpec.InitContainers, configContainerName)]
+		assert.Nil(t, configContainer.SecurityContext.Capabilities)
+		assert.Equal(t, -1, GetContainerIndex(podSpec.InitContainers, configCopyContainerName))
+		assert.Equal(t, -1, GetVolumeIndex(podSpec.Volumes, TmpVolumeName))
+		assert.Equal(t, -1, GetVolumeIndex(podSpec.Volumes, WritableConfigVolumeName))
+		assert.Equal(t, -1, GetVolumeMountIndex(podSpec.Containers[0].VolumeMounts, TmpMountPath))
+		assert.Equal(t, -1, GetVolumeMountIndex(podSpec.Containers[0].VolumeMounts, MilvusConfigRootPath))
+	})
+
+	t.Run("pod security context reset when removed", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: int64Ptr(2000)}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, inst.Spec.Com.PodSecurityContext, deployment.Spec.Template.Spec.SecurityContext)
+
+		inst.Spec.Com.PodSecurityContext = nil
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, &corev1.PodSecurityContext{}, deployment.Spec.Template.Spec.SecurityContext)
+	})
+
+	t.Run("security context overrides profile", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.SecurityProfile = v1beta1.SecurityProfileRestricted
+		inst.Spec.Com.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: int64Ptr(2000)}
+		inst.Spec.Com.Standalone.SecurityContext = &corev1.SecurityContext{Privileged: boolPtr(true)}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, inst.Spec.Com.PodSecurityContext, deployment.Spec.Template.Spec.SecurityContext)
+		assert.Equal(t, inst.Spec.Com.Standalone.SecurityContext, deployment.Spec.Template.Spec.Containers[0].SecurityContext)
+	})
+
 	const oldImage = "milvusdb/milvus:v2.3.0"
 	const newImage = "milvusdb/milvus:v2.3.1"
 
@@ -300,8 +540,690 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 		assert.Equal(t, corev1.DNSPolicy("Default"), deployment.Spec.Template.Spec.DNSPolicy)
 	})
 
+	t.Run("spread across topology", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.Proxy.SpreadAcross = "topology.kubernetes.io/zone"
+		nodeAffinity := &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{},
+		}
+		inst.Spec.Com.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, Proxy)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+
+		affinity := deployment.Spec.Template.Spec.Affinity
+		assert.Equal(t, nodeAffinity, affinity.NodeAffinity)
+		terms := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		assert.Len(t, terms, 1)
+		assert.Equal(t, "topology.kubernetes.io/zone", terms[0].PodAffinityTerm.TopologyKey)
+		assert.Equal(t, NewComponentAppLabels(inst.Name, ProxyName), terms[0].PodAffinityTerm.LabelSelector.MatchLabels)
+		// spec not changed
+		assert.Nil(t, inst.Spec.Com.Affinity.PodAntiAffinity)
+
+		// other components not affected
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Nil(t, deployment.Spec.Template.Spec.Affinity.PodAntiAffinity)
+	})
+
+	t.Run("host aliases", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.HostNetwork = true
+		inst.Spec.Com.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		inst.Spec.Com.HostAliases = []corev1.HostAlias{
+			{IP: "10.0.0.10", Hostnames: []string{"s3.internal", "minio.internal"}},
+		}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, inst.Spec.Com.HostAliases, deployment.Spec.Template.Spec.HostAliases)
+		assert.True(t, deployment.Spec.Template.Spec.HostNetwork)
+		assert.Equal(t, corev1.DNSClusterFirstWithHostNet, deployment.Spec.Template.Spec.DNSPolicy)
+
+		// removed
+		inst.Spec.Com.HostAliases = nil
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Empty(t, deployment.Spec.Template.Spec.HostAliases)
+	})
+
+	t.Run("image update deferred outside update window", func(t *testing.T) {
+		bak := scheduleNow
+		defer func() { scheduleNow = bak }()
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeAll
+		inst.Spec.Com.Image = "milvusdb/milvus:v2.5.0"
+		inst.Spec.Com.UpdateWindow = &v1beta1.UpdateWindow{Start: "0 2 * * *", End: "0 4 * * *"}
+		getImage := func(deployment *appsv1.Deployment) string {
+			idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, StandaloneName)
+			return deployment.Spec.Template.Spec.Containers[idx].Image
+		}
+
+		// outside the window, new deployment still uses the spec image
+		scheduleNow = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvusdb/milvus:v2.5.0", getImage(deployment))
+
+		// image change deferred
+		inst.Spec.Com.Image = "milvusdb/milvus:v2.5.1"
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvusdb/milvus:v2.5.0", getImage(deployment))
+
+		// applied inside the window
+		scheduleNow = func() time.Time { return time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC) }
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvusdb/milvus:v2.5.1", getImage(deployment))
+	})
+
+	t.Run("auto GOMAXPROCS", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.AutoGOMAXPROCS = true
+		inst.Spec.Com.QueryNode.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("4"),
+			},
+		}
+		getEnv := func(deployment *appsv1.Deployment) []corev1.EnvVar {
+			idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, QueryNodeName)
+			return deployment.Spec.Template.Spec.Containers[idx].Env
+		}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Contains(t, getEnv(deployment), corev1.EnvVar{Name: GOMAXPROCSEnvName, Value: "4"})
+
+		// fractional cores rounded up
+		inst.Spec.Com.QueryNode.Resources.Limits[corev1.ResourceCPU] = resource.MustParse("2500m")
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Contains(t, getEnv(deployment), corev1.EnvVar{Name: GOMAXPROCSEnvName, Value: "3"})
+
+		// no limit
+		inst.Spec.Com.QueryNode.Resources = &corev1.ResourceRequirements{}
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.False(t, hasEnvVar(getEnv(deployment), GOMAXPROCSEnvName))
+
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.False(t, hasEnvVar(getEnv(deployment), GOMAXPROCSEnvName))
+	})
+
+	t.Run("querynode rolling batch", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.QueryNode.RollingBatch = 5
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+		assert.Equal(t, intstr.FromInt32(5), *deployment.Spec.Strategy.RollingUpdate.MaxSurge)
+		assert.Equal(t, intstr.FromInt32(0), *deployment.Spec.Strategy.RollingUpdate.MaxUnavailable)
+
+		// other components still roll one at a time
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{StandbyReplicas: int32Ptr(1)}
+		for _, component := range []MilvusComponent{DataNode, MixCoord} {
+			updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, component)
+			deployment = sampleDeployment.DeepCopy()
+			err = updateDeployment(deployment, updater)
+			assert.NoError(t, err)
+			assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+			assert.Equal(t, intstr.FromInt32(1), *deployment.Spec.Strategy.RollingUpdate.MaxSurge)
+		}
+	})
+
+	t.Run("arch node selector", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.NotContains(t, deployment.Spec.Template.Spec.NodeSelector, corev1.LabelArchStable)
+
+		inst.Spec.Com.Arch = "arm64"
+		inst.Spec.Com.NodeSelector = map[string]string{"pool": "milvus"}
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"pool":                 "milvus",
+			corev1.LabelArchStable: "arm64",
+		}, deployment.Spec.Template.Spec.NodeSelector)
+		// user defined node selector not mutated
+		assert.Equal(t, map[string]string{"pool": "milvus"}, inst.Spec.Com.NodeSelector)
+
+		// user defined arch takes precedence
+		inst.Spec.Com.NodeSelector = map[string]string{corev1.LabelArchStable: "amd64"}
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "amd64", deployment.Spec.Template.Spec.NodeSelector[corev1.LabelArchStable])
+	})
+
+	t.Run("digest pinned image", func(t *testing.T) {
+		digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Com.Image = "milvusdb/milvus:v2.5.10@" + digest
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, StandaloneName)
+		assert.Equal(t, inst.Spec.Com.Image, deployment.Spec.Template.Spec.Containers[idx].Image)
+
+		// same digest pinned without tag
+		inst.Spec.Com.Image = "milvusdb/milvus@" + digest
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvusdb/milvus:v2.5.10@"+digest, deployment.Spec.Template.Spec.Containers[idx].Image)
+	})
+
+	t.Run("mixcoord standby replicas", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		inst.Spec.Com.MixCoord.Replicas = int32Ptr(1)
+		inst.Spec.Com.MixCoord.StandbyReplicas = int32Ptr(1)
+		getEnv := func(deployment *appsv1.Deployment) []corev1.EnvVar {
+			idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, MixCoordName)
+			return deployment.Spec.Template.Spec.Containers[idx].Env
+		}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+		assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+		for _, name := range activeStandbyEnvNames {
+			assert.Contains(t, getEnv(deployment), corev1.EnvVar{Name: name, Value: "true"})
+		}
+
+		// stopped
+		inst.Spec.Com.MixCoord.Replicas = int32Ptr(0)
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+
+		// standby removed
+		inst.Spec.Com.MixCoord.Replicas = int32Ptr(1)
+		inst.Spec.Com.MixCoord.StandbyReplicas = nil
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+		for _, name := range activeStandbyEnvNames {
+			assert.False(t, hasEnvVar(getEnv(deployment), name))
+		}
+	})
+
+	t.Run("component log level", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.QueryNode.LogLevel = "debug"
+		inst.Spec.Com.QueryNode.LogFormat = "json"
+		getEnv := func(deployment *appsv1.Deployment, component MilvusComponent) []corev1.EnvVar {
+			idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, component.Name)
+			return deployment.Spec.Template.Spec.Containers[idx].Env
+		}
+		queryNodeDeploy := sampleDeployment.DeepCopy()
+		err := updateDeployment(queryNodeDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		assert.Contains(t, getEnv(queryNodeDeploy, QueryNode), corev1.EnvVar{Name: LogLevelEnvName, Value: "debug"})
+		assert.Contains(t, getEnv(queryNodeDeploy, QueryNode), corev1.EnvVar{Name: LogFormatEnvName, Value: "json"})
+
+		// others stay default
+		for _, component := range []MilvusComponent{DataNode, Proxy, MixCoord} {
+			deployment := sampleDeployment.DeepCopy()
+			err := updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, component))
+			assert.NoError(t, err)
+			assert.False(t, hasEnvVar(getEnv(deployment, component), LogLevelEnvName), component.Name)
+			assert.False(t, hasEnvVar(getEnv(deployment, component), LogFormatEnvName), component.Name)
+		}
+
+		// log level removed
+		inst.Spec.Com.QueryNode.LogLevel = ""
+		err = updateDeployment(queryNodeDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		assert.False(t, hasEnvVar(getEnv(queryNodeDeploy, QueryNode), LogLevelEnvName))
+		assert.True(t, hasEnvVar(getEnv(queryNodeDeploy, QueryNode), LogFormatEnvName))
+	})
+
+	t.Run("secret mounts", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.Volumes = []v1beta1.Values{
+			{Data: map[string]interface{}{"name": "user-volume", "emptyDir": map[string]interface{}{}}},
+		}
+		inst.Spec.Com.VolumeMounts = []corev1.VolumeMount{{Name: "user-volume", MountPath: "/user"}}
+		inst.Spec.Com.QueryNode.SecretMounts = []v1beta1.SecretMount{
+			{SecretName: "embedding-keys", MountPath: "/milvus/secrets/embedding"},
+		}
+		getContainer := func(deployment *appsv1.Deployment, component MilvusComponent) corev1.Container {
+			idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, component.Name)
+			return deployment.Spec.Template.Spec.Containers[idx]
+		}
+		secretVolume := corev1.Volume{
+			Name: SecretMountVolumePrefix + "0",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  "embedding-keys",
+					DefaultMode: int32Ptr(int(corev1.SecretVolumeSourceDefaultMode)),
+				},
+			},
+		}
+		secretVolumeMount := corev1.VolumeMount{
+			Name:      SecretMountVolumePrefix + "0",
+			ReadOnly:  true,
+			MountPath: "/milvus/secrets/embedding",
+		}
+		queryNodeDeploy := sampleDeployment.DeepCopy()
+		err := updateDeployment(queryNodeDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		assert.Contains(t, queryNodeDeploy.Spec.Template.Spec.Volumes, secretVolume)
+		assert.Contains(t, getContainer(queryNodeDeploy, QueryNode).VolumeMounts, secretVolumeMount)
+		// user defined volumes kept
+		assert.True(t, GetVolumeIndex(queryNodeDeploy.Spec.Template.Spec.Volumes, "user-volume") >= 0)
+		assert.True(t, GetVolumeMountIndex(getContainer(queryNodeDeploy, QueryNode).VolumeMounts, "/user") >= 0)
+
+		// not on other components
+		deployment := sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, DataNode))
+		assert.NoError(t, err)
+		assert.NotContains(t, deployment.Spec.Template.Spec.Volumes, secretVolume)
+		assert.NotContains(t, getContainer(deployment, DataNode).VolumeMounts, secretVolumeMount)
+
+		// secret mounts removed
+		inst.Spec.Com.QueryNode.SecretMounts = nil
+		err = updateDeployment(queryNodeDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		assert.NotContains(t, queryNodeDeploy.Spec.Template.Spec.Volumes, secretVolume)
+		assert.NotContains(t, getContainer(queryNodeDeploy, QueryNode).VolumeMounts, secretVolumeMount)
+	})
+
+	t.Run("component pod annotations", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		inst.Default()
+		const injectAnnotation = "sidecar.istio.io/inject"
+		inst.Spec.Com.PodAnnotations = map[string]string{injectAnnotation: "true"}
+		inst.Spec.Com.MixCoord.PodAnnotations = map[string]string{injectAnnotation: "false"}
+
+		mixCoordDeploy := sampleDeployment.DeepCopy()
+		err := updateDeployment(mixCoordDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord))
+		assert.NoError(t, err)
+		assert.Equal(t, "false", mixCoordDeploy.Spec.Template.Annotations[injectAnnotation])
+
+		for _, component := range []MilvusComponent{Proxy, QueryNode, DataNode} {
+			deployment := sampleDeployment.DeepCopy()
+			err := updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, component))
+			assert.NoError(t, err)
+			assert.Equal(t, "true", deployment.Spec.Template.Annotations[injectAnnotation], component.Name)
+		}
+	})
+
+	t.Run("component pod labels", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.PodLabels = map[string]string{"team": "search"}
+		inst.Spec.Com.QueryNode.PodLabels = map[string]string{
+			"cost-center":     "querynode",
+			AppLabelComponent: "not-querynode",
+		}
+
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		podLabels := deployment.Spec.Template.Labels
+		assert.Equal(t, "search", podLabels["team"])
+		assert.Equal(t, "querynode", podLabels["cost-center"])
+		// operator labels not clobbered
+		assert.Equal(t, QueryNodeName, podLabels[AppLabelComponent])
+		assert.Equal(t, inst.Name, podLabels[AppLabelInstance])
+		for k, v := range deployment.Spec.Selector.MatchLabels {
+			assert.Equal(t, v, podLabels[k])
+		}
+	})
+
+	t.Run("scratch size limit", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+		inst.Default()
+		sizeLimit := resource.MustParse("10Gi")
+		inst.Spec.Com.QueryNode.ScratchSizeLimit = &sizeLimit
+		queryNodeDeploy := sampleDeployment.DeepCopy()
+		err := updateDeployment(queryNodeDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		volumes := queryNodeDeploy.Spec.Template.Spec.Volumes
+		volumeIdx := GetVolumeIndex(volumes, ScratchVolumeName)
+		assert.True(t, volumeIdx >= 0)
+		assert.Equal(t, &sizeLimit, volumes[volumeIdx].EmptyDir.SizeLimit)
+		container := queryNodeDeploy.Spec.Template.Spec.Containers[GetContainerIndex(queryNodeDeploy.Spec.Template.Spec.Containers, QueryNode.Name)]
+		assert.True(t, GetVolumeMountIndex(container.VolumeMounts, ScratchMountPath) >= 0)
+
+		// not on other components
+		deployment := sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, DataNode))
+		assert.NoError(t, err)
+		assert.True(t, GetVolumeIndex(deployment.Spec.Template.Spec.Volumes, ScratchVolumeName) < 0)
+
+		// removed
+		inst.Spec.Com.QueryNode.ScratchSizeLimit = nil
+		err = updateDeployment(queryNodeDeploy, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+		assert.NoError(t, err)
+		assert.True(t, GetVolumeIndex(queryNodeDeploy.Spec.Template.Spec.Volumes, ScratchVolumeName) < 0)
+		container = queryNodeDeploy.Spec.Template.Spec.Containers[GetContainerIndex(queryNodeDeploy.Spec.Template.Spec.Containers, QueryNode.Name)]
+		assert.True(t, GetVolumeMountIndex(container.VolumeMounts, ScratchMountPath) < 0)
+
+		t.Run("set on the data emptyDir", func(t *testing.T) {
+			inst := env.Inst.DeepCopy()
+			inst.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeRocksMQ
+			inst.Spec.Com.Standalone.ScratchSizeLimit = &sizeLimit
+			deployment := sampleDeployment.DeepCopy()
+			err := updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone))
+			assert.NoError(t, err)
+			volumes := deployment.Spec.Template.Spec.Volumes
+			assert.True(t, GetVolumeIndex(volumes, ScratchVolumeName) < 0)
+			assert.Equal(t, &sizeLimit, volumes[GetVolumeIndex(volumes, MilvusDataVolumeName)].EmptyDir.SizeLimit)
+		})
+	})
+
+	t.Run("custom ports propagate to container & service", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Com.Proxy.Ports = []corev1.ContainerPort{
+			{Name: MilvusName, ContainerPort: 29530},
+			{Name: "debug", ContainerPort: 6060},
+		}
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, Proxy))
+		assert.NoError(t, err)
+		containers := deployment.Spec.Template.Spec.Containers
+		container := containers[GetContainerIndex(containers, ProxyName)]
+		assert.Equal(t, []corev1.ContainerPort{
+			{Name: MilvusName, ContainerPort: 29530, Protocol: corev1.ProtocolTCP},
+			{Name: MetricPortName, ContainerPort: MetricPort, Protocol: corev1.ProtocolTCP},
+			{Name: "debug", ContainerPort: 6060, Protocol: corev1.ProtocolTCP},
+		}, container.Ports)
+
+		service := &corev1.Service{}
+		service.Namespace = inst.Namespace
+		err = env.Reconciler.updateService(*inst, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(29530), service.Spec.Ports[0].Port)
+		assert.Equal(t, MilvusName, service.Spec.Ports[0].Name)
+
+		// the defaults are restored when the ports are removed
+		inst.Spec.Com.Proxy.Ports = nil
+		err = updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, Proxy))
+		assert.NoError(t, err)
+		container = deployment.Spec.Template.Spec.Containers[GetContainerIndex(containers, ProxyName)]
+		assert.Len(t, container.Ports, 2)
+		assert.Equal(t, int32(ProxyPort), container.Ports[0].ContainerPort)
+	})
+
+	t.Run("liveness policy", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, GetDefaultLivenessProbe(), deployment.Spec.Template.Spec.Containers[0].LivenessProbe)
+
+		inst.Spec.Com.LivenessPolicy = v1beta1.LivenessPolicyStrict
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, GetDefaultLivenessProbe(), deployment.Spec.Template.Spec.Containers[0].LivenessProbe)
+
+		inst.Spec.Com.LivenessPolicy = v1beta1.LivenessPolicyLenient
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		livenessProbe := deployment.Spec.Template.Spec.Containers[0].LivenessProbe
+		assert.Equal(t, int32(LenientLivenessFailureThreshold), livenessProbe.FailureThreshold)
+		assert.Greater(t, livenessProbe.FailureThreshold, GetDefaultLivenessProbe().FailureThreshold)
+		assert.Equal(t, GetDefaultReadinessProbe(), deployment.Spec.Template.Spec.Containers[0].ReadinessProbe)
+	})
+
+	t.Run("component env with valueFrom", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Default()
+		inst.Spec.Dep.Storage.SecretRef = "storage-secret"
+		apiKeyEnv := corev1.EnvVar{
+			Name: "API_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "api-secret"},
+					Key:                  "key",
+				},
+			},
+		}
+		accessKeyEnv := corev1.EnvVar{Name: "MINIO_ACCESS_KEY", Value: "override"}
+		inst.Spec.Com.Proxy.Env = []corev1.EnvVar{apiKeyEnv, accessKeyEnv}
+
+		getEnv := func(envs []corev1.EnvVar, name string) []corev1.EnvVar {
+			ret := []corev1.EnvVar{}
+			for _, env := range envs {
+				if env.Name == name {
+					ret = append(ret, env)
+				}
+			}
+			return ret
+		}
+
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, Proxy)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		proxyEnv := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, []corev1.EnvVar{apiKeyEnv}, getEnv(proxyEnv, "API_KEY"))
+		assert.Equal(t, []corev1.EnvVar{accessKeyEnv}, getEnv(proxyEnv, "MINIO_ACCESS_KEY"))
+		assert.Len(t, getEnv(proxyEnv, "MINIO_SECRET_KEY"), 1)
+
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, DataNode)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		dataNodeEnv := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Empty(t, getEnv(dataNodeEnv, "API_KEY"))
+		accessKeys := getEnv(dataNodeEnv, "MINIO_ACCESS_KEY")
+		assert.Len(t, accessKeys, 1)
+		assert.Equal(t, "storage-secret", accessKeys[0].ValueFrom.SecretKeyRef.Name)
+		assert.Len(t, getEnv(dataNodeEnv, CacheSizeEnvVarName), 1)
+	})
+
+	t.Run("node selector template", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Name = "tenant1"
+		inst.Spec.Com.NodeSelector = map[string]string{
+			"tenant":     "${name}",
+			"pool":       "${namespace}-${name}",
+			"node-group": "milvus",
+		}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"tenant":     "tenant1",
+			"pool":       inst.Namespace + "-tenant1",
+			"node-group": "milvus",
+		}, deployment.Spec.Template.Spec.NodeSelector)
+		// spec not changed
+		assert.Equal(t, "${name}", inst.Spec.Com.NodeSelector["tenant"])
+	})
+
+	t.Run("deployment strategy type", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		inst.Default()
+		inst.Spec.Com.MixCoord.DeploymentStrategyType = appsv1.RecreateDeploymentStrategyType
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
+		deployment := sampleDeployment.DeepCopy()
+		deployment.Spec.Strategy = newRollingUpdateStrategy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1.RecreateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+		assert.Nil(t, deployment.Spec.Strategy.RollingUpdate)
+
+		// other components not affected
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, Proxy)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+
+		// global RollingUpdate overrides operator's Recreate
+		inst.Spec.Com.MixCoord.DeploymentStrategyType = ""
+		inst.Spec.Com.DeploymentStrategyType = appsv1.RollingUpdateDeploymentStrategyType
+		inst.Spec.Conf.Data = map[string]interface{}{}
+		assert.Equal(t, appsv1.RecreateDeploymentStrategyType, MixCoord.GetDeploymentStrategy(inst.Spec.Conf.Data).Type)
+		strategy := GetDeploymentStrategy(inst, MixCoord)
+		assert.Equal(t, newRollingUpdateStrategy(), strategy)
+	})
+
+	t.Run("priority class name per component", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		inst.Default()
+		inst.Spec.Com.PriorityClassName = "low-priority"
+		inst.Spec.Com.MixCoord.PriorityClassName = "high-priority"
+
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "high-priority", deployment.Spec.Template.Spec.PriorityClassName)
+
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, "low-priority", deployment.Spec.Template.Spec.PriorityClassName)
+	})
+
+	t.Run("disabled component scaled to 0", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		inst.Spec.Com.IndexNode = &v1beta1.MilvusIndexNode{}
+		inst.Default()
+		inst.Spec.Com.IndexNode.Replicas = int32Ptr(2)
+		inst.Spec.Com.IndexNode.Enabled = boolPtr(false)
+
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, IndexNode)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+		// the deployment is still reconciled instead of being deleted
+		assert.Contains(t, GetComponentsBySpec(inst.Spec), IndexNode)
+		assert.Equal(t, int32(2), *inst.Spec.Com.IndexNode.Replicas)
+
+		inst.Spec.Com.IndexNode.Enabled = boolPtr(true)
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, IndexNode)
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	})
+
+	t.Run("lifecycle preStop hook per component", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+		inst.Default()
+		preStop := &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/milvus/tools/flush.sh"},
+			},
+		}
+		inst.Spec.Com.DataNode.Lifecycle = &corev1.Lifecycle{PreStop: preStop}
+
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, DataNode)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		containerIdx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, DataNodeName)
+		container := deployment.Spec.Template.Spec.Containers[containerIdx]
+		assert.Equal(t, preStop, container.Lifecycle.PreStop)
+
+		// other components keep the default
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, Proxy)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		containerIdx = GetContainerIndex(deployment.Spec.Template.Spec.Containers, ProxyName)
+		container = deployment.Spec.Template.Spec.Containers[containerIdx]
+		assert.Equal(t, []string{"sleep", "30"}, container.Lifecycle.PreStop.Exec.Command)
+	})
+
+	t.Run("config projected sources", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		activeConfigMap := inst.GetActiveConfigMap()
+		extraSource := corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "extra-config"},
+			},
+		}
+		inst.Spec.ConfProjectedSources = []corev1.VolumeProjection{
+			extraSource,
+			{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: activeConfigMap},
+				},
+			},
+		}
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MilvusStandalone)
+		deployment := sampleDeployment.DeepCopy()
+		err := updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		volumeIdx := GetVolumeIndex(deployment.Spec.Template.Spec.Volumes, MilvusConfigVolumeName)
+		assert.GreaterOrEqual(t, volumeIdx, 0)
+		configVolume := deployment.Spec.Template.Spec.Volumes[volumeIdx]
+		assert.Nil(t, configVolume.ConfigMap)
+		assert.NotNil(t, configVolume.Projected)
+		sources := configVolume.Projected.Sources
+		assert.Len(t, sources, 2)
+		assert.Equal(t, activeConfigMap, sources[0].ConfigMap.Name)
+		assert.Equal(t, extraSource, sources[1])
+	})
+
 	t.Run("streamingnode set env", func(t *testing.T) {
-		t.Skip()
 		inst := env.Inst.DeepCopy()
 		inst.Spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{}
 		inst.Default()
@@ -317,6 +1239,16 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 			}
 		}
 		assert.True(t, envAdded)
+
+		// enabled by default since 2.6
+		inst.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+		updater = newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, StreamingNode)
+		deployment = sampleDeployment.DeepCopy()
+		err = updateDeployment(deployment, updater)
+		assert.NoError(t, err)
+		for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+			assert.NotEqual(t, StreamingServiceEnabledEnvName, env.Name)
+		}
 	})
 
 	t.Run("verify 2.6 upgrade dependency graph", func(t *testing.T) {
@@ -340,6 +1272,7 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 		// Test MixCoord update - should not update because StreamingNode is not updated
 		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
 		assert.False(t, updater.RollingUpdateImageDependencyReady())
+		assert.Equal(t, []string{StreamingNodeName}, updater.RollingUpdateImageDependencyBlockers())
 
 		// Update StreamingNode to 2.6
 		inst.Status.ComponentsDeployStatus[StreamingNodeName] = v1beta1.ComponentDeployStatus{
@@ -350,5 +1283,75 @@ func TestMilvus_UpdateDeployment(t *testing.T) {
 
 		// Test MixCoord update - should update because StreamingNode is updated
 		assert.True(t, updater.RollingUpdateImageDependencyReady())
+		assert.Empty(t, updater.RollingUpdateImageDependencyBlockers())
+	})
+
+	t.Run("streaming node groups gate mixcoord update", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.EnableRollingUpdate = util.BoolPtr(true)
+		inst.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingUpgrade
+		inst.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+		inst.Spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{
+			Groups: []v1beta1.StreamingNodeGroup{{Name: "a"}, {Name: "b"}},
+		}
+		inst.Status.CurrentImage = "milvusdb/milvus:v2.5.0"
+		inst.Generation = 1
+		inst.Status.ObservedGeneration = 1
+		inst.Default()
+		inst.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			MixCoordName: {
+				Image: "milvusdb/milvus:v2.5.0",
+			},
+		}
+		updatedStatus := v1beta1.ComponentDeployStatus{
+			Image:      "milvusdb/milvus:v2.6.0",
+			Status:     readyDeployStatus,
+			Generation: 1,
+		}
+
+		updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, MixCoord)
+		assert.False(t, updater.RollingUpdateImageDependencyReady())
+
+		assert.Equal(t, []string{"streamingnode-a", "streamingnode-b"}, updater.RollingUpdateImageDependencyBlockers())
+
+		inst.Status.ComponentsDeployStatus["streamingnode-a"] = updatedStatus
+		assert.False(t, updater.RollingUpdateImageDependencyReady())
+		assert.Equal(t, []string{"streamingnode-b"}, updater.RollingUpdateImageDependencyBlockers())
+
+		inst.Status.ComponentsDeployStatus["streamingnode-b"] = updatedStatus
+		assert.True(t, updater.RollingUpdateImageDependencyReady())
+	})
+
+	t.Run("streaming node groups produce deployments", func(t *testing.T) {
+		inst := env.Inst.DeepCopy()
+		inst.Spec.Mode = v1beta1.MilvusModeCluster
+		inst.Spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{
+			Groups: []v1beta1.StreamingNodeGroup{{Name: "a", Replicas: int32Ptr(2)}, {Name: "b"}},
+		}
+		inst.Default()
+
+		var groupComponents []MilvusComponent
+		for _, component := range GetDeploymentComponentsBySpec(inst.Spec) {
+			if component.Name == StreamingNodeName {
+				groupComponents = append(groupComponents, component)
+			}
+		}
+		assert.Len(t, groupComponents, 2)
+
+		for _, component := range groupComponents {
+			deploy := new(appsv1.Deployment)
+			deploy.Name = component.GetDeploymentName(inst.Name)
+			deploy.Namespace = inst.Namespace
+			updater := newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, component)
+			assert.NoError(t, updateDeployment(deploy, updater))
+			assert.Equal(t, "mc-milvus-streamingnode-"+component.Group, deploy.Name)
+			assert.Equal(t, component.Group, deploy.Labels[StreamingNodeGroupLabel])
+			assert.Equal(t, component.Group, deploy.Spec.Selector.MatchLabels[StreamingNodeGroupLabel])
+			assert.Equal(t, StreamingNodeName, deploy.Spec.Template.Spec.Containers[0].Name)
+			assert.Equal(t, "streamingnode-"+component.Group, getDeploymentComponentName(deploy))
+		}
+		assert.Equal(t, int32(2), *groupComponents[0].GetReplicas(inst.Spec))
+		assert.Equal(t, int32(1), *groupComponents[1].GetReplicas(inst.Spec))
 	})
 }
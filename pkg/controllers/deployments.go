@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	pkgerr "github.com/pkg/errors"
@@ -31,11 +34,17 @@ const (
 	SecretKey                  = "secretkey"
 	AnnotationCheckSum         = "checksum/config"
 	AnnotationMilvusGeneration = v1beta1.AnnotationMilvusGeneration
+	AnnotationOperatorVersion  = v1beta1.AnnotationOperatorVersion
+
+	// SecretMountVolumePrefix is the name prefix of the volumes of spec.components.secretMounts
+	SecretMountVolumePrefix = "secret-mount-"
 
 	ToolsVolumeName = "tools"
 	ToolsMountPath  = "/milvus/tools"
 	RunScriptPath   = ToolsMountPath + "/run.sh"
 	MergeToolPath   = ToolsMountPath + "/merge"
+	// IAMVerifyToolPath is the path of the tool verifying the IAM of the object storage
+	IAMVerifyToolPath = ToolsMountPath + "/iam-verify"
 )
 
 var (
@@ -43,6 +52,82 @@ var (
 	ErrRequeue           = errors.New("requeue")
 )
 
+// StreamingServiceEnabledEnvName is the env to enable streaming service for milvus 2.5
+// it's enabled by default since 2.6
+const StreamingServiceEnabledEnvName = "MILVUS_STREAMING_SERVICE_ENABLED"
+
+// WoodPeckerStorageTypeLocal means woodpecker stores data in local disk instead of object storage
+const WoodPeckerStorageTypeLocal = "local"
+
+// GetStreamingServiceEnv returns the env to enable streaming service if streaming node is used
+func GetStreamingServiceEnv(spec v1beta1.MilvusSpec) []corev1.EnvVar {
+	if !spec.UseStreamingNode() || spec.IsVersionGreaterThan2_6() {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: StreamingServiceEnabledEnvName, Value: "1"},
+	}
+}
+
+const (
+	// LogLevelEnvName overrides log.level in milvus config
+	LogLevelEnvName = "LOG_LEVEL"
+	// LogFormatEnvName overrides log.format in milvus config
+	LogFormatEnvName = "LOG_FORMAT"
+)
+
+// GetLogEnv returns the env overriding the log config set for the component
+func GetLogEnv(spec v1beta1.MilvusSpec, component MilvusComponent) []corev1.EnvVar {
+	level, format := component.GetLogConfig(spec)
+	env := []corev1.EnvVar{}
+	if level != "" {
+		env = append(env, corev1.EnvVar{Name: LogLevelEnvName, Value: level})
+	}
+	if format != "" {
+		env = append(env, corev1.EnvVar{Name: LogFormatEnvName, Value: format})
+	}
+	return env
+}
+
+// activeStandbyEnvNames are the envs overriding <coord>.enableActiveStandby in milvus config
+var activeStandbyEnvNames = []string{
+	"ROOTCOORD_ENABLEACTIVESTANDBY",
+	"DATACOORD_ENABLEACTIVESTANDBY",
+	"INDEXCOORD_ENABLEACTIVESTANDBY",
+	"QUERYCOORD_ENABLEACTIVESTANDBY",
+	"MIXCOORD_ENABLEACTIVESTANDBY",
+}
+
+// GetActiveStandbyEnv returns the env to enable active-standby mode if the component has standby replicas
+func GetActiveStandbyEnv(spec v1beta1.MilvusSpec, component MilvusComponent) []corev1.EnvVar {
+	if component.GetStandbyReplicas(spec) < 1 {
+		return nil
+	}
+	ret := make([]corev1.EnvVar, 0, len(activeStandbyEnvNames))
+	for _, name := range activeStandbyEnvNames {
+		ret = append(ret, corev1.EnvVar{Name: name, Value: "true"})
+	}
+	return ret
+}
+
+// GOMAXPROCSEnvName is the env to set the max number of CPUs used by the go runtime
+const GOMAXPROCSEnvName = "GOMAXPROCS"
+
+// GetGOMAXPROCSEnv returns the GOMAXPROCS env by the CPU limit if AutoGOMAXPROCS is enabled
+func GetGOMAXPROCSEnv(spec v1beta1.MilvusSpec, resources *corev1.ResourceRequirements) []corev1.EnvVar {
+	if !spec.Com.AutoGOMAXPROCS || resources == nil {
+		return nil
+	}
+	cpuLimit, ok := resources.Limits[corev1.ResourceCPU]
+	if !ok || cpuLimit.IsZero() {
+		return nil
+	}
+	// Value() rounds up the fractional cores
+	return []corev1.EnvVar{
+		{Name: GOMAXPROCSEnvName, Value: strconv.FormatInt(cpuLimit.Value(), 10)},
+	}
+}
+
 func GetStorageSecretRefEnv(secretRef string) []corev1.EnvVar {
 	env := []corev1.EnvVar{}
 	if secretRef == "" {
@@ -137,6 +222,7 @@ func (r *MilvusReconciler) ReconcileComponentDeployment(
 		if err := r.updateDeployment(ctx, mc, new, component); err != nil {
 			return err
 		}
+		renewDeployGenerationAnnotation(mc, new)
 
 		r.logger.Info("Create Deployment", "name", new.Name, "namespace", new.Namespace)
 		return r.Create(ctx, new)
@@ -153,12 +239,23 @@ func (r *MilvusReconciler) ReconcileComponentDeployment(
 	if err := r.updateDeployment(ctx, mc, cur, component); err != nil {
 		return err
 	}
+	holdDeploymentRollout(mc, cur)
+	renewDeployGenerationAnnotation(mc, cur)
 
 	if IsEqual(old, cur) {
-		return nil
+		return pkgerr.Wrap(clearConfigurationDrift(ctx, r.Client, mc, component), "clear configuration drift")
 	}
 
 	diff := util.DiffStr(old, cur)
+	hpaEnabled := newMilvusDeploymentUpdater(mc, r.Scheme, component).IsHPAEnabled()
+	if isDeploymentDrifted(mc, old, cur, hpaEnabled) {
+		r.logger.Info("Revert drifted Deployment", "name", cur.Name, "namespace", cur.Namespace, "diff", string(diff))
+		if err := recordConfigurationDrift(ctx, r.Client, mc, component); err != nil {
+			return pkgerr.Wrap(err, "record configuration drift")
+		}
+	} else if err := clearConfigurationDrift(ctx, r.Client, mc, component); err != nil {
+		return pkgerr.Wrap(err, "clear configuration drift")
+	}
 	r.logger.Info("Update Deployment", "name", cur.Name, "namespace", cur.Namespace, "diff", string(diff))
 	return r.Update(ctx, cur)
 }
@@ -178,8 +275,10 @@ func (r *MilvusReconciler) handleOldInstanceChangingMode(ctx context.Context, mc
 			return pkgerr.Wrap(err, "label service pods")
 		}
 
+		// patch instead of update, the replicas of mc in reconcile may differ from the spec in cluster, like when stopped by schedule
+		base := mc.DeepCopy()
 		mc.Annotations[v1beta1.PodServiceLabelAddedAnnotation] = v1beta1.TrueStr
-		if err := r.Update(ctx, &mc); err != nil {
+		if err := r.Patch(ctx, &mc, client.MergeFrom(base)); err != nil {
 			return pkgerr.Wrap(err, "update milvus annotation")
 		}
 		return pkgerr.Wrap(ErrRequeue, "requeue after updated milvus annotation")
@@ -246,7 +345,7 @@ func (r *MilvusReconciler) ReconcileDeployments(ctx context.Context, mc v1beta1.
 		return err
 	}
 	var errs = []error{}
-	for _, component := range GetComponentsBySpec(mc.Spec) {
+	for _, component := range GetDeploymentComponentsBySpec(mc.Spec) {
 		switch {
 		case component == QueryNode ||
 			mc.Spec.Com.RollingMode == v1beta1.RollingModeV3:
@@ -268,9 +367,81 @@ func (r *MilvusReconciler) ReconcileDeployments(ctx context.Context, mc v1beta1.
 		return err
 	}
 
+	return r.cleanupOrphanedDeployments(ctx, mc)
+}
+
+// cleanupOrphanedDeployments deletes the deployments owned by the milvus
+// whose component is no longer part of the current mode / spec
+func (r *MilvusReconciler) cleanupOrphanedDeployments(ctx context.Context, mc v1beta1.Milvus) error {
+	// don't touch anything during migrations, the old deployments are still in use
+	if mc.IsChangingMode() {
+		return nil
+	}
+	// the MilvusUpdated condition can be stale, e.g. right after an image bump
+	if !isImageRolledOut(mc) {
+		return nil
+	}
+
+	deployList := &appsv1.DeploymentList{}
+	opts := &client.ListOptions{
+		Namespace: mc.Namespace,
+	}
+	opts.LabelSelector = labels.SelectorFromSet(map[string]string{
+		AppLabelInstance: mc.GetName(),
+		AppLabelName:     "milvus",
+	})
+	if err := r.List(ctx, deployList, opts); err != nil {
+		return pkgerr.Wrap(err, "list deployments")
+	}
+
+	diff := DiffComponents(mc, deployList.Items)
+	if len(diff.Orphaned) < 1 {
+		return nil
+	}
+	orphaned := sets.New(diff.Orphaned...)
+	for i := range deployList.Items {
+		deploy := &deployList.Items[i]
+		if !metav1.IsControlledBy(deploy, &mc) {
+			continue
+		}
+		component := getDeploymentComponentName(deploy)
+		if !orphaned.Has(component) || componentsWithMigration.Has(component) {
+			continue
+		}
+		r.logger.Info("Deleting orphaned deployment",
+			"component", component,
+			"deployment name", deploy.Name,
+			"namespace", deploy.Namespace)
+		if err := r.Delete(ctx, deploy); err != nil {
+			return pkgerr.Wrapf(err, "delete orphaned deployment %s/%s", deploy.Namespace, deploy.Name)
+		}
+	}
 	return nil
 }
 
+// componentsWithMigration are removed by their dedicated migrations in order, e.g. cleanupIndexNodeIfNeeded,
+// they're never cleaned up as orphaned
+var componentsWithMigration = sets.New(IndexNodeName)
+
+// isImageRolledOut returns true if the current image is the one in spec,
+// and the deployments of the components in spec all report their images in spec
+func isImageRolledOut(mc v1beta1.Milvus) bool {
+	if !v1beta1.IsSameImage(mc.Status.CurrentImage, mc.Spec.Com.Image) {
+		return false
+	}
+	for _, component := range GetDeploymentComponentsBySpec(mc.Spec) {
+		deployStatus, ok := mc.Status.ComponentsDeployStatus[component.GetName()]
+		if !ok {
+			return false
+		}
+		image := MergeComponentSpec(component.GetComponentSpec(mc.Spec), mc.Spec.Com.ComponentSpec).Image
+		if !v1beta1.IsSameImage(image, deployStatus.Image) {
+			return false
+		}
+	}
+	return true
+}
+
 // cleanupIndexNodeIfNeeded is part of the upgrade process to remove IndexNode which is no longer needed in 2.6+
 func (r *MilvusReconciler) cleanupIndexNodeIfNeeded(ctx context.Context, mc v1beta1.Milvus) error {
 	// offline indexnode for version >= 2.6, when proxy component's image has been updated
@@ -282,8 +453,9 @@ func (r *MilvusReconciler) cleanupIndexNodeIfNeeded(ctx context.Context, mc v1be
 			return err
 		}
 
+		base := mc.DeepCopy()
 		mc.Spec.Com.IndexNode = nil
-		err = r.Update(ctx, &mc)
+		err = r.Patch(ctx, &mc, client.MergeFrom(base))
 		if err != nil {
 			return err
 		}
@@ -323,6 +495,8 @@ func addVolumeMount(volumeMounts *[]corev1.VolumeMount, volumeMount corev1.Volum
 
 const configContainerName = "config"
 
+var defaultConfigContainerArgs = []string{"/init.sh"}
+
 func renderInitContainer(container *corev1.Container, toolImage string) *corev1.Container {
 	imageInfo := globalCommonInfo.OperatorImageInfo
 	if toolImage == "" {
@@ -380,6 +554,36 @@ func configVolumeByName(name string) corev1.Volume {
 	}
 }
 
+// projectedConfigVolume projects the extra sources into the config volume,
+// the operator managed configmap is always the first source, the extra sources referring to it are ignored
+func projectedConfigVolume(name string, extraSources []corev1.VolumeProjection) corev1.Volume {
+	configmapMode := int32(0777)
+	sources := []corev1.VolumeProjection{
+		{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: name,
+				},
+			},
+		},
+	}
+	for _, source := range extraSources {
+		if source.ConfigMap != nil && source.ConfigMap.Name == name {
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return corev1.Volume{
+		Name: MilvusConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources:     sources,
+				DefaultMode: &configmapMode,
+			},
+		},
+	}
+}
+
 func emptyDirDataVolume() corev1.Volume {
 	return corev1.Volume{
 		Name: MilvusDataVolumeName,
@@ -401,6 +605,52 @@ func persisentDataVolumeByName(name string) corev1.Volume {
 	}
 }
 
+// secretMountVolumes returns the volumes & mounts of the secret mounts, the volumes are named by index to avoid collisions
+func secretMountVolumes(secretMounts []v1beta1.SecretMount) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	for i, secretMount := range secretMounts {
+		name := fmt.Sprintf("%s%d", SecretMountVolumePrefix, i)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  secretMount.SecretName,
+					DefaultMode: int32Ptr(int(corev1.SecretVolumeSourceDefaultMode)),
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      name,
+			ReadOnly:  true,
+			MountPath: secretMount.MountPath,
+		})
+	}
+	return volumes, volumeMounts
+}
+
+// removeSecretMountVolumes removes the volumes of secret mounts, so that the removed ones don't remain
+func removeSecretMountVolumes(volumes *[]corev1.Volume) {
+	result := make([]corev1.Volume, 0)
+	for _, volume := range *volumes {
+		if !strings.HasPrefix(volume.Name, SecretMountVolumePrefix) {
+			result = append(result, volume)
+		}
+	}
+	*volumes = result
+}
+
+// removeSecretMountVolumeMounts removes the mounts of secret mounts, so that the removed ones don't remain
+func removeSecretMountVolumeMounts(volumeMounts *[]corev1.VolumeMount) {
+	result := make([]corev1.VolumeMount, 0)
+	for _, volumeMount := range *volumeMounts {
+		if !strings.HasPrefix(volumeMount.Name, SecretMountVolumePrefix) {
+			result = append(result, volumeMount)
+		}
+	}
+	*volumeMounts = result
+}
+
 func dataVolumeMount() corev1.VolumeMount {
 	return corev1.VolumeMount{
 		Name:      MilvusDataVolumeName,
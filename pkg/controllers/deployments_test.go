@@ -11,8 +11,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
 )
@@ -157,6 +160,104 @@ func TestClusterReconciler_ReconcileDeployments_DeleteIfExists(t *testing.T) {
 	})
 }
 
+func TestReconciler_cleanupOrphanedDeployments(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	mockClient := env.MockClient
+	ctx := env.ctx
+	mc := env.Inst
+	mc.Spec.Mode = v1beta1.MilvusModeStandalone
+	mc.Default()
+	mc.Status.CurrentImage = mc.Spec.Com.Image
+	mc.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+		MilvusStandalone.Name: {Image: mc.Spec.Com.Image},
+	}
+
+	newOwnedDeploy := func(component MilvusComponent) appsv1.Deployment {
+		deploy := appsv1.Deployment{}
+		deploy.Namespace = mc.Namespace
+		deploy.Name = component.GetDeploymentName(mc.Name)
+		deploy.Labels = NewComponentAppLabels(mc.Name, component.Name)
+		SetControllerReference(&mc, &deploy, r.Scheme)
+		return deploy
+	}
+
+	t.Run("stale datanode deleted in standalone mode", func(t *testing.T) {
+		mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DeploymentList{}), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				list.(*appsv1.DeploymentList).Items = []appsv1.Deployment{
+					newOwnedDeploy(MilvusStandalone),
+					newOwnedDeploy(DataNode),
+				}
+				return nil
+			})
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+				assert.Equal(t, DataNode.GetDeploymentName(mc.Name), obj.GetName())
+				return nil
+			})
+		err := r.cleanupOrphanedDeployments(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not owned deployment ignored", func(t *testing.T) {
+		mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DeploymentList{}), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				deploy := newOwnedDeploy(DataNode)
+				deploy.OwnerReferences = nil
+				list.(*appsv1.DeploymentList).Items = []appsv1.Deployment{deploy}
+				return nil
+			})
+		err := r.cleanupOrphanedDeployments(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete failed", func(t *testing.T) {
+		mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DeploymentList{}), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				list.(*appsv1.DeploymentList).Items = []appsv1.Deployment{newOwnedDeploy(DataNode)}
+				return nil
+			})
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.New("delete error"))
+		err := r.cleanupOrphanedDeployments(ctx, mc)
+		assert.Error(t, err)
+	})
+
+	t.Run("skip when current image not updated", func(t *testing.T) {
+		updating := *mc.DeepCopy()
+		updating.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+		err := r.cleanupOrphanedDeployments(ctx, updating)
+		assert.NoError(t, err)
+	})
+
+	t.Run("skip when deployment image not updated", func(t *testing.T) {
+		updating := *mc.DeepCopy()
+		updating.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
+		updating.Status.CurrentImage = updating.Spec.Com.Image
+		err := r.cleanupOrphanedDeployments(ctx, updating)
+		assert.NoError(t, err)
+	})
+
+	t.Run("indexnode left to its migration", func(t *testing.T) {
+		mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&appsv1.DeploymentList{}), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				list.(*appsv1.DeploymentList).Items = []appsv1.Deployment{newOwnedDeploy(IndexNode)}
+				return nil
+			})
+		err := r.cleanupOrphanedDeployments(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("skip when changing mode", func(t *testing.T) {
+		changing := *mc.DeepCopy()
+		changing.Spec.Mode = v1beta1.MilvusModeCluster
+		changing.Spec.Com.Standalone.Replicas = int32Ptr(1)
+		err := r.cleanupOrphanedDeployments(ctx, changing)
+		assert.NoError(t, err)
+	})
+}
+
 func TestClusterReconciler_ReconcileDeployments_Existed(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.checkMocks()
@@ -220,6 +321,7 @@ func TestClusterReconciler_ReconcileDeployments_Existed(t *testing.T) {
 				case "mc-milvus-standalone":
 					r.updateDeployment(ctx, m, cm, MilvusStandalone)
 				}
+				renewDeployGenerationAnnotation(m, cm)
 				return nil
 			}).Times(len(MixtureComponents) - 1)
 
@@ -275,7 +377,7 @@ func TestReconciler_handleOldInstanceChangingMode(t *testing.T) {
 		})
 		pod := corev1.Pod{}
 		mockClient.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(&pod)).Times(2)
-		mockClient.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(&m)).Times(1)
+		mockClient.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&m), gomock.Any()).Times(1)
 
 		err := r.handleOldInstanceChangingMode(ctx, m, component)
 		assert.Error(t, err)
@@ -314,3 +416,106 @@ func Test_removeVolumeMounts(t *testing.T) {
 	assert.Equal(t, "p3", vms[0].MountPath)
 	assert.Equal(t, "p4", vms[1].MountPath)
 }
+
+func TestReconciler_ReconcileComponentDeployment_RevertDrift(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	driftScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(driftScheme)
+	v1beta1.AddToScheme(driftScheme)
+
+	setup := func(t *testing.T) (client.Client, v1beta1.Milvus, *appsv1.Deployment) {
+		mc := env.Inst.DeepCopy()
+		mc.Generation = 1
+		mc.Spec.Com.Standalone.Replicas = int32Ptr(1)
+		cli := fake.NewClientBuilder().WithScheme(driftScheme).WithObjects(mc).WithStatusSubresource(mc).Build()
+		r.Client = cli
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, *mc, MilvusStandalone))
+		deploy := &appsv1.Deployment{}
+		key := client.ObjectKey{Namespace: mc.Namespace, Name: MilvusStandalone.GetDeploymentName(mc.Name)}
+		assert.NoError(t, cli.Get(ctx, key, deploy))
+		assert.Equal(t, "1", deploy.Annotations[AnnotationMilvusGeneration])
+		return cli, *mc, deploy
+	}
+
+	getDriftCondition := func(t *testing.T, cli client.Client, mc v1beta1.Milvus) *v1beta1.MilvusCondition {
+		latest := &v1beta1.Milvus{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(&mc), latest))
+		return GetMilvusConditionByType(latest.Status.Conditions, v1beta1.ConfigurationDrift)
+	}
+
+	t.Run("manual edit reverted with drift condition", func(t *testing.T) {
+		cli, mc, deploy := setup(t)
+		deploy.Spec.Replicas = int32Ptr(5)
+		deploy.Spec.Template.Spec.Containers[0].Args = []string{"edited"}
+		assert.NoError(t, cli.Update(ctx, deploy))
+
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, mc, MilvusStandalone))
+		reverted := &appsv1.Deployment{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(deploy), reverted))
+		assert.Equal(t, int32(1), *reverted.Spec.Replicas)
+		assert.NotEqual(t, []string{"edited"}, reverted.Spec.Template.Spec.Containers[0].Args)
+		cond := getDriftCondition(t, cli, mc)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, corev1.ConditionTrue, cond.Status)
+			assert.Equal(t, v1beta1.ReasonDeploymentDriftCorrected, cond.Reason)
+		}
+	})
+
+	t.Run("new generation is not drift", func(t *testing.T) {
+		cli, mc, deploy := setup(t)
+		mc.Generation = 2
+		mc.Spec.Com.Standalone.Replicas = int32Ptr(2)
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, mc, MilvusStandalone))
+		updated := &appsv1.Deployment{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(deploy), updated))
+		assert.Equal(t, int32(2), *updated.Spec.Replicas)
+		assert.Equal(t, "2", updated.Annotations[AnnotationMilvusGeneration])
+		assert.Nil(t, getDriftCondition(t, cli, mc))
+	})
+
+	t.Run("drift condition cleared when no drift found", func(t *testing.T) {
+		cli, mc, deploy := setup(t)
+		deploy.Spec.Template.Spec.Containers[0].Args = []string{"edited"}
+		assert.NoError(t, cli.Update(ctx, deploy))
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, mc, MilvusStandalone))
+
+		latest := &v1beta1.Milvus{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(&mc), latest))
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, *latest, MilvusStandalone))
+		cond := getDriftCondition(t, cli, mc)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, corev1.ConditionFalse, cond.Status)
+			assert.Equal(t, v1beta1.ReasonNoDeploymentDrift, cond.Reason)
+		}
+	})
+
+	t.Run("rendered by another operator version is not drift", func(t *testing.T) {
+		cli, mc, deploy := setup(t)
+		assert.Equal(t, v1beta1.Version, deploy.Annotations[AnnotationOperatorVersion])
+		deploy.Annotations[AnnotationOperatorVersion] = "old-version"
+		deploy.Spec.Template.Spec.Containers[0].Args = []string{"rendered-by-old-version"}
+		assert.NoError(t, cli.Update(ctx, deploy))
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, mc, MilvusStandalone))
+		updated := &appsv1.Deployment{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(deploy), updated))
+		assert.Equal(t, v1beta1.Version, updated.Annotations[AnnotationOperatorVersion])
+		assert.NotEqual(t, []string{"rendered-by-old-version"}, updated.Spec.Template.Spec.Containers[0].Args)
+		assert.Nil(t, getDriftCondition(t, cli, mc))
+	})
+
+	t.Run("replicas managed by HPA is not drift", func(t *testing.T) {
+		cli, mc, deploy := setup(t)
+		mc.Spec.Com.Standalone.Replicas = int32Ptr(-1)
+		deploy.Spec.Replicas = int32Ptr(3)
+		assert.NoError(t, cli.Update(ctx, deploy))
+		assert.NoError(t, r.ReconcileComponentDeployment(ctx, mc, MilvusStandalone))
+		updated := &appsv1.Deployment{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(deploy), updated))
+		assert.Equal(t, int32(3), *updated.Spec.Replicas)
+		assert.Nil(t, getDriftCondition(t, cli, mc))
+	})
+}
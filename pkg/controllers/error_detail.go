@@ -152,6 +152,74 @@ func getContainerStateReason(containerState corev1.ContainerState) string {
 	}
 }
 
+// imagePullFailedReasons are the waiting reasons of containers failed to pull image
+var imagePullFailedReasons = map[string]bool{
+	"ImagePullBackOff": true,
+	"ErrImagePull":     true,
+}
+
+func isContainerImagePullFailed(status corev1.ContainerStatus) bool {
+	return status.State.Waiting != nil && imagePullFailedReasons[status.State.Waiting.Reason]
+}
+
+// getImagePullFailedContainerStatus returns the first container status failed to pull image, including init containers
+func getImagePullFailedContainerStatus(pod corev1.Pod) *corev1.ContainerStatus {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if isContainerImagePullFailed(status) {
+				return &status
+			}
+		}
+	}
+	return nil
+}
+
+// GetImagePullFailedImage returns the image and true if the component is not ready because of image pull failure
+func (m ComponentErrorDetail) GetImagePullFailedImage() (string, bool) {
+	if m.Container == nil || !isContainerImagePullFailed(*m.Container) {
+		return "", false
+	}
+	return m.Container.Image, true
+}
+
+const (
+	containerReasonCrashLoopBackOff = "CrashLoopBackOff"
+	containerReasonOOMKilled        = "OOMKilled"
+)
+
+// GetCrashLoopTermination returns the last termination state and true if the component is not ready because its container is crash looping
+func (m ComponentErrorDetail) GetCrashLoopTermination() (*corev1.ContainerStateTerminated, bool) {
+	if m.Container == nil ||
+		m.Container.State.Waiting == nil ||
+		m.Container.State.Waiting.Reason != containerReasonCrashLoopBackOff ||
+		m.Container.LastTerminationState.Terminated == nil {
+		return nil, false
+	}
+	return m.Container.LastTerminationState.Terminated, true
+}
+
+// isOOMKilled returns whether the container is terminated because of out of memory
+func isOOMKilled(terminated *corev1.ContainerStateTerminated) bool {
+	return terminated.Reason == containerReasonOOMKilled
+}
+
+// GetCrashLoopMessage returns the message of the last termination of the crash looping container
+func GetCrashLoopMessage(container string, terminated *corev1.ContainerStateTerminated) string {
+	return fmt.Sprintf("container[%s] crash looping, last terminated: reason[%s] exitCode[%d] oomKilled[%t]",
+		container, terminated.Reason, terminated.ExitCode, isOOMKilled(terminated))
+}
+
+// GetUnschedulableMessage returns the scheduler message and true if the component is not ready because its pod can't be scheduled
+func (m ComponentErrorDetail) GetUnschedulableMessage() (string, bool) {
+	if m.Pod == nil ||
+		m.Pod.Type != corev1.PodScheduled ||
+		m.Pod.Status != corev1.ConditionFalse ||
+		m.Pod.Reason != corev1.PodReasonUnschedulable {
+		return "", false
+	}
+	return m.Pod.Message, true
+}
+
 func getFirstNotReadyContainerStatus(statuses []corev1.ContainerStatus) *corev1.ContainerStatus {
 	for _, status := range statuses {
 		if !status.Ready {
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"path"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+const (
+	etcdSnapshotContainerName = "snapshot"
+	etcdUploadContainerName   = "upload"
+	etcdSnapshotVolumeName    = "etcd-snapshot"
+	etcdSnapshotMountPath     = "/snapshot"
+	etcdSnapshotFile          = etcdSnapshotMountPath + "/etcd.db"
+	defaultEtcdBackupImage    = "minio/mc:latest"
+	etcdBackupJobsHistory     = 3
+)
+
+// etcdUploadScript uploads the snapshot into the destination, named by the time of the backup
+const etcdUploadScript = `mc alias set backup "$BACKUP_ENDPOINT" "$MINIO_ACCESS_KEY" "$MINIO_SECRET_KEY" && ` +
+	`mc cp ` + etcdSnapshotFile + ` "backup/$BACKUP_DESTINATION/etcd-$(date +%Y%m%d%H%M%S).db"`
+
+func getEtcdBackupCronJobName(instance string) string {
+	return instance + "-etcd-backup"
+}
+
+// newEtcdBackupJobLabels returns the labels of the backup cronjob & its pods,
+// they're not selected as the pods of milvus
+func newEtcdBackupJobLabels(instance string) map[string]string {
+	return map[string]string{
+		AppLabelInstance:  instance,
+		AppLabelName:      "milvus-etcd-backup",
+		AppLabelManagedBy: ManagerName,
+	}
+}
+
+// getEtcdBackup returns the backup config of the managed etcd, nil if it's not enabled
+func getEtcdBackup(mc v1beta1.Milvus) *v1beta1.EtcdBackup {
+	etcd := mc.Spec.Dep.Etcd
+	if etcd.External || etcd.InCluster == nil {
+		return nil
+	}
+	return etcd.Backup
+}
+
+// getEtcdBackupDestination returns the bucket/path the snapshots are uploaded to
+func getEtcdBackupDestination(mc v1beta1.Milvus) string {
+	destination := getEtcdBackup(mc).Destination
+	bucket := destination.Bucket
+	if bucket == "" {
+		bucket = GetMinioBucket(mc.Spec.Conf.Data)
+	}
+	backupPath := destination.Path
+	if backupPath == "" {
+		backupPath = path.Join("etcd-backup", mc.Name)
+	}
+	return path.Join(bucket, backupPath)
+}
+
+func (r *MilvusReconciler) updateEtcdBackupCronJob(mc v1beta1.Milvus, cronJob *batchv1.CronJob) error {
+	labels := newEtcdBackupJobLabels(mc.Name)
+	cronJob.Labels = MergeLabels(cronJob.Labels, labels)
+	if err := SetControllerReference(&mc, cronJob, r.Scheme); err != nil {
+		r.logger.Error(err, "CronJob SetControllerReference error", "name", mc.Name, "namespace", mc.Namespace)
+		return err
+	}
+	if len(mc.Spec.Dep.Etcd.Endpoints) < 1 {
+		return errors.New("no etcd endpoint to backup")
+	}
+
+	backup := getEtcdBackup(mc)
+	snapshotVolumeMount := corev1.VolumeMount{
+		Name:      etcdSnapshotVolumeName,
+		MountPath: etcdSnapshotMountPath,
+	}
+	// snapshot save only accepts one endpoint
+	snapshotContainer := corev1.Container{
+		Name:  etcdSnapshotContainerName,
+		Image: getEtcdImage(mc),
+		Command: []string{
+			"etcdctl",
+			"--endpoints=" + mc.Spec.Dep.Etcd.Endpoints[0],
+			"snapshot", "save", etcdSnapshotFile,
+		},
+		Env:          getEtcdctlEnv(mc),
+		VolumeMounts: []corev1.VolumeMount{snapshotVolumeMount},
+	}
+	fillContainerDefaultValues(&snapshotContainer)
+
+	image := backup.Image
+	if image == "" {
+		image = defaultEtcdBackupImage
+	}
+	scheme := "http://"
+	if GetMinioSecure(mc.Spec.Conf.Data) {
+		scheme = "https://"
+	}
+	env := []corev1.EnvVar{
+		{Name: "BACKUP_ENDPOINT", Value: scheme + mc.Spec.Dep.Storage.Endpoint},
+		{Name: "BACKUP_DESTINATION", Value: getEtcdBackupDestination(mc)},
+	}
+	env = append(env, GetStorageSecretRefEnv(mc.Spec.Dep.Storage.SecretRef)...)
+	uploadContainer := corev1.Container{
+		Name:         etcdUploadContainerName,
+		Image:        image,
+		Command:      []string{"/bin/sh", "-c", etcdUploadScript},
+		Env:          env,
+		VolumeMounts: []corev1.VolumeMount{snapshotVolumeMount},
+	}
+	fillContainerDefaultValues(&uploadContainer)
+
+	cronJob.Spec.Schedule = backup.Schedule
+	cronJob.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+	cronJob.Spec.SuccessfulJobsHistoryLimit = int32Ptr(etcdBackupJobsHistory)
+	cronJob.Spec.FailedJobsHistoryLimit = int32Ptr(etcdBackupJobsHistory)
+	cronJob.Spec.JobTemplate.Labels = labels
+	cronJob.Spec.JobTemplate.Spec.Template.Labels = labels
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	podSpec.InitContainers = []corev1.Container{snapshotContainer}
+	podSpec.Containers = []corev1.Container{uploadContainer}
+	podSpec.Volumes = []corev1.Volume{
+		{
+			Name: etcdSnapshotVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	return nil
+}
+
+// ReconcileEtcdBackup creates the cronjob snapshotting the managed etcd into the object storage by the schedule,
+// it's deleted when the backup is removed or the etcd is external
+func (r *MilvusReconciler) ReconcileEtcdBackup(ctx context.Context, mc v1beta1.Milvus) error {
+	return r.reconcileEtcdCronJob(ctx, mc, getEtcdBackupCronJobName(mc.Name),
+		getEtcdBackup(mc) != nil, r.updateEtcdBackupCronJob)
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusReconciler_ReconcileEtcdBackup(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+	r.Scheme = testScheme
+
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Dep.Storage.SecretRef = "minio-secret"
+	mc.Spec.Dep.Etcd.Backup = &v1beta1.EtcdBackup{
+		Schedule: "0 2 * * *",
+	}
+	cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mc).Build()
+	r.Client = cli
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(mc), mc))
+	cronJobKey := client.ObjectKey{Namespace: mc.Namespace, Name: getEtcdBackupCronJobName(mc.Name)}
+
+	// created for managed etcd
+	assert.NoError(t, r.ReconcileEtcdBackup(ctx, *mc))
+	cronJob := &batchv1.CronJob{}
+	assert.NoError(t, cli.Get(ctx, cronJobKey, cronJob))
+	assert.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+	assert.Equal(t, batchv1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	snapshot := podSpec.InitContainers[0]
+	assert.Equal(t, []string{"etcdctl", "--endpoints=" + mc.Spec.Dep.Etcd.Endpoints[0], "snapshot", "save", etcdSnapshotFile}, snapshot.Command)
+	upload := podSpec.Containers[0]
+	assert.Equal(t, defaultEtcdBackupImage, upload.Image)
+	assert.Contains(t, upload.Env, corev1.EnvVar{Name: "BACKUP_ENDPOINT", Value: "http://" + mc.Spec.Dep.Storage.Endpoint})
+	assert.Contains(t, upload.Env, corev1.EnvVar{Name: "BACKUP_DESTINATION", Value: GetMinioBucket(mc.Spec.Conf.Data) + "/etcd-backup/" + mc.Name})
+	assert.True(t, hasEnvVar(upload.Env, "MINIO_ACCESS_KEY"))
+	assert.Equal(t, snapshot.VolumeMounts, upload.VolumeMounts)
+	assert.Equal(t, etcdSnapshotVolumeName, podSpec.Volumes[0].Name)
+
+	// destination & schedule updated
+	mc.Spec.Dep.Etcd.Backup.Schedule = "0 4 * * *"
+	mc.Spec.Dep.Etcd.Backup.Destination = v1beta1.EtcdBackupDestination{Bucket: "dr", Path: "etcd/mc"}
+	mc.Spec.Dep.Etcd.Backup.Image = "my-registry/mc:latest"
+	assert.NoError(t, r.ReconcileEtcdBackup(ctx, *mc))
+	assert.NoError(t, cli.Get(ctx, cronJobKey, cronJob))
+	assert.Equal(t, "0 4 * * *", cronJob.Spec.Schedule)
+	upload = cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "my-registry/mc:latest", upload.Image)
+	assert.Contains(t, upload.Env, corev1.EnvVar{Name: "BACKUP_DESTINATION", Value: "dr/etcd/mc"})
+
+	// deleted when the backup is removed
+	mc.Spec.Dep.Etcd.Backup = nil
+	assert.NoError(t, r.ReconcileEtcdBackup(ctx, *mc))
+	assert.True(t, k8sErrors.IsNotFound(cli.Get(ctx, cronJobKey, cronJob)))
+
+	t.Run("absent for external etcd", func(t *testing.T) {
+		mc := env.Inst.DeepCopy()
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.Etcd.Backup = &v1beta1.EtcdBackup{Schedule: "0 2 * * *"}
+		cli := fake.NewClientBuilder().WithScheme(testScheme).Build()
+		r.Client = cli
+		assert.NoError(t, r.ReconcileEtcdBackup(ctx, *mc))
+		cronJobs := &batchv1.CronJobList{}
+		assert.NoError(t, cli.List(ctx, cronJobs))
+		assert.Empty(t, cronJobs.Items)
+	})
+}
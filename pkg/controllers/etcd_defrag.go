@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/util"
+)
+
+const (
+	etcdDefragContainerName = "defrag"
+	defaultEtcdRepository   = "milvusdb/etcd"
+	defaultEtcdTag          = "3.5.18-r1"
+	etcdDefragJobsHistory   = 1
+)
+
+func getEtcdDefragCronJobName(instance string) string {
+	return instance + "-etcd-defrag"
+}
+
+// newEtcdDefragJobLabels returns the labels of the defrag cronjob & its pods,
+// they're not selected as the pods of milvus
+func newEtcdDefragJobLabels(instance string) map[string]string {
+	return map[string]string{
+		AppLabelInstance:  instance,
+		AppLabelName:      "milvus-etcd-defrag",
+		AppLabelManagedBy: ManagerName,
+	}
+}
+
+// getEtcdDefragSchedule returns the defrag schedule of the managed etcd, empty if it's not enabled
+func getEtcdDefragSchedule(mc v1beta1.Milvus) string {
+	etcd := mc.Spec.Dep.Etcd
+	if etcd.External || etcd.InCluster == nil {
+		return ""
+	}
+	return etcd.InCluster.DefragSchedule
+}
+
+// getEtcdImage returns the image of the managed etcd by its helm values
+func getEtcdImage(mc v1beta1.Milvus) string {
+	data := mc.Spec.Dep.Etcd.InCluster.Values.Data
+	repository := GetStringValueWithDefault(data, defaultEtcdRepository, "image", "repository")
+	tag := GetStringValueWithDefault(data, defaultEtcdTag, "image", "tag")
+	if registry, _ := util.GetStringValue(data, "image", "registry"); registry != "" {
+		repository = registry + "/" + repository
+	}
+	return repository + ":" + tag
+}
+
+// getEtcdctlEnv returns the env of etcdctl to access the managed etcd
+func getEtcdctlEnv(mc v1beta1.Milvus) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "ETCDCTL_API", Value: "3"},
+	}
+	if authEnabled, _ := util.GetBoolValue(mc.Spec.Conf.Data, "etcd", "auth", "enabled"); authEnabled {
+		userName, _ := util.GetStringValue(mc.Spec.Conf.Data, "etcd", "auth", "userName")
+		password, _ := util.GetStringValue(mc.Spec.Conf.Data, "etcd", "auth", "password")
+		env = append(env, corev1.EnvVar{Name: "ETCDCTL_USER", Value: fmt.Sprintf("%s:%s", userName, password)})
+	}
+	return env
+}
+
+func (r *MilvusReconciler) updateEtcdDefragCronJob(mc v1beta1.Milvus, cronJob *batchv1.CronJob) error {
+	labels := newEtcdDefragJobLabels(mc.Name)
+	cronJob.Labels = MergeLabels(cronJob.Labels, labels)
+	if err := SetControllerReference(&mc, cronJob, r.Scheme); err != nil {
+		r.logger.Error(err, "CronJob SetControllerReference error", "name", mc.Name, "namespace", mc.Namespace)
+		return err
+	}
+
+	container := corev1.Container{
+		Name:  etcdDefragContainerName,
+		Image: getEtcdImage(mc),
+		Command: []string{
+			"etcdctl",
+			"--endpoints=" + strings.Join(mc.Spec.Dep.Etcd.Endpoints, ","),
+			"defrag",
+		},
+		Env: getEtcdctlEnv(mc),
+	}
+	fillContainerDefaultValues(&container)
+
+	cronJob.Spec.Schedule = getEtcdDefragSchedule(mc)
+	cronJob.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+	cronJob.Spec.SuccessfulJobsHistoryLimit = int32Ptr(etcdDefragJobsHistory)
+	cronJob.Spec.FailedJobsHistoryLimit = int32Ptr(etcdDefragJobsHistory)
+	cronJob.Spec.JobTemplate.Labels = labels
+	cronJob.Spec.JobTemplate.Spec.Template.Labels = labels
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	podSpec.Containers = []corev1.Container{container}
+	return nil
+}
+
+// ReconcileEtcdDefrag creates the cronjob defragmenting the managed etcd by the schedule,
+// it's deleted when the schedule is removed or the etcd is external
+func (r *MilvusReconciler) ReconcileEtcdDefrag(ctx context.Context, mc v1beta1.Milvus) error {
+	return r.reconcileEtcdCronJob(ctx, mc, getEtcdDefragCronJobName(mc.Name),
+		getEtcdDefragSchedule(mc) != "", r.updateEtcdDefragCronJob)
+}
+
+// reconcileEtcdCronJob creates or updates the cronjob of the managed etcd by updateCronJob if it's enabled,
+// otherwise deletes it
+func (r *MilvusReconciler) reconcileEtcdCronJob(ctx context.Context, mc v1beta1.Milvus, name string, enabled bool,
+	updateCronJob func(v1beta1.Milvus, *batchv1.CronJob) error) error {
+	namespacedName := NamespacedName(mc.Namespace, name)
+	old := &batchv1.CronJob{}
+	err := r.Get(ctx, namespacedName, old)
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.Wrapf(err, "get cronjob %s", name)
+	}
+	exists := err == nil
+
+	if !enabled {
+		if !exists {
+			return nil
+		}
+		r.logger.Info("Delete CronJob", "name", old.Name, "namespace", old.Namespace)
+		return errors.Wrapf(client.IgnoreNotFound(r.Delete(ctx, old)), "delete cronjob %s", name)
+	}
+
+	if !exists {
+		new := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := updateCronJob(mc, new); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create CronJob", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	}
+
+	cur := old.DeepCopy()
+	if err := updateCronJob(mc, cur); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update CronJob", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusReconciler_ReconcileEtcdDefrag(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+	r.Scheme = testScheme
+
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Dep.Etcd.InCluster.DefragSchedule = "0 3 * * 0"
+	cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mc).Build()
+	r.Client = cli
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(mc), mc))
+	cronJobKey := client.ObjectKey{Namespace: mc.Namespace, Name: getEtcdDefragCronJobName(mc.Name)}
+
+	// created for managed etcd
+	assert.NoError(t, r.ReconcileEtcdDefrag(ctx, *mc))
+	cronJob := &batchv1.CronJob{}
+	assert.NoError(t, cli.Get(ctx, cronJobKey, cronJob))
+	assert.Equal(t, "0 3 * * 0", cronJob.Spec.Schedule)
+	assert.Equal(t, batchv1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"etcdctl", "--endpoints=" + mc.Spec.Dep.Etcd.Endpoints[0], "defrag"}, container.Command)
+	assert.NotEqual(t, NewAppLabels(mc.Name), cronJob.Spec.JobTemplate.Spec.Template.Labels)
+
+	// schedule updated
+	mc.Spec.Dep.Etcd.InCluster.DefragSchedule = "0 4 * * *"
+	assert.NoError(t, r.ReconcileEtcdDefrag(ctx, *mc))
+	assert.NoError(t, cli.Get(ctx, cronJobKey, cronJob))
+	assert.Equal(t, "0 4 * * *", cronJob.Spec.Schedule)
+
+	// deleted when the etcd is external
+	mc.Spec.Dep.Etcd.External = true
+	assert.NoError(t, r.ReconcileEtcdDefrag(ctx, *mc))
+	assert.True(t, k8sErrors.IsNotFound(cli.Get(ctx, cronJobKey, cronJob)))
+
+	t.Run("absent for external etcd", func(t *testing.T) {
+		mc := env.Inst.DeepCopy()
+		mc.Spec.Dep.Etcd.External = true
+		mc.Spec.Dep.Etcd.InCluster.DefragSchedule = "0 3 * * 0"
+		cli := fake.NewClientBuilder().WithScheme(testScheme).Build()
+		r.Client = cli
+		assert.NoError(t, r.ReconcileEtcdDefrag(ctx, *mc))
+		cronJobs := &batchv1.CronJobList{}
+		assert.NoError(t, cli.List(ctx, cronJobs))
+		assert.Empty(t, cronJobs.Items)
+	})
+}
+
+func TestGetEtcdImage(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	mc.Spec.Dep.Etcd.InCluster = &v1beta1.InClusterConfig{}
+	assert.Equal(t, "milvusdb/etcd:3.5.18-r1", getEtcdImage(mc))
+
+	mc.Spec.Dep.Etcd.InCluster.Values.Data = map[string]interface{}{
+		"image": map[string]interface{}{
+			"registry":   "docker.io",
+			"repository": "bitnami/etcd",
+			"tag":        "3.5.5",
+		},
+	}
+	assert.Equal(t, "docker.io/bitnami/etcd:3.5.5", getEtcdImage(mc))
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// GracefulTerminationFinalizer holds the milvus on deletion until its components are stopped in order
+const GracefulTerminationFinalizer = "milvus.milvus.io/graceful-termination"
+
+// gracefulTerminationTiers are the components stopped one tier after another:
+// the proxies stop accepting requests first, then the nodes flush their data, the coords are stopped at last
+var gracefulTerminationTiers = [][]string{
+	{ProxyName},
+	{QueryNodeName, IndexNodeName},
+	{DataNodeName, StreamingNodeName, StandaloneName},
+	{MixCoordName, RootCoordName, DataCoordName, QueryCoordName, IndexCoordName},
+}
+
+// updateGracefulTerminationFinalizer adds or removes the finalizer by spec, returns true if it's changed
+func updateGracefulTerminationFinalizer(mc *v1beta1.Milvus) bool {
+	if mc.Spec.IsGracefulTerminationEnabled() {
+		return controllerutil.AddFinalizer(mc, GracefulTerminationFinalizer)
+	}
+	return controllerutil.RemoveFinalizer(mc, GracefulTerminationFinalizer)
+}
+
+// gracefulTerminate scales the deployments of the milvus to 0 tier by tier,
+// it returns true when all the pods are gone or the deadline is exceeded
+func (r *MilvusReconciler) gracefulTerminate(ctx context.Context, mc *v1beta1.Milvus) (bool, error) {
+	logger := ctrl.LoggerFrom(ctx)
+	deadline := mc.DeletionTimestamp.Add(mc.Spec.GetGracefulTerminationTimeout())
+	if time.Now().After(deadline) {
+		logger.Info("graceful termination exceeds deadline, continue deleting", "deadline", deadline)
+		return true, nil
+	}
+
+	deployList := &appsv1.DeploymentList{}
+	opts := &client.ListOptions{
+		Namespace: mc.Namespace,
+	}
+	opts.LabelSelector = labels.SelectorFromSet(NewAppLabels(mc.Name))
+	if err := r.List(ctx, deployList, opts); err != nil {
+		return false, errors.Wrap(err, "list deployments")
+	}
+	for _, tier := range gracefulTerminationTiers {
+		stopped, err := r.stopDeploymentsOfComponents(ctx, mc, deployList.Items, tier)
+		if err != nil || !stopped {
+			return false, err
+		}
+	}
+
+	terminatingPods, err := ListMilvusTerminatingPods(ctx, r.Client, *mc)
+	if err != nil {
+		return false, errors.Wrap(err, "list terminating pods")
+	}
+	if len(terminatingPods.Items) > 0 {
+		logger.Info("graceful termination: waiting for terminating pods", "count", len(terminatingPods.Items))
+		return false, nil
+	}
+	return true, nil
+}
+
+// stopDeploymentsOfComponents scales the deployments of the components to 0,
+// returns true when all the pods of them are gone, which means the data is flushed
+func (r *MilvusReconciler) stopDeploymentsOfComponents(ctx context.Context, mc *v1beta1.Milvus, deploys []appsv1.Deployment, components []string) (bool, error) {
+	logger := ctrl.LoggerFrom(ctx)
+	stopped := true
+	for i := range deploys {
+		deploy := &deploys[i]
+		if !metav1.IsControlledBy(deploy, mc) || !slices.Contains(components, deploy.Labels[AppLabelComponent]) {
+			continue
+		}
+		if getDeployReplicas(deploy) != 0 {
+			logger.Info("graceful termination: stopping deployment", "deployment", deploy.Name)
+			deploy.Spec.Replicas = int32Ptr(0)
+			if err := r.Update(ctx, deploy); err != nil {
+				return false, errors.Wrapf(err, "stop deployment %s", deploy.Name)
+			}
+		}
+		if deploy.Status.Replicas > 0 {
+			stopped = false
+		}
+	}
+	return stopped, nil
+}
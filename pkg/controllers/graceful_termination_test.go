@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestUpdateGracefulTerminationFinalizer(t *testing.T) {
+	mc := &v1beta1.Milvus{}
+	assert.False(t, updateGracefulTerminationFinalizer(mc))
+
+	mc.Spec.GracefulTermination = &v1beta1.MilvusGracefulTermination{Enabled: true}
+	assert.True(t, updateGracefulTerminationFinalizer(mc))
+	assert.Equal(t, []string{GracefulTerminationFinalizer}, mc.Finalizers)
+	assert.False(t, updateGracefulTerminationFinalizer(mc))
+
+	mc.Spec.GracefulTermination.Enabled = false
+	assert.True(t, updateGracefulTerminationFinalizer(mc))
+	assert.Empty(t, mc.Finalizers)
+}
+
+func TestMilvusReconciler_gracefulTerminate(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+
+	mc := env.Inst.DeepCopy()
+	mc.UID = "uid"
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Spec.GracefulTermination = &v1beta1.MilvusGracefulTermination{Enabled: true}
+	mc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	newDeploy := func(component MilvusComponent) *appsv1.Deployment {
+		deploy := &appsv1.Deployment{}
+		deploy.Name = component.GetDeploymentName(mc.Name)
+		deploy.Namespace = mc.Namespace
+		deploy.Labels = NewComponentAppLabels(mc.Name, component.Name)
+		deploy.OwnerReferences = []metav1.OwnerReference{{UID: mc.UID, Controller: boolPtr(true)}}
+		deploy.Spec.Replicas = int32Ptr(1)
+		deploy.Status.Replicas = 1
+		return deploy
+	}
+	cli := fake.NewClientBuilder().WithScheme(testScheme).
+		WithObjects(newDeploy(Proxy), newDeploy(DataNode), newDeploy(MixCoord)).
+		WithStatusSubresource(&appsv1.Deployment{}).Build()
+	r.Client = cli
+
+	getReplicas := func(component MilvusComponent) int {
+		deploy := &appsv1.Deployment{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: component.GetDeploymentName(mc.Name)}, deploy))
+		return getDeployReplicas(deploy)
+	}
+	podsGone := func(component MilvusComponent) {
+		deploy := &appsv1.Deployment{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: component.GetDeploymentName(mc.Name)}, deploy))
+		deploy.Status.Replicas = 0
+		assert.NoError(t, cli.Status().Update(ctx, deploy))
+	}
+
+	// proxy stopped first
+	terminated, err := r.gracefulTerminate(ctx, mc)
+	assert.NoError(t, err)
+	assert.False(t, terminated)
+	assert.Equal(t, 0, getReplicas(Proxy))
+	assert.Equal(t, 1, getReplicas(DataNode))
+	assert.Equal(t, 1, getReplicas(MixCoord))
+
+	// then the nodes flush
+	podsGone(Proxy)
+	terminated, err = r.gracefulTerminate(ctx, mc)
+	assert.NoError(t, err)
+	assert.False(t, terminated)
+	assert.Equal(t, 0, getReplicas(DataNode))
+	assert.Equal(t, 1, getReplicas(MixCoord))
+
+	// coords at last
+	podsGone(DataNode)
+	terminated, err = r.gracefulTerminate(ctx, mc)
+	assert.NoError(t, err)
+	assert.False(t, terminated)
+	assert.Equal(t, 0, getReplicas(MixCoord))
+
+	podsGone(MixCoord)
+	terminated, err = r.gracefulTerminate(ctx, mc)
+	assert.NoError(t, err)
+	assert.True(t, terminated)
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		expired := mc.DeepCopy()
+		expired.Spec.GracefulTermination.TimeoutSeconds = 60
+		expired.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		terminated, err := r.gracefulTerminate(ctx, expired)
+		assert.NoError(t, err)
+		assert.True(t, terminated)
+	})
+}
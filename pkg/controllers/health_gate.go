@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+//go:generate mockgen -package=controllers -source=health_gate.go -destination=health_gate_mock.go
+
+// HealthGateClient calls the external health gate webhook of milvus
+type HealthGateClient interface {
+	// Check returns nil if the health gate accepts milvus to be healthy
+	Check(ctx context.Context, url string) error
+}
+
+type healthGateClientImpl struct {
+	httpClient *http.Client
+}
+
+func newHealthGateClientImpl() *healthGateClientImpl {
+	return &healthGateClientImpl{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c healthGateClientImpl) Check(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "new request")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "call health gate webhook")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("health gate webhook returns status[%d]: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// checkHealthGate calls the health gate webhook when milvus is transitioning to healthy,
+// it returns false if milvus should not be marked as healthy yet
+func (r *MilvusStatusSyncer) checkHealthGate(ctx context.Context, mc *v1beta1.Milvus, statusInfo MilvusHealthStatusInfo) bool {
+	if mc.Spec.HealthGateWebhook == "" {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.HealthGatePassed})
+		return true
+	}
+	if !statusInfo.IsHealthy ||
+		statusInfo.IsStopping ||
+		statusInfo.LastState == v1beta1.StatusHealthy {
+		return true
+	}
+	err := r.healthGateClient.Check(ctx, mc.Spec.HealthGateWebhook)
+	if err != nil {
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+			Type:    v1beta1.HealthGatePassed,
+			Status:  corev1.ConditionFalse,
+			Reason:  v1beta1.ReasonHealthGateFailed,
+			Message: err.Error(),
+		})
+		return false
+	}
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.HealthGatePassed,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonHealthGatePassed,
+		Message: "Health gate webhook passed",
+	})
+	return true
+}
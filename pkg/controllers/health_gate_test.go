@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusStatusSyncer_checkHealthGate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCli := NewMockK8sClient(ctrl)
+	mockGate := NewMockHealthGateClient(ctrl)
+	ctx := context.Background()
+	s := NewMilvusStatusSyncer(ctx, mockCli, logf.Log.WithName("test"))
+	s.healthGateClient = mockGate
+
+	const webhook = "http://smoke-test.ns/check"
+	newPendingMilvus := func() *v1beta1.Milvus {
+		mc := &v1beta1.Milvus{}
+		mc.Spec.HealthGateWebhook = webhook
+		mc.Status.Status = v1beta1.StatusPending
+		return mc
+	}
+	// gateStatus returns the final status computed the same way as UpdateStatusForNewGeneration
+	gateStatus := func(mc *v1beta1.Milvus, statusInfo MilvusHealthStatusInfo) v1beta1.MilvusHealthStatus {
+		if !s.checkHealthGate(ctx, mc, statusInfo) {
+			statusInfo.IsHealthy = false
+		}
+		return statusInfo.GetMilvusHealthStatus()
+	}
+
+	t.Run("gate passed becomes healthy", func(t *testing.T) {
+		mc := newPendingMilvus()
+		mockGate.EXPECT().Check(gomock.Any(), webhook).Return(nil)
+		status := gateStatus(mc, MilvusHealthStatusInfo{LastState: v1beta1.StatusPending, IsHealthy: true})
+		assert.Equal(t, v1beta1.StatusHealthy, status)
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.HealthGatePassed)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+		assert.Equal(t, v1beta1.ReasonHealthGatePassed, cond.Reason)
+	})
+
+	t.Run("gate failed keeps pending", func(t *testing.T) {
+		mc := newPendingMilvus()
+		mockGate.EXPECT().Check(gomock.Any(), webhook).Return(errors.New("status 503"))
+		status := gateStatus(mc, MilvusHealthStatusInfo{LastState: v1beta1.StatusPending, IsHealthy: true})
+		assert.Equal(t, v1beta1.StatusPending, status)
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.HealthGatePassed)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonHealthGateFailed, cond.Reason)
+		assert.Contains(t, cond.Message, "status 503")
+	})
+
+	t.Run("not called when already healthy or not ready", func(t *testing.T) {
+		mc := newPendingMilvus()
+		status := gateStatus(mc, MilvusHealthStatusInfo{LastState: v1beta1.StatusHealthy, IsHealthy: true})
+		assert.Equal(t, v1beta1.StatusHealthy, status)
+		status = gateStatus(mc, MilvusHealthStatusInfo{LastState: v1beta1.StatusPending, IsHealthy: false})
+		assert.Equal(t, v1beta1.StatusPending, status)
+	})
+
+	t.Run("webhook not set removes condition", func(t *testing.T) {
+		mc := newPendingMilvus()
+		mc.Status.Conditions = []v1beta1.MilvusCondition{{Type: v1beta1.HealthGatePassed}}
+		mc.Spec.HealthGateWebhook = ""
+		status := gateStatus(mc, MilvusHealthStatusInfo{LastState: v1beta1.StatusPending, IsHealthy: true})
+		assert.Equal(t, v1beta1.StatusHealthy, status)
+		assert.Empty(t, mc.Status.Conditions)
+	})
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// hpaConflict is a component scaled by a HorizontalPodAutoscaler while its replicas in spec is not -1
+type hpaConflict struct {
+	component MilvusComponent
+	hpa       string
+}
+
+// getComponentDeployNames returns the names of all the deployments the component may have,
+// including the ones of the 2 deployments mode
+func getComponentDeployNames(mc v1beta1.Milvus, component MilvusComponent) []string {
+	return []string{
+		component.GetDeploymentName(mc.Name),
+		formatComponentDeployName(mc, component, 0),
+		formatComponentDeployName(mc, component, 1),
+	}
+}
+
+// getHPAConflicts returns the components whose deployments are targeted by HPAs in the namespace of milvus,
+// while their replicas in spec are not -1. The disabled components are not counted
+func getHPAConflicts(ctx context.Context, cli client.Client, mc v1beta1.Milvus) ([]hpaConflict, error) {
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := cli.List(ctx, hpaList, client.InNamespace(mc.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "list hpa")
+	}
+	if len(hpaList.Items) < 1 {
+		return nil, nil
+	}
+	hpaByDeploy := make(map[string]string)
+	for _, hpa := range hpaList.Items {
+		target := hpa.Spec.ScaleTargetRef
+		if target.Kind != "Deployment" {
+			continue
+		}
+		hpaByDeploy[target.Name] = hpa.Name
+	}
+
+	var ret []hpaConflict
+	for _, component := range GetDeploymentComponentsBySpec(mc.Spec) {
+		if !component.IsEnabled(mc.Spec) || ReplicasValue(component.GetReplicas(mc.Spec)) < 0 {
+			continue
+		}
+		for _, deployName := range getComponentDeployNames(mc, component) {
+			if hpa, ok := hpaByDeploy[deployName]; ok {
+				ret = append(ret, hpaConflict{component: component, hpa: hpa})
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+// preferHPAReplicas returns a copy of milvus whose conflicting components' replicas are set to -1,
+// so that their deployments are left to the HPAs as if they're configured in spec
+func preferHPAReplicas(mc v1beta1.Milvus, conflicts []hpaConflict) v1beta1.Milvus {
+	if len(conflicts) < 1 {
+		return mc
+	}
+	ret := *mc.DeepCopy()
+	for _, conflict := range conflicts {
+		if group := conflict.component.getStreamingNodeGroup(ret.Spec); group != nil {
+			group.Replicas = int32Ptr(-1)
+			continue
+		}
+		// the component field is not nil, otherwise it's not enabled
+		_ = conflict.component.SetReplicas(ret.Spec, int32Ptr(-1))
+	}
+	return ret
+}
+
+// updateReplicasConflictCondition sets the ReplicasConflict condition & emits a warning event if there're conflicts,
+// returns true if the conditions are changed
+func (r *MilvusReconciler) updateReplicasConflictCondition(mc *v1beta1.Milvus, conflicts []hpaConflict) bool {
+	oldConditions := mc.Status.DeepCopy().Conditions
+	if len(conflicts) < 1 {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.ReplicasConflict})
+		return !IsEqual(oldConditions, mc.Status.Conditions)
+	}
+
+	items := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		items = append(items, fmt.Sprintf("%s(hpa %s)", conflict.component.GetName(), conflict.hpa))
+	}
+	sort.Strings(items)
+	msg := fmt.Sprintf("replicas in spec of %s are ignored as they're scaled by HPA, set their replicas to -1 to resolve",
+		strings.Join(items, ", "))
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.ReplicasConflict,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonHPAReplicasConflict,
+		Message: msg,
+	})
+	changed := !IsEqual(oldConditions, mc.Status.Conditions)
+	if changed && r.eventRecorder != nil {
+		r.eventRecorder.Event(mc, corev1.EventTypeWarning, v1beta1.ReasonHPAReplicasConflict, msg)
+	}
+	return changed
+}
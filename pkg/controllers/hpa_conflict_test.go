@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func newTestHPA(name, kind, target string) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       kind,
+				Name:       target,
+			},
+			MaxReplicas: 5,
+		},
+	}
+}
+
+func TestHPAConflict(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	recorder := record.NewFakeRecorder(10)
+	r.eventRecorder = recorder
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Com.Proxy.Replicas = int32Ptr(2)
+	mc.Spec.Com.QueryNode.Replicas = int32Ptr(-1)
+	mc.Spec.Com.DataNode.Replicas = int32Ptr(1)
+
+	cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		newTestHPA("proxy-hpa", "Deployment", Proxy.GetDeploymentName(mc.Name)),
+		// replicas -1 in spec, not conflicting
+		newTestHPA("querynode-hpa", "Deployment", formatComponentDeployName(*mc, QueryNode, 1)),
+		// not a deployment
+		newTestHPA("datanode-hpa", "StatefulSet", DataNode.GetDeploymentName(mc.Name)),
+		newTestHPA("other-hpa", "Deployment", "other"),
+	).Build()
+
+	conflicts, err := getHPAConflicts(ctx, cli, *mc)
+	assert.NoError(t, err)
+	assert.Equal(t, []hpaConflict{{component: Proxy, hpa: "proxy-hpa"}}, conflicts)
+
+	t.Run("no hpa", func(t *testing.T) {
+		conflicts, err := getHPAConflicts(ctx, fake.NewClientBuilder().WithScheme(testScheme).Build(), *mc)
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("hpa in other namespace not counted", func(t *testing.T) {
+		hpa := newTestHPA("proxy-hpa", "Deployment", Proxy.GetDeploymentName(mc.Name))
+		hpa.Namespace = "other"
+		cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(hpa).Build()
+		conflicts, err := getHPAConflicts(ctx, cli, *mc)
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("disabled component not counted", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mc.Spec.Com.Proxy.Enabled = new(bool)
+		conflicts, err := getHPAConflicts(ctx, cli, *mc)
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("list failed", func(t *testing.T) {
+		cli := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+		_, err := getHPAConflicts(ctx, cli, *mc)
+		assert.Error(t, err)
+	})
+
+	t.Run("resolved by preferring hpa", func(t *testing.T) {
+		resolved := preferHPAReplicas(*mc, conflicts)
+		assert.Equal(t, int32(-1), ReplicasValue(Proxy.GetReplicas(resolved.Spec)))
+		assert.Equal(t, int32(1), ReplicasValue(DataNode.GetReplicas(resolved.Spec)))
+		// the spec of milvus is untouched
+		assert.Equal(t, int32(2), ReplicasValue(Proxy.GetReplicas(mc.Spec)))
+
+		updater := newMilvusDeploymentUpdater(resolved, r.Scheme, Proxy)
+		assert.True(t, updater.IsHPAEnabled())
+		deploy := &appsv1.Deployment{}
+		deploy.Spec.Replicas = int32Ptr(4)
+		updateDeploymentReplicas(deploy, updater)
+		assert.Equal(t, int32(4), *deploy.Spec.Replicas)
+
+		assert.Equal(t, *mc, preferHPAReplicas(*mc, nil))
+	})
+
+	t.Run("resolved for streaming node group", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mc.Spec.Com.StreamingNode = &v1beta1.MilvusStreamingNode{}
+		mc.Spec.Com.StreamingNode.Replicas = int32Ptr(1)
+		mc.Spec.Com.StreamingNode.Groups = []v1beta1.StreamingNodeGroup{{Name: "a", Replicas: int32Ptr(2)}}
+		group := StreamingNode.WithGroup("a")
+		resolved := preferHPAReplicas(*mc, []hpaConflict{{component: group, hpa: "a-hpa"}})
+		assert.Equal(t, int32(-1), ReplicasValue(group.GetReplicas(resolved.Spec)))
+		assert.Equal(t, int32(2), ReplicasValue(group.GetReplicas(mc.Spec)))
+	})
+
+	t.Run("condition & event", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		assert.True(t, r.updateReplicasConflictCondition(mc, conflicts))
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ReplicasConflict)
+		assert.NotNil(t, cond)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+		assert.Equal(t, v1beta1.ReasonHPAReplicasConflict, cond.Reason)
+		assert.Contains(t, cond.Message, "proxy(hpa proxy-hpa)")
+		event := <-recorder.Events
+		assert.Contains(t, event, corev1.EventTypeWarning)
+		assert.Contains(t, event, v1beta1.ReasonHPAReplicasConflict)
+
+		// not emitted again when unchanged
+		assert.False(t, r.updateReplicasConflictCondition(mc, conflicts))
+		assert.Empty(t, recorder.Events)
+
+		assert.True(t, r.updateReplicasConflictCondition(mc, nil))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ReplicasConflict))
+	})
+}
@@ -16,27 +16,39 @@ import (
 
 func (r *MilvusReconciler) ReconcileIngress(ctx context.Context, mc v1beta1.Milvus) error {
 	ingress := mc.Spec.GetServiceComponent().Ingress
+	restfulIngressKey := NamespacedName(mc.Namespace, getRESTfulIngressName(mc.Name))
 	if ingress == nil {
-		return nil
+		return r.deleteIfExists(ctx, &networkingv1.Ingress{}, restfulIngressKey)
+	}
+	err := reconcileIngress(ctx, r.logger, r.Client, r.Scheme, &mc, ingressRenderer.Render(&mc, *ingress))
+	if err != nil {
+		return err
 	}
-	return reconcileIngress(ctx, r.logger, r.Client, r.Scheme, &mc, *ingress)
+	if ingress.RESTful == nil {
+		return r.deleteIfExists(ctx, &networkingv1.Ingress{}, restfulIngressKey)
+	}
+	return reconcileIngress(ctx, r.logger, r.Client, r.Scheme, &mc, ingressRenderer.RenderRESTful(&mc, *ingress))
 }
 
-func reconcileIngress(ctx context.Context, logger logr.Logger,
-	cli client.Client, scheme *runtime.Scheme, crd client.Object, ingress v1beta1.MilvusIngress) error {
+func getIngressName(instance string) string {
+	return instance + "-milvus"
+}
+
+func getRESTfulIngressName(instance string) string {
+	return instance + "-milvus-restful"
+}
 
-	new := ingressRenderer.Render(crd, ingress)
+func reconcileIngress(ctx context.Context, logger logr.Logger,
+	cli client.Client, scheme *runtime.Scheme, crd client.Object, new *networkingv1.Ingress) error {
 
 	if err := SetControllerReference(crd, new, scheme); err != nil {
 		return errors.Wrap(err, "failed to set controller reference")
 	}
 
 	old := &networkingv1.Ingress{}
-	key := client.ObjectKeyFromObject(crd)
-	key.Name += "-milvus"
-	err := cli.Get(ctx, key, old)
+	err := cli.Get(ctx, client.ObjectKeyFromObject(new), old)
 	if kerrors.IsNotFound(err) {
-		logger.Info("Create Ingress")
+		logger.Info("Create Ingress", "name", new.Name)
 		err = cli.Create(ctx, new)
 		return errors.Wrap(err, "failed to create ingress")
 	} else if err != nil {
@@ -55,7 +67,7 @@ func reconcileIngress(ctx context.Context, logger logr.Logger,
 	new.ObjectMeta = *meta
 	new.Status = *old.Status.DeepCopy()
 
-	logger.Info("Update Ingress")
+	logger.Info("Update Ingress", "name", new.Name)
 	err = cli.Update(ctx, new)
 	return errors.Wrap(err, "failed to update ingress")
 }
@@ -63,6 +75,7 @@ func reconcileIngress(ctx context.Context, logger logr.Logger,
 //go:generate mockgen -package=controllers -source=ingress.go -destination=ingress_mock.go ingressRendererInterface
 type ingressRendererInterface interface {
 	Render(crd client.Object, spec v1beta1.MilvusIngress) *networkingv1.Ingress
+	RenderRESTful(crd client.Object, spec v1beta1.MilvusIngress) *networkingv1.Ingress
 }
 
 // ingressRender singleton
@@ -73,13 +86,34 @@ type ingressRendererImpl struct {
 
 // Render implements ingressRenderInterface
 func (r *ingressRendererImpl) Render(crd client.Object, spec v1beta1.MilvusIngress) *networkingv1.Ingress {
+	return renderIngress(crd, getIngressName(crd.GetName()), spec, spec.Annotations, spec.Hosts, MilvusPort)
+}
+
+// RenderRESTful implements ingressRenderInterface
+func (r *ingressRendererImpl) RenderRESTful(crd client.Object, spec v1beta1.MilvusIngress) *networkingv1.Ingress {
+	hosts := spec.RESTful.Hosts
+	if len(hosts) == 0 {
+		hosts = spec.Hosts
+	}
+	port := int32(MetricPort)
+	if mc, ok := crd.(*v1beta1.Milvus); ok {
+		serviceComponent := MilvusStandalone
+		if mc.Spec.Mode == v1beta1.MilvusModeCluster {
+			serviceComponent = Proxy
+		}
+		port = serviceComponent.GetMetricPort(mc.Spec)
+	}
+	return renderIngress(crd, getRESTfulIngressName(crd.GetName()), spec, spec.RESTful.Annotations, hosts, port)
+}
+
+func renderIngress(crd client.Object, name string, spec v1beta1.MilvusIngress, annotations map[string]string, hosts []string, port int32) *networkingv1.Ingress {
 	ingress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      crd.GetName() + "-milvus",
+			Name:      name,
 			Namespace: crd.GetNamespace(),
 			// TODO: we need provide merge common labels of milvus
 			Labels:      spec.Labels,
-			Annotations: spec.Annotations,
+			Annotations: annotations,
 		},
 	}
 
@@ -94,7 +128,7 @@ func (r *ingressRendererImpl) Render(crd client.Object, spec v1beta1.MilvusIngre
 
 	pathType := networkingv1.PathTypePrefix
 
-	for _, host := range spec.Hosts {
+	for _, host := range hosts {
 		ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1.IngressRule{
 			Host: host,
 			IngressRuleValue: networkingv1.IngressRuleValue{
@@ -107,7 +141,7 @@ func (r *ingressRendererImpl) Render(crd client.Object, spec v1beta1.MilvusIngre
 								Service: &networkingv1.IngressServiceBackend{
 									Name: GetServiceInstanceName(crd.GetName()),
 									Port: networkingv1.ServiceBackendPort{
-										Number: MilvusPort,
+										Number: port,
 									},
 								},
 							},
@@ -6,10 +6,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
 )
@@ -40,6 +42,9 @@ func TestMilvusClusterReconciler_ReconcileIngress(t *testing.T) {
 	mc.Spec.Mode = v1beta1.MilvusModeCluster
 	mc.Default()
 	t.Run("disabled", func(t *testing.T) {
+		defer env.checkMocks()
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName(mc.Namespace, getRESTfulIngressName(mc.Name)), gomock.Any()).
+			Return(kerrors.NewNotFound(networkingv1.Resource("ingress"), "test"))
 		err := r.ReconcileIngress(ctx, mc)
 		assert.NoError(t, err)
 	})
@@ -74,6 +79,9 @@ func TestMilvusClusterReconciler_ReconcileIngress(t *testing.T) {
 	t.Run("ingress found, equal not update", func(t *testing.T) {
 		defer env.checkMocks()
 		mockRenderer.EXPECT().Render(gomock.Any(), gomock.Any()).Return(&rendered)
+		// restful ingress disabled
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName(mc.Namespace, getRESTfulIngressName(mc.Name)), gomock.Any()).
+			Return(kerrors.NewNotFound(networkingv1.Resource("ingress"), "test"))
 		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Do(
 			func(any1, any2, input interface{}, opt ...any) {
 				ingress := input.(*networkingv1.Ingress)
@@ -100,6 +108,37 @@ func TestMilvusClusterReconciler_ReconcileIngress(t *testing.T) {
 		assert.Equal(t, finalizers, rendered.Finalizers)
 		assert.Error(t, err)
 	})
+
+	mc.Spec.Com.Proxy.Ingress.RESTful = &v1beta1.MilvusIngressBackend{}
+	t.Run("restful ingress created", func(t *testing.T) {
+		defer env.checkMocks()
+		grpcIngress := &networkingv1.Ingress{}
+		grpcIngress.Name = getIngressName(mc.Name)
+		restfulIngress := &networkingv1.Ingress{}
+		restfulIngress.Name = getRESTfulIngressName(mc.Name)
+		mockRenderer.EXPECT().Render(gomock.Any(), gomock.Any()).Return(grpcIngress)
+		mockRenderer.EXPECT().RenderRESTful(gomock.Any(), gomock.Any()).Return(restfulIngress)
+		notFound := kerrors.NewNotFound(networkingv1.Resource("ingress"), "test")
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(notFound).Times(2)
+		mockClient.EXPECT().Create(gomock.Any(), grpcIngress).Return(nil)
+		mockClient.EXPECT().Create(gomock.Any(), restfulIngress).Return(nil)
+		err := r.ReconcileIngress(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	mc.Spec.Com.Proxy.Ingress.RESTful = nil
+	t.Run("restful ingress disabled, delete", func(t *testing.T) {
+		defer env.checkMocks()
+		grpcIngress := &networkingv1.Ingress{}
+		grpcIngress.Name = getIngressName(mc.Name)
+		mockRenderer.EXPECT().Render(gomock.Any(), gomock.Any()).Return(grpcIngress)
+		mockClient.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(grpcIngress), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Update(gomock.Any(), grpcIngress).Return(nil)
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName(mc.Namespace, getRESTfulIngressName(mc.Name)), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1.Ingress{})).Return(nil)
+		err := r.ReconcileIngress(ctx, mc)
+		assert.NoError(t, err)
+	})
 }
 
 func TestIngressRenderer_Render(t *testing.T) {
@@ -128,3 +167,45 @@ func TestIngressRenderer_Render(t *testing.T) {
 	assert.Len(t, ingress.Spec.TLS, 2)
 	assert.Len(t, ingress.Spec.TLS[0].Hosts, 2)
 }
+
+func TestIngressRenderer_RenderRESTful(t *testing.T) {
+	env := newTestEnv(t)
+	mc := env.Inst
+	ingressSpec := v1beta1.MilvusIngress{
+		Labels:      map[string]string{"label1": "value1"},
+		Annotations: map[string]string{"backend-protocol": "GRPC"},
+		Hosts:       []string{"host1", "host2"},
+		TLSSecretRefs: map[string][]string{
+			"secret1": {"host1", "host2"},
+		},
+		RESTful: &v1beta1.MilvusIngressBackend{
+			Annotations: map[string]string{"backend-protocol": "HTTP"},
+		},
+	}
+	renderer := ingressRendererImpl{}
+	grpcIngress := renderer.Render(&mc, ingressSpec)
+	restfulIngress := renderer.RenderRESTful(&mc, ingressSpec)
+	assert.Equal(t, mc.Name+"-milvus-restful", restfulIngress.Name)
+	assert.Equal(t, ingressSpec.Labels, restfulIngress.Labels)
+	assert.Equal(t, "HTTP", restfulIngress.Annotations["backend-protocol"])
+	assert.Equal(t, "GRPC", grpcIngress.Annotations["backend-protocol"])
+	assert.Len(t, restfulIngress.Spec.TLS, 1)
+
+	// hosts default to the grpc ones
+	assert.Len(t, restfulIngress.Spec.Rules, 2)
+	for i, rule := range restfulIngress.Spec.Rules {
+		assert.Equal(t, ingressSpec.Hosts[i], rule.Host)
+		assert.Equal(t, int32(MetricPort), rule.HTTP.Paths[0].Backend.Service.Port.Number)
+		assert.Equal(t, int32(MilvusPort), grpcIngress.Spec.Rules[i].HTTP.Paths[0].Backend.Service.Port.Number)
+	}
+
+	ingressSpec.RESTful.Hosts = []string{"rest-host"}
+	restfulIngress = renderer.RenderRESTful(&mc, ingressSpec)
+	assert.Len(t, restfulIngress.Spec.Rules, 1)
+	assert.Equal(t, "rest-host", restfulIngress.Spec.Rules[0].Host)
+
+	// the overridden metric port of the service
+	mc.Spec.Com.Standalone.Ports = []corev1.ContainerPort{{Name: MetricPortName, ContainerPort: 19091}}
+	restfulIngress = renderer.RenderRESTful(&mc, ingressSpec)
+	assert.Equal(t, int32(19091), restfulIngress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// getFailedInitContainerStatus returns the status of the config init container if it restarted beyond the threshold
+func getFailedInitContainerStatus(pod corev1.Pod, threshold int32) *corev1.ContainerStatus {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name == configContainerName && !status.Ready && status.RestartCount >= threshold {
+			return &status
+		}
+	}
+	return nil
+}
+
+// updateInitContainerCondition sets the InitContainerFailed condition
+// if the config init container of any milvus pod restarted beyond the spec.components.initContainerFailureThreshold
+func (r *MilvusStatusSyncer) updateInitContainerCondition(ctx context.Context, mc *v1beta1.Milvus) error {
+	threshold := mc.Spec.Com.InitContainerFailureThreshold
+	if threshold == nil {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.InitContainerFailed})
+		return nil
+	}
+	pods, err := listMilvusPods(ctx, r.Client, *mc)
+	if err != nil {
+		return errors.Wrap(err, "list milvus pods")
+	}
+	var failedPods []string
+	var containerMsg string
+	for _, pod := range pods.Items {
+		status := getFailedInitContainerStatus(pod, *threshold)
+		if status == nil {
+			continue
+		}
+		failedPods = append(failedPods, pod.Name)
+		if containerMsg == "" {
+			containerMsg = GetContainerMessage(*status)
+		}
+	}
+	if len(failedPods) < 1 {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.InitContainerFailed})
+		return nil
+	}
+	msg := fmt.Sprintf("config init container of pods[%s] restarted at least %d times, %s",
+		strings.Join(failedPods, ","), *threshold, containerMsg)
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.InitContainerFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonConfigInitFailed,
+		Message: msg,
+	})
+	return nil
+}
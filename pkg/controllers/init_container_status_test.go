@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusStatusSyncer_updateInitContainerCondition(t *testing.T) {
+	ctx := context.Background()
+	mc := &v1beta1.Milvus{}
+	mc.Namespace = "ns"
+	mc.Name = "mc"
+	threshold := int32(3)
+	mc.Spec.Com.InitContainerFailureThreshold = &threshold
+
+	pod := &corev1.Pod{}
+	pod.Namespace = mc.Namespace
+	pod.Name = "querynode-pod"
+	pod.Labels = NewComponentAppLabels(mc.Name, QueryNodeName)
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         configContainerName,
+			RestartCount: 5,
+			State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: containerReasonCrashLoopBackOff},
+			},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "merge config failed"},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+	s := NewMilvusStatusSyncer(ctx, cli, logf.Log.WithName("test"))
+
+	// repeated failures
+	assert.NoError(t, s.updateInitContainerCondition(ctx, mc))
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.InitContainerFailed)
+	assert.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, v1beta1.ReasonConfigInitFailed, cond.Reason)
+	assert.Contains(t, cond.Message, "querynode-pod")
+	assert.Contains(t, cond.Message, "merge config failed")
+
+	t.Run("below threshold", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		threshold := int32(10)
+		mc.Spec.Com.InitContainerFailureThreshold = &threshold
+		assert.NoError(t, s.updateInitContainerCondition(ctx, mc))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.InitContainerFailed))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mc.Spec.Com.InitContainerFailureThreshold = nil
+		assert.NoError(t, s.updateInitContainerCondition(ctx, mc))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.InitContainerFailed))
+	})
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// ResolveKafkaBrokerList returns the kafka broker list of milvus,
+// it's read from the secret if BrokerListFromSecret is set, otherwise the BrokerList is returned
+func ResolveKafkaBrokerList(ctx context.Context, cli client.Client, mc v1beta1.Milvus) ([]string, error) {
+	selector := mc.Spec.Dep.Kafka.BrokerListFromSecret
+	if selector == nil {
+		return mc.Spec.Dep.Kafka.BrokerList, nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: mc.Namespace, Name: selector.Name}
+	err := cli.Get(ctx, key, secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get kafka broker list secret[%s]", selector.Name)
+	}
+	value, found := secret.Data[selector.Key]
+	if !found {
+		return nil, errors.Errorf("key[%s] not found in kafka broker list secret[%s]", selector.Key, selector.Name)
+	}
+	var brokers []string
+	for _, broker := range strings.Split(string(value), ",") {
+		broker = strings.TrimSpace(broker)
+		if broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) < 1 {
+		return nil, errors.Errorf("empty broker list in kafka broker list secret[%s] key[%s]", selector.Name, selector.Key)
+	}
+	return brokers, nil
+}
+
+// withResolvedKafkaBrokers returns the milvus with the broker list resolved from the secret if it's configured,
+// so that the config checksum of the deployments changes with the secret. It's not written back to the milvus
+func withResolvedKafkaBrokers(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (v1beta1.Milvus, error) {
+	if mc.Spec.Dep.MsgStreamType != v1beta1.MsgStreamTypeKafka || mc.Spec.Dep.Kafka.BrokerListFromSecret == nil {
+		return mc, nil
+	}
+	brokers, err := ResolveKafkaBrokerList(ctx, cli, mc)
+	if err != nil {
+		return mc, err
+	}
+	mc.Spec.Dep.Kafka.BrokerList = brokers
+	return mc, nil
+}
+
+// kafkaBrokersSecretToMilvus maps a secret to the milvus in its namespace reading the kafka broker list from it
+func (r *MilvusReconciler) kafkaBrokersSecretToMilvus(ctx context.Context, obj client.Object) []reconcile.Request {
+	milvuses := &v1beta1.MilvusList{}
+	if err := r.List(ctx, milvuses, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.logger.Error(err, "list milvus for kafka broker list secret", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return nil
+	}
+	var ret []reconcile.Request
+	for _, mc := range milvuses.Items {
+		selector := mc.Spec.Dep.Kafka.BrokerListFromSecret
+		if selector == nil || selector.Name != obj.GetName() {
+			continue
+		}
+		ret = append(ret, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mc)})
+	}
+	return ret
+}
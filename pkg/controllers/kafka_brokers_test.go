@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestResolveKafkaBrokerList(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kafka-brokers"},
+		Data: map[string][]byte{
+			"brokers": []byte("kafka-0:9092, kafka-1:9092,"),
+			"empty":   []byte(" "),
+		},
+	}
+	cli := fake.NewClientBuilder().WithObjects(secret).Build()
+	mc := v1beta1.Milvus{}
+	mc.Namespace = "ns"
+	mc.Spec.Dep.Kafka.BrokerList = []string{"kafka:9092"}
+
+	t.Run("from spec", func(t *testing.T) {
+		brokers, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kafka:9092"}, brokers)
+	})
+
+	mc.Spec.Dep.Kafka.BrokerListFromSecret = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-brokers"},
+		Key:                  "brokers",
+	}
+
+	t.Run("from secret", func(t *testing.T) {
+		brokers, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kafka-0:9092", "kafka-1:9092"}, brokers)
+	})
+
+	t.Run("re-read when secret changed", func(t *testing.T) {
+		updated := &corev1.Secret{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(secret), updated))
+		updated.Data["brokers"] = []byte("kafka-2:9092")
+		assert.NoError(t, cli.Update(ctx, updated))
+		brokers, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kafka-2:9092"}, brokers)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Dep.Kafka.BrokerListFromSecret.Key = "notexist"
+		_, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Dep.Kafka.BrokerListFromSecret.Key = "empty"
+		_, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Dep.Kafka.BrokerListFromSecret.Name = "notexist"
+		_, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		assert.Error(t, err)
+	})
+
+	t.Run("msgstream condition with missing key", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeKafka
+		mc.Spec.Dep.Kafka.BrokerListFromSecret.Key = "notexist"
+		s := NewMilvusStatusSyncer(ctx, cli, logf.Log.WithName("test"))
+		ret, err := s.GetMsgStreamCondition(ctx, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonSecretErr, ret.Reason)
+	})
+}
+
+func TestWithResolvedKafkaBrokers(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kafka-brokers"},
+		Data:       map[string][]byte{"brokers": []byte("kafka-0:9092")},
+	}
+	cli := fake.NewClientBuilder().WithObjects(secret).Build()
+	mc := v1beta1.Milvus{}
+	mc.Namespace = "ns"
+	mc.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeKafka
+	mc.Spec.Dep.Kafka.BrokerListFromSecret = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-brokers"},
+		Key:                  "brokers",
+	}
+
+	ret, err := withResolvedKafkaBrokers(ctx, cli, mc)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kafka-0:9092"}, ret.Spec.Dep.Kafka.BrokerList)
+	assert.Empty(t, mc.Spec.Dep.Kafka.BrokerList)
+	checksum := GetConfCheckSum(ret.Spec)
+
+	secret.Data["brokers"] = []byte("kafka-1:9092")
+	assert.NoError(t, cli.Update(ctx, secret))
+	ret, err = withResolvedKafkaBrokers(ctx, cli, mc)
+	assert.NoError(t, err)
+	assert.NotEqual(t, checksum, GetConfCheckSum(ret.Spec))
+
+	mc.Spec.Dep.Kafka.BrokerListFromSecret.Name = "notexist"
+	_, err = withResolvedKafkaBrokers(ctx, cli, mc)
+	assert.Error(t, err)
+}
+
+func TestMilvusReconciler_kafkaBrokersSecretToMilvus(t *testing.T) {
+	ctx := context.Background()
+	newMilvus := func(namespace, name, secretName string) *v1beta1.Milvus {
+		mc := &v1beta1.Milvus{}
+		mc.Namespace = namespace
+		mc.Name = name
+		if secretName != "" {
+			mc.Spec.Dep.Kafka.BrokerListFromSecret = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "brokers",
+			}
+		}
+		return mc
+	}
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+	cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		newMilvus("ns", "mc1", "kafka-brokers"),
+		newMilvus("ns", "mc2", "other"),
+		newMilvus("ns", "mc3", ""),
+		newMilvus("ns2", "mc4", "kafka-brokers"),
+	).Build()
+	r := &MilvusReconciler{Client: cli, logger: logf.Log.WithName("test")}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kafka-brokers"}}
+	ret := r.kafkaBrokersSecretToMilvus(ctx, secret)
+	if assert.Len(t, ret, 1) {
+		assert.Equal(t, "mc1", ret[0].Name)
+		assert.Equal(t, "ns", ret[0].Namespace)
+	}
+}
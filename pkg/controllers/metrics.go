@@ -1,12 +1,15 @@
 package controllers
 
 import (
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/common/version"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	v1beta1 "github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/util"
 )
 
 var (
@@ -21,8 +24,52 @@ var (
 		Name:      "total_count",
 		Help:      "Total count of milvus in different status",
 	}, []string{"status"})
+
+	milvusDependencyConditionCountCollector = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "milvus",
+		Name:      "dependency_condition_count",
+		Help:      "Count of milvus by the status of each dependency condition",
+	}, []string{"condition", "status"})
+
+	dependencyProbeRetryCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "milvus",
+		Name:      "dependency_probe_retry_total",
+		Help:      "Count of dependency probes which needed retries, by the outcome",
+	}, []string{"dependency", "outcome"})
+
+	reconcileDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: "milvus",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of each reconcile of milvus",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
+	}, []string{"milvus_namespace", "milvus_name"})
+
+	webhookCertReadyGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: "milvus_operator",
+		Name:      "webhook_cert_ready",
+		Help:      "Whether the serving certificate of the operator webhook is present & valid, 1 for ready",
+	})
+
+	webhookCertExpiryGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: "milvus_operator",
+		Name:      "webhook_cert_expiry_timestamp_seconds",
+		Help:      "The expiry time of the serving certificate of the operator webhook",
+	})
 )
 
+// observeDependencyProbeRetry records the retries of dependency probes like checkEtcd, checkMinIO
+func observeDependencyProbeRetry(name, outcome string) {
+	dependency := strings.ToLower(strings.TrimPrefix(name, "check"))
+	dependencyProbeRetryCounter.WithLabelValues(dependency, outcome).Inc()
+}
+
+// dependencyConditionTypes are the condition types of milvus dependencies counted in metrics
+var dependencyConditionTypes = []v1beta1.MilvusConditionType{
+	v1beta1.EtcdReady,
+	v1beta1.StorageReady,
+	v1beta1.MsgStreamReady,
+}
+
 // MilvusStatusCode for milvusStatusCollector
 const (
 	MilvusStatusCodePending     = float64(0)
@@ -56,6 +103,12 @@ func InitializeMetrics() {
 	// register our own
 	metrics.Registry.MustRegister(milvusStatusCollector)
 	metrics.Registry.MustRegister(milvusTotalCountCollector)
+	metrics.Registry.MustRegister(milvusDependencyConditionCountCollector)
+	metrics.Registry.MustRegister(dependencyProbeRetryCounter)
+	metrics.Registry.MustRegister(reconcileDurationHistogram)
+	metrics.Registry.MustRegister(webhookCertReadyGauge)
+	metrics.Registry.MustRegister(webhookCertExpiryGauge)
+	util.BackoffObserver = observeDependencyProbeRetry
 
 	// Register a build info metric.
 	version.Version = v1beta1.Version
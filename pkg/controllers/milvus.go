@@ -3,10 +3,14 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
@@ -24,7 +28,8 @@ func (r *MilvusReconciler) SetDefaultStatus(ctx context.Context, mc *v1beta1.Mil
 		mc.Status.Status = v1beta1.StatusPending
 		mc.Status.RollingMode = mc.Spec.Com.RollingMode
 		mc.Status.CurrentImage = mc.Spec.Com.Image
-		mc.Status.CurrentVersion = mc.Spec.Com.Version
+		mc.Status.CurrentVersion = mc.Spec.GetMilvusVersion()
+		initRestoreCondition(mc)
 		// metrics
 		milvusStatusCollector.WithLabelValues(mc.Namespace, mc.Name).
 			Set(MilvusStatusToCode(mc.Status.Status, mc.GetAnnotations()[MaintainingAnnotation] == "true"))
@@ -33,7 +38,7 @@ func (r *MilvusReconciler) SetDefaultStatus(ctx context.Context, mc *v1beta1.Mil
 		return errors.Wrapf(err, "set mc default status[%s/%s] failed", mc.Namespace, mc.Name)
 	} else if mc.Status.CurrentImage == "" {
 		mc.Status.CurrentImage = mc.Spec.Com.Image
-		mc.Status.CurrentVersion = mc.Spec.Com.Version
+		mc.Status.CurrentVersion = mc.Spec.GetMilvusVersion()
 		err := r.Client.Status().Update(ctx, mc)
 		return errors.Wrapf(err, "set mc current image and version[%s/%s] failed", mc.Namespace, mc.Name)
 	}
@@ -56,19 +61,34 @@ func (r *MilvusReconciler) ReconcileMilvus(ctx context.Context, mc v1beta1.Milvu
 	if !IsDependencyReady(mc.Status.Conditions) {
 		return nil
 	}
+	ctx = withRenderedConfCache(ctx)
+	mc, err := withResolvedKafkaBrokers(ctx, r.Client, mc)
+	if err != nil {
+		return errors.Wrap(err, "resolve kafka broker list")
+	}
 
 	if err := r.ReconcileConfigMaps(ctx, mc); err != nil {
 		return fmt.Errorf("configmap: %w", err)
 	}
 
+	if err := r.ReconcileComponentConfigMaps(ctx, mc); err != nil {
+		return fmt.Errorf("component configmap: %w", err)
+	}
+
 	comReconcilers := []Func{
 		r.ReconcilePVCs,
 		r.ReconcileDeployments,
 		r.ReconcileServices,
 		r.ReconcileIngress,
 		r.ReconcilePodMonitor,
+		r.ReconcileServiceMonitor,
+		r.ReconcileNetworkPolicy,
+		r.ReconcileEtcdDefrag,
+		r.ReconcileEtcdBackup,
+		r.ReconcileRestore,
+		r.ReconcileRenderedConfigMap,
 	}
-	err := defaultGroupRunner.Run(comReconcilers, ctx, mc)
+	err = defaultGroupRunner.Run(comReconcilers, ctx, mc)
 	return errors.Wrap(err, "reconcile milvus")
 }
 
@@ -93,6 +113,41 @@ func (r *MilvusReconciler) batchDeletePVC(ctx context.Context, namespace, labelK
 	return nil
 }
 
+// deleteIfExists deletes the object of the key if it exists, it's used to clean up the resources of the disabled features
+func (r *MilvusReconciler) deleteIfExists(ctx context.Context, obj client.Object, key types.NamespacedName) error {
+	kind := reflect.TypeOf(obj).Elem().Name()
+	err := r.Get(ctx, key, obj)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "get %s %s", kind, key.Name)
+	}
+	r.logger.Info("Delete "+kind, "name", key.Name, "namespace", key.Namespace)
+	return errors.Wrapf(client.IgnoreNotFound(r.Delete(ctx, obj)), "delete %s %s", kind, key.Name)
+}
+
+// deleteDependencyRelease uninstalls the release of a dependency, and deletes its pvcs if deletePVC
+func (r *MilvusReconciler) deleteDependencyRelease(ctx context.Context, cfg *action.Configuration, namespace, releaseName string, deletePVC bool) error {
+	if err := helm.Uninstall(cfg, releaseName); err != nil {
+		return err
+	}
+	if !deletePVC {
+		return nil
+	}
+	// for etcd charts
+	err := r.batchDeletePVC(ctx, namespace, AppLabelInstance, releaseName)
+	if err != nil {
+		return errors.Wrapf(err, "delete pvc with label %s=%s failed", AppLabelInstance, releaseName)
+	}
+	// for pulsar & minio charts
+	err = r.batchDeletePVC(ctx, namespace, HelmReleaseLabel, releaseName)
+	if err != nil {
+		return errors.Wrapf(err, "delete pvc with label %s=%s failed", HelmReleaseLabel, releaseName)
+	}
+	return nil
+}
+
 var Finalize = func(ctx context.Context, r *MilvusReconciler, mc v1beta1.Milvus) error {
 	deletingReleases := map[string]bool{}
 	if !mc.Spec.Dep.Etcd.External && mc.Spec.Dep.Etcd.InCluster.DeletionPolicy == v1beta1.DeletionPolicyDelete {
@@ -136,31 +191,14 @@ var Finalize = func(ctx context.Context, r *MilvusReconciler, mc v1beta1.Milvus)
 		deletingReleases[mc.Name+"-tei"] = mc.Spec.Dep.Tei.InCluster.PVCDeletion
 	}
 
-	if len(deletingReleases) > 0 {
+	// releases of dependencies managed externally are not deleted
+	if len(deletingReleases) > 0 && mc.Spec.Dep.IsManageDependencies() {
 		cfg := r.helmReconciler.NewHelmCfg(mc.Namespace)
 
 		errs := []error{}
 		for releaseName, deletePVC := range deletingReleases {
-			if err := helm.Uninstall(cfg, releaseName); err != nil {
+			if err := r.deleteDependencyRelease(ctx, cfg, mc.Namespace, releaseName, deletePVC); err != nil {
 				errs = append(errs, err)
-				continue
-			}
-
-			if deletePVC {
-				// for etcd charts
-				err := r.batchDeletePVC(ctx, mc.Namespace, AppLabelInstance, releaseName)
-				if err != nil {
-					err = errors.Wrapf(err, "delete pvc with label %s=%s failed", AppLabelInstance, releaseName)
-					errs = append(errs, err)
-					continue
-				}
-				// for pulsar & minio charts
-				err = r.batchDeletePVC(ctx, mc.Namespace, HelmReleaseLabel, releaseName)
-				if err != nil {
-					err = errors.Wrapf(err, "delete pvc with label %s=%s failed", HelmReleaseLabel, releaseName)
-					errs = append(errs, err)
-					continue
-				}
 			}
 		}
 
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	pkgErr "github.com/pkg/errors"
@@ -26,11 +27,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -42,6 +45,7 @@ const (
 	MilvusFinalizerName         = "milvus.milvus.io/finalizer"
 	ForegroundDeletionFinalizer = "foregroundDeletion"
 	PauseReconcileAnnotation    = "milvus.io/pause-reconcile"
+	PauseRolloutAnnotation      = "milvus.io/pause-rollout"
 	MaintainingAnnotation       = "milvus.io/maintaining"
 )
 
@@ -53,19 +57,22 @@ type MilvusReconciler struct {
 	helmReconciler HelmReconciler
 	statusSyncer   MilvusStatusSyncerInterface
 	deployCtrl     DeployController
+	eventRecorder  record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=milvus.io,resources=milvuses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=milvus.io,resources=milvuses/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=milvus.io,resources=milvuses/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments;replicasets;statefulsets;controllerrevisions,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups="",resources=pods;pods/exec;configmaps;serviceaccounts;secrets;services;persistentvolumeclaims;persistentvolumes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=pods;pods/exec;pods/status;configmaps;serviceaccounts;secrets;services;persistentvolumeclaims;persistentvolumes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets;podsecuritypolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles;rolebindings;clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses;networkpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="monitoring.coreos.com",resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="autoscaling",resources=horizontalpodautoscalers,verbs=get;list;watch
 //+kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=list;get;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // below wrong statements are introduced by pulsar helm chart. we have to keep them before pulsar fixes.
 //+kubebuilder:rbac:groups="";extensions;apps,resources=statefulsets;deployments;pods;secrets;services;ingresses,verbs=get;list;watch;create;update;patch;delete
@@ -76,6 +83,14 @@ type MilvusReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.8.3/pkg/reconcile
 func (r *MilvusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, eventRecorder := withReconcileEventRecorder(ctx, req.NamespacedName)
+	result, err := r.reconcile(ctx, req)
+	eventRecorder.emit(err)
+	return result, err
+}
+
+func (r *MilvusReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	start := reconcileNow()
 	r.statusSyncer.RunIfNot()
 	globalCommonInfo.InitIfNot(r.Client)
 	logger := r.logger.WithValues("milvus", req.NamespacedName)
@@ -93,16 +108,19 @@ func (r *MilvusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		if errors.IsNotFound(err) {
 			// The resource may have be deleted after reconcile request coming in
 			// Reconcile is done
+			forgetReconcileStats(ctx)
 			return ctrl.Result{}, nil
 		}
 
 		return ctrl.Result{}, fmt.Errorf("error get milvus : %w", err)
 	}
+	observeMilvus(ctx, milvus)
 
 	// Finalize
 	if milvus.DeletionTimestamp.IsZero() {
-		if !controllerutil.ContainsFinalizer(milvus, MilvusFinalizerName) {
-			controllerutil.AddFinalizer(milvus, MilvusFinalizerName)
+		addedFinalizer := controllerutil.AddFinalizer(milvus, MilvusFinalizerName)
+		updatedGracefulTermination := updateGracefulTerminationFinalizer(milvus)
+		if updateBackupBeforeDeleteFinalizer(milvus) || updatedGracefulTermination || addedFinalizer {
 			err := r.Update(ctx, milvus)
 			if err != nil {
 				return ctrl.Result{}, err
@@ -117,6 +135,32 @@ func (r *MilvusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			}
 		}
 
+		// back up before the components are stopped, the backup tool needs milvus running
+		if controllerutil.ContainsFinalizer(milvus, BackupBeforeDeleteFinalizer) {
+			finalized, err := r.finalizeBackupBeforeDelete(ctx, milvus)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !finalized {
+				logger.Info("deleting milvus: backup in progress, requeue")
+				return ctrl.Result{RequeueAfter: unhealthySyncInterval}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+
+		if controllerutil.ContainsFinalizer(milvus, GracefulTerminationFinalizer) {
+			terminated, err := r.gracefulTerminate(ctx, milvus)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !terminated {
+				logger.Info("deleting milvus: graceful termination in progress, requeue")
+				return ctrl.Result{RequeueAfter: unhealthySyncInterval}, nil
+			}
+			controllerutil.RemoveFinalizer(milvus, GracefulTerminationFinalizer)
+			return ctrl.Result{}, r.Update(ctx, milvus)
+		}
+
 		stopped, err := CheckMilvusStopped(ctx, r.Client, *milvus)
 		if !stopped || err != nil {
 			if err != nil {
@@ -142,6 +186,9 @@ func (r *MilvusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			// metrics
 			logger.Info("deleted milvus")
 			milvusStatusCollector.DeleteLabelValues(milvus.Namespace, milvus.Name)
+			reconcileDurationHistogram.DeleteLabelValues(milvus.Namespace, milvus.Name)
+			unavailableOptionalKinds.Forget(req.NamespacedName)
+			forgetReconcileStats(ctx)
 			controllerutil.RemoveFinalizer(milvus, MilvusFinalizerName)
 			err := r.Update(ctx, milvus)
 			return ctrl.Result{}, err
@@ -181,7 +228,17 @@ func (r *MilvusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
-	if err := r.ReconcileAll(ctx, *milvus); err != nil {
+	err = r.ReconcileExternalizedDependencies(ctx, milvus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hpaConflicts, err := getHPAConflicts(ctx, r.Client, *milvus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ReconcileAll(ctx, applyScheduledStop(preferHPAReplicas(*milvus, hpaConflicts))); err != nil {
 		if pkgErr.Is(err, ErrRequeue) {
 			r.logger.Info("requeue", "err", err.Error())
 			return ctrl.Result{RequeueAfter: unhealthySyncInterval / 2}, nil
@@ -194,11 +251,57 @@ func (r *MilvusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	if err := r.statusSyncer.UpdateStatusForNewGeneration(ctx, milvus, false); err != nil {
 		return ctrl.Result{}, err
 	}
+	slowReconcileChanged := r.updateSlowReconcileCondition(milvus, start)
+	optionalKindChanged := updateOptionalKindCondition(milvus)
+	replicasConflictChanged := r.updateReplicasConflictCondition(milvus, hpaConflicts)
+	if updateLastReconcileTime(milvus, time.Now()) || slowReconcileChanged || optionalKindChanged || replicasConflictChanged {
+		if err := r.Status().Update(ctx, milvus); err != nil {
+			return ctrl.Result{}, pkgErr.Wrap(err, "update last reconcile time")
+		}
+	}
 	// metrics
 	milvusStatusCollector.WithLabelValues(milvus.Namespace, milvus.Name).
 		Set(MilvusStatusToCode(milvus.Status.Status, milvus.GetAnnotations()[MaintainingAnnotation] == "true"))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: getRequeueInterval(milvus)}, nil
+}
+
+// RequeueBaseInterval is the base interval to requeue a reconciled milvus, 0 disables the requeue
+var RequeueBaseInterval time.Duration
+
+// stableRequeueIntervalFactor backs off the requeue of the stable milvus
+const stableRequeueIntervalFactor = 4
+
+// getRequeueInterval returns a longer interval for the healthy & updated milvus than the churning one
+func getRequeueInterval(mc *milvusv1beta1.Milvus) time.Duration {
+	// the proxy readiness gate is opened by the reconcile once the coordinators are ready,
+	// so requeue soon instead of waiting for the status sync routine
+	if IsMilvusConditionTrueByType(mc.Status.Conditions, milvusv1beta1.ProxyTrafficGated) {
+		return unhealthySyncInterval / 2
+	}
+	if RequeueBaseInterval <= 0 {
+		return 0
+	}
+	if mc.Status.Status == milvusv1beta1.StatusHealthy &&
+		IsMilvusConditionTrueByType(mc.Status.Conditions, milvusv1beta1.MilvusUpdated) {
+		return RequeueBaseInterval * stableRequeueIntervalFactor
+	}
+	return RequeueBaseInterval
+}
+
+// lastReconcileTimeUpdateInterval throttles the updates of status.lastReconcileTime,
+// because each status update triggers another reconcile
+var lastReconcileTimeUpdateInterval = time.Minute
+
+// updateLastReconcileTime returns true if the status.lastReconcileTime is updated
+func updateLastReconcileTime(mc *milvusv1beta1.Milvus, now time.Time) bool {
+	last := mc.Status.LastReconcileTime
+	if last != nil && now.Sub(last.Time) < lastReconcileTimeUpdateInterval {
+		return false
+	}
+	reconcileTime := metav1.NewTime(now)
+	mc.Status.LastReconcileTime = &reconcileTime
+	return true
 }
 
 func (r *MilvusReconciler) VerifyCR(ctx context.Context, milvus *milvusv1beta1.Milvus) error {
@@ -223,6 +326,8 @@ func (r *MilvusReconciler) VerifyCR(ctx context.Context, milvus *milvusv1beta1.M
 func (r *MilvusReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&milvusv1beta1.Milvus{}).
+		// re-render the config when the kafka broker list in secret changes
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.kafkaBrokersSecretToMilvus)).
 		// For(&milvusv1alpha1.MilvusCluster{}).
 		// Owns(&appsv1.Deployment{}).
 		// Owns(&corev1.ConfigMap{}).
@@ -236,7 +341,7 @@ func (r *MilvusReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		builder.WithEventFilter(DebugPredicate())
 	} */
 
-	return builder.Complete(r)
+	return builder.Complete(withReconcileStats("Milvus", r))
 }
 
 var predicateLog = logf.Log.WithName("predicates").WithName("Milvus")
@@ -8,6 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -88,6 +90,61 @@ func TestClusterReconciler(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("create with graceful termination", func(t *testing.T) {
+		defer ctrl.Finish()
+		m := m.DeepCopy()
+		m.Finalizers = nil
+		m.Spec.GracefulTermination = &v1beta1.MilvusGracefulTermination{Enabled: true}
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(ctx, key, obj interface{}, opt ...any) {
+				o := obj.(*v1beta1.Milvus)
+				*o = *m.DeepCopy()
+			}).
+			Return(nil)
+
+		mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Do(
+			func(ctx, obj interface{}, opts ...interface{}) {
+				u := obj.(*v1beta1.Milvus)
+				assert.Equal(t, []string{MilvusFinalizerName, GracefulTerminationFinalizer}, u.Finalizers)
+			},
+		).Return(errors.Errorf("mock"))
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		assert.Error(t, err)
+	})
+
+	t.Run("delete with graceful termination completed", func(t *testing.T) {
+		defer ctrl.Finish()
+		bakListTerminatingPods := ListMilvusTerminatingPods
+		defer func() { ListMilvusTerminatingPods = bakListTerminatingPods }()
+		ListMilvusTerminatingPods = func(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (*corev1.PodList, error) {
+			return &corev1.PodList{}, nil
+		}
+
+		m := m.DeepCopy()
+		m.Finalizers = []string{MilvusFinalizerName, GracefulTerminationFinalizer}
+		m.Spec.GracefulTermination = &v1beta1.MilvusGracefulTermination{Enabled: true}
+		m.Status.Status = v1beta1.StatusDeleting
+		m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(ctx, key, obj interface{}, opts ...any) {
+				o := obj.(*v1beta1.Milvus)
+				*o = *m.DeepCopy()
+			}).
+			Return(nil)
+		// no deployment left
+		mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Do(
+			func(ctx, obj interface{}, opts ...interface{}) {
+				u := obj.(*v1beta1.Milvus)
+				assert.Equal(t, []string{MilvusFinalizerName}, u.Finalizers)
+			},
+		).Return(nil)
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		assert.NoError(t, err)
+	})
+
 	t.Run("case delete background, change to foreground failed", func(t *testing.T) {
 		defer ctrl.Finish()
 		m.Finalizers = []string{MilvusFinalizerName}
@@ -158,6 +215,109 @@ func TestClusterReconciler(t *testing.T) {
 		assert.Error(t, err)
 		assert.True(t, ret.RequeueAfter > 0)
 	})
+
+	t.Run("reconcile succeeded updates last reconcile time", func(t *testing.T) {
+		defer ctrl.Finish()
+		mockRunner := NewMockGroupRunner(ctrl)
+		bakRunner := defaultGroupRunner
+		defaultGroupRunner = mockRunner
+		defer func() { defaultGroupRunner = bakRunner }()
+		mockHelm := helm.NewMockClient(ctrl)
+		helm.SetDefaultClient(mockHelm)
+		mockHelm.EXPECT().GetRelease(gomock.Any(), gomock.Any()).Return(nil, driver.ErrReleaseNotFound).AnyTimes()
+
+		m := v1beta1.Milvus{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "ns",
+				Name:       "mc",
+				Finalizers: []string{MilvusFinalizerName},
+			},
+		}
+		m.Default()
+		m.Status.Status = v1beta1.StatusHealthy
+		m.Status.CurrentImage = m.Spec.Com.Image
+		m.Status.ManagedReleases = getInClusterDependencyReleases(m)
+		lastReconcileTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		m.Status.LastReconcileTime = &lastReconcileTime
+
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(ctx, key, obj interface{}, opts ...any) {
+				o := obj.(*v1beta1.Milvus)
+				*o = *m.DeepCopy()
+			}).
+			Return(nil).Times(2)
+		// no hpa
+		mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockRunner.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockSyncer.EXPECT().UpdateStatusForNewGeneration(gomock.Any(), gomock.Any(), false).Return(nil).Times(2)
+		mockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...any) error {
+				mc := obj.(*v1beta1.Milvus)
+				assert.True(t, mc.Status.LastReconcileTime.After(lastReconcileTime.Time))
+				m.Status.LastReconcileTime = mc.Status.LastReconcileTime
+				return nil
+			})
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		assert.NoError(t, err)
+
+		// not updated again within the interval
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestUpdateLastReconcileTime(t *testing.T) {
+	mc := &v1beta1.Milvus{}
+	now := time.Now()
+	assert.True(t, updateLastReconcileTime(mc, now))
+	assert.Equal(t, now.Unix(), mc.Status.LastReconcileTime.Unix())
+
+	assert.False(t, updateLastReconcileTime(mc, now.Add(lastReconcileTimeUpdateInterval/2)))
+	assert.Equal(t, now.Unix(), mc.Status.LastReconcileTime.Unix())
+
+	later := now.Add(lastReconcileTimeUpdateInterval)
+	assert.True(t, updateLastReconcileTime(mc, later))
+	assert.Equal(t, later.Unix(), mc.Status.LastReconcileTime.Unix())
+}
+
+func TestGetRequeueInterval(t *testing.T) {
+	bak := RequeueBaseInterval
+	defer func() {
+		RequeueBaseInterval = bak
+	}()
+
+	stable := &v1beta1.Milvus{}
+	stable.Status.Status = v1beta1.StatusHealthy
+	stable.Status.Conditions = []v1beta1.MilvusCondition{
+		{Type: v1beta1.MilvusUpdated, Status: corev1.ConditionTrue},
+	}
+	churning := &v1beta1.Milvus{}
+	churning.Status.Status = v1beta1.StatusUnhealthy
+
+	RequeueBaseInterval = 0
+	assert.Equal(t, time.Duration(0), getRequeueInterval(stable))
+	assert.Equal(t, time.Duration(0), getRequeueInterval(churning))
+
+	RequeueBaseInterval = time.Minute
+	assert.Equal(t, time.Minute, getRequeueInterval(churning))
+	assert.Greater(t, getRequeueInterval(stable), getRequeueInterval(churning))
+
+	pending := stable.DeepCopy()
+	pending.Status.Status = v1beta1.StatusPending
+	assert.Equal(t, time.Minute, getRequeueInterval(pending))
+
+	updating := stable.DeepCopy()
+	updating.Status.Conditions[0].Status = corev1.ConditionFalse
+	assert.Equal(t, time.Minute, getRequeueInterval(updating))
+
+	gated := churning.DeepCopy()
+	gated.Status.Conditions = []v1beta1.MilvusCondition{
+		{Type: v1beta1.ProxyTrafficGated, Status: corev1.ConditionTrue},
+	}
+	assert.Equal(t, unhealthySyncInterval/2, getRequeueInterval(gated))
+	RequeueBaseInterval = 0
+	assert.Equal(t, unhealthySyncInterval/2, getRequeueInterval(gated))
 }
 
 func TestMilvusReconciler_ReconcileLegacyValues(t *testing.T) {
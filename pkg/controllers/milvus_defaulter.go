@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/util"
+)
+
+// milvusDefaulter is the defaulter of the milvus mutating webhook.
+// The cluster-wide defaults are only applied on creation,
+// so that changing the MilvusDefaults doesn't update the existing milvus & restart their pods.
+// The MilvusDefaults is read from the cache of the manager, because the webhook is served by every replica, not only the leader
+type milvusDefaulter struct {
+	client client.Reader
+	logger logr.Logger
+}
+
+var _ admission.CustomDefaulter = milvusDefaulter{}
+
+//+kubebuilder:rbac:groups=milvus.io,resources=milvusdefaults,verbs=get;list;watch
+
+func (d milvusDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	mc, ok := obj.(*v1beta1.Milvus)
+	if !ok {
+		return errors.Errorf("expect a Milvus but got a %T", obj)
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err == nil && req.Operation == admissionv1.Create {
+		defaults, err := d.getClusterDefaults(ctx)
+		if err != nil {
+			return err
+		}
+		if err := mergeClusterDefaults(mc, req.Object.Raw, defaults); err != nil {
+			return errors.Wrap(err, "merge cluster defaults")
+		}
+	}
+	mc.Default()
+	return nil
+}
+
+// getClusterDefaults returns the spec of the MilvusDefaults applied to the created milvus,
+// nil if it's not found, invalid, or its CRD is not installed
+func (d milvusDefaulter) getClusterDefaults(ctx context.Context) (map[string]interface{}, error) {
+	defaults := &v1beta1.MilvusDefaults{}
+	err := d.client.Get(ctx, client.ObjectKey{Name: v1beta1.MilvusDefaultsName}, defaults)
+	if kerrors.IsNotFound(err) || isKindUnavailableError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get MilvusDefaults")
+	}
+	if err := validateMilvusDefaults(defaults.Spec.Spec.Data); err != nil {
+		d.logger.Error(err, "invalid MilvusDefaults, ignored", "name", defaults.Name)
+		return nil, nil
+	}
+	return defaults.Spec.Spec.Data, nil
+}
+
+// validateMilvusDefaults checks the defaults can be decoded as a milvus spec
+func validateMilvusDefaults(spec map[string]interface{}) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Wrap(err, "marshal defaults")
+	}
+	if err := json.Unmarshal(data, &v1beta1.MilvusSpec{}); err != nil {
+		return errors.Wrap(err, "decode defaults as milvus spec")
+	}
+	return nil
+}
+
+// mergeClusterDefaults merges the cluster-wide defaults under the spec of the raw milvus object.
+// The raw object is used instead of the typed one, because the typed one has the unset fields
+// without omitempty marshaled as zero values, which would take precedence over the defaults
+func mergeClusterDefaults(mc *v1beta1.Milvus, raw []byte, defaults map[string]interface{}) error {
+	if len(defaults) < 1 {
+		return nil
+	}
+	rawObj := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &rawObj); err != nil {
+		return errors.Wrap(err, "decode raw milvus")
+	}
+	spec, _ := rawObj["spec"].(map[string]interface{})
+	// the fields explicitly set to null shouldn't shadow the defaults
+	removeNullValues(spec)
+	util.MergeValues(defaults, spec)
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return errors.Wrap(err, "marshal merged spec")
+	}
+	merged := v1beta1.MilvusSpec{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return errors.Wrap(err, "decode merged spec")
+	}
+	mc.Spec = merged
+	return nil
+}
+
+// removeNullValues removes the null fields in the values recursively
+func removeNullValues(values map[string]interface{}) {
+	for k, v := range values {
+		switch typed := v.(type) {
+		case nil:
+			delete(values, k)
+		case map[string]interface{}:
+			removeNullValues(typed)
+		}
+	}
+}
+
+// setupMilvusWebhookWithManager registers the webhooks of milvus v1beta1
+func setupMilvusWebhookWithManager(mgr ctrl.Manager, logger logr.Logger) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1beta1.Milvus{}).
+		WithDefaulter(milvusDefaulter{
+			client: mgr.GetClient(),
+			logger: logger,
+		}).
+		Complete()
+}
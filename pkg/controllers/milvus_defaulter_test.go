@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/config"
+)
+
+func TestMilvusDefaulter_Default(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+	newDefaulter := func(objs ...client.Object) milvusDefaulter {
+		return milvusDefaulter{
+			client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build(),
+			logger: ctrl.Log.WithName("test"),
+		}
+	}
+	newDefaults := func(name string, spec map[string]interface{}) *v1beta1.MilvusDefaults {
+		return &v1beta1.MilvusDefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1beta1.MilvusDefaultsSpec{Spec: v1beta1.Values{Data: spec}},
+		}
+	}
+	defaulter := newDefaulter(newDefaults(v1beta1.MilvusDefaultsName, map[string]interface{}{
+		"components": map[string]interface{}{
+			"image": "harbor.example.com/milvusdb/milvus:v2.4.0",
+			"imagePullSecrets": []interface{}{
+				map[string]interface{}{"name": "harbor"},
+			},
+			"disableMetric": true,
+		},
+		"config": map[string]interface{}{
+			"log": map[string]interface{}{
+				"level": "warn",
+			},
+		},
+	}))
+
+	// newAdmissionCtx returns the ctx of the webhook request with the raw object decoded into the returned milvus
+	newAdmissionCtx := func(t *testing.T, operation admissionv1.Operation, rawSpec map[string]interface{}) (context.Context, *v1beta1.Milvus) {
+		raw, err := json.Marshal(map[string]interface{}{
+			"apiVersion": "milvus.io/v1beta1",
+			"kind":       "Milvus",
+			"metadata":   map[string]interface{}{"name": "mc", "namespace": "ns"},
+			"spec":       rawSpec,
+		})
+		assert.NoError(t, err)
+		mc := &v1beta1.Milvus{}
+		assert.NoError(t, json.Unmarshal(raw, mc))
+		req := admission.Request{}
+		req.Operation = operation
+		req.Object.Raw = raw
+		return admission.NewContextWithRequest(context.Background(), req), mc
+	}
+
+	t.Run("applied on create when omitted", func(t *testing.T) {
+		ctx, mc := newAdmissionCtx(t, admissionv1.Create, map[string]interface{}{})
+		assert.NoError(t, defaulter.Default(ctx, mc))
+		assert.Equal(t, "harbor.example.com/milvusdb/milvus:v2.4.0", mc.Spec.Com.Image)
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: "harbor"}}, mc.Spec.Com.ImagePullSecrets)
+		assert.True(t, mc.Spec.Com.DisableMetric)
+		assert.Equal(t, map[string]interface{}{"level": "warn"}, mc.Spec.Conf.Data["log"])
+	})
+
+	t.Run("overridden on create when set", func(t *testing.T) {
+		ctx, mc := newAdmissionCtx(t, admissionv1.Create, map[string]interface{}{
+			"components": map[string]interface{}{
+				"image":         "milvusdb/milvus:v2.4.1",
+				"disableMetric": false,
+			},
+			"config": map[string]interface{}{
+				"log": map[string]interface{}{
+					"level": "debug",
+				},
+			},
+		})
+		assert.NoError(t, defaulter.Default(ctx, mc))
+		assert.Equal(t, "milvusdb/milvus:v2.4.1", mc.Spec.Com.Image)
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: "harbor"}}, mc.Spec.Com.ImagePullSecrets)
+		assert.False(t, mc.Spec.Com.DisableMetric)
+		assert.Equal(t, map[string]interface{}{"level": "debug"}, mc.Spec.Conf.Data["log"])
+	})
+
+	t.Run("not applied on update", func(t *testing.T) {
+		ctx, mc := newAdmissionCtx(t, admissionv1.Update, map[string]interface{}{})
+		assert.NoError(t, defaulter.Default(ctx, mc))
+		assert.Equal(t, config.DefaultMilvusImage, mc.Spec.Com.Image)
+		assert.Empty(t, mc.Spec.Com.ImagePullSecrets)
+		assert.False(t, mc.Spec.Com.DisableMetric)
+	})
+
+	t.Run("not applied out of webhook", func(t *testing.T) {
+		mc := &v1beta1.Milvus{}
+		assert.NoError(t, defaulter.Default(context.Background(), mc))
+		assert.Equal(t, config.DefaultMilvusImage, mc.Spec.Com.Image)
+	})
+
+	t.Run("not applied when not found", func(t *testing.T) {
+		ctx, mc := newAdmissionCtx(t, admissionv1.Create, map[string]interface{}{})
+		assert.NoError(t, newDefaulter().Default(ctx, mc))
+		assert.Equal(t, config.DefaultMilvusImage, mc.Spec.Com.Image)
+		assert.Empty(t, mc.Spec.Com.ImagePullSecrets)
+	})
+
+	t.Run("other name ignored", func(t *testing.T) {
+		ctx, mc := newAdmissionCtx(t, admissionv1.Create, map[string]interface{}{})
+		assert.NoError(t, newDefaulter(newDefaults("other", map[string]interface{}{
+			"components": map[string]interface{}{"image": "harbor.example.com/milvusdb/milvus:v2.4.0"},
+		})).Default(ctx, mc))
+		assert.Equal(t, config.DefaultMilvusImage, mc.Spec.Com.Image)
+	})
+
+	t.Run("invalid ignored", func(t *testing.T) {
+		ctx, mc := newAdmissionCtx(t, admissionv1.Create, map[string]interface{}{})
+		assert.NoError(t, newDefaulter(newDefaults(v1beta1.MilvusDefaultsName, map[string]interface{}{
+			"components": map[string]interface{}{"image": 1},
+		})).Default(ctx, mc))
+		assert.Equal(t, config.DefaultMilvusImage, mc.Spec.Com.Image)
+	})
+
+	t.Run("not applied when crd not installed", func(t *testing.T) {
+		noDefaultsScheme := runtime.NewScheme()
+		clientgoscheme.AddToScheme(noDefaultsScheme)
+		ctx, mc := newAdmissionCtx(t, admissionv1.Create, map[string]interface{}{})
+		d := milvusDefaulter{
+			client: fake.NewClientBuilder().WithScheme(noDefaultsScheme).Build(),
+			logger: ctrl.Log.WithName("test"),
+		}
+		assert.NoError(t, d.Default(ctx, mc))
+		assert.Equal(t, config.DefaultMilvusImage, mc.Spec.Com.Image)
+	})
+}
@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/util"
+)
+
+//go:generate mockgen -package=controllers -source=milvus_query_client.go -destination=milvus_query_client_mock.go MilvusQueryClient
+
+// MilvusQueryClient calls the milvus as a client
+type MilvusQueryClient interface {
+	// ListDatabases lists the names of the databases
+	ListDatabases(ctx context.Context) ([]string, error)
+	// ListCollections lists the names of the collections in the database
+	ListCollections(ctx context.Context, dbName string) ([]string, error)
+	// Compact triggers a compaction of the collection
+	Compact(ctx context.Context, dbName, collectionName string) error
+}
+
+// deepHealthCheckTimeout is the timeout of a deep health check call
+var deepHealthCheckTimeout = 5 * time.Second
+
+const (
+	defaultMilvusRootUser     = "root"
+	defaultMilvusRootPassword = "Milvus"
+	defaultMilvusDatabase     = "default"
+)
+
+// keys in the secret of spec.clientCredentialsSecretRef
+const (
+	MilvusClientUsernameKey = "username"
+	MilvusClientPasswordKey = "password"
+	MilvusClientCAKey       = "ca.crt"
+	MilvusClientCertKey     = "tls.crt"
+	MilvusClientKeyKey      = "tls.key"
+)
+
+// milvusClientCredentials is what the operator & its jobs connect to the milvus with
+type milvusClientCredentials struct {
+	// username & password are empty if authorization is disabled
+	username string
+	password string
+	// secretRef is the secret the username & password are read from, nil if they're the defaults
+	secretRef *corev1.LocalObjectReference
+
+	caCert     []byte
+	clientCert []byte
+	clientKey  []byte
+}
+
+// token returns the token of the user, empty if authorization is disabled
+func (c milvusClientCredentials) token() string {
+	if c.username == "" {
+		return ""
+	}
+	return c.username + ":" + c.password
+}
+
+// getMilvusClientCredentials returns the credentials by spec.clientCredentialsSecretRef,
+// or the root user with the default root password in config if it's not set
+func getMilvusClientCredentials(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (*milvusClientCredentials, error) {
+	ret := &milvusClientCredentials{}
+	secretRef := mc.Spec.ClientCredentialsSecretRef
+	if secretRef != nil {
+		secret := &corev1.Secret{}
+		if err := cli.Get(ctx, NamespacedName(mc.Namespace, secretRef.Name), secret); err != nil {
+			return nil, errors.Wrapf(err, "get client credentials secret[%s]", secretRef.Name)
+		}
+		ret.caCert = secret.Data[MilvusClientCAKey]
+		ret.clientCert = secret.Data[MilvusClientCertKey]
+		ret.clientKey = secret.Data[MilvusClientKeyKey]
+		if isMilvusAuthorizationEnabled(mc.Spec) {
+			ret.username = string(secret.Data[MilvusClientUsernameKey])
+			ret.password = string(secret.Data[MilvusClientPasswordKey])
+			ret.secretRef = secretRef
+			if ret.username == "" {
+				return nil, errors.Errorf("client credentials secret[%s] has no key %s", secretRef.Name, MilvusClientUsernameKey)
+			}
+		}
+		return ret, nil
+	}
+	if isMilvusAuthorizationEnabled(mc.Spec) {
+		ret.username = defaultMilvusRootUser
+		ret.password = getDefaultRootPassword(mc.Spec)
+	}
+	return ret, nil
+}
+
+// getDefaultRootPassword returns common.security.defaultRootPassword in config, or the default of milvus if it's not set
+func getDefaultRootPassword(spec v1beta1.MilvusSpec) string {
+	password, found := util.GetStringValue(spec.Conf.Data, "common", "security", "defaultRootPassword")
+	if !found {
+		return defaultMilvusRootPassword
+	}
+	return password
+}
+
+func isMilvusAuthorizationEnabled(spec v1beta1.MilvusSpec) bool {
+	authEnabled, _ := util.GetBoolValue(spec.Conf.Data, "common", "security", "authorizationEnabled")
+	return authEnabled
+}
+
+// getMilvusTLSMode returns common.security.tlsMode: 0 for no tls, 1 for one-way tls, 2 for mutual tls
+func getMilvusTLSMode(spec v1beta1.MilvusSpec) int {
+	tlsMode, _ := util.GetNumberValue(spec.Conf.Data, "common", "security", "tlsMode")
+	return int(tlsMode)
+}
+
+// newMilvusTLSConfig returns the tls config to connect to the milvus by common.security.tlsMode, nil if tls is disabled.
+// the server certificate is verified unless tls.serverVerify is false in config
+func newMilvusTLSConfig(mc v1beta1.Milvus, credentials milvusClientCredentials) (*tls.Config, error) {
+	tlsMode := getMilvusTLSMode(mc.Spec)
+	if tlsMode < 1 {
+		return nil, nil
+	}
+	ret := &tls.Config{
+		ServerName: fmt.Sprintf("%s.%s", GetServiceInstanceName(mc.Name), mc.Namespace),
+		MinVersion: tls.VersionTLS12,
+	}
+	if serverVerify, found := util.GetBoolValue(mc.Spec.Conf.Data, "tls", "serverVerify"); found && !serverVerify {
+		ret.InsecureSkipVerify = true
+	}
+	if len(credentials.caCert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(credentials.caCert) {
+			return nil, errors.Errorf("invalid %s in client credentials secret", MilvusClientCAKey)
+		}
+		ret.RootCAs = pool
+	}
+	if tlsMode > 1 {
+		cert, err := tls.X509KeyPair(credentials.clientCert, credentials.clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate for mutual tls")
+		}
+		ret.Certificates = []tls.Certificate{cert}
+	}
+	return ret, nil
+}
+
+// getMilvusClientEndpoint returns the endpoint of the milvus service, in https if tls is enabled
+func getMilvusClientEndpoint(mc v1beta1.Milvus) string {
+	scheme := "http"
+	if getMilvusTLSMode(mc.Spec) > 0 {
+		scheme = "https"
+	}
+	serviceComponent := MilvusStandalone
+	if mc.Spec.Mode == v1beta1.MilvusModeCluster {
+		serviceComponent = Proxy
+	}
+	return fmt.Sprintf("%s://%s.%s:%d", scheme, GetServiceInstanceName(mc.Name), mc.Namespace, serviceComponent.GetComponentPort(mc.Spec))
+}
+
+// newMilvusQueryClient creates the client to the milvus, it's a variable for testing
+var newMilvusQueryClient = func(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (MilvusQueryClient, error) {
+	credentials, err := getMilvusClientCredentials(ctx, cli, mc)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newMilvusTLSConfig(mc, *credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &milvusRestfulClient{
+		endpoint: getMilvusClientEndpoint(mc),
+		token:    credentials.token(),
+		httpClient: &http.Client{
+			Timeout: deepHealthCheckTimeout,
+			// the client is created for each call of the syncer, so no idle connections are kept
+			Transport: &http.Transport{
+				TLSClientConfig:   tlsConfig,
+				DisableKeepAlives: true,
+			},
+		},
+	}, nil
+}
+
+// milvusRestfulClient calls the milvus by the restful api served on the milvus port
+type milvusRestfulClient struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+type milvusRestfulResponse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// call posts the request to the restful api of the path, and decodes the data of the response into ret if it's not nil
+func (c milvusRestfulClient) call(ctx context.Context, path string, request interface{}, ret interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "marshal request")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "new request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "call %s", path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("call %s: http status %d", path, resp.StatusCode)
+	}
+	response := milvusRestfulResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.Wrapf(err, "decode response of %s", path)
+	}
+	if response.Code != 0 {
+		return errors.Errorf("call %s: code[%d] message[%s]", path, response.Code, response.Message)
+	}
+	if ret == nil || len(response.Data) < 1 {
+		return nil
+	}
+	return errors.Wrapf(json.Unmarshal(response.Data, ret), "decode data of %s", path)
+}
+
+func (c milvusRestfulClient) ListDatabases(ctx context.Context) ([]string, error) {
+	ret := []string{}
+	err := c.call(ctx, "/v2/vectordb/databases/list", map[string]string{}, &ret)
+	return ret, err
+}
+
+func (c milvusRestfulClient) ListCollections(ctx context.Context, dbName string) ([]string, error) {
+	ret := []string{}
+	err := c.call(ctx, "/v2/vectordb/collections/list", map[string]string{"dbName": dbName}, &ret)
+	return ret, err
+}
+
+func (c milvusRestfulClient) Compact(ctx context.Context, dbName, collectionName string) error {
+	return c.call(ctx, "/v2/vectordb/collections/compact", map[string]string{
+		"dbName":         dbName,
+		"collectionName": collectionName,
+	}, nil)
+}
+
+// deepHealthCheck returns nil if the milvus can serve the client calls
+func deepHealthCheck(ctx context.Context, cli client.Client, mc v1beta1.Milvus) error {
+	ctx, cancel := context.WithTimeout(ctx, deepHealthCheckTimeout)
+	defer cancel()
+	queryClient, err := newMilvusQueryClient(ctx, cli, mc)
+	if err != nil {
+		return err
+	}
+	_, err = queryClient.ListCollections(ctx, defaultMilvusDatabase)
+	return err
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusRestfulClient(t *testing.T) {
+	var authHeader string
+	var requests []string
+	code := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.URL.Path+" "+string(body))
+		switch r.URL.Path {
+		case "/v2/vectordb/databases/list":
+			fmt.Fprintf(w, `{"code":%d,"message":"mock","data":["default","db1"]}`, code)
+		case "/v2/vectordb/collections/list":
+			fmt.Fprintf(w, `{"code":%d,"message":"mock","data":["c1"]}`, code)
+		default:
+			fmt.Fprintf(w, `{"code":%d,"message":"mock","data":{}}`, code)
+		}
+	}))
+	defer server.Close()
+
+	cli := milvusRestfulClient{
+		endpoint:   server.URL,
+		token:      "root:Milvus",
+		httpClient: server.Client(),
+	}
+	dbs, err := cli.ListDatabases(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "db1"}, dbs)
+	assert.Equal(t, "Bearer root:Milvus", authHeader)
+
+	collections, err := cli.ListCollections(context.TODO(), "db1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c1"}, collections)
+
+	assert.NoError(t, cli.Compact(context.TODO(), "db1", "c1"))
+	assert.Equal(t, []string{
+		`/v2/vectordb/databases/list {}`,
+		`/v2/vectordb/collections/list {"dbName":"db1"}`,
+		`/v2/vectordb/collections/compact {"collectionName":"c1","dbName":"db1"}`,
+	}, requests)
+
+	code = 1800
+	_, err = cli.ListCollections(context.TODO(), "default")
+	assert.Error(t, err)
+
+	cli.token = ""
+	code = 0
+	_, err = cli.ListCollections(context.TODO(), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "", authHeader)
+}
+
+func TestGetMilvusClientCredentials(t *testing.T) {
+	ctx := context.TODO()
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "creds"},
+		Data: map[string][]byte{
+			MilvusClientUsernameKey: []byte("operator"),
+			MilvusClientPasswordKey: []byte("rotated"),
+			MilvusClientCAKey:       []byte("ca"),
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(secret).Build()
+
+	mc := v1beta1.Milvus{}
+	mc.Namespace = "ns"
+	mc.Name = "mc"
+
+	t.Run("auth disabled", func(t *testing.T) {
+		ret, err := getMilvusClientCredentials(ctx, cli, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, "", ret.token())
+	})
+
+	mc.Spec.Conf.Data = map[string]interface{}{
+		"common": map[string]interface{}{
+			"security": map[string]interface{}{
+				"authorizationEnabled": true,
+			},
+		},
+	}
+	t.Run("default root", func(t *testing.T) {
+		ret, err := getMilvusClientCredentials(ctx, cli, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, "root:Milvus", ret.token())
+		assert.Nil(t, ret.secretRef)
+	})
+
+	t.Run("from secret", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.ClientCredentialsSecretRef = &corev1.LocalObjectReference{Name: "creds"}
+		ret, err := getMilvusClientCredentials(ctx, cli, mc)
+		assert.NoError(t, err)
+		assert.Equal(t, "operator:rotated", ret.token())
+		assert.Equal(t, "creds", ret.secretRef.Name)
+		assert.Equal(t, []byte("ca"), ret.caCert)
+	})
+
+	t.Run("secret not found", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.ClientCredentialsSecretRef = &corev1.LocalObjectReference{Name: "notfound"}
+		_, err := getMilvusClientCredentials(ctx, cli, mc)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewMilvusTLSConfig(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	mc.Namespace = "ns"
+	mc.Name = "mc"
+	mc.Spec.Com.Standalone = &v1beta1.MilvusStandalone{}
+
+	t.Run("tls disabled", func(t *testing.T) {
+		ret, err := newMilvusTLSConfig(mc, milvusClientCredentials{})
+		assert.NoError(t, err)
+		assert.Nil(t, ret)
+		assert.Equal(t, "http://mc-milvus.ns:19530", getMilvusClientEndpoint(mc))
+	})
+
+	t.Run("endpoint with custom port", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mc.Spec.Mode = v1beta1.MilvusModeCluster
+		mc.Spec.Com.Proxy = &v1beta1.MilvusProxy{}
+		mc.Spec.Com.Proxy.Port = 29530
+		assert.Equal(t, "http://mc-milvus.ns:29530", getMilvusClientEndpoint(*mc))
+	})
+
+	mc.Spec.Conf.Data = map[string]interface{}{
+		"common": map[string]interface{}{
+			"security": map[string]interface{}{
+				"tlsMode": int64(1),
+			},
+		},
+	}
+	t.Run("one-way tls verifies server", func(t *testing.T) {
+		ret, err := newMilvusTLSConfig(mc, milvusClientCredentials{})
+		assert.NoError(t, err)
+		assert.False(t, ret.InsecureSkipVerify)
+		assert.Equal(t, "mc-milvus.ns", ret.ServerName)
+		assert.Equal(t, "https://mc-milvus.ns:19530", getMilvusClientEndpoint(mc))
+	})
+
+	t.Run("invalid ca", func(t *testing.T) {
+		_, err := newMilvusTLSConfig(mc, milvusClientCredentials{caCert: []byte("bad")})
+		assert.Error(t, err)
+	})
+
+	t.Run("server verify disabled", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Conf.Data["tls"] = map[string]interface{}{"serverVerify": false}
+		ret, err := newMilvusTLSConfig(mc, milvusClientCredentials{})
+		assert.NoError(t, err)
+		assert.True(t, ret.InsecureSkipVerify)
+	})
+
+	t.Run("mutual tls requires client cert", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Conf.Data["common"].(map[string]interface{})["security"].(map[string]interface{})["tlsMode"] = int64(2)
+		_, err := newMilvusTLSConfig(mc, milvusClientCredentials{})
+		assert.Error(t, err)
+	})
+}
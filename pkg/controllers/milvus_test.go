@@ -144,10 +144,12 @@ func TestCluster_SetDefaultStatus(t *testing.T) {
 
 	t.Run("no status, set default ok", func(t *testing.T) {
 		m := env.Inst
+		m.Spec.Com.Image = "milvusdb/milvus:v2.6.0"
 		mockClient.EXPECT().Status().Return(mockStatusCli)
 		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any())
 		err := r.SetDefaultStatus(ctx, &m)
 		assert.NoError(t, err)
+		assert.Equal(t, "2.6.0", m.Status.CurrentVersion)
 	})
 
 	t.Run("has status, missing currentImage, set default ok", func(t *testing.T) {
@@ -226,7 +228,7 @@ func TestCluster_ReconcileMilvus(t *testing.T) {
 			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr")),
 		mockClient.EXPECT().
 			Create(gomock.Any(), gomock.Any()).Return(nil),
-		mockGroup.EXPECT().Run(gomock.Len(5), gomock.Any(), m),
+		mockGroup.EXPECT().Run(gomock.Len(11), gomock.Any(), m),
 	)
 
 	err = r.ReconcileMilvus(ctx, m)
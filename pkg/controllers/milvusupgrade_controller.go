@@ -20,6 +20,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -84,8 +85,12 @@ func (r *MilvusUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	upgrade := new(v1beta1.MilvusUpgrade)
 	err := r.Get(ctx, req.NamespacedName, upgrade)
+	if kerrors.IsNotFound(err) {
+		forgetReconcileStats(ctx)
+		return ret, nil
+	}
 	if err != nil {
-		return ret, client.IgnoreNotFound(err)
+		return ret, err
 	}
 
 	err = r.RunStateMachine(ctx, upgrade)
@@ -110,5 +115,5 @@ func (r *MilvusUpgradeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1beta1.MilvusUpgrade{}).
 		Owns(&corev1.Pod{}).
-		Complete(r)
+		Complete(withReconcileStats("MilvusUpgrade", r))
 }
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// nativeSidecarSupported is whether the cluster supports the init containers with restartPolicy Always,
+// it's detected by the server version when the controllers setup
+var nativeSidecarSupported bool
+
+// isNativeSidecarSupported returns true if the kubernetes version is 1.29+,
+// the SidecarContainers feature gate is alpha & disabled by default in 1.28, the restartPolicy would be dropped
+func isNativeSidecarSupported(info *version.Info) bool {
+	if info == nil {
+		return false
+	}
+	v, err := semver.ParseTolerant(info.GitVersion)
+	if err != nil {
+		return false
+	}
+	// only compare the major & minor, the vendors' versions like v1.28.0-gke.1 are pre-releases in semver
+	return v.Major > 1 || v.Major == 1 && v.Minor >= 29
+}
+
+// detectNativeSidecarSupport sets nativeSidecarSupported by the server version
+func detectNativeSidecarSupport(cfg *rest.Config) error {
+	cli, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "new discovery client")
+	}
+	info, err := cli.ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "get server version")
+	}
+	nativeSidecarSupported = isNativeSidecarSupported(info)
+	return nil
+}
+
+// configSidecarStartupProbe passes when the tools are copied, the milvus container starts only after a native sidecar is started
+var configSidecarStartupProbe = &corev1.Probe{
+	ProbeHandler: corev1.ProbeHandler{
+		Exec: &corev1.ExecAction{
+			Command: []string{"/bin/sh", "-c", fmt.Sprintf("test -f %s && test -f %s && test -f %s", RunScriptPath, MergeToolPath, IAMVerifyToolPath)},
+		},
+	},
+	PeriodSeconds:    1,
+	TimeoutSeconds:   1,
+	SuccessThreshold: 1,
+	FailureThreshold: 60,
+}
+
+// updateConfigContainerRestartPolicy runs the config container as a native sidecar if sidecar mode is enabled & supported
+func updateConfigContainerRestartPolicy(template *corev1.PodTemplateSpec, sidecarMode bool) {
+	configContainerIdx := GetContainerIndex(template.Spec.InitContainers, configContainerName)
+	if configContainerIdx < 0 {
+		return
+	}
+	container := &template.Spec.InitContainers[configContainerIdx]
+	if sidecarMode && nativeSidecarSupported {
+		restartPolicy := corev1.ContainerRestartPolicyAlways
+		container.RestartPolicy = &restartPolicy
+		container.StartupProbe = configSidecarStartupProbe.DeepCopy()
+		return
+	}
+	container.RestartPolicy = nil
+	container.StartupProbe = nil
+}
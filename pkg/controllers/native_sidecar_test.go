@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestIsNativeSidecarSupported(t *testing.T) {
+	assert.False(t, isNativeSidecarSupported(nil))
+	assert.False(t, isNativeSidecarSupported(&version.Info{GitVersion: "invalid"}))
+	assert.False(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.27.9"}))
+	assert.False(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.27.16-eks-a737599"}))
+	// alpha & disabled by default in 1.28
+	assert.False(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.28.0"}))
+	assert.False(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.28.0-gke.1"}))
+	assert.True(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.29.0"}))
+	assert.True(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.29.1-gke.1"}))
+	assert.True(t, isNativeSidecarSupported(&version.Info{GitVersion: "v1.30.2+k3s1"}))
+	assert.True(t, isNativeSidecarSupported(&version.Info{GitVersion: "v2.0.0"}))
+}
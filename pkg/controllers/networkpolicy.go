@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+
+	"sort"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/config"
+)
+
+// namespaceNameLabel is set by k8s on every namespace
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+func getNetworkPolicyName(mc v1beta1.Milvus) string {
+	return mc.Name + "-milvus"
+}
+
+func (r *MilvusReconciler) updateNetworkPolicy(
+	mc v1beta1.Milvus, networkPolicy *networkingv1.NetworkPolicy) error {
+
+	appLabels := NewAppLabels(mc.Name)
+	networkPolicy.Labels = MergeLabels(networkPolicy.Labels, appLabels)
+	if err := SetControllerReference(&mc, networkPolicy, r.Scheme); err != nil {
+		r.logger.Error(err, "NetworkPolicy SetControllerReference error", "name", mc.Name, "namespace", mc.Namespace)
+		return err
+	}
+
+	// traffic between milvus components
+	peers := []networkingv1.NetworkPolicyPeer{
+		{
+			PodSelector: &metav1.LabelSelector{MatchLabels: appLabels},
+		},
+	}
+	policy := mc.Spec.Com.NetworkPolicy
+	if len(policy.AllowedNamespaces) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      namespaceNameLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   policy.AllowedNamespaces,
+					},
+				},
+			},
+		})
+	}
+	for _, cidr := range policy.AllowedCIDRs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+		})
+	}
+
+	// the operator calls milvus for health checks & scrapes the metrics
+	operatorPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{namespaceNameLabel: config.OperatorNamespace},
+		},
+	}
+
+	networkPolicy.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: appLabels},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{From: peers},
+			{
+				From:  []networkingv1.NetworkPolicyPeer{operatorPeer},
+				Ports: getOperatorAccessedPorts(mc.Spec),
+			},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+	return nil
+}
+
+// getOperatorAccessedPorts returns the milvus port & the metric ports of the components
+func getOperatorAccessedPorts(spec v1beta1.MilvusSpec) []networkingv1.NetworkPolicyPort {
+	portSet := map[int32]bool{}
+	for _, component := range GetComponentsBySpec(spec) {
+		portSet[component.GetMetricPort(spec)] = true
+		if component == Proxy || component == MilvusStandalone {
+			portSet[component.GetComponentPort(spec)] = true
+		}
+	}
+	ports := make([]int32, 0, len(portSet))
+	for port := range portSet {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	ret := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		port := intstr.FromInt32(port)
+		ret = append(ret, networkingv1.NetworkPolicyPort{Port: &port})
+	}
+	return ret
+}
+
+func (r *MilvusReconciler) ReconcileNetworkPolicy(ctx context.Context, mc v1beta1.Milvus) error {
+	namespacedName := NamespacedName(mc.Namespace, getNetworkPolicyName(mc))
+	if mc.Spec.Com.NetworkPolicy == nil || !mc.Spec.Com.NetworkPolicy.Enabled {
+		return r.deleteIfExists(ctx, &networkingv1.NetworkPolicy{}, namespacedName)
+	}
+
+	old := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, namespacedName, old)
+	if errors.IsNotFound(err) {
+		new := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := r.updateNetworkPolicy(mc, new); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create NetworkPolicy", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	}
+	if err != nil {
+		return err
+	}
+
+	cur := old.DeepCopy()
+	if err := r.updateNetworkPolicy(mc, cur); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update NetworkPolicy", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
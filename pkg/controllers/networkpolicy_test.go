@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/config"
+)
+
+func newNetworkPolicyTestMilvus() v1beta1.Milvus {
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Default()
+	m.Spec.Com.NetworkPolicy = &v1beta1.MilvusNetworkPolicy{
+		Enabled:           true,
+		AllowedNamespaces: []string{"app"},
+		AllowedCIDRs:      []string{"10.0.0.0/16"},
+	}
+	return m
+}
+
+func TestReconciler_ReconcileNetworkPolicy_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newNetworkPolicyTestMilvus()
+	m.Spec.Com.NetworkPolicy = nil
+
+	t.Run("not exist", func(t *testing.T) {
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+		err := r.ReconcileNetworkPolicy(ctx, m)
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		m.Spec.Com.NetworkPolicy = &v1beta1.MilvusNetworkPolicy{}
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName("ns", "mc-milvus"), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1.NetworkPolicy{})).Return(nil)
+		err := r.ReconcileNetworkPolicy(ctx, m)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReconciler_ReconcileNetworkPolicy_CreateIfNotExist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newNetworkPolicyTestMilvus()
+
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+	mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, obj *networkingv1.NetworkPolicy, _ ...any) error {
+			assert.Equal(t, "mc-milvus", obj.Name)
+			// selecting component pods
+			assert.Equal(t, NewAppLabels(m.Name), obj.Spec.PodSelector.MatchLabels)
+			for _, component := range GetComponentsBySpec(m.Spec) {
+				selector, err := metav1.LabelSelectorAsSelector(&obj.Spec.PodSelector)
+				assert.NoError(t, err)
+				assert.True(t, selector.Matches(labels.Set(NewComponentAppLabels(m.Name, component.Name))))
+			}
+			assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, obj.Spec.PolicyTypes)
+
+			// allow rules
+			assert.Len(t, obj.Spec.Ingress, 2)
+			peers := obj.Spec.Ingress[0].From
+			assert.Len(t, peers, 3)
+			assert.Equal(t, NewAppLabels(m.Name), peers[0].PodSelector.MatchLabels)
+			assert.Equal(t, namespaceNameLabel, peers[1].NamespaceSelector.MatchExpressions[0].Key)
+			assert.Equal(t, []string{"app"}, peers[1].NamespaceSelector.MatchExpressions[0].Values)
+			assert.Equal(t, "10.0.0.0/16", peers[2].IPBlock.CIDR)
+
+			// operator allowed to the milvus & metric ports
+			operatorRule := obj.Spec.Ingress[1]
+			assert.Equal(t, map[string]string{namespaceNameLabel: config.OperatorNamespace},
+				operatorRule.From[0].NamespaceSelector.MatchLabels)
+			var ports []int
+			for _, port := range operatorRule.Ports {
+				ports = append(ports, port.Port.IntValue())
+			}
+			assert.Equal(t, []int{MetricPort, MilvusPort}, ports)
+			return nil
+		})
+
+	err := r.ReconcileNetworkPolicy(ctx, m)
+	assert.NoError(t, err)
+}
+
+func TestReconciler_ReconcileNetworkPolicy_UpdateIfExisted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newNetworkPolicyTestMilvus()
+	m.Spec.Com.NetworkPolicy.AllowedNamespaces = nil
+	m.Spec.Com.NetworkPolicy.AllowedCIDRs = nil
+
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key client.ObjectKey, obj *networkingv1.NetworkPolicy, _ ...any) error {
+			obj.Namespace = key.Namespace
+			obj.Name = key.Name
+			return nil
+		})
+	mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, obj *networkingv1.NetworkPolicy, _ ...any) error {
+			// only intra-cluster component traffic allowed
+			assert.Len(t, obj.Spec.Ingress[0].From, 1)
+			return nil
+		})
+
+	err := r.ReconcileNetworkPolicy(ctx, m)
+	assert.NoError(t, err)
+}
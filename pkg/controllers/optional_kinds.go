@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// isKindUnavailableError returns true if the kind of the object is not registered in the scheme
+// or not served by the apiserver, which is expected for the optional kinds whose CRDs may be not installed
+func isKindUnavailableError(err error) bool {
+	return meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err)
+}
+
+// optionalKindTracker records the optional kinds skipped for each milvus by the concurrent reconcilers
+type optionalKindTracker struct {
+	mu          sync.Mutex
+	unavailable map[types.NamespacedName]map[string]bool
+}
+
+var unavailableOptionalKinds = &optionalKindTracker{
+	unavailable: make(map[types.NamespacedName]map[string]bool),
+}
+
+// Set records whether the kind is unavailable for the milvus
+func (t *optionalKindTracker) Set(key types.NamespacedName, kind string, unavailable bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !unavailable {
+		delete(t.unavailable[key], kind)
+		return
+	}
+	if t.unavailable[key] == nil {
+		t.unavailable[key] = make(map[string]bool)
+	}
+	t.unavailable[key][kind] = true
+}
+
+// Get returns the sorted unavailable kinds of the milvus
+func (t *optionalKindTracker) Get(key types.NamespacedName) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ret := make([]string, 0, len(t.unavailable[key]))
+	for kind := range t.unavailable[key] {
+		ret = append(ret, kind)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Forget removes the records of the deleted milvus
+func (t *optionalKindTracker) Forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.unavailable, key)
+}
+
+// updateOptionalKindCondition sets the OptionalResourceUnavailable condition if any optional resource of milvus is skipped,
+// returns true if the conditions are changed
+func updateOptionalKindCondition(mc *v1beta1.Milvus) bool {
+	oldConditions := mc.Status.DeepCopy().Conditions
+	kinds := unavailableOptionalKinds.Get(NamespacedName(mc.Namespace, mc.Name))
+	if len(kinds) < 1 {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.OptionalResourceUnavailable})
+		return !IsEqual(oldConditions, mc.Status.Conditions)
+	}
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.OptionalResourceUnavailable,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonKindUnavailable,
+		Message: fmt.Sprintf("%s skipped, the kinds are not registered or their CRDs are not installed", strings.Join(kinds, ", ")),
+	})
+	return !IsEqual(oldConditions, mc.Status.Conditions)
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusReconciler_OptionalKindsUnavailable(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+
+	// the monitoring types are not registered
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+	r.Scheme = testScheme
+	r.Client = fake.NewClientBuilder().WithScheme(testScheme).Build()
+
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Com.ServiceMonitor = &v1beta1.MilvusServiceMonitor{Enabled: true}
+	key := client.ObjectKeyFromObject(mc)
+	defer unavailableOptionalKinds.Forget(key)
+
+	// reconcile continues without the optional resources
+	err := new(ParallelGroupRunner).Run([]Func{r.ReconcilePodMonitor, r.ReconcileServiceMonitor}, ctx, *mc)
+	assert.NoError(t, err)
+	assert.True(t, updateOptionalKindCondition(mc))
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.OptionalResourceUnavailable)
+	assert.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, v1beta1.ReasonKindUnavailable, cond.Reason)
+	assert.Contains(t, cond.Message, monitoringv1.PodMonitorsKind+", "+monitoringv1.ServiceMonitorsKind)
+	assert.False(t, updateOptionalKindCondition(mc))
+
+	t.Run("removed when the monitors are disabled", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mc.Spec.Com.DisableMetric = true
+		mc.Spec.Com.ServiceMonitor.Enabled = false
+		err := new(ParallelGroupRunner).Run([]Func{r.ReconcilePodMonitor, r.ReconcileServiceMonitor}, ctx, *mc)
+		assert.NoError(t, err)
+		assert.True(t, updateOptionalKindCondition(mc))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.OptionalResourceUnavailable))
+	})
+}
+
+func TestIsKindUnavailableError(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	_, _, err := testScheme.ObjectKinds(&monitoringv1.PodMonitor{})
+	assert.True(t, isKindUnavailableError(err))
+	assert.False(t, isKindUnavailableError(nil))
+	assert.False(t, isKindUnavailableError(assert.AnError))
+}
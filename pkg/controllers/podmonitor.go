@@ -5,7 +5,6 @@ import (
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
@@ -86,14 +85,17 @@ func (r *MilvusReconciler) updatePodMonitor(
 }
 
 func (r *MilvusReconciler) ReconcilePodMonitor(ctx context.Context, mc v1beta1.Milvus) error {
+	namespacedName := NamespacedName(mc.Namespace, mc.Name)
 	if mc.Spec.Com.DisableMetric {
+		unavailableOptionalKinds.Set(namespacedName, monitoringv1.PodMonitorsKind, false)
 		return nil
 	}
-	namespacedName := NamespacedName(mc.Namespace, mc.Name)
 	old := &monitoringv1.PodMonitor{}
 	err := r.Get(ctx, namespacedName, old)
-	if meta.IsNoMatchError(err) {
-		r.logger.Info("podmonitor kind no matchs, maybe is not installed")
+	unavailable := isKindUnavailableError(err)
+	unavailableOptionalKinds.Set(namespacedName, monitoringv1.PodMonitorsKind, unavailable)
+	if unavailable {
+		r.logger.Info("podmonitor kind no matchs, maybe is not installed", "err", err.Error())
 		return nil
 	}
 
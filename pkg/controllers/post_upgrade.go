@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+//go:generate mockgen -package=controllers -source=post_upgrade.go -destination=post_upgrade_mock.go
+
+// MilvusAdminClient calls the admin APIs of milvus
+type MilvusAdminClient interface {
+	// TriggerCompaction triggers the compaction of at most limit collections after the cursor,
+	// it returns the cursor of the last collection triggered, and whether all the collections are done
+	TriggerCompaction(ctx context.Context, mc v1beta1.Milvus, cursor string, limit int) (string, bool, error)
+}
+
+// postUpgradeCompactionBatchSize is the max number of collections compacted in each sync,
+// to avoid blocking the status syncer with a large number of collections
+var postUpgradeCompactionBatchSize = 20
+
+// milvusAdminClientImpl performs the admin operations through the milvus query client
+type milvusAdminClientImpl struct {
+	cli client.Client
+}
+
+func newMilvusAdminClientImpl(cli client.Client) *milvusAdminClientImpl {
+	return &milvusAdminClientImpl{cli: cli}
+}
+
+// compactionCursor returns the cursor of the collection, in format <database>/<collection>
+func compactionCursor(dbName, collection string) string {
+	return dbName + "/" + collection
+}
+
+// TriggerCompaction triggers the compaction of the collections in all the databases in order of the names,
+// the compactions run in background in milvus
+func (c milvusAdminClientImpl) TriggerCompaction(ctx context.Context, mc v1beta1.Milvus, cursor string, limit int) (string, bool, error) {
+	queryClient, err := newMilvusQueryClient(ctx, c.cli, mc)
+	if err != nil {
+		return cursor, false, err
+	}
+	dbNames, err := queryClient.ListDatabases(ctx)
+	if err != nil {
+		return cursor, false, errors.Wrap(err, "list databases")
+	}
+	sort.Strings(dbNames)
+	cursorDB, _, _ := strings.Cut(cursor, "/")
+	var triggered int
+	for _, dbName := range dbNames {
+		if dbName < cursorDB {
+			continue
+		}
+		collections, err := queryClient.ListCollections(ctx, dbName)
+		if err != nil {
+			return cursor, false, errors.Wrapf(err, "list collections of database[%s]", dbName)
+		}
+		sort.Strings(collections)
+		for _, collection := range collections {
+			if dbName == cursorDB && compactionCursor(dbName, collection) <= cursor {
+				continue
+			}
+			if triggered >= limit {
+				return cursor, false, nil
+			}
+			if err := queryClient.Compact(ctx, dbName, collection); err != nil {
+				return cursor, false, errors.Wrapf(err, "compact collection[%s.%s]", dbName, collection)
+			}
+			cursor = compactionCursor(dbName, collection)
+			triggered++
+		}
+	}
+	return cursor, true, nil
+}
+
+// markPostUpgradeActions records the post upgrade actions to perform when the current image changes by an upgrade
+func markPostUpgradeActions(mc *v1beta1.Milvus, lastImage string) {
+	if lastImage == "" || lastImage == mc.Status.CurrentImage {
+		return
+	}
+	if len(mc.Spec.Com.PostUpgradeActions) < 1 {
+		return
+	}
+	mc.Status.PendingPostUpgradeActions = append([]v1beta1.PostUpgradeAction{}, mc.Spec.Com.PostUpgradeActions...)
+	mc.Status.PostUpgradeCompactionCursor = ""
+}
+
+// runPendingPostUpgradeActions performs the pending post upgrade actions when milvus is healthy,
+// the actions done are removed from the pending list, so that each action runs once per upgrade.
+// the compaction is triggered in batches, its progress is kept in the status for the next sync
+func (r *MilvusStatusSyncer) runPendingPostUpgradeActions(ctx context.Context, mc *v1beta1.Milvus) error {
+	if len(mc.Status.PendingPostUpgradeActions) < 1 ||
+		mc.Status.Status != v1beta1.StatusHealthy {
+		return nil
+	}
+	var pending []v1beta1.PostUpgradeAction
+	var ret error
+	for _, action := range mc.Status.PendingPostUpgradeActions {
+		var err error
+		switch action {
+		case v1beta1.PostUpgradeActionTriggerCompaction:
+			r.logger.Info("trigger compaction after upgrade", "namespace", mc.Namespace, "name", mc.Name,
+				"cursor", mc.Status.PostUpgradeCompactionCursor)
+			var done bool
+			mc.Status.PostUpgradeCompactionCursor, done, err = r.adminClient.TriggerCompaction(ctx, *mc,
+				mc.Status.PostUpgradeCompactionCursor, postUpgradeCompactionBatchSize)
+			if err == nil && !done {
+				pending = append(pending, action)
+				continue
+			}
+			if done {
+				mc.Status.PostUpgradeCompactionCursor = ""
+			}
+		default:
+			r.logger.Info("unknown post upgrade action, skip", "action", action)
+		}
+		if err != nil {
+			pending = append(pending, action)
+			ret = errors.Wrapf(err, "post upgrade action[%s]", action)
+		}
+	}
+	mc.Status.PendingPostUpgradeActions = pending
+	return ret
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMarkPostUpgradeActions(t *testing.T) {
+	mc := &v1beta1.Milvus{}
+	mc.Spec.Com.PostUpgradeActions = []v1beta1.PostUpgradeAction{v1beta1.PostUpgradeActionTriggerCompaction}
+	mc.Status.CurrentImage = "milvus:v2"
+
+	t.Run("fresh install not marked", func(t *testing.T) {
+		markPostUpgradeActions(mc, "")
+		assert.Empty(t, mc.Status.PendingPostUpgradeActions)
+	})
+
+	t.Run("image not changed not marked", func(t *testing.T) {
+		markPostUpgradeActions(mc, "milvus:v2")
+		assert.Empty(t, mc.Status.PendingPostUpgradeActions)
+	})
+
+	t.Run("no actions not marked", func(t *testing.T) {
+		noActions := mc.DeepCopy()
+		noActions.Spec.Com.PostUpgradeActions = nil
+		markPostUpgradeActions(noActions, "milvus:v1")
+		assert.Empty(t, noActions.Status.PendingPostUpgradeActions)
+	})
+
+	t.Run("upgraded marked", func(t *testing.T) {
+		markPostUpgradeActions(mc, "milvus:v1")
+		assert.Equal(t, []v1beta1.PostUpgradeAction{v1beta1.PostUpgradeActionTriggerCompaction}, mc.Status.PendingPostUpgradeActions)
+	})
+}
+
+func TestMilvusStatusSyncer_runPendingPostUpgradeActions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCli := NewMockK8sClient(ctrl)
+	mockAdmin := NewMockMilvusAdminClient(ctrl)
+	ctx := context.Background()
+	s := NewMilvusStatusSyncer(ctx, mockCli, logf.Log.WithName("test"))
+	s.adminClient = mockAdmin
+
+	mc := &v1beta1.Milvus{}
+	mc.Spec.Com.PostUpgradeActions = []v1beta1.PostUpgradeAction{v1beta1.PostUpgradeActionTriggerCompaction}
+	mc.Status.CurrentImage = "milvus:v2"
+	mc.Status.Status = v1beta1.StatusUnhealthy
+	markPostUpgradeActions(mc, "milvus:v1")
+
+	t.Run("not run when unhealthy", func(t *testing.T) {
+		err := s.runPendingPostUpgradeActions(ctx, mc)
+		assert.NoError(t, err)
+		assert.Len(t, mc.Status.PendingPostUpgradeActions, 1)
+	})
+
+	t.Run("failed kept pending", func(t *testing.T) {
+		mc.Status.Status = v1beta1.StatusHealthy
+		mockAdmin.EXPECT().TriggerCompaction(gomock.Any(), gomock.Any(), "", postUpgradeCompactionBatchSize).
+			Return("default/c1", false, errors.New("test"))
+		err := s.runPendingPostUpgradeActions(ctx, mc)
+		assert.Error(t, err)
+		assert.Len(t, mc.Status.PendingPostUpgradeActions, 1)
+		assert.Equal(t, "default/c1", mc.Status.PostUpgradeCompactionCursor)
+	})
+
+	t.Run("continued after cursor in next sync", func(t *testing.T) {
+		mockAdmin.EXPECT().TriggerCompaction(gomock.Any(), gomock.Any(), "default/c1", postUpgradeCompactionBatchSize).
+			Return("default/c2", false, nil)
+		err := s.runPendingPostUpgradeActions(ctx, mc)
+		assert.NoError(t, err)
+		assert.Len(t, mc.Status.PendingPostUpgradeActions, 1)
+		assert.Equal(t, "default/c2", mc.Status.PostUpgradeCompactionCursor)
+	})
+
+	t.Run("run once after upgrade completed", func(t *testing.T) {
+		mockAdmin.EXPECT().TriggerCompaction(gomock.Any(), gomock.Any(), "default/c2", postUpgradeCompactionBatchSize).
+			Return("default/c3", true, nil).Times(1)
+		err := s.runPendingPostUpgradeActions(ctx, mc)
+		assert.NoError(t, err)
+		assert.Empty(t, mc.Status.PendingPostUpgradeActions)
+		assert.Empty(t, mc.Status.PostUpgradeCompactionCursor)
+
+		// later syncs with same image do nothing
+		markPostUpgradeActions(mc, mc.Status.CurrentImage)
+		err = s.runPendingPostUpgradeActions(ctx, mc)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMilvusAdminClientImpl_TriggerCompaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockQueryClient := NewMockMilvusQueryClient(ctrl)
+	bak := newMilvusQueryClient
+	defer func() { newMilvusQueryClient = bak }()
+	newMilvusQueryClient = func(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (MilvusQueryClient, error) {
+		return mockQueryClient, nil
+	}
+	ctx := context.Background()
+	c := newMilvusAdminClientImpl(nil)
+
+	t.Run("compact all collections", func(t *testing.T) {
+		gomock.InOrder(
+			mockQueryClient.EXPECT().ListDatabases(gomock.Any()).Return([]string{"db1", "default"}, nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "db1").Return(nil, nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "default").Return([]string{"c2", "c1"}, nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "default", "c1").Return(nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "default", "c2").Return(nil),
+		)
+		cursor, done, err := c.TriggerCompaction(ctx, v1beta1.Milvus{}, "", 10)
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, "default/c2", cursor)
+	})
+
+	t.Run("compact in batches", func(t *testing.T) {
+		gomock.InOrder(
+			mockQueryClient.EXPECT().ListDatabases(gomock.Any()).Return([]string{"db1", "default"}, nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "db1").Return([]string{"c1", "c2"}, nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "db1", "c1").Return(nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "db1", "c2").Return(nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "default").Return([]string{"c1"}, nil),
+		)
+		cursor, done, err := c.TriggerCompaction(ctx, v1beta1.Milvus{}, "", 2)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, "db1/c2", cursor)
+
+		// the databases before the cursor are skipped
+		gomock.InOrder(
+			mockQueryClient.EXPECT().ListDatabases(gomock.Any()).Return([]string{"a", "db1", "default"}, nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "db1").Return([]string{"c1", "c2"}, nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "default").Return([]string{"c1"}, nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "default", "c1").Return(nil),
+		)
+		cursor, done, err = c.TriggerCompaction(ctx, v1beta1.Milvus{}, cursor, 2)
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, "default/c1", cursor)
+	})
+
+	t.Run("compact failed", func(t *testing.T) {
+		gomock.InOrder(
+			mockQueryClient.EXPECT().ListDatabases(gomock.Any()).Return([]string{"default"}, nil),
+			mockQueryClient.EXPECT().ListCollections(gomock.Any(), "default").Return([]string{"c1", "c2"}, nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "default", "c1").Return(nil),
+			mockQueryClient.EXPECT().Compact(gomock.Any(), "default", "c2").Return(errors.New("test")),
+		)
+		cursor, done, err := c.TriggerCompaction(ctx, v1beta1.Milvus{}, "", 10)
+		assert.Error(t, err)
+		assert.False(t, done)
+		// the progress before the failure is kept
+		assert.Equal(t, "default/c1", cursor)
+	})
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// CoordReadyPodCondition is the readiness gate of the proxy pods, it's set true by the operator when the coordinators are ready
+const CoordReadyPodCondition corev1.PodConditionType = v1beta1.MilvusIO + "coord-ready"
+
+// isCoordReadinessGateEnabled returns true if the component is the proxy with the coordinator readiness gate enabled
+func isCoordReadinessGateEnabled(spec v1beta1.MilvusSpec, component MilvusComponent) bool {
+	return component == Proxy && spec.Mode == v1beta1.MilvusModeCluster &&
+		spec.Com.Proxy != nil && spec.Com.Proxy.CoordReadinessGate
+}
+
+// updateReadinessGates adds or removes the coordinator readiness gate of the pod template
+func updateReadinessGates(template *corev1.PodTemplateSpec, updater deploymentUpdater) {
+	idx := slices.IndexFunc(template.Spec.ReadinessGates, func(gate corev1.PodReadinessGate) bool {
+		return gate.ConditionType == CoordReadyPodCondition
+	})
+	enabled := isCoordReadinessGateEnabled(updater.GetMilvus().Spec, updater.GetComponent())
+	switch {
+	case enabled && idx < 0:
+		template.Spec.ReadinessGates = append(template.Spec.ReadinessGates, corev1.PodReadinessGate{ConditionType: CoordReadyPodCondition})
+	case !enabled && idx >= 0:
+		template.Spec.ReadinessGates = slices.Delete(template.Spec.ReadinessGates, idx, idx+1)
+		if len(template.Spec.ReadinessGates) == 0 {
+			template.Spec.ReadinessGates = nil
+		}
+	}
+}
+
+// getUnreadyCoords returns the enabled coordinators without any ready replica by the deploy status
+func getUnreadyCoords(mc v1beta1.Milvus) []string {
+	unready := []string{}
+	for _, component := range GetComponentsBySpec(mc.Spec) {
+		if !component.IsCoord() || ReplicasValue(component.GetDesiredReplicas(mc.Spec)) < 1 {
+			continue
+		}
+		if mc.Status.ComponentsDeployStatus[component.GetName()].Status.ReadyReplicas < 1 {
+			unready = append(unready, component.Name)
+		}
+	}
+	return unready
+}
+
+// updateProxyReadinessGate opens the readiness gate of the proxy pods when the coordinators are ready,
+// and sets the ProxyTrafficGated condition while they're waiting
+func (r *MilvusStatusSyncer) updateProxyReadinessGate(ctx context.Context, mc *v1beta1.Milvus) error {
+	if !isCoordReadinessGateEnabled(mc.Spec, Proxy) {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.ProxyTrafficGated})
+		return nil
+	}
+	unreadyCoords := getUnreadyCoords(*mc)
+	if len(unreadyCoords) > 0 {
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+			Type:    v1beta1.ProxyTrafficGated,
+			Status:  corev1.ConditionTrue,
+			Reason:  v1beta1.ReasonWaitingForCoordinators,
+			Message: fmt.Sprintf("proxy waits for coordinators[%s] to be ready", strings.Join(unreadyCoords, ",")),
+		})
+		return nil
+	}
+	RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.ProxyTrafficGated})
+
+	pods := &corev1.PodList{}
+	opts := &client.ListOptions{Namespace: mc.Namespace}
+	opts.LabelSelector = labels.SelectorFromSet(NewComponentAppLabels(mc.Name, ProxyName))
+	if err := r.List(ctx, pods, opts); err != nil {
+		return errors.Wrap(err, "list proxy pods")
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !slices.ContainsFunc(pod.Spec.ReadinessGates, func(gate corev1.PodReadinessGate) bool {
+			return gate.ConditionType == CoordReadyPodCondition
+		}) {
+			continue
+		}
+		if !setPodConditionTrue(pod, CoordReadyPodCondition, "CoordinatorsReady") {
+			continue
+		}
+		if err := r.Status().Update(ctx, pod); err != nil {
+			return errors.Wrapf(err, "open readiness gate of pod %s", pod.Name)
+		}
+	}
+	return nil
+}
+
+// setPodConditionTrue returns false if the condition is already true
+func setPodConditionTrue(pod *corev1.Pod, conditionType corev1.PodConditionType, reason string) bool {
+	cond := corev1.PodCondition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+	}
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type != conditionType {
+			continue
+		}
+		if pod.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return false
+		}
+		pod.Status.Conditions[i] = cond
+		return true
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, cond)
+	return true
+}
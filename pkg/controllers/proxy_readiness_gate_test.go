@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestUpdateReadinessGates(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Com.Proxy.CoordReadinessGate = true
+
+	template := &corev1.PodTemplateSpec{}
+	updateReadinessGates(template, newMilvusDeploymentUpdater(mc, nil, Proxy))
+	assert.Equal(t, []corev1.PodReadinessGate{{ConditionType: CoordReadyPodCondition}}, template.Spec.ReadinessGates)
+	// not added twice
+	updateReadinessGates(template, newMilvusDeploymentUpdater(mc, nil, Proxy))
+	assert.Len(t, template.Spec.ReadinessGates, 1)
+
+	t.Run("not proxy", func(t *testing.T) {
+		template := &corev1.PodTemplateSpec{}
+		updateReadinessGates(template, newMilvusDeploymentUpdater(mc, nil, DataNode))
+		assert.Empty(t, template.Spec.ReadinessGates)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Com.Proxy.CoordReadinessGate = false
+		updateReadinessGates(template, newMilvusDeploymentUpdater(mc, nil, Proxy))
+		assert.Nil(t, template.Spec.ReadinessGates)
+	})
+}
+
+func TestMilvusStatusSyncer_updateProxyReadinessGate(t *testing.T) {
+	ctx := context.Background()
+	mc := &v1beta1.Milvus{}
+	mc.Namespace = "ns"
+	mc.Name = "mc"
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Com.Proxy.CoordReadinessGate = true
+
+	pod := &corev1.Pod{}
+	pod.Namespace = mc.Namespace
+	pod.Name = "proxy-pod"
+	pod.Labels = NewComponentAppLabels(mc.Name, ProxyName)
+	pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: CoordReadyPodCondition}}
+	cli := fake.NewClientBuilder().WithObjects(pod).WithStatusSubresource(&corev1.Pod{}).Build()
+	s := NewMilvusStatusSyncer(ctx, cli, logf.Log.WithName("test"))
+	getGateCondition := func() *corev1.PodCondition {
+		pod := &corev1.Pod{}
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "proxy-pod"}, pod))
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == CoordReadyPodCondition {
+				return &cond
+			}
+		}
+		return nil
+	}
+
+	// coords not ready, proxy gated
+	assert.NoError(t, s.updateProxyReadinessGate(ctx, mc))
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ProxyTrafficGated)
+	assert.NotNil(t, cond)
+	assert.Equal(t, v1beta1.ReasonWaitingForCoordinators, cond.Reason)
+	assert.Contains(t, cond.Message, MixCoordName)
+	assert.Nil(t, getGateCondition())
+
+	// coords ready, gate opened
+	mc.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+		MixCoordName: {Status: appsv1.DeploymentStatus{ReadyReplicas: 1}},
+	}
+	assert.NoError(t, s.updateProxyReadinessGate(ctx, mc))
+	assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ProxyTrafficGated))
+	gateCond := getGateCondition()
+	assert.NotNil(t, gateCond)
+	assert.Equal(t, corev1.ConditionTrue, gateCond.Status)
+
+	t.Run("disabled", func(t *testing.T) {
+		mc := mc.DeepCopy()
+		mc.Spec.Com.Proxy.CoordReadinessGate = false
+		mc.Status.ComponentsDeployStatus = nil
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{Type: v1beta1.ProxyTrafficGated, Status: corev1.ConditionTrue})
+		assert.NoError(t, s.updateProxyReadinessGate(ctx, mc))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ProxyTrafficGated))
+	})
+}
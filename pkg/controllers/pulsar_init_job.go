@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// HelmReleaseNameAnnotation is set by helm on the resources of a release
+const HelmReleaseNameAnnotation = "meta.helm.sh/release-name"
+
+// isJobComplete returns true if the job has finished successfully
+func isJobComplete(job batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupPulsarInitJobs deletes the completed initialization jobs of the managed pulsar release once the pulsar is ready.
+// the jobs are only rendered on install, so they're not recreated by the later updates of the release
+func (r *MilvusReconciler) cleanupPulsarInitJobs(ctx context.Context, mc v1beta1.Milvus, release string) error {
+	if !IsMilvusConditionTrueByType(mc.Status.Conditions, v1beta1.MsgStreamReady) {
+		return nil
+	}
+	jobList := &batchv1.JobList{}
+	if err := r.List(ctx, jobList, client.InNamespace(mc.Namespace)); err != nil {
+		return errors.Wrap(err, "list jobs")
+	}
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if job.Annotations[HelmReleaseNameAnnotation] != release || !isJobComplete(*job) {
+			continue
+		}
+		r.logger.Info("delete completed pulsar init job", "name", job.Name, "namespace", job.Namespace)
+		err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "delete pulsar init job[%s]", job.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func newTestPulsarInitJob(name, release string, complete bool) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Annotations: map[string]string{
+				HelmReleaseNameAnnotation: release,
+			},
+		},
+	}
+	if complete {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+	}
+	return job
+}
+
+func TestMilvusReconciler_ReconcilePulsar_CleanupInitJobs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	mockHelm := NewMockHelmReconciler(env.Ctrl)
+	r.helmReconciler = mockHelm
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+	mc.Spec.Dep.Pulsar.InCluster = &v1beta1.InClusterConfig{}
+	release := mc.Name + "-pulsar"
+
+	newClient := func() client.Client {
+		return fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			newTestPulsarInitJob("pulsar-init", release, true),
+			newTestPulsarInitJob("bookie-init", release, false),
+			newTestPulsarInitJob("other-init", "other-pulsar", true),
+		).Build()
+	}
+	assertJobExists := func(t *testing.T, cli client.Client, name string, exists bool) {
+		err := cli.Get(ctx, NamespacedName("ns", name), &batchv1.Job{})
+		if exists {
+			assert.NoError(t, err)
+		} else {
+			assert.True(t, kerrors.IsNotFound(err))
+		}
+	}
+
+	t.Run("pulsar not ready, jobs kept", func(t *testing.T) {
+		r.Client = newClient()
+		mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).Return(nil)
+		assert.NoError(t, r.ReconcilePulsar(ctx, *mc))
+		assertJobExists(t, r.Client, "pulsar-init", true)
+	})
+
+	t.Run("completed job cleaned up, in progress one kept", func(t *testing.T) {
+		r.Client = newClient()
+		mc := mc.DeepCopy()
+		mc.Status.Conditions = []v1beta1.MilvusCondition{
+			{Type: v1beta1.MsgStreamReady, Status: corev1.ConditionTrue},
+		}
+		mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).Return(nil)
+		assert.NoError(t, r.ReconcilePulsar(ctx, *mc))
+		assertJobExists(t, r.Client, "pulsar-init", false)
+		assertJobExists(t, r.Client, "bookie-init", true)
+		assertJobExists(t, r.Client, "other-init", true)
+	})
+
+	t.Run("helm reconcile failed", func(t *testing.T) {
+		r.Client = newClient()
+		mockHelm.EXPECT().Reconcile(gomock.Any(), gomock.Any()).Return(errMock)
+		assert.Error(t, r.ReconcilePulsar(ctx, *mc))
+	})
+}
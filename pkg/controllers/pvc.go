@@ -55,7 +55,7 @@ func (r *MilvusReconciler) syncUpdatePVC(ctx context.Context, namespacedName typ
 	r.syncPVC(ctx, milvusPVC, new)
 	// volume name set by pvc controller
 	new.Spec.VolumeName = old.Spec.VolumeName
-	if milvusPVC.GetSpec().StorageClassName == nil {
+	if new.Spec.StorageClassName == nil {
 		// if nil, default storage class name set by pvc controller
 		new.Spec.StorageClassName = old.Spec.StorageClassName
 	}
@@ -72,6 +72,9 @@ func (r *MilvusReconciler) syncPVC(ctx context.Context, milvusPVC v1beta1.Persis
 	pvc.Labels = milvusPVC.Labels
 	pvc.Annotations = milvusPVC.Annotations
 	pvc.Spec = *milvusPVC.GetSpec()
+	if milvusPVC.StorageClassName != nil {
+		pvc.Spec.StorageClassName = milvusPVC.StorageClassName
+	}
 	if len(pvc.Spec.AccessModes) < 1 {
 		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
 			corev1.ReadWriteOnce,
@@ -61,6 +61,27 @@ func TestMilvusReconciler_ReconcilePVCs(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	storageClassName := "fast-ssd"
+	m.Spec.Dep.RocksMQ.Persistence.PersistentVolumeClaim.StorageClassName = &storageClassName
+	t.Run("sync:create_new_with_storage_class", func(t *testing.T) {
+		defer env.Ctrl.Finish()
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errNotFound)
+		mockClient.EXPECT().Create(ctx, gomock.Any()).Do(func(_, obj interface{}, opts ...any) {
+			pvc := obj.(*corev1.PersistentVolumeClaim)
+			assert.Equal(t, storageClassName, *pvc.Spec.StorageClassName)
+		}).Return(nil)
+		err := r.ReconcilePVCs(ctx, m)
+		assert.NoError(t, err)
+	})
+
+	t.Run("storage_class_ignored_for_existing_claim", func(t *testing.T) {
+		m := m.DeepCopy()
+		m.Spec.Dep.RocksMQ.Persistence.PersistentVolumeClaim.ExistingClaim = "claim"
+		err := r.ReconcilePVCs(ctx, *m)
+		assert.NoError(t, err)
+	})
+	m.Spec.Dep.RocksMQ.Persistence.PersistentVolumeClaim.StorageClassName = nil
+
 	m.Spec.Dep.RocksMQ.Persistence.PersistentVolumeClaim.Annotations = map[string]string{"bla": "bla"}
 	t.Run("sync:update", func(t *testing.T) {
 		defer env.Ctrl.Finish()
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// ReconcileEventWriter is where the structured reconcile events are written as JSON lines, nil disables them
+var ReconcileEventWriter io.Writer
+
+var reconcileEventWriterMu sync.Mutex
+
+// Actions taken on the dependency helm releases
+const (
+	DependencyActionInstall   = "install"
+	DependencyActionUpgrade   = "upgrade"
+	DependencyActionUninstall = "uninstall"
+)
+
+// DependencyAction is an action taken on a dependency helm release in a reconcile
+type DependencyAction struct {
+	Release string `json:"release"`
+	Action  string `json:"action"`
+}
+
+// ReconcileEvent is the machine-parseable outcome of a reconcile of milvus
+type ReconcileEvent struct {
+	Time              time.Time                  `json:"time"`
+	Instance          string                     `json:"instance"`
+	Generation        int64                      `json:"generation"`
+	Status            v1beta1.MilvusHealthStatus `json:"status"`
+	ConditionsChanged []string                   `json:"conditionsChanged"`
+	DependencyActions []DependencyAction         `json:"dependencyActions"`
+	DurationSeconds   float64                    `json:"durationSeconds"`
+	Error             string                     `json:"error,omitempty"`
+}
+
+// reconcileEventRecorder collects the outcome of a reconcile, it's carried in the context
+type reconcileEventRecorder struct {
+	instance types.NamespacedName
+	start    time.Time
+
+	mu                sync.Mutex
+	milvus            *v1beta1.Milvus
+	oldConditions     []v1beta1.MilvusCondition
+	dependencyActions []DependencyAction
+}
+
+type reconcileEventRecorderKey struct{}
+
+// withReconcileEventRecorder returns a context carrying a new recorder of the reconcile of the instance
+func withReconcileEventRecorder(ctx context.Context, instance types.NamespacedName) (context.Context, *reconcileEventRecorder) {
+	recorder := &reconcileEventRecorder{
+		instance: instance,
+		start:    reconcileNow(),
+	}
+	return context.WithValue(ctx, reconcileEventRecorderKey{}, recorder), recorder
+}
+
+func getReconcileEventRecorder(ctx context.Context) *reconcileEventRecorder {
+	recorder, _ := ctx.Value(reconcileEventRecorderKey{}).(*reconcileEventRecorder)
+	return recorder
+}
+
+// observeMilvus records the milvus reconciled, its conditions at the time are compared with the final ones
+func observeMilvus(ctx context.Context, mc *v1beta1.Milvus) {
+	recorder := getReconcileEventRecorder(ctx)
+	if recorder == nil {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.milvus = mc
+	recorder.oldConditions = append([]v1beta1.MilvusCondition{}, mc.Status.Conditions...)
+}
+
+// recordDependencyAction records an action taken on a dependency release, the dependencies may be reconciled concurrently
+func recordDependencyAction(ctx context.Context, release, action string) {
+	recorder := getReconcileEventRecorder(ctx)
+	if recorder == nil {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.dependencyActions = append(recorder.dependencyActions, DependencyAction{Release: release, Action: action})
+}
+
+// event returns the event of the reconcile, false if no milvus is observed, e.g. it's not found
+func (r *reconcileEventRecorder) event(reconcileErr error) (ReconcileEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.milvus == nil {
+		return ReconcileEvent{}, false
+	}
+	now := reconcileNow()
+	event := ReconcileEvent{
+		Time:              now,
+		Instance:          r.instance.String(),
+		Generation:        r.milvus.Generation,
+		Status:            r.milvus.Status.Status,
+		ConditionsChanged: getChangedConditionTypes(r.oldConditions, r.milvus.Status.Conditions),
+		DependencyActions: append([]DependencyAction{}, r.dependencyActions...),
+		DurationSeconds:   now.Sub(r.start).Seconds(),
+	}
+	if reconcileErr != nil {
+		event.Error = reconcileErr.Error()
+	}
+	return event, true
+}
+
+// emit writes the event of the reconcile to ReconcileEventWriter
+func (r *reconcileEventRecorder) emit(reconcileErr error) {
+	if ReconcileEventWriter == nil {
+		return
+	}
+	event, ok := r.event(reconcileErr)
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	reconcileEventWriterMu.Lock()
+	defer reconcileEventWriterMu.Unlock()
+	_, _ = ReconcileEventWriter.Write(append(data, '\n'))
+}
+
+// getChangedConditionTypes returns the types of the conditions added, removed, or whose status or reason changed
+func getChangedConditionTypes(oldConditions, newConditions []v1beta1.MilvusCondition) []string {
+	ret := []string{}
+	oldByType := make(map[v1beta1.MilvusConditionType]v1beta1.MilvusCondition, len(oldConditions))
+	for _, cond := range oldConditions {
+		oldByType[cond.Type] = cond
+	}
+	for _, cond := range newConditions {
+		old, ok := oldByType[cond.Type]
+		delete(oldByType, cond.Type)
+		if ok && old.Status == cond.Status && old.Reason == cond.Reason {
+			continue
+		}
+		ret = append(ret, string(cond.Type))
+	}
+	// removed ones, in the original order
+	for _, cond := range oldConditions {
+		if _, ok := oldByType[cond.Type]; ok {
+			ret = append(ret, string(cond.Type))
+		}
+	}
+	return ret
+}
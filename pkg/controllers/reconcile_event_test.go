@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+	"github.com/zilliztech/milvus-operator/pkg/config"
+	"github.com/zilliztech/milvus-operator/pkg/util"
+)
+
+func captureReconcileEvents(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	bak := ReconcileEventWriter
+	ReconcileEventWriter = buf
+	t.Cleanup(func() { ReconcileEventWriter = bak })
+	return buf
+}
+
+func parseReconcileEvents(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	ret := []map[string]interface{}{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		event := map[string]interface{}{}
+		assert.NoError(t, decoder.Decode(&event))
+		ret = append(ret, event)
+	}
+	return ret
+}
+
+func TestReconcileEventRecorder_Emit(t *testing.T) {
+	buf := captureReconcileEvents(t)
+	bak := reconcileNow
+	defer func() { reconcileNow = bak }()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reconcileNow = func() time.Time { return now }
+
+	ctx, recorder := withReconcileEventRecorder(context.Background(), NamespacedName("ns", "mc"))
+
+	t.Run("not observed, no event", func(t *testing.T) {
+		recorder.emit(nil)
+		assert.Empty(t, parseReconcileEvents(t, buf))
+	})
+
+	mc := &v1beta1.Milvus{}
+	mc.Generation = 2
+	mc.Status.Conditions = []v1beta1.MilvusCondition{
+		{Type: v1beta1.MilvusReady, Status: corev1.ConditionFalse, Reason: v1beta1.ReasonMilvusComponentNotHealthy},
+		{Type: v1beta1.MilvusUpdated, Status: corev1.ConditionTrue, Reason: v1beta1.ReasonMilvusComponentsUpdated},
+		{Type: v1beta1.SlowReconcile, Status: corev1.ConditionTrue},
+	}
+	observeMilvus(ctx, mc)
+	recordDependencyAction(ctx, "mc-etcd", DependencyActionInstall)
+	recordDependencyAction(ctx, "mc-minio", DependencyActionUpgrade)
+	mc.Status.Status = v1beta1.StatusHealthy
+	mc.Status.Conditions = []v1beta1.MilvusCondition{
+		{Type: v1beta1.MilvusReady, Status: corev1.ConditionTrue, Reason: v1beta1.ReasonMilvusHealthy},
+		{Type: v1beta1.MilvusUpdated, Status: corev1.ConditionTrue, Reason: v1beta1.ReasonMilvusComponentsUpdated},
+		{Type: v1beta1.EtcdReady, Status: corev1.ConditionTrue},
+	}
+	reconcileNow = func() time.Time { return now.Add(1500 * time.Millisecond) }
+
+	t.Run("event fields", func(t *testing.T) {
+		recorder.emit(errors.New("mock"))
+		events := parseReconcileEvents(t, buf)
+		assert.Len(t, events, 1)
+		assert.Equal(t, map[string]interface{}{
+			"time":       now.Add(1500 * time.Millisecond).Format(time.RFC3339Nano),
+			"instance":   "ns/mc",
+			"generation": float64(2),
+			"status":     "Healthy",
+			"conditionsChanged": []interface{}{
+				string(v1beta1.MilvusReady), string(v1beta1.EtcdReady), string(v1beta1.SlowReconcile),
+			},
+			"dependencyActions": []interface{}{
+				map[string]interface{}{"release": "mc-etcd", "action": "install"},
+				map[string]interface{}{"release": "mc-minio", "action": "upgrade"},
+			},
+			"durationSeconds": 1.5,
+			"error":           "mock",
+		}, events[0])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ReconcileEventWriter = nil
+		recorder.emit(nil)
+		assert.Empty(t, parseReconcileEvents(t, buf))
+	})
+}
+
+func TestMilvusReconciler_Reconcile_EmitEvent(t *testing.T) {
+	buf := captureReconcileEvents(t)
+	config.Init(util.GetGitRepoRootDir())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockSyncer := NewMockMilvusStatusSyncerInterface(ctrl)
+	r.statusSyncer = mockSyncer
+	mockSyncer.EXPECT().RunIfNot().AnyTimes()
+	globalCommonInfo.once.Do(func() {})
+	mockClient := r.Client.(*MockK8sClient)
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "mc",
+			Generation: 3,
+		},
+	}
+	m.Status.Status = v1beta1.StatusPending
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx, key, obj interface{}, opt ...any) {
+			*obj.(*v1beta1.Milvus) = m
+		}).
+		Return(nil)
+	mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: NamespacedName("ns", "mc")})
+	assert.Error(t, err)
+
+	events := parseReconcileEvents(t, buf)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "ns/mc", events[0]["instance"])
+	assert.Equal(t, float64(3), events[0]["generation"])
+	assert.Equal(t, "Pending", events[0]["status"])
+	assert.Equal(t, []interface{}{}, events[0]["conditionsChanged"])
+	assert.Equal(t, []interface{}{}, events[0]["dependencyActions"])
+	assert.Equal(t, "mock", events[0]["error"])
+}
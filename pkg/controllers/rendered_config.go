@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+const (
+	// RenderedConfigYaml is the key of the rendered config in the rendered config configmap
+	RenderedConfigYaml = "milvus.yaml"
+	// RedactedValue replaces the values of the secret-like keys in the rendered config
+	RedactedValue = "******"
+)
+
+// secretLikeKeyWords are the key words of the config keys whose values should be redacted, compared in lower case
+var secretLikeKeyWords = []string{
+	"password",
+	"secret",
+	"accesskey",
+	"token",
+	"privatekey",
+	"credential",
+	// pulsar.authParams
+	"authparams",
+	// etcd.ssl.tlsKey, kafka.ssl.tlsKey, tls.serverKeyPath etc.
+	"tlskey",
+	"serverkey",
+}
+
+// GetRenderedConfigMapName returns the name of the configmap containing the rendered config
+func GetRenderedConfigMapName(instance string) string {
+	return instance + "-rendered-config"
+}
+
+// newRenderedConfigMapLabels differs from the app labels, so that it's not treated as a milvus configmap
+func newRenderedConfigMapLabels(instance string) map[string]string {
+	return map[string]string{
+		AppLabelInstance:  instance,
+		AppLabelName:      "milvus-rendered-config",
+		AppLabelManagedBy: ManagerName,
+	}
+}
+
+func isSecretLikeKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, keyWord := range secretLikeKeyWords {
+		if strings.Contains(key, keyWord) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecretValues returns a copy of the config with the non-empty values of secret-like keys redacted
+func redactSecretValues(conf map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(conf))
+	for k, v := range conf {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			ret[k] = redactSecretValues(value)
+		case nil:
+			ret[k] = nil
+		case string:
+			if value != "" && isSecretLikeKey(k) {
+				ret[k] = RedactedValue
+				continue
+			}
+			ret[k] = value
+		default:
+			if isSecretLikeKey(k) {
+				ret[k] = RedactedValue
+				continue
+			}
+			ret[k] = value
+		}
+	}
+	return ret
+}
+
+func (r *MilvusReconciler) updateRenderedConfigMap(ctx context.Context, mc v1beta1.Milvus, configmap *corev1.ConfigMap) error {
+	conf, err := r.renderMilvusConf(ctx, mc)
+	if err != nil {
+		return err
+	}
+	renderedYaml, err := yaml.Marshal(redactSecretValues(conf))
+	if err != nil {
+		return errors.Wrap(err, "marshal rendered config")
+	}
+
+	configmap.Labels = MergeLabels(configmap.Labels, newRenderedConfigMapLabels(mc.Name))
+	if err := SetControllerReference(&mc, configmap, r.Scheme); err != nil {
+		return err
+	}
+	configmap.Data = map[string]string{
+		RenderedConfigYaml: string(renderedYaml),
+	}
+	return nil
+}
+
+// ReconcileRenderedConfigMap writes the rendered config to a dedicated configmap if enabled
+func (r *MilvusReconciler) ReconcileRenderedConfigMap(ctx context.Context, mc v1beta1.Milvus) error {
+	namespacedName := NamespacedName(mc.Namespace, GetRenderedConfigMapName(mc.Name))
+	old := &corev1.ConfigMap{}
+	err := r.Get(ctx, namespacedName, old)
+	if !mc.Spec.ExportRenderedConf {
+		// delete the one exported before
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.logger.Info("Delete Configmap", "name", old.Name, "namespace", old.Namespace)
+		return client.IgnoreNotFound(r.Delete(ctx, old))
+	}
+	if kerrors.IsNotFound(err) {
+		new := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := r.updateRenderedConfigMap(ctx, mc, new); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create Configmap", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	} else if err != nil {
+		return err
+	}
+
+	cur := old.DeepCopy()
+	if err := r.updateRenderedConfigMap(ctx, mc, cur); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update Configmap", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+func TestMilvusReconciler_ReconcileRenderedConfigMap(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	ctx := env.ctx
+	mockClient := env.MockClient
+
+	t.Run("disabled", func(t *testing.T) {
+		mc := *env.Inst.DeepCopy()
+		mockClient.EXPECT().
+			Get(gomock.Any(), NamespacedName(mc.Namespace, "mc-rendered-config"), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+		assert.NoError(t, r.ReconcileRenderedConfigMap(ctx, mc))
+	})
+
+	t.Run("disabled, exported one deleted", func(t *testing.T) {
+		mc := *env.Inst.DeepCopy()
+		mockClient.EXPECT().
+			Get(gomock.Any(), NamespacedName(mc.Namespace, "mc-rendered-config"), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+			DoAndReturn(func(_, key interface{}, obj *corev1.ConfigMap, _ ...interface{}) error {
+				obj.Namespace = mc.Namespace
+				obj.Name = "mc-rendered-config"
+				return nil
+			})
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+			DoAndReturn(func(_, obj interface{}, _ ...interface{}) error {
+				assert.Equal(t, "mc-rendered-config", obj.(*corev1.ConfigMap).Name)
+				return nil
+			})
+		assert.NoError(t, r.ReconcileRenderedConfigMap(ctx, mc))
+	})
+
+	t.Run("create with secrets redacted", func(t *testing.T) {
+		mc := *env.Inst.DeepCopy()
+		mc.Spec.ExportRenderedConf = true
+		mc.Spec.Dep.Storage.SecretRef = "minio-secret"
+		mc.Spec.Conf.Data = map[string]interface{}{
+			"common": map[string]interface{}{
+				"security": map[string]interface{}{
+					"authorizationEnabled": true,
+					"defaultRootPassword":  "Milvus",
+				},
+			},
+			"proxy": map[string]interface{}{
+				"maxNameLength": 255,
+			},
+		}
+		mockClient.EXPECT().
+			Get(gomock.Any(), NamespacedName(mc.Namespace, "mc-rendered-config"), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+		mockClient.EXPECT().
+			Get(gomock.Any(), NamespacedName(mc.Namespace, "minio-secret"), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(_, _ interface{}, obj *corev1.Secret, _ ...interface{}) error {
+				obj.Data = map[string][]byte{
+					AccessKey: []byte("ak"),
+					SecretKey: []byte("sk"),
+				}
+				return nil
+			})
+		var created *corev1.ConfigMap
+		mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, obj interface{}, _ ...interface{}) error {
+				created = obj.(*corev1.ConfigMap)
+				return nil
+			})
+		assert.NoError(t, r.ReconcileRenderedConfigMap(ctx, mc))
+
+		assert.Equal(t, "mc-rendered-config", created.Name)
+		assert.Equal(t, newRenderedConfigMapLabels(mc.Name), created.Labels)
+		assert.NotEqual(t, NewAppLabels(mc.Name)[AppLabelName], created.Labels[AppLabelName])
+		conf := map[string]interface{}{}
+		assert.NoError(t, yaml.Unmarshal([]byte(created.Data[RenderedConfigYaml]), &conf))
+
+		minio := conf["minio"].(map[string]interface{})
+		assert.Equal(t, RedactedValue, minio["accessKeyID"])
+		assert.Equal(t, RedactedValue, minio["secretAccessKey"])
+		assert.Equal(t, "mc-minio.ns", minio["address"])
+		security := conf["common"].(map[string]interface{})["security"].(map[string]interface{})
+		assert.Equal(t, RedactedValue, security["defaultRootPassword"])
+		assert.Equal(t, true, security["authorizationEnabled"])
+		assert.Equal(t, 255.0, conf["proxy"].(map[string]interface{})["maxNameLength"])
+		assert.Contains(t, conf, "etcd")
+		assert.Contains(t, conf, "mq")
+		// the user config not mutated
+		assert.Equal(t, "Milvus", mc.Spec.Conf.Data["common"].(map[string]interface{})["security"].(map[string]interface{})["defaultRootPassword"])
+	})
+}
+
+func TestRedactSecretValues(t *testing.T) {
+	conf := map[string]interface{}{
+		"tls": map[string]interface{}{
+			"privateKeyPath": "/certs/key.pem",
+		},
+		"minio": map[string]interface{}{
+			"accessKeyID":     "",
+			"secretAccessKey": "sk",
+		},
+		"token": nil,
+		"pulsar": map[string]interface{}{
+			"authParams": "token:xxx",
+		},
+		"etcd": map[string]interface{}{
+			"ssl": map[string]interface{}{
+				"tlsKey":         "/certs/etcd.key",
+				"tlsKeyPassword": "pwd",
+			},
+		},
+	}
+	redacted := redactSecretValues(conf)
+	assert.Equal(t, RedactedValue, redacted["tls"].(map[string]interface{})["privateKeyPath"])
+	// empty values are kept to show they're not set
+	assert.Equal(t, "", redacted["minio"].(map[string]interface{})["accessKeyID"])
+	assert.Equal(t, RedactedValue, redacted["minio"].(map[string]interface{})["secretAccessKey"])
+	assert.Nil(t, redacted["token"])
+	assert.Equal(t, RedactedValue, redacted["pulsar"].(map[string]interface{})["authParams"])
+	etcdSSL := redacted["etcd"].(map[string]interface{})["ssl"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, etcdSSL["tlsKey"])
+	assert.Equal(t, RedactedValue, etcdSSL["tlsKeyPassword"])
+	assert.Equal(t, "sk", conf["minio"].(map[string]interface{})["secretAccessKey"])
+}
+
+func TestMilvusReconciler_renderMilvusConf_cached(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	mc := *env.Inst.DeepCopy()
+	mc.Spec.Dep.Storage.SecretRef = "minio-secret"
+
+	// the secret is read once in the same reconcile
+	env.MockClient.EXPECT().
+		Get(gomock.Any(), NamespacedName(mc.Namespace, "minio-secret"), gomock.AssignableToTypeOf(&corev1.Secret{})).
+		Return(nil).Times(2)
+
+	ctx := withRenderedConfCache(env.ctx)
+	conf, err := r.renderMilvusConf(ctx, mc)
+	assert.NoError(t, err)
+	conf["minio"].(map[string]interface{})["address"] = "mutated"
+	again, err := r.renderMilvusConf(ctx, mc)
+	assert.NoError(t, err)
+	assert.Equal(t, "mc-minio.ns", again["minio"].(map[string]interface{})["address"])
+
+	// rendered again in another reconcile
+	_, err = r.renderMilvusConf(withRenderedConfCache(env.ctx), mc)
+	assert.NoError(t, err)
+}
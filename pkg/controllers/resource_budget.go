@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// resourceBudgetWeights are the shares of a pod of the components in the resource budget,
+// the nodes do the heavy work so they get more. components not listed have weight 1
+var resourceBudgetWeights = map[string]int64{
+	QueryNodeName:     4,
+	DataNodeName:      2,
+	IndexNodeName:     2,
+	StreamingNodeName: 2,
+}
+
+func getResourceBudgetWeight(component MilvusComponent) int64 {
+	if weight, ok := resourceBudgetWeights[component.Name]; ok {
+		return weight
+	}
+	return 1
+}
+
+// getResourceSetByUser returns the resource of the container set in the spec, prefers the limit to the request
+func getResourceSetByUser(resources *corev1.ResourceRequirements, name corev1.ResourceName) (resource.Quantity, bool) {
+	if resources == nil {
+		return resource.Quantity{}, false
+	}
+	if quantity, ok := resources.Limits[name]; ok {
+		return quantity, true
+	}
+	quantity, ok := resources.Requests[name]
+	return quantity, ok
+}
+
+// getResourceBudgetShare returns the resource of a pod of the component divided from the budget.
+// the budget is for one pod of each component, the resources set by users are deducted from it first,
+// the rest is divided across the components without the resource set by their weights.
+// the replicas are not counted, so that scaling a component, manually or by HPA, doesn't restart the others.
+// it returns false if the component has no share
+func getResourceBudgetShare(spec v1beta1.MilvusSpec, component MilvusComponent, name corev1.ResourceName, budget resource.Quantity) (resource.Quantity, bool) {
+	var left = budget.MilliValue()
+	var totalWeight int64
+	for _, c := range GetComponentsBySpec(spec) {
+		if c.IsStandalone() && spec.Mode == v1beta1.MilvusModeCluster {
+			// only kept for the mode change, it has no pods in cluster mode
+			continue
+		}
+		resources := MergeComponentSpec(c.GetComponentSpec(spec), spec.Com.ComponentSpec).Resources
+		if quantity, ok := getResourceSetByUser(resources, name); ok {
+			left -= quantity.MilliValue()
+			continue
+		}
+		totalWeight += getResourceBudgetWeight(c)
+	}
+	if left <= 0 || totalWeight == 0 {
+		return resource.Quantity{}, false
+	}
+	share := left * getResourceBudgetWeight(component) / totalWeight
+	if name == corev1.ResourceMemory {
+		// round down to Mi for readability
+		const mi = 1024 * 1024
+		return *resource.NewQuantity(share/1000/mi*mi, resource.BinarySI), true
+	}
+	return *resource.NewMilliQuantity(share, resource.DecimalSI), true
+}
+
+// applyResourceBudget sets the requests & limits of the CPU & memory not set in the resources by the budget
+func applyResourceBudget(spec v1beta1.MilvusSpec, component MilvusComponent, resources *corev1.ResourceRequirements) *corev1.ResourceRequirements {
+	budget := spec.Com.ResourceBudget
+	if budget == nil {
+		return resources
+	}
+	ret := &corev1.ResourceRequirements{}
+	if resources != nil {
+		ret = resources.DeepCopy()
+	}
+	budgets := map[corev1.ResourceName]*resource.Quantity{
+		corev1.ResourceCPU:    budget.CPU,
+		corev1.ResourceMemory: budget.Memory,
+	}
+	for name, quantity := range budgets {
+		if quantity == nil {
+			continue
+		}
+		if _, ok := getResourceSetByUser(ret, name); ok {
+			continue
+		}
+		share, ok := getResourceBudgetShare(spec, component, name, *quantity)
+		if !ok {
+			continue
+		}
+		if ret.Requests == nil {
+			ret.Requests = corev1.ResourceList{}
+		}
+		if ret.Limits == nil {
+			ret.Limits = corev1.ResourceList{}
+		}
+		ret.Requests[name] = share
+		ret.Limits[name] = share
+	}
+	return ret
+}
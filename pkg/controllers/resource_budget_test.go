@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestApplyResourceBudget(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Com.QueryNode.Replicas = int32Ptr(2)
+
+	t.Run("no budget", func(t *testing.T) {
+		assert.Nil(t, applyResourceBudget(mc.Spec, Proxy, nil))
+	})
+
+	cpu := resource.MustParse("10")
+	memory := resource.MustParse("10Gi")
+	mc.Spec.Com.ResourceBudget = &v1beta1.MilvusResourceBudget{CPU: &cpu, Memory: &memory}
+
+	t.Run("split by weights", func(t *testing.T) {
+		// mixcoord 1 + datanode 2 + querynode 4 + indexnode 2 + proxy 1 = 10
+		expects := map[MilvusComponent][2]string{
+			MixCoord:  {"1", "1Gi"},
+			Proxy:     {"1", "1Gi"},
+			DataNode:  {"2", "2Gi"},
+			IndexNode: {"2", "2Gi"},
+			QueryNode: {"4", "4Gi"},
+		}
+		for component, expect := range expects {
+			resources := applyResourceBudget(mc.Spec, component, nil)
+			assert.Equal(t, expect[0], resources.Limits.Cpu().String(), component.Name)
+			assert.Equal(t, expect[1], resources.Limits.Memory().String(), component.Name)
+			assert.Equal(t, resources.Limits, resources.Requests, component.Name)
+		}
+	})
+
+	t.Run("resources set by user deducted", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Com.DataNode.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		}
+		// the datanode's memory is still set by the budget
+		resources := applyResourceBudget(mc.Spec, DataNode, mc.Spec.Com.DataNode.Resources)
+		assert.Equal(t, "2", resources.Limits.Cpu().String())
+		assert.Equal(t, "2Gi", resources.Limits.Memory().String())
+		assert.Empty(t, mc.Spec.Com.DataNode.Resources.Requests)
+
+		// (10 - 2) cpu divided by the weights 8
+		resources = applyResourceBudget(mc.Spec, QueryNode, nil)
+		assert.Equal(t, "4", resources.Limits.Cpu().String())
+	})
+
+	t.Run("not changed by replicas", func(t *testing.T) {
+		scaled := *mc.DeepCopy()
+		scaled.Spec.Com.QueryNode.Replicas = int32Ptr(5)
+		// scaled by HPA
+		scaled.Spec.Com.DataNode.Replicas = int32Ptr(-1)
+		scaled.Spec.Com.Proxy.Replicas = int32Ptr(0)
+		var totalCPU int64
+		for _, component := range []MilvusComponent{MixCoord, Proxy, DataNode, IndexNode, QueryNode} {
+			resources := applyResourceBudget(scaled.Spec, component, nil)
+			assert.Equal(t, applyResourceBudget(mc.Spec, component, nil), resources, component.Name)
+			totalCPU += resources.Limits.Cpu().MilliValue()
+		}
+		assert.Equal(t, cpu.MilliValue(), totalCPU)
+	})
+
+	t.Run("global resources set", func(t *testing.T) {
+		mc := *mc.DeepCopy()
+		mc.Spec.Com.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}
+		// the cpu of all the components is set by the global resources
+		resources := applyResourceBudget(mc.Spec, QueryNode, nil)
+		_, ok := resources.Limits[corev1.ResourceCPU]
+		assert.False(t, ok)
+		assert.Equal(t, "4Gi", resources.Limits.Memory().String())
+	})
+
+	t.Run("applied to merged component spec", func(t *testing.T) {
+		updater := newMilvusDeploymentUpdater(mc, nil, QueryNode)
+		assert.Equal(t, "4", updater.GetMergedComponentSpec().Resources.Limits.Cpu().String())
+	})
+}
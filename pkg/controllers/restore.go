@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func getRestoreJobName(instance string) string {
+	return instance + "-restore"
+}
+
+// initRestoreCondition marks milvus to be restored, it's called when milvus is created
+func initRestoreCondition(mc *v1beta1.Milvus) {
+	if mc.Spec.RestoreFrom == nil {
+		return
+	}
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.RestoreCompleted,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1beta1.ReasonRestoring,
+		Message: fmt.Sprintf("Restoring from %s", getRestoreSource(*mc.Spec.RestoreFrom)),
+	})
+}
+
+// shouldRestore returns true if milvus is created with spec.restoreFrom and the restore is not completed yet
+func shouldRestore(mc v1beta1.Milvus) bool {
+	if mc.Spec.RestoreFrom == nil {
+		return false
+	}
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RestoreCompleted)
+	return cond != nil && cond.Status == corev1.ConditionFalse
+}
+
+// ReconcileRestore creates the restore job once milvus is ready, the milvus-backup tool restores through the milvus
+func (r *MilvusReconciler) ReconcileRestore(ctx context.Context, mc v1beta1.Milvus) error {
+	if !shouldRestore(mc) || !IsMilvusConditionTrueByType(mc.Status.Conditions, v1beta1.MilvusReady) {
+		return nil
+	}
+	job := &batchv1.Job{}
+	err := r.Get(ctx, NamespacedName(mc.Namespace, getRestoreJobName(mc.Name)), job)
+	if err == nil {
+		return nil
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return errors.Wrap(err, "get restore job")
+	}
+	job = renderRestoreJob(mc)
+	if err := SetControllerReference(&mc, job, r.Scheme); err != nil {
+		return errors.Wrap(err, "set restore job owner")
+	}
+	ctrl.LoggerFrom(ctx).Info("create restore job", "job", job.Name)
+	return errors.Wrap(r.Create(ctx, job), "create restore job")
+}
+
+// syncRestoreCondition marks the restore completed when the restore job succeeds, or failed with the message of the job
+func syncRestoreCondition(ctx context.Context, cli client.Client, mc *v1beta1.Milvus) error {
+	if !shouldRestore(*mc) {
+		return nil
+	}
+	job := &batchv1.Job{}
+	err := cli.Get(ctx, NamespacedName(mc.Namespace, getRestoreJobName(mc.Name)), job)
+	if k8sErrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "get restore job")
+	}
+	source := getRestoreSource(*mc.Spec.RestoreFrom)
+	if job.Status.Succeeded > 0 {
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+			Type:    v1beta1.RestoreCompleted,
+			Status:  corev1.ConditionTrue,
+			Reason:  v1beta1.ReasonRestoreCompleted,
+			Message: fmt.Sprintf("Restored from %s", source),
+		})
+		return nil
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+				Type:    v1beta1.RestoreCompleted,
+				Status:  corev1.ConditionFalse,
+				Reason:  v1beta1.ReasonRestoreFailed,
+				Message: fmt.Sprintf("Restore job[%s] from %s failed: %s; delete the job to retry", job.Name, source, cond.Message),
+			})
+			return nil
+		}
+	}
+	return nil
+}
+
+func getRestoreSource(restoreFrom v1beta1.MilvusRestoreSource) string {
+	return path.Join(restoreFrom.Bucket, restoreFrom.Path, restoreFrom.BackupName)
+}
+
+// renderRestoreJob renders the job to restore the backup into the milvus by the milvus-backup tool
+func renderRestoreJob(mc v1beta1.Milvus) *batchv1.Job {
+	restoreFrom := mc.Spec.RestoreFrom
+	return renderMilvusBackupJob(mc, milvusBackupJobConfig{
+		name:   getRestoreJobName(mc.Name),
+		image:  restoreFrom.Image,
+		bucket: restoreFrom.Bucket,
+		path:   restoreFrom.Path,
+		args:   []string{"restore", "-n", restoreFrom.BackupName},
+	})
+}
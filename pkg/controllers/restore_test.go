@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestRestoreFrom(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	mockStatusCli := NewMockK8sStatusClient(env.Ctrl)
+	ctx := env.ctx
+
+	testScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(testScheme)
+	v1beta1.AddToScheme(testScheme)
+	r.Scheme = testScheme
+
+	newRestoringMilvus := func(t *testing.T) v1beta1.Milvus {
+		mc := *env.Inst.DeepCopy()
+		mc.Status = v1beta1.MilvusStatus{}
+		mc.Spec.Dep.Storage.Endpoint = "minio:9000"
+		mc.Spec.RestoreFrom = &v1beta1.MilvusRestoreSource{
+			BackupName: "backup_20240101",
+			Bucket:     "backup-bucket",
+		}
+		env.MockClient.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any())
+		assert.NoError(t, r.SetDefaultStatus(ctx, &mc))
+		return mc
+	}
+	jobKey := client.ObjectKey{Namespace: env.Inst.Namespace, Name: getRestoreJobName(env.Inst.Name)}
+
+	t.Run("restoring on creation", func(t *testing.T) {
+		mc := newRestoringMilvus(t)
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RestoreCompleted)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, corev1.ConditionFalse, cond.Status)
+			assert.Equal(t, v1beta1.ReasonRestoring, cond.Reason)
+		}
+		assert.True(t, shouldRestore(mc))
+	})
+
+	t.Run("restore job created after milvus ready", func(t *testing.T) {
+		mc := newRestoringMilvus(t)
+		cli := fake.NewClientBuilder().WithScheme(testScheme).WithStatusSubresource(&batchv1.Job{}).Build()
+		r.Client = cli
+		defer func() { r.Client = env.MockClient }()
+
+		// not before milvus ready
+		assert.NoError(t, r.ReconcileRestore(ctx, mc))
+		job := &batchv1.Job{}
+		assert.Error(t, cli.Get(ctx, jobKey, job))
+		assert.NoError(t, syncRestoreCondition(ctx, cli, &mc))
+		assert.True(t, shouldRestore(mc))
+
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{Type: v1beta1.MilvusReady, Status: corev1.ConditionTrue})
+		assert.NoError(t, r.ReconcileRestore(ctx, mc))
+		assert.NoError(t, cli.Get(ctx, jobKey, job))
+		container := job.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, defaultBackupImage, container.Image)
+		assert.Equal(t, []string{"restore", "-n", "backup_20240101"}, container.Args)
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "MINIO_BACKUP_BUCKET_NAME", Value: "backup-bucket"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "MINIO_BUCKET_NAME", Value: "milvus-bucket"})
+
+		// running
+		assert.NoError(t, r.ReconcileRestore(ctx, mc))
+		assert.NoError(t, syncRestoreCondition(ctx, cli, &mc))
+		assert.True(t, shouldRestore(mc))
+
+		// failed
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "mock"}}
+		assert.NoError(t, cli.Status().Update(ctx, job))
+		assert.NoError(t, syncRestoreCondition(ctx, cli, &mc))
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RestoreCompleted)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonRestoreFailed, cond.Reason)
+		assert.True(t, shouldRestore(mc))
+
+		// succeeded
+		job.Status.Conditions = nil
+		job.Status.Succeeded = 1
+		assert.NoError(t, cli.Status().Update(ctx, job))
+		assert.NoError(t, syncRestoreCondition(ctx, cli, &mc))
+		assert.True(t, IsMilvusConditionTrueByType(mc.Status.Conditions, v1beta1.RestoreCompleted))
+		assert.False(t, shouldRestore(mc))
+	})
+
+	t.Run("not restore when set after creation", func(t *testing.T) {
+		mc := *env.Inst.DeepCopy()
+		mc.Status.Status = v1beta1.StatusHealthy
+		mc.Status.CurrentImage = mc.Spec.Com.Image
+		mc.Spec.RestoreFrom = &v1beta1.MilvusRestoreSource{BackupName: "backup"}
+		assert.NoError(t, r.SetDefaultStatus(ctx, &mc))
+		assert.False(t, shouldRestore(mc))
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{Type: v1beta1.MilvusReady, Status: corev1.ConditionTrue})
+		assert.NoError(t, r.ReconcileRestore(ctx, mc))
+	})
+}
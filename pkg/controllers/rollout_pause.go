@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// isRolloutPaused returns true if the components' images are held at the current step of the rolling update
+// by the PauseRolloutAnnotation
+func isRolloutPaused(mc v1beta1.Milvus) bool {
+	return mc.GetAnnotations()[PauseRolloutAnnotation] == "true"
+}
+
+// holdDeploymentRollout pauses the existing deployment of one deployment mode when the rollout is paused,
+// so that no template changes are rolled out. In two deployment mode, the rollout is held by the deploy controller
+func holdDeploymentRollout(mc v1beta1.Milvus, deployment *appsv1.Deployment) {
+	if isRolloutPaused(mc) {
+		deployment.Spec.Paused = true
+	}
+}
+
+// updateRolloutPausedCondition sets the RolloutPaused condition true with the components not updated when paused,
+// and sets it false when resumed, so that the resumed time is kept
+func updateRolloutPausedCondition(mc *v1beta1.Milvus) {
+	if isRolloutPaused(*mc) {
+		var pending []string
+		for _, component := range GetComponentsBySpec(mc.Spec) {
+			if !component.IsImageUpdated(mc) {
+				pending = append(pending, component.Name)
+			}
+		}
+		msg := "rollout paused by annotation " + PauseRolloutAnnotation
+		if len(pending) > 0 {
+			msg += fmt.Sprintf(", components[%s] not updated", strings.Join(pending, ","))
+		}
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+			Type:    v1beta1.RolloutPaused,
+			Status:  corev1.ConditionTrue,
+			Reason:  v1beta1.ReasonRolloutPaused,
+			Message: msg,
+		})
+		return
+	}
+	if !IsMilvusConditionTrueByType(mc.Status.Conditions, v1beta1.RolloutPaused) {
+		return
+	}
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.RolloutPaused,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1beta1.ReasonRolloutResumed,
+		Message: "rollout resumed",
+	})
+}
+
+// getRolloutResumedTime returns the last time the rollout resumed, zero if it's never paused
+func getRolloutResumedTime(mc v1beta1.Milvus) time.Time {
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RolloutPaused)
+	if cond == nil || cond.Status != corev1.ConditionFalse || cond.LastTransitionTime == nil {
+		return time.Time{}
+	}
+	return cond.LastTransitionTime.Time
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestUpdateDeployment_RolloutPaused(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	inst := env.Inst.DeepCopy()
+	inst.Spec.Mode = v1beta1.MilvusModeCluster
+	inst.Default()
+	inst.Spec.Com.Image = "milvusdb/milvus:v2"
+	inst.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeAll
+	inst.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+
+	getImage := func(deployment *appsv1.Deployment, component MilvusComponent) string {
+		idx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, component.Name)
+		return deployment.Spec.Template.Spec.Containers[idx].Image
+	}
+	deployment := &appsv1.Deployment{}
+	deployment.Name = "deploy"
+	deployment.Namespace = "ns"
+	deployment.Spec.Replicas = int32Ptr(1)
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{
+		{Name: QueryNodeName, Image: "milvusdb/milvus:v1"},
+	}
+
+	// paused, image not advanced
+	err := updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+	assert.NoError(t, err)
+	assert.Equal(t, "milvusdb/milvus:v1", getImage(deployment, QueryNode))
+
+	// new deployment still created with the spec image
+	newDeployment := &appsv1.Deployment{}
+	newDeployment.Namespace = "ns"
+	err = updateDeployment(newDeployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+	assert.NoError(t, err)
+	assert.Equal(t, "milvusdb/milvus:v2", getImage(newDeployment, QueryNode))
+
+	// resumed
+	delete(inst.Annotations, PauseRolloutAnnotation)
+	err = updateDeployment(deployment, newMilvusDeploymentUpdater(*inst, env.Reconciler.Scheme, QueryNode))
+	assert.NoError(t, err)
+	assert.Equal(t, "milvusdb/milvus:v2", getImage(deployment, QueryNode))
+}
+
+func TestUpdateRolloutPausedCondition(t *testing.T) {
+	mc := &v1beta1.Milvus{}
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Com.Image = "milvusdb/milvus:v2"
+	mc.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+		MixCoordName: {
+			Image: "milvusdb/milvus:v2",
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: v1beta1.NewReplicaSetAvailableReason},
+				},
+			},
+		},
+	}
+
+	// never paused
+	updateRolloutPausedCondition(mc)
+	assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RolloutPaused))
+
+	mc.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+	updateRolloutPausedCondition(mc)
+	cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RolloutPaused)
+	assert.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, v1beta1.ReasonRolloutPaused, cond.Reason)
+	// the coordinator is updated, the nodes are held
+	assert.NotContains(t, cond.Message, MixCoordName)
+	assert.Contains(t, cond.Message, QueryNodeName)
+	assert.True(t, getRolloutResumedTime(*mc).IsZero())
+
+	mc.Annotations = nil
+	updateRolloutPausedCondition(mc)
+	cond = GetMilvusConditionByType(mc.Status.Conditions, v1beta1.RolloutPaused)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	assert.Equal(t, v1beta1.ReasonRolloutResumed, cond.Reason)
+	assert.Equal(t, cond.LastTransitionTime.Time, getRolloutResumedTime(*mc))
+}
+
+func TestHoldDeploymentRollout(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	deployment := &appsv1.Deployment{}
+	holdDeploymentRollout(mc, deployment)
+	assert.False(t, deployment.Spec.Paused)
+
+	mc.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+	holdDeploymentRollout(mc, deployment)
+	assert.True(t, deployment.Spec.Paused)
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// scheduleNow is a variable for the convenience of testing
+var scheduleNow = time.Now
+
+// scheduleLookbacks are the windows to search for the latest activation of a cron schedule
+var scheduleLookbacks = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	32 * 24 * time.Hour,
+}
+
+// lastActivation returns the latest activation time of the schedule no later than now
+// returns zero time if it's not activated within the max lookback window
+func lastActivation(schedule cron.Schedule, now time.Time) time.Time {
+	for _, lookback := range scheduleLookbacks {
+		var last time.Time
+		for t := schedule.Next(now.Add(-lookback)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+			last = t
+		}
+		if !last.IsZero() {
+			return last
+		}
+	}
+	return time.Time{}
+}
+
+// shouldStopBySchedule returns whether the milvus should be stopped at the given time
+func shouldStopBySchedule(schedule v1beta1.MilvusSchedule, now time.Time) (bool, error) {
+	stopped, err := isWithinCronWindow(schedule.Stop, schedule.Start, schedule.TimeZone, now)
+	return stopped, errors.Wrap(err, "check schedule")
+}
+
+// isUpdateWindowOpen returns whether the image updates are allowed at the given time,
+// it's always open if no window set or the window is invalid
+func isUpdateWindowOpen(window *v1beta1.UpdateWindow, now time.Time) bool {
+	if window == nil {
+		return true
+	}
+	open, err := isWithinCronWindow(window.Start, window.End, window.TimeZone, now)
+	if err != nil {
+		return true
+	}
+	return open
+}
+
+// isWithinCronWindow returns whether the latest activation of the begin schedule is after the end schedule's
+func isWithinCronWindow(beginExpr, endExpr, timeZone string, now time.Time) (bool, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return false, errors.Wrap(err, "load timezone")
+	}
+	begin, err := cron.ParseStandard(beginExpr)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse schedule %s", beginExpr)
+	}
+	end, err := cron.ParseStandard(endExpr)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse schedule %s", endExpr)
+	}
+	now = now.In(location)
+	return lastActivation(begin, now).After(lastActivation(end, now)), nil
+}
+
+// syncSchedules stops & starts the milvus instances according to their schedules
+func (r *MilvusStatusSyncer) syncSchedules() error {
+	milvusList := &v1beta1.MilvusList{}
+	err := r.List(r.ctx, milvusList)
+	if err != nil {
+		return errors.Wrap(err, "list milvus failed")
+	}
+	var ret error
+	for i := range milvusList.Items {
+		mc := &milvusList.Items[i]
+		if mc.Spec.Schedule == nil || mc.DeletionTimestamp != nil {
+			continue
+		}
+		err = r.applySchedule(r.ctx, mc, scheduleNow())
+		if err != nil {
+			r.logger.Error(err, "apply schedule failed", "namespace", mc.Namespace, "name", mc.Name)
+			ret = err
+		}
+	}
+	return errors.Wrap(ret, "apply schedule failed")
+}
+
+// applySchedule sets the ScheduledStop condition by whether it's within the stop window.
+// the components are scaled to 0 by the reconcile while the condition is true, the replicas in spec are untouched,
+// so an instance stopped manually stays stopped when the window ends
+func (r *MilvusStatusSyncer) applySchedule(ctx context.Context, mc *v1beta1.Milvus, now time.Time) error {
+	shouldStop, err := shouldStopBySchedule(*mc.Spec.Schedule, now)
+	if err != nil {
+		return err
+	}
+	if shouldStop == mc.IsStoppedBySchedule() {
+		return nil
+	}
+	cond := v1beta1.MilvusCondition{
+		Type:    v1beta1.ScheduledStop,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1beta1.ReasonOutOfStopWindow,
+		Message: "Milvus is started by schedule",
+	}
+	if shouldStop {
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = v1beta1.ReasonInStopWindow
+		cond.Message = "Milvus is stopped by schedule"
+	}
+	UpdateCondition(&mc.Status, cond)
+	r.logger.Info(cond.Message, "namespace", mc.Namespace, "name", mc.Name)
+	return errors.Wrap(r.Status().Update(ctx, mc), "update scheduled stop condition")
+}
+
+// applyScheduledStop returns a copy of milvus whose components' replicas are set to 0 if it's stopped by schedule,
+// so that it's reconciled as if it's stopped in spec
+func applyScheduledStop(mc v1beta1.Milvus) v1beta1.Milvus {
+	if !mc.IsStoppedBySchedule() {
+		return mc
+	}
+	ret := *mc.DeepCopy()
+	for _, component := range GetComponentsBySpec(ret.Spec) {
+		// Ignore errors from SetReplicas()
+		_ = component.SetReplicas(ret.Spec, int32Ptr(0))
+	}
+	return ret
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestShouldStopBySchedule(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	schedule := v1beta1.MilvusSchedule{
+		Stop:     "0 22 * * *",
+		Start:    "0 8 * * *",
+		TimeZone: "Asia/Shanghai",
+	}
+
+	t.Run("in stop window", func(t *testing.T) {
+		for _, now := range []time.Time{
+			time.Date(2024, 1, 1, 23, 0, 0, 0, location),
+			time.Date(2024, 1, 2, 2, 0, 0, 0, location),
+			time.Date(2024, 1, 1, 22, 0, 0, 0, location),
+		} {
+			stop, err := shouldStopBySchedule(schedule, now)
+			assert.NoError(t, err)
+			assert.True(t, stop, now.String())
+		}
+	})
+
+	t.Run("out of stop window", func(t *testing.T) {
+		for _, now := range []time.Time{
+			time.Date(2024, 1, 1, 9, 0, 0, 0, location),
+			time.Date(2024, 1, 1, 21, 59, 0, 0, location),
+			// 12:00 in UTC is 20:00 in Shanghai
+			time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		} {
+			stop, err := shouldStopBySchedule(schedule, now)
+			assert.NoError(t, err)
+			assert.False(t, stop, now.String())
+		}
+	})
+
+	t.Run("weekly schedule", func(t *testing.T) {
+		weekly := v1beta1.MilvusSchedule{
+			Stop:  "0 20 * * 5",
+			Start: "0 8 * * 1",
+		}
+		// 2024-01-06 is Saturday
+		stop, err := shouldStopBySchedule(weekly, time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.True(t, stop)
+		// 2024-01-09 is Tuesday
+		stop, err = shouldStopBySchedule(weekly, time.Date(2024, 1, 9, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.False(t, stop)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := shouldStopBySchedule(v1beta1.MilvusSchedule{Stop: "bad", Start: "0 8 * * *"}, time.Now())
+		assert.Error(t, err)
+		_, err = shouldStopBySchedule(v1beta1.MilvusSchedule{Stop: "0 22 * * *", Start: "0 8 * * *", TimeZone: "bad"}, time.Now())
+		assert.Error(t, err)
+	})
+}
+
+func TestMilvusStatusSyncer_applySchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCli := NewMockK8sClient(ctrl)
+	mockStatusWriter := NewMockK8sStatusClient(ctrl)
+	mockCli.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+	ctx := context.Background()
+	s := NewMilvusStatusSyncer(ctx, mockCli, logf.Log.WithName("test"))
+
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Com.Standalone.Replicas = int32Ptr(2)
+	mc.Spec.Schedule = &v1beta1.MilvusSchedule{
+		Stop:  "0 22 * * *",
+		Start: "0 8 * * *",
+	}
+	inStopWindow := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	outOfStopWindow := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("not stopped out of stop window", func(t *testing.T) {
+		err := s.applySchedule(ctx, mc, outOfStopWindow)
+		assert.NoError(t, err)
+		assert.False(t, mc.IsStopping())
+	})
+
+	t.Run("stopped in stop window without changing spec", func(t *testing.T) {
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		err := s.applySchedule(ctx, mc, inStopWindow)
+		assert.NoError(t, err)
+		assert.True(t, mc.IsStoppedBySchedule())
+		assert.True(t, mc.IsStopping())
+		assert.Equal(t, int32(2), *mc.Spec.Com.Standalone.Replicas)
+
+		// idempotent
+		err = s.applySchedule(ctx, mc, inStopWindow.Add(time.Hour))
+		assert.NoError(t, err)
+	})
+
+	t.Run("started out of stop window", func(t *testing.T) {
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		err := s.applySchedule(ctx, mc, outOfStopWindow)
+		assert.NoError(t, err)
+		assert.False(t, mc.IsStoppedBySchedule())
+		assert.False(t, mc.IsStopping())
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.ScheduledStop)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, v1beta1.ReasonOutOfStopWindow, cond.Reason)
+		}
+	})
+
+	t.Run("manually stopped stays stopped", func(t *testing.T) {
+		stopped := mc.DeepCopy()
+		stopped.Spec.Com.Standalone.Replicas = int32Ptr(0)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		assert.NoError(t, s.applySchedule(ctx, stopped, inStopWindow))
+		assert.NoError(t, s.applySchedule(ctx, stopped, outOfStopWindow))
+		assert.True(t, stopped.IsStopping())
+		assert.Equal(t, int32(0), *stopped.Spec.Com.Standalone.Replicas)
+	})
+}
+
+func TestApplyScheduledStop(t *testing.T) {
+	mc := v1beta1.Milvus{}
+	mc.Spec.Mode = v1beta1.MilvusModeCluster
+	mc.Default()
+	mc.Spec.Com.Proxy.Replicas = int32Ptr(2)
+	mc.Spec.Schedule = &v1beta1.MilvusSchedule{
+		Stop:  "0 22 * * *",
+		Start: "0 8 * * *",
+	}
+
+	ret := applyScheduledStop(mc)
+	assert.False(t, ret.Spec.IsStopping())
+
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:   v1beta1.ScheduledStop,
+		Status: corev1.ConditionTrue,
+		Reason: v1beta1.ReasonInStopWindow,
+	})
+	ret = applyScheduledStop(mc)
+	assert.True(t, ret.Spec.IsStopping())
+	assert.Equal(t, int32(0), ReplicasValue(Proxy.GetDesiredReplicas(ret.Spec)))
+	assert.Equal(t, int32(2), *mc.Spec.Com.Proxy.Replicas)
+
+	mc.Spec.Schedule = nil
+	ret = applyScheduledStop(mc)
+	assert.False(t, ret.Spec.IsStopping())
+}
+
+func TestMilvusStatusSyncer_syncSchedules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCli := NewMockK8sClient(ctrl)
+	mockStatusWriter := NewMockK8sStatusClient(ctrl)
+	ctx := context.Background()
+	s := NewMilvusStatusSyncer(ctx, mockCli, logf.Log.WithName("test"))
+
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	scheduled := env.Inst.DeepCopy()
+	scheduled.Spec.Schedule = &v1beta1.MilvusSchedule{
+		Stop:  "0 22 * * *",
+		Start: "0 8 * * *",
+	}
+	bak := scheduleNow
+	defer func() { scheduleNow = bak }()
+	scheduleNow = func() time.Time {
+		return time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	}
+
+	mockCli.EXPECT().List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, list *v1beta1.MilvusList, opts ...any) error {
+			list.Items = []v1beta1.Milvus{*env.Inst.DeepCopy(), *scheduled}
+			return nil
+		})
+	mockCli.EXPECT().Status().Return(mockStatusWriter)
+	mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, mc *v1beta1.Milvus, opts ...any) error {
+			assert.True(t, mc.IsStoppedBySchedule())
+			assert.Equal(t, int32(1), *mc.Spec.Com.Standalone.Replicas)
+			return nil
+		})
+	err := s.syncSchedules()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+var serviceMonitorGroupKind = schema.GroupKind{
+	Group: monitoringv1.SchemeGroupVersion.Group,
+	Kind:  monitoringv1.ServiceMonitorsKind,
+}
+
+func (r *MilvusReconciler) updateServiceMonitor(
+	mc v1beta1.Milvus, serviceMonitor *monitoringv1.ServiceMonitor) error {
+
+	appLabels := NewAppLabels(mc.Name)
+	serviceMonitor.Labels = MergeLabels(serviceMonitor.Labels, mc.Spec.Com.ServiceMonitor.Labels, appLabels)
+	if err := SetControllerReference(&mc, serviceMonitor, r.Scheme); err != nil {
+		r.logger.Error(err, "ServiceMonitor SetControllerReference error", "name", mc.Name, "namespace", mc.Namespace)
+		return err
+	}
+
+	interval := mc.Spec.Com.ServiceMonitor.Interval
+	if interval == "" {
+		interval = "30s"
+	}
+
+	serviceMonitor.Spec.Endpoints = []monitoringv1.Endpoint{
+		{
+			HonorLabels: true,
+			Interval:    interval,
+			Path:        MetricPath,
+			Port:        MetricPortName,
+		},
+	}
+	serviceMonitor.Spec.NamespaceSelector = monitoringv1.NamespaceSelector{
+		MatchNames: []string{mc.Namespace},
+	}
+	// only the main service, the pods behind the internal & headless services are the same ones
+	serviceMonitor.Spec.Selector.MatchLabels = MergeLabels(appLabels, map[string]string{
+		MainServiceLabel: v1beta1.TrueStr,
+	})
+	serviceMonitor.Spec.TargetLabels = []string{
+		AppLabelInstance, AppLabelName,
+	}
+	return nil
+}
+
+// isServiceMonitorInstalled checks whether the ServiceMonitor CRD is installed by the RESTMapper
+func (r *MilvusReconciler) isServiceMonitorInstalled() (bool, error) {
+	_, err := r.RESTMapper().RESTMapping(serviceMonitorGroupKind, monitoringv1.SchemeGroupVersion.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *MilvusReconciler) ReconcileServiceMonitor(ctx context.Context, mc v1beta1.Milvus) error {
+	namespacedName := NamespacedName(mc.Namespace, mc.Name)
+	if mc.Spec.Com.ServiceMonitor == nil || !mc.Spec.Com.ServiceMonitor.Enabled {
+		unavailableOptionalKinds.Set(namespacedName, monitoringv1.ServiceMonitorsKind, false)
+		installed, err := r.isServiceMonitorInstalled()
+		if err != nil || !installed {
+			return err
+		}
+		return r.deleteIfExists(ctx, &monitoringv1.ServiceMonitor{}, namespacedName)
+	}
+	installed, err := r.isServiceMonitorInstalled()
+	if err != nil {
+		return err
+	}
+	if !installed {
+		unavailableOptionalKinds.Set(namespacedName, monitoringv1.ServiceMonitorsKind, true)
+		r.logger.Info("servicemonitor kind no matchs, maybe is not installed")
+		return nil
+	}
+
+	old := &monitoringv1.ServiceMonitor{}
+	err = r.Get(ctx, namespacedName, old)
+	unavailable := isKindUnavailableError(err)
+	unavailableOptionalKinds.Set(namespacedName, monitoringv1.ServiceMonitorsKind, unavailable)
+	if unavailable {
+		r.logger.Info("servicemonitor kind is not registered", "err", err.Error())
+		return nil
+	}
+	if errors.IsNotFound(err) {
+		new := &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := r.updateServiceMonitor(mc, new); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create ServiceMonitor", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	}
+	if err != nil {
+		return err
+	}
+
+	cur := old.DeepCopy()
+	if err := r.updateServiceMonitor(mc, cur); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update ServiceMonitor", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func newServiceMonitorTestMilvus() v1beta1.Milvus {
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Default()
+	m.Spec.Com.ServiceMonitor = &v1beta1.MilvusServiceMonitor{
+		Enabled:  true,
+		Interval: "15s",
+		Labels:   map[string]string{"release": "prometheus"},
+	}
+	return m
+}
+
+func TestReconciler_ReconcileServiceMonitor_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newServiceMonitorTestMilvus()
+	m.Spec.Com.ServiceMonitor = nil
+
+	t.Run("crd not installed", func(t *testing.T) {
+		mockClient.EXPECT().RESTMapper().Return(meta.NewDefaultRESTMapper(nil))
+		err := r.ReconcileServiceMonitor(ctx, m)
+		assert.NoError(t, err)
+	})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(monitoringv1.SchemeGroupVersion.WithKind(monitoringv1.ServiceMonitorsKind), meta.RESTScopeNamespace)
+	t.Run("not exist", func(t *testing.T) {
+		mockClient.EXPECT().RESTMapper().Return(mapper)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&monitoringv1.ServiceMonitor{})).
+			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+		err := r.ReconcileServiceMonitor(ctx, m)
+		assert.NoError(t, err)
+	})
+
+	m.Spec.Com.ServiceMonitor = &v1beta1.MilvusServiceMonitor{}
+	t.Run("delete existing", func(t *testing.T) {
+		mockClient.EXPECT().RESTMapper().Return(mapper)
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName(m.Namespace, m.Name), gomock.AssignableToTypeOf(&monitoringv1.ServiceMonitor{})).
+			Return(nil)
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&monitoringv1.ServiceMonitor{})).Return(nil)
+		err := r.ReconcileServiceMonitor(ctx, m)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReconciler_ReconcileServiceMonitor_CRDNotInstalled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newServiceMonitorTestMilvus()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mockClient.EXPECT().RESTMapper().Return(mapper)
+
+	err := r.ReconcileServiceMonitor(ctx, m)
+	assert.NoError(t, err)
+}
+
+func TestReconciler_ReconcileServiceMonitor_CreateIfNotExist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newServiceMonitorTestMilvus()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(monitoringv1.SchemeGroupVersion.WithKind(monitoringv1.ServiceMonitorsKind), meta.RESTScopeNamespace)
+	mockClient.EXPECT().RESTMapper().Return(mapper)
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+	mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, obj *monitoringv1.ServiceMonitor, _ ...any) error {
+			assert.Equal(t, "mc", obj.Name)
+			assert.Equal(t, "prometheus", obj.Labels["release"])
+			assert.Equal(t, v1beta1.TrueStr, obj.Spec.Selector.MatchLabels[MainServiceLabel])
+			assert.Len(t, obj.Spec.Endpoints, 1)
+			assert.Equal(t, MetricPortName, obj.Spec.Endpoints[0].Port)
+			assert.Equal(t, monitoringv1.Duration("15s"), obj.Spec.Endpoints[0].Interval)
+			return nil
+		})
+
+	err := r.ReconcileServiceMonitor(ctx, m)
+	assert.NoError(t, err)
+}
+
+func TestReconciler_ReconcileServiceMonitor_UpdateIfExisted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+	m := newServiceMonitorTestMilvus()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(monitoringv1.SchemeGroupVersion.WithKind(monitoringv1.ServiceMonitorsKind), meta.RESTScopeNamespace)
+	mockClient.EXPECT().RESTMapper().Return(mapper)
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	err := r.ReconcileServiceMonitor(ctx, m)
+	assert.NoError(t, err)
+}
+
+func TestReconciler_updateServiceMonitor_SelectMainServiceOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	m := newServiceMonitorTestMilvus()
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+	m.Spec.Com.Proxy.InternalService = &v1beta1.MilvusInternalService{}
+	m.Spec.Com.MixCoord.HeadlessService = true
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	serviceMonitor.Namespace = m.Namespace
+	assert.NoError(t, r.updateServiceMonitor(m, serviceMonitor))
+	selector, err := metav1.LabelSelectorAsSelector(&serviceMonitor.Spec.Selector)
+	assert.NoError(t, err)
+
+	mainService := &corev1.Service{}
+	mainService.Namespace = m.Namespace
+	assert.NoError(t, r.updateService(m, mainService, Proxy))
+	assert.True(t, selector.Matches(labels.Set(mainService.Labels)))
+
+	internalService := &corev1.Service{}
+	internalService.Namespace = m.Namespace
+	assert.NoError(t, r.updateInternalService(m, internalService, Proxy))
+	assert.False(t, selector.Matches(labels.Set(internalService.Labels)))
+
+	headlessService := &corev1.Service{}
+	headlessService.Namespace = m.Namespace
+	assert.NoError(t, r.updateHeadlessService(m, headlessService, MixCoord))
+	assert.False(t, selector.Matches(labels.Set(headlessService.Labels)))
+}
@@ -8,14 +8,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
 )
 
+// MainServiceLabel marks the main service of milvus,
+// to tell it from the internal & headless services with the same app labels
+const MainServiceLabel = "milvus.io/main-service"
+
 func (r *MilvusReconciler) updateService(
 	mc v1beta1.Milvus, service *corev1.Service, component MilvusComponent,
 ) error {
 	serviceLabels := NewAppLabels(mc.Name)
+	serviceLabels[MainServiceLabel] = v1beta1.TrueStr
 	service.Labels = MergeLabels(service.Labels, serviceLabels)
 
 	if err := SetControllerReference(&mc, service, r.Scheme); err != nil {
@@ -23,17 +29,12 @@ func (r *MilvusReconciler) updateService(
 	}
 	service.Spec.Ports = MergeServicePort(service.Spec.Ports, component.GetServicePorts(mc.Spec))
 
-	if mc.IsPodServiceLabelAdded() {
-		// new service will use milvus.io/service to dertermine
-		// which pods to select instead of app.kubernetes.io/component
-		// to no downtime support upgrading from standalone to cluster
-		service.Spec.Selector = NewServicePodLabels(mc.Name)
-	} else {
-		// backward compatibility
-		service.Spec.Selector = NewComponentAppLabels(mc.Name, component.Name)
-	}
+	service.Spec.Selector = getServicePodSelector(mc, component)
 
 	service.Spec.Type = component.GetServiceType(mc.Spec)
+	clearServiceFieldsInvalidForType(service)
+	updateServiceSessionAffinity(service, mc.Spec.GetServiceComponent())
+	updateServiceExternalTrafficPolicy(service, mc.Spec.GetServiceComponent())
 
 	if mc.Spec.Mode == v1beta1.MilvusModeCluster {
 		service.Labels = MergeLabels(service.Labels, mc.Spec.Com.Proxy.ServiceLabels)
@@ -47,6 +48,65 @@ func (r *MilvusReconciler) updateService(
 	return nil
 }
 
+func getServicePodSelector(mc v1beta1.Milvus, component MilvusComponent) map[string]string {
+	if mc.IsPodServiceLabelAdded() {
+		// new service will use milvus.io/service to dertermine
+		// which pods to select instead of app.kubernetes.io/component
+		// to no downtime support upgrading from standalone to cluster
+		return NewServicePodLabels(mc.Name)
+	}
+	// backward compatibility
+	return NewComponentAppLabels(mc.Name, component.Name)
+}
+
+// clearServiceFieldsInvalidForType clears the fields left by the previous service type,
+// which k8s rejects for the current type
+func clearServiceFieldsInvalidForType(service *corev1.Service) {
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		return
+	case corev1.ServiceTypeNodePort:
+	default:
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = 0
+		}
+	}
+	service.Spec.HealthCheckNodePort = 0
+	service.Spec.AllocateLoadBalancerNodePorts = nil
+	service.Spec.LoadBalancerClass = nil
+	service.Spec.LoadBalancerIP = ""
+	service.Spec.LoadBalancerSourceRanges = nil
+}
+
+func updateServiceSessionAffinity(service *corev1.Service, serviceComponent *v1beta1.ServiceComponent) {
+	service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	if len(serviceComponent.ServiceSessionAffinity) > 0 {
+		service.Spec.SessionAffinity = serviceComponent.ServiceSessionAffinity
+	}
+	if service.Spec.SessionAffinity == corev1.ServiceAffinityNone {
+		service.Spec.SessionAffinityConfig = nil
+		return
+	}
+	// keep the config defaulted by k8s if not specified
+	if serviceComponent.ServiceSessionAffinityConfig != nil {
+		service.Spec.SessionAffinityConfig = serviceComponent.ServiceSessionAffinityConfig.DeepCopy()
+	}
+}
+
+func updateServiceExternalTrafficPolicy(service *corev1.Service, serviceComponent *v1beta1.ServiceComponent) {
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+	default:
+		// k8s rejects externalTrafficPolicy for other service types
+		service.Spec.ExternalTrafficPolicy = ""
+		return
+	}
+	service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	if len(serviceComponent.ServiceExternalTrafficPolicy) > 0 {
+		service.Spec.ExternalTrafficPolicy = serviceComponent.ServiceExternalTrafficPolicy
+	}
+}
+
 func (r *MilvusReconciler) ReconcileComponentService(
 	ctx context.Context, mc v1beta1.Milvus, component MilvusComponent,
 ) error {
@@ -98,13 +158,166 @@ func (r *MilvusReconciler) ReconcileComponentService(
 	return r.Update(ctx, cur)
 }
 
+// updateInternalService updates the internal-only service.
+// If selectUpdatedPodsOnRollout is set, it selects only the pods of the current group during a rollout
+func (r *MilvusReconciler) updateInternalService(
+	mc v1beta1.Milvus, service *corev1.Service, component MilvusComponent,
+) error {
+	service.Labels = MergeLabels(service.Labels, NewAppLabels(mc.Name))
+	if err := SetControllerReference(&mc, service, r.Scheme); err != nil {
+		return err
+	}
+	service.Spec.Ports = MergeServicePort(service.Spec.Ports, component.GetServicePorts(mc.Spec))
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	clearServiceFieldsInvalidForType(service)
+
+	selector := getServicePodSelector(mc, component)
+	labelHelper := v1beta1.Labels()
+	currentGroupId := labelHelper.GetCurrentGroupId(&mc, component.Name)
+	if mc.Spec.GetServiceComponent().InternalService.SelectUpdatedPodsOnRollout &&
+		labelHelper.IsComponentRolling(mc, component.Name) && currentGroupId != "" {
+		labelHelper.SetGroupIDStr(component.Name, selector, currentGroupId)
+	}
+	service.Spec.Selector = selector
+	return nil
+}
+
+// ReconcileInternalService creates or updates the internal-only service if enabled
+func (r *MilvusReconciler) ReconcileInternalService(
+	ctx context.Context, mc v1beta1.Milvus, component MilvusComponent,
+) error {
+	if mc.Spec.GetServiceComponent().InternalService == nil {
+		return r.deleteIfExists(ctx, &corev1.Service{}, NamespacedName(mc.Namespace, GetInternalServiceName(mc.Name)))
+	}
+
+	if mc.IsChangingMode() && !mc.IsPodServiceLabelAdded() {
+		return nil
+	}
+
+	namespacedName := NamespacedName(mc.Namespace, GetInternalServiceName(mc.Name))
+	old := &corev1.Service{}
+	err := r.Get(ctx, namespacedName, old)
+	if errors.IsNotFound(err) {
+		new := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := r.updateInternalService(mc, new, component); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create Service", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	} else if err != nil {
+		return err
+	}
+
+	cur := old.DeepCopy()
+	if err := r.updateInternalService(mc, cur, component); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update Service", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
+
+// updateHeadlessService updates the headless service which selects the pods of the component
+func (r *MilvusReconciler) updateHeadlessService(
+	mc v1beta1.Milvus, service *corev1.Service, component MilvusComponent,
+) error {
+	service.Labels = MergeLabels(service.Labels, NewComponentAppLabels(mc.Name, component.Name))
+	if err := SetControllerReference(&mc, service, r.Scheme); err != nil {
+		return err
+	}
+
+	servicePorts := []corev1.ServicePort{}
+	// mixcoord serves multiple ports, they're not exposed in the container spec
+	if port := component.GetComponentPort(mc.Spec); port > 0 {
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:       component.GetPortName(),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port,
+			TargetPort: intstr.FromInt32(port),
+		})
+	}
+	servicePorts = append(servicePorts, corev1.ServicePort{
+		Name:       MetricPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       component.GetMetricPort(mc.Spec),
+		TargetPort: intstr.FromString(MetricPortName),
+	})
+	service.Spec.Ports = MergeServicePort(service.Spec.Ports, servicePorts)
+	service.Spec.Selector = NewComponentAppLabels(mc.Name, component.Name)
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.ClusterIP = corev1.ClusterIPNone
+	// coords register themselves before ready, let them be resolved as soon as they're up
+	service.Spec.PublishNotReadyAddresses = true
+	return nil
+}
+
+// ReconcileHeadlessService creates or updates the headless service of the coordinator if enabled
+func (r *MilvusReconciler) ReconcileHeadlessService(
+	ctx context.Context, mc v1beta1.Milvus, component MilvusComponent,
+) error {
+	if !component.IsHeadlessServiceEnabled(mc.Spec) {
+		return r.deleteIfExists(ctx, &corev1.Service{}, NamespacedName(mc.Namespace, component.GetHeadlessServiceName(mc.Name)))
+	}
+
+	namespacedName := NamespacedName(mc.Namespace, component.GetHeadlessServiceName(mc.Name))
+	old := &corev1.Service{}
+	err := r.Get(ctx, namespacedName, old)
+	if errors.IsNotFound(err) {
+		new := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		if err := r.updateHeadlessService(mc, new, component); err != nil {
+			return err
+		}
+
+		r.logger.Info("Create Service", "name", new.Name, "namespace", new.Namespace)
+		return r.Create(ctx, new)
+	} else if err != nil {
+		return err
+	}
+
+	cur := old.DeepCopy()
+	if err := r.updateHeadlessService(mc, cur, component); err != nil {
+		return err
+	}
+
+	if IsEqual(old, cur) {
+		return nil
+	}
+
+	r.logger.Info("Update Service", "name", cur.Name, "namespace", cur.Namespace)
+	return r.Update(ctx, cur)
+}
+
 func (r *MilvusReconciler) ReconcileServices(ctx context.Context, mc v1beta1.Milvus) error {
-	var err error
+	serviceComponent := MilvusStandalone
 	if mc.Spec.Mode == v1beta1.MilvusModeCluster {
-		err = r.ReconcileComponentService(ctx, mc, Proxy)
-	} else {
-		err = r.ReconcileComponentService(ctx, mc, MilvusStandalone)
+		serviceComponent = Proxy
+	}
+	if err := r.ReconcileComponentService(ctx, mc, serviceComponent); err != nil {
+		return pkgerr.Wrap(err, "reconcile milvus services")
+	}
+	if err := r.ReconcileInternalService(ctx, mc, serviceComponent); err != nil {
+		return pkgerr.Wrap(err, "reconcile milvus internal service")
 	}
 
-	return pkgerr.Wrap(err, "reconcile milvus services")
+	for _, component := range GetComponentsBySpec(mc.Spec) {
+		if err := r.ReconcileHeadlessService(ctx, mc, component); err != nil {
+			return pkgerr.Wrapf(err, "reconcile headless service of %s", component.Name)
+		}
+	}
+	return nil
 }
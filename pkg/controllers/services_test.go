@@ -16,6 +16,21 @@ import (
 	"github.com/zilliztech/milvus-operator/pkg/util"
 )
 
+// expectDisabledServicesNotFound expects the lookups to delete the disabled internal & headless services
+func expectDisabledServicesNotFound(mockClient *MockK8sClient, m v1beta1.Milvus) {
+	notFound := k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr")
+	if m.Spec.GetServiceComponent().InternalService == nil {
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName(m.Namespace, GetInternalServiceName(m.Name)), gomock.Any()).
+			Return(notFound)
+	}
+	for _, component := range GetComponentsBySpec(m.Spec) {
+		if !component.IsHeadlessServiceEnabled(m.Spec) {
+			mockClient.EXPECT().Get(gomock.Any(), NamespacedName(m.Namespace, component.GetHeadlessServiceName(m.Name)), gomock.Any()).
+				Return(notFound)
+		}
+	}
+}
+
 func TestReconciler_ReconcileServices_CreateIfNotExist(t *testing.T) {
 	config.Init(util.GetGitRepoRootDir())
 
@@ -33,6 +48,7 @@ func TestReconciler_ReconcileServices_CreateIfNotExist(t *testing.T) {
 	}
 	m.Default()
 
+	expectDisabledServicesNotFound(mockClient, m)
 	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr")).Times(1)
 
@@ -51,6 +67,7 @@ func TestReconciler_ReconcileServices_CreateIfNotExist(t *testing.T) {
 		m.Default()
 		m.Spec.Mode = v1beta1.MilvusModeCluster
 		m.Default()
+		expectDisabledServicesNotFound(mockClient, m)
 		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr")).Times(1)
 
@@ -78,6 +95,7 @@ func TestReconciler_ReconcileServices_UpdateIfExisted(t *testing.T) {
 	}
 	m.Default()
 
+	expectDisabledServicesNotFound(mockClient, m)
 	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
 		Do(func(ctx, key, obj interface{}, opts ...any) error {
 			s := obj.(*corev1.Service)
@@ -101,6 +119,7 @@ func TestReconciler_ReconcileServices_UpdateIfExisted(t *testing.T) {
 		m.Spec.Mode = v1beta1.MilvusModeCluster
 		m.Default()
 
+		expectDisabledServicesNotFound(mockClient, m)
 		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
 			Do(func(ctx, key, obj interface{}, opts ...any) error {
 				s := obj.(*corev1.Service)
@@ -115,3 +134,355 @@ func TestReconciler_ReconcileServices_UpdateIfExisted(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestReconciler_updateService_SessionAffinity(t *testing.T) {
+	config.Init(util.GetGitRepoRootDir())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+
+	t.Run("default none", func(t *testing.T) {
+		service := &corev1.Service{}
+		err := r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceAffinityNone, service.Spec.SessionAffinity)
+		assert.Nil(t, service.Spec.SessionAffinityConfig)
+	})
+
+	t.Run("client ip", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Com.Proxy.ServiceSessionAffinity = corev1.ServiceAffinityClientIP
+		m.Spec.Com.Proxy.ServiceSessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: int32Ptr(600)},
+		}
+		service := &corev1.Service{}
+		err := r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceAffinityClientIP, service.Spec.SessionAffinity)
+		assert.Equal(t, int32(600), *service.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
+
+		// config defaulted by k8s kept
+		m.Spec.Com.Proxy.ServiceSessionAffinityConfig = nil
+		err = r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(600), *service.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
+
+		// changed back to none
+		m.Spec.Com.Proxy.ServiceSessionAffinity = corev1.ServiceAffinityNone
+		err = r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceAffinityNone, service.Spec.SessionAffinity)
+		assert.Nil(t, service.Spec.SessionAffinityConfig)
+	})
+}
+
+func TestReconciler_updateService_ExternalTrafficPolicy(t *testing.T) {
+	config.Init(util.GetGitRepoRootDir())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+
+	t.Run("not set for ClusterIP", func(t *testing.T) {
+		service := &corev1.Service{}
+		err := r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Empty(t, service.Spec.ExternalTrafficPolicy)
+	})
+
+	t.Run("local on LoadBalancer", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+		m.Spec.Com.Proxy.ServiceExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+		service := &corev1.Service{}
+		err := r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+		assert.Equal(t, corev1.ServiceExternalTrafficPolicyLocal, service.Spec.ExternalTrafficPolicy)
+
+		// unset falls back to Cluster
+		m.Spec.Com.Proxy.ServiceExternalTrafficPolicy = ""
+		err = r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceExternalTrafficPolicyCluster, service.Spec.ExternalTrafficPolicy)
+
+		// rejected for ClusterIP
+		m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeClusterIP
+		m.Spec.Com.Proxy.ServiceExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+		_, err = m.ValidateCreate()
+		assert.Error(t, err)
+	})
+}
+
+func TestReconciler_ReconcileServices_HeadlessService(t *testing.T) {
+	config.Init(util.GetGitRepoRootDir())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+	m.Spec.Com.MixCoord.HeadlessService = true
+
+	expectDisabledServicesNotFound(mockClient, m)
+	mockClient.EXPECT().Get(gomock.Any(), NamespacedName("ns", "mc-milvus"), gomock.Any()).
+		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+	mockClient.EXPECT().Get(gomock.Any(), NamespacedName("ns", "mc-milvus-mixcoord-headless"), gomock.Any()).
+		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+	var created []*corev1.Service
+	mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, obj interface{}, _ ...interface{}) error {
+			created = append(created, obj.(*corev1.Service))
+			return nil
+		}).Times(2)
+
+	err := r.ReconcileServices(ctx, m)
+	assert.NoError(t, err)
+	assert.Len(t, created, 2)
+	headless := created[1]
+	assert.Equal(t, "mc-milvus-mixcoord-headless", headless.Name)
+	assert.Equal(t, corev1.ClusterIPNone, headless.Spec.ClusterIP)
+	assert.Equal(t, NewComponentAppLabels("mc", MixCoordName), headless.Spec.Selector)
+	assert.True(t, headless.Spec.PublishNotReadyAddresses)
+	assert.Len(t, headless.Spec.Ports, 1)
+	assert.Equal(t, MetricPortName, headless.Spec.Ports[0].Name)
+
+	t.Run("coord with port", func(t *testing.T) {
+		service := &corev1.Service{}
+		service.Namespace = m.Namespace
+		err := r.updateHeadlessService(m, service, RootCoord)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+		assert.Equal(t, NewComponentAppLabels("mc", RootCoordName), service.Spec.Selector)
+		assert.Len(t, service.Spec.Ports, 2)
+		assert.Equal(t, RootCoord.DefaultPort, service.Spec.Ports[0].Port)
+	})
+
+	t.Run("not coord", func(t *testing.T) {
+		m := m.DeepCopy()
+		m.Spec.Com.Proxy.HeadlessService = true
+		assert.False(t, Proxy.IsHeadlessServiceEnabled(m.Spec))
+	})
+
+	t.Run("disabled, delete", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Com.MixCoord.HeadlessService = false
+		key := NamespacedName("ns", "mc-milvus-mixcoord-headless")
+		mockClient.EXPECT().Get(gomock.Any(), key, gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&corev1.Service{})).Return(nil)
+		err := r.ReconcileHeadlessService(ctx, m, MixCoord)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReconciler_updateService_ServiceTypeTransition(t *testing.T) {
+	config.Init(util.GetGitRepoRootDir())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+
+	t.Run("LoadBalancer to ClusterIP", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+		m.Spec.Com.Proxy.ServiceExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+		service := &corev1.Service{}
+		err := r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		// fields allocated & defaulted by k8s
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = int32(30000 + i)
+		}
+		service.Spec.HealthCheckNodePort = 31000
+		service.Spec.AllocateLoadBalancerNodePorts = boolPtr(true)
+		service.Spec.LoadBalancerClass = new(string)
+		service.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+
+		m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeClusterIP
+		err = r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceTypeClusterIP, service.Spec.Type)
+		for _, port := range service.Spec.Ports {
+			assert.Zero(t, port.NodePort)
+		}
+		assert.Zero(t, service.Spec.HealthCheckNodePort)
+		assert.Nil(t, service.Spec.AllocateLoadBalancerNodePorts)
+		assert.Nil(t, service.Spec.LoadBalancerClass)
+		assert.Nil(t, service.Spec.LoadBalancerSourceRanges)
+		assert.Empty(t, service.Spec.ExternalTrafficPolicy)
+	})
+
+	t.Run("NodePort to LoadBalancer", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeNodePort
+		service := &corev1.Service{}
+		err := r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = int32(30000 + i)
+		}
+
+		m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+		err = r.updateService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+		// node ports kept for the load balancer
+		for i, port := range service.Spec.Ports {
+			assert.Equal(t, int32(30000+i), port.NodePort)
+		}
+		assert.Equal(t, corev1.ServiceExternalTrafficPolicyCluster, service.Spec.ExternalTrafficPolicy)
+	})
+}
+
+func TestReconciler_updateService_CloudLBAnnotations(t *testing.T) {
+	config.Init(util.GetGitRepoRootDir())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+	m.Spec.Com.Proxy.ServiceType = corev1.ServiceTypeLoadBalancer
+	m.Spec.Com.Proxy.ServiceAnnotations = map[string]string{
+		"service.beta.kubernetes.io/aws-load-balancer-type":                              "nlb",
+		"service.beta.kubernetes.io/aws-load-balancer-internal":                          "true",
+		"service.beta.kubernetes.io/aws-load-balancer-connection-idle-timeout":           "3600",
+		"service.beta.kubernetes.io/aws-load-balancer-cross-zone-load-balancing-enabled": "true",
+	}
+
+	service := &corev1.Service{}
+	err := r.updateService(m, service, Proxy)
+	assert.NoError(t, err)
+	for k, v := range m.Spec.Com.Proxy.ServiceAnnotations {
+		assert.Equal(t, v, service.Annotations[k])
+	}
+
+	// annotations set by others like the cloud controller are kept, and the cloud annotations survive reconciles
+	const otherAnnotation = "service.kubernetes.io/load-balancer-cleanup"
+	service.Annotations[otherAnnotation] = "true"
+	cur := service.DeepCopy()
+	err = r.updateService(m, cur, Proxy)
+	assert.NoError(t, err)
+	assert.True(t, IsEqual(service, cur))
+	assert.Equal(t, "true", cur.Annotations[otherAnnotation])
+	assert.Equal(t, "nlb", cur.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"])
+}
+
+func TestReconciler_ReconcileServices_InternalService(t *testing.T) {
+	config.Init(util.GetGitRepoRootDir())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := newMilvusReconcilerForTest(ctrl)
+	mockClient := r.Client.(*MockK8sClient)
+	ctx := context.Background()
+
+	m := v1beta1.Milvus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "mc",
+		},
+	}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Default()
+	m.Spec.Com.Proxy.InternalService = &v1beta1.MilvusInternalService{SelectUpdatedPodsOnRollout: true}
+
+	expectDisabledServicesNotFound(mockClient, m)
+	mockClient.EXPECT().Get(gomock.Any(), NamespacedName("ns", "mc-milvus"), gomock.Any()).
+		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+	mockClient.EXPECT().Get(gomock.Any(), NamespacedName("ns", "mc-milvus-internal"), gomock.Any()).
+		Return(k8sErrors.NewNotFound(schema.GroupResource{}, "mockErr"))
+	var created []*corev1.Service
+	mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, obj interface{}, _ ...interface{}) error {
+			created = append(created, obj.(*corev1.Service))
+			return nil
+		}).Times(2)
+
+	err := r.ReconcileServices(ctx, m)
+	assert.NoError(t, err)
+	assert.Len(t, created, 2)
+	internal := created[1]
+	assert.Equal(t, "mc-milvus-internal", internal.Name)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, internal.Spec.Type)
+	assert.Equal(t, getServicePodSelector(m, Proxy), internal.Spec.Selector)
+
+	groupIdLabel := v1beta1.GetComponentGroupIdLabel(ProxyName)
+	t.Run("during rollout", func(t *testing.T) {
+		m := *m.DeepCopy()
+		v1beta1.Labels().SetCurrentGroupID(&m, ProxyName, 1)
+		v1beta1.Labels().SetComponentRolling(&m, ProxyName, true)
+		service := &corev1.Service{}
+		err := r.updateInternalService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", service.Spec.Selector[groupIdLabel])
+
+		m.Spec.Com.Proxy.InternalService.SelectUpdatedPodsOnRollout = false
+		err = r.updateInternalService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.NotContains(t, service.Spec.Selector, groupIdLabel)
+	})
+
+	t.Run("after rollout", func(t *testing.T) {
+		m := *m.DeepCopy()
+		v1beta1.Labels().SetCurrentGroupID(&m, ProxyName, 1)
+		service := &corev1.Service{}
+		service.Spec.Selector = map[string]string{groupIdLabel: "0"}
+		err := r.updateInternalService(m, service, Proxy)
+		assert.NoError(t, err)
+		assert.Equal(t, getServicePodSelector(m, Proxy), service.Spec.Selector)
+	})
+
+	t.Run("disabled, delete", func(t *testing.T) {
+		m := *m.DeepCopy()
+		m.Spec.Com.Proxy.InternalService = nil
+		mockClient.EXPECT().Get(gomock.Any(), NamespacedName("ns", "mc-milvus-internal"), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&corev1.Service{})).Return(nil)
+		err := r.ReconcileInternalService(ctx, m, Proxy)
+		assert.NoError(t, err)
+	})
+}
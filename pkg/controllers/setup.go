@@ -43,8 +43,13 @@ func SetupControllers(ctx context.Context, mgr manager.Manager, stopReconcilers
 		settings.MaxHistory = 2
 		helmReconciler := MustNewLocalHelmReconciler(settings, logger.WithName("helm"), mgr)
 
+		if err := detectNativeSidecarSupport(mgr.GetConfig()); err != nil {
+			logger.Error(err, "detect native sidecar support failed, config sidecar mode disabled")
+		}
+
 		// should be run after mgr started to make sure the client is ready
 		statusSyncer := NewMilvusStatusSyncer(ctx, mgr.GetClient(), logger.WithName("status-syncer"))
+		statusSyncer.eventRecorder = mgr.GetEventRecorderFor("milvus-status-syncer")
 
 		reconciler := &MilvusReconciler{
 			Client:         mgr.GetClient(),
@@ -52,6 +57,7 @@ func SetupControllers(ctx context.Context, mgr manager.Manager, stopReconcilers
 			logger:         logger.WithName("milvus"),
 			helmReconciler: helmReconciler,
 			statusSyncer:   statusSyncer,
+			eventRecorder:  mgr.GetEventRecorderFor("milvus-controller"),
 		}
 		k8sUtil := NewK8sUtil(mgr.GetClient())
 		bizUtilFactory := NewDeployControllerBizUtilFactory(mgr.GetClient(), k8sUtil)
@@ -83,7 +89,7 @@ func SetupControllers(ctx context.Context, mgr manager.Manager, stopReconcilers
 
 	logger.Info("enable webhook", "enable", enableHook)
 	if enableHook {
-		if err := (&milvusv1beta1.Milvus{}).SetupWebhookWithManager(mgr); err != nil {
+		if err := setupMilvusWebhookWithManager(mgr, logger.WithName("milvus-defaulter")); err != nil {
 			logger.Error(err, "unable to create webhook", "webhook", "Milvus")
 			return err
 		}
@@ -95,6 +101,10 @@ func SetupControllers(ctx context.Context, mgr manager.Manager, stopReconcilers
 			logger.Error(err, "unable to create webhook", "webhook", "MilvusUpgrade")
 			return err
 		}
+		if err := mgr.AddReadyzCheck(WebhookCertCheckName, newWebhookCertChecker(mgr.GetWebhookServer()).Check); err != nil {
+			logger.Error(err, "unable to set up ready check", "check", WebhookCertCheckName)
+			return err
+		}
 	}
 
 	return nil
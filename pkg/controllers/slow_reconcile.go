@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// SlowReconcileThreshold is the duration beyond which a reconcile of milvus is considered slow, 0 disables the check
+var SlowReconcileThreshold = 2 * time.Minute
+
+// reconcileNow is the clock of reconcile durations, replaced in tests
+var reconcileNow = time.Now
+
+// updateSlowReconcileCondition records the duration of the reconcile started at start,
+// it sets the SlowReconcile condition & emits a warning event if it's beyond the SlowReconcileThreshold,
+// the condition is removed when a later reconcile is fast again.
+// returns true if the conditions are changed
+func (r *MilvusReconciler) updateSlowReconcileCondition(mc *v1beta1.Milvus, start time.Time) bool {
+	elapsed := reconcileNow().Sub(start)
+	reconcileDurationHistogram.WithLabelValues(mc.Namespace, mc.Name).Observe(elapsed.Seconds())
+
+	oldConditions := mc.Status.DeepCopy().Conditions
+	if SlowReconcileThreshold <= 0 || elapsed < SlowReconcileThreshold {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.SlowReconcile})
+		return !IsEqual(oldConditions, mc.Status.Conditions)
+	}
+
+	msg := fmt.Sprintf("reconcile took %s, beyond the threshold %s", elapsed.Round(time.Second), SlowReconcileThreshold)
+	r.logger.Info("slow reconcile", "milvus", mc.Name, "namespace", mc.Namespace, "elapsed", elapsed)
+	if r.eventRecorder != nil {
+		r.eventRecorder.Event(mc, corev1.EventTypeWarning, v1beta1.ReasonReconcileSlow, msg)
+	}
+	UpdateCondition(&mc.Status, v1beta1.MilvusCondition{
+		Type:    v1beta1.SlowReconcile,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.ReasonReconcileSlow,
+		Message: msg,
+	})
+	return !IsEqual(oldConditions, mc.Status.Conditions)
+}
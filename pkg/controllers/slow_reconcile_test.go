@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusReconciler_updateSlowReconcileCondition(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	r := env.Reconciler
+	recorder := record.NewFakeRecorder(10)
+	r.eventRecorder = recorder
+
+	start := time.Now()
+	elapsed := time.Second
+	reconcileNow = func() time.Time {
+		return start.Add(elapsed)
+	}
+	defer func() { reconcileNow = time.Now }()
+
+	mc := env.Inst.DeepCopy()
+	t.Run("fast reconcile no condition", func(t *testing.T) {
+		assert.False(t, r.updateSlowReconcileCondition(mc, start))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.SlowReconcile))
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("slow reconcile sets condition & emits event", func(t *testing.T) {
+		elapsed = SlowReconcileThreshold + time.Minute
+		assert.True(t, r.updateSlowReconcileCondition(mc, start))
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.SlowReconcile)
+		assert.NotNil(t, cond)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+		assert.Equal(t, v1beta1.ReasonReconcileSlow, cond.Reason)
+		assert.Contains(t, cond.Message, "3m0s")
+		event := <-recorder.Events
+		assert.Contains(t, event, corev1.EventTypeWarning)
+		assert.Contains(t, event, v1beta1.ReasonReconcileSlow)
+	})
+
+	t.Run("fast again removes condition", func(t *testing.T) {
+		elapsed = time.Second
+		assert.True(t, r.updateSlowReconcileCondition(mc, start))
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.SlowReconcile))
+	})
+
+	t.Run("disabled by zero threshold", func(t *testing.T) {
+		defer func(threshold time.Duration) { SlowReconcileThreshold = threshold }(SlowReconcileThreshold)
+		SlowReconcileThreshold = 0
+		elapsed = time.Hour
+		assert.False(t, r.updateSlowReconcileCondition(mc, start))
+		assert.Empty(t, recorder.Events)
+	})
+}
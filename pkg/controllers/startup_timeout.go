@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// getStartupTimedOutComponents returns the components whose deployments have no ready replicas
+// beyond spec.components.startupTimeoutSeconds since they're created
+func getStartupTimedOutComponents(mc v1beta1.Milvus, now time.Time) []string {
+	timeoutSeconds := mc.Spec.Com.StartupTimeoutSeconds
+	if timeoutSeconds == nil {
+		return nil
+	}
+	timeout := time.Duration(*timeoutSeconds) * time.Second
+	var ret []string
+	for _, component := range GetDeploymentComponentsBySpec(mc.Spec) {
+		if ReplicasValue(component.GetDesiredReplicas(mc.Spec)) == 0 {
+			continue
+		}
+		deployStatus, ok := mc.Status.ComponentsDeployStatus[component.GetName()]
+		if !ok || deployStatus.CreationTimestamp == nil ||
+			deployStatus.Status.ReadyReplicas > 0 {
+			continue
+		}
+		if now.Sub(deployStatus.CreationTimestamp.Time) > timeout {
+			ret = append(ret, component.GetName())
+		}
+	}
+	return ret
+}
+
+// isStartingUp returns true if milvus has never been healthy since it's created,
+// or it's already marked unhealthy for startup timeout
+func isStartingUp(mc v1beta1.Milvus) bool {
+	if mc.Status.Status == v1beta1.StatusPending {
+		return true
+	}
+	readyCond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.MilvusReady)
+	return readyCond != nil && readyCond.Reason == v1beta1.ReasonStartupTimeout
+}
+
+// checkStartupTimeout sets the reason of the not ready MilvusReady condition to StartupTimeout
+// if some components don't start up in time, returns true if it's timed out
+func checkStartupTimeout(mc v1beta1.Milvus, milvusCond *v1beta1.MilvusCondition, now time.Time) bool {
+	if milvusCond.Status == corev1.ConditionTrue ||
+		mc.IsStopping() ||
+		!isStartingUp(mc) {
+		return false
+	}
+	components := getStartupTimedOutComponents(mc, now)
+	if len(components) < 1 {
+		return false
+	}
+	milvusCond.Reason = v1beta1.ReasonStartupTimeout
+	milvusCond.Message = fmt.Sprintf("components[%s] have no ready replicas %ds after created: %s",
+		strings.Join(components, ","), *mc.Spec.Com.StartupTimeoutSeconds, milvusCond.Message)
+	return true
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestCheckStartupTimeout(t *testing.T) {
+	now := time.Now()
+	createdAt := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	newStartingMilvus := func() v1beta1.Milvus {
+		mc := v1beta1.Milvus{}
+		mc.Default()
+		mc.Spec.Com.StartupTimeoutSeconds = ptr.To(int32(300))
+		mc.Status.Status = v1beta1.StatusPending
+		mc.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			StandaloneName: {
+				CreationTimestamp: &createdAt,
+			},
+		}
+		return mc
+	}
+	newNotReadyCond := func() v1beta1.MilvusCondition {
+		return v1beta1.MilvusCondition{
+			Type:    v1beta1.MilvusReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  v1beta1.ReasonMilvusComponentNotHealthy,
+			Message: "standalone not ready",
+		}
+	}
+
+	t.Run("timeout exceeded", func(t *testing.T) {
+		mc := newStartingMilvus()
+		cond := newNotReadyCond()
+		assert.True(t, checkStartupTimeout(mc, &cond, now))
+		assert.Equal(t, v1beta1.ReasonStartupTimeout, cond.Reason)
+		assert.Equal(t, "components[standalone] have no ready replicas 300s after created: standalone not ready", cond.Message)
+
+		// stays timed out after marked unhealthy
+		mc.Status.Status = v1beta1.StatusUnhealthy
+		mc.Status.Conditions = []v1beta1.MilvusCondition{cond}
+		cond = newNotReadyCond()
+		assert.True(t, checkStartupTimeout(mc, &cond, now))
+		assert.Equal(t, v1beta1.ReasonStartupTimeout, cond.Reason)
+	})
+
+	t.Run("within timeout", func(t *testing.T) {
+		mc := newStartingMilvus()
+		cond := newNotReadyCond()
+		assert.False(t, checkStartupTimeout(mc, &cond, createdAt.Add(time.Minute)))
+		assert.Equal(t, v1beta1.ReasonMilvusComponentNotHealthy, cond.Reason)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mc := newStartingMilvus()
+		mc.Spec.Com.StartupTimeoutSeconds = nil
+		cond := newNotReadyCond()
+		assert.False(t, checkStartupTimeout(mc, &cond, now))
+	})
+
+	t.Run("has ready replicas", func(t *testing.T) {
+		mc := newStartingMilvus()
+		mc.Status.ComponentsDeployStatus[StandaloneName] = v1beta1.ComponentDeployStatus{
+			CreationTimestamp: &createdAt,
+			Status:            appsv1.DeploymentStatus{ReadyReplicas: 1},
+		}
+		cond := newNotReadyCond()
+		assert.False(t, checkStartupTimeout(mc, &cond, now))
+	})
+
+	t.Run("milvus ready", func(t *testing.T) {
+		mc := newStartingMilvus()
+		cond := newNotReadyCond()
+		cond.Status = corev1.ConditionTrue
+		assert.False(t, checkStartupTimeout(mc, &cond, now))
+	})
+
+	t.Run("stopping", func(t *testing.T) {
+		mc := newStartingMilvus()
+		mc.Spec.Com.Standalone.Replicas = ptr.To(int32(0))
+		cond := newNotReadyCond()
+		assert.False(t, checkStartupTimeout(mc, &cond, now))
+	})
+
+	t.Run("not fresh", func(t *testing.T) {
+		mc := newStartingMilvus()
+		mc.Status.Status = v1beta1.StatusUnhealthy
+		cond := newNotReadyCond()
+		assert.False(t, checkStartupTimeout(mc, &cond, now))
+	})
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,8 @@ import (
 	networkv1 "k8s.io/api/networking/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -70,6 +73,9 @@ type MilvusStatusSyncer struct {
 	client.Client
 	logger              logr.Logger
 	deployStatusUpdater componentsDeployStatusUpdater
+	adminClient         MilvusAdminClient
+	healthGateClient    HealthGateClient
+	eventRecorder       record.EventRecorder
 
 	sync.Once
 }
@@ -80,6 +86,8 @@ func NewMilvusStatusSyncer(ctx context.Context, client client.Client, logger log
 		ctx:                 ctx,
 		Client:              client,
 		deployStatusUpdater: newComponentsDeployStatusUpdaterImpl(client),
+		adminClient:         newMilvusAdminClientImpl(client),
+		healthGateClient:    newHealthGateClientImpl(),
 		logger:              logger,
 	}
 }
@@ -91,6 +99,7 @@ func (r *MilvusStatusSyncer) RunIfNot() {
 		go LoopWithInterval(r.ctx, r.syncUnealthyOrUpdating, unhealthySyncInterval, r.logger)
 		go LoopWithInterval(r.ctx, r.syncHealthyUpdated, unhealthySyncInterval*2, r.logger)
 		go LoopWithInterval(r.ctx, r.updateMetrics, unhealthySyncInterval, r.logger)
+		go LoopWithInterval(r.ctx, r.syncSchedules, unhealthySyncInterval, r.logger)
 	})
 }
 
@@ -112,8 +121,18 @@ func (r *MilvusStatusSyncer) updateMetrics() error {
 	unhealthyCount = 0
 	deletingCount = 0
 	creatingCount = 0
+	dependencyConditionCounts := map[v1beta1.MilvusConditionType]map[corev1.ConditionStatus]int{}
+	for _, conditionType := range dependencyConditionTypes {
+		dependencyConditionCounts[conditionType] = map[corev1.ConditionStatus]int{}
+	}
 	for i := range milvusList.Items {
 		mc := &milvusList.Items[i]
+		for _, conditionType := range dependencyConditionTypes {
+			cond := GetMilvusConditionByType(mc.Status.Conditions, conditionType)
+			if cond != nil {
+				dependencyConditionCounts[conditionType][cond.Status]++
+			}
+		}
 		switch mc.Status.Status {
 		case v1beta1.StatusHealthy:
 			healthyCount++
@@ -129,9 +148,52 @@ func (r *MilvusStatusSyncer) updateMetrics() error {
 	milvusTotalCountCollector.WithLabelValues(string(v1beta1.StatusUnhealthy)).Set(float64(unhealthyCount))
 	milvusTotalCountCollector.WithLabelValues(string(v1beta1.StatusDeleting)).Set(float64(deletingCount))
 	milvusTotalCountCollector.WithLabelValues(string(v1beta1.StatusPending)).Set(float64(creatingCount))
+	for conditionType, counts := range dependencyConditionCounts {
+		for _, status := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown} {
+			milvusDependencyConditionCountCollector.WithLabelValues(string(conditionType), string(status)).Set(float64(counts[status]))
+		}
+	}
 	return nil
 }
 
+// MilvusStatusSummary is the summarized health of a milvus instance
+type MilvusStatusSummary struct {
+	Namespace string
+	Name      string
+	Status    v1beta1.MilvusHealthStatus
+	// Reason is the reason of the MilvusReady condition
+	Reason string
+	// ReadyReplicas is the sum of ready replicas of all components
+	ReadyReplicas int32
+}
+
+// ListMilvusStatusSummaries returns the health summaries of the milvus instances with a single List
+func (r *MilvusStatusSyncer) ListMilvusStatusSummaries(ctx context.Context, opts ...client.ListOption) ([]MilvusStatusSummary, error) {
+	milvusList := &v1beta1.MilvusList{}
+	err := r.List(ctx, milvusList, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "list milvus failed")
+	}
+	ret := make([]MilvusStatusSummary, 0, len(milvusList.Items))
+	for i := range milvusList.Items {
+		mc := &milvusList.Items[i]
+		summary := MilvusStatusSummary{
+			Namespace: mc.Namespace,
+			Name:      mc.Name,
+			Status:    mc.Status.Status,
+		}
+		readyCond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.MilvusReady)
+		if readyCond != nil {
+			summary.Reason = readyCond.Reason
+		}
+		for _, deployStatus := range mc.Status.ComponentsDeployStatus {
+			summary.ReadyReplicas += deployStatus.Status.ReadyReplicas
+		}
+		ret = append(ret, summary)
+	}
+	return ret, nil
+}
+
 func (r *MilvusStatusSyncer) syncUnealthyOrUpdating() error {
 	startTime := time.Now()
 	r.logger.Info("syncUnealthyOrUpdating start", "time", startTime)
@@ -214,7 +276,7 @@ func (r *MilvusStatusSyncer) UpdateStatusRoutine(ctx context.Context, mc *v1beta
 	}
 	// ObservedGeneration not up to date meaning it is being reconciled
 	if mc.Status.ObservedGeneration < mc.Generation {
-		return nil
+		return r.updateGenerationObservedCondition(ctx, mc)
 	}
 	// some default values may not be set if there's an upgrade
 	// so we call default again to ensure
@@ -224,12 +286,40 @@ func (r *MilvusStatusSyncer) UpdateStatusRoutine(ctx context.Context, mc *v1beta
 	return errors.Wrapf(err, "UpdateStatus for milvus[%s/%s]", mc.Namespace, mc.Name)
 }
 
+// updateGenerationObservedCondition surfaces the lagging observedGeneration in the GenerationObserved condition
+func (r *MilvusStatusSyncer) updateGenerationObservedCondition(ctx context.Context, mc *v1beta1.Milvus) error {
+	beginStatus := mc.Status.DeepCopy()
+	UpdateCondition(&mc.Status, GetGenerationObservedCondition(*mc))
+	if IsEqual(beginStatus, &mc.Status) {
+		return nil
+	}
+	return errors.Wrapf(r.Status().Update(ctx, mc), "update GenerationObserved condition for milvus[%s/%s]", mc.Namespace, mc.Name)
+}
+
+// GetGenerationObservedCondition returns false condition if the status.observedGeneration lags behind the generation
+func GetGenerationObservedCondition(mc v1beta1.Milvus) v1beta1.MilvusCondition {
+	if mc.Status.ObservedGeneration < mc.Generation {
+		return v1beta1.MilvusCondition{
+			Type:    v1beta1.GenerationObserved,
+			Status:  corev1.ConditionFalse,
+			Reason:  v1beta1.ReasonGenerationLagging,
+			Message: fmt.Sprintf("observedGeneration[%d] lags behind generation[%d]", mc.Status.ObservedGeneration, mc.Generation),
+		}
+	}
+	return v1beta1.MilvusCondition{
+		Type:   v1beta1.GenerationObserved,
+		Status: corev1.ConditionTrue,
+		Reason: v1beta1.ReasonGenerationObserved,
+	}
+}
+
 func (r *MilvusStatusSyncer) checkDependencyConditions(ctx context.Context, mc *v1beta1.Milvus) error {
-	if !mc.Spec.IsStopping() {
+	if !mc.IsStopping() {
 		funcs := []Func{
 			r.GetEtcdCondition,
 			r.GetMinioCondition,
 			r.GetMsgStreamCondition,
+			r.GetDependencyConflictCondition,
 		}
 		ress := defaultGroupRunner.RunWithResult(funcs, ctx, *mc)
 		errTexts := []string{}
@@ -243,13 +333,16 @@ func (r *MilvusStatusSyncer) checkDependencyConditions(ctx context.Context, mc *
 		if len(errTexts) > 0 {
 			return fmt.Errorf("check dependency conditions error: %s", strings.Join(errTexts, ":"))
 		}
+		r.updateStorageCapacityCondition(ctx, mc)
 		return nil
 	}
 	// is stopping, remove all dependency conditions
 	RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{
 		v1beta1.EtcdReady,
 		v1beta1.StorageReady,
+		v1beta1.StorageCapacityWarning,
 		v1beta1.MsgStreamReady,
+		v1beta1.DependencyConflict,
 	})
 	return nil
 }
@@ -258,6 +351,7 @@ func (r *MilvusStatusSyncer) checkDependencyConditions(ctx context.Context, mc *
 func (r *MilvusStatusSyncer) UpdateStatusForNewGeneration(ctx context.Context, mc *v1beta1.Milvus, checkDependency bool) error {
 	beginStatus := mc.Status.DeepCopy()
 	mc.Status.ObservedGeneration = mc.Generation
+	UpdateCondition(&mc.Status, GetGenerationObservedCondition(*mc))
 
 	if Debug {
 		checkDependency = false
@@ -274,37 +368,77 @@ func (r *MilvusStatusSyncer) UpdateStatusForNewGeneration(ctx context.Context, m
 	if err != nil {
 		return errors.Wrap(err, "update ingress status failed")
 	}
+	r.updateTLSCertificateCondition(ctx, mc)
 
 	err = r.deployStatusUpdater.Update(ctx, mc)
 	if err != nil {
 		return errors.Wrap(err, "update deploy status failed")
 	}
+	err = r.updateProxyReadinessGate(ctx, mc)
+	if err != nil {
+		return errors.Wrap(err, "update proxy readiness gate failed")
+	}
+	err = r.updateInitContainerCondition(ctx, mc)
+	if err != nil {
+		return errors.Wrap(err, "update init container condition failed")
+	}
 
+	mc.Status.RollingUpdateProgress = GetRollingUpdateProgress(mc)
+	mc.Status.RenderedConfigMap = ""
+	if mc.Spec.ExportRenderedConf {
+		mc.Status.RenderedConfigMap = GetRenderedConfigMapName(mc.Name)
+	}
 	mc.Status.Endpoint = r.GetMilvusEndpoint(ctx, *mc)
+	mc.Status.DependencyEndpoints, err = GetDependencyEndpoints(ctx, r.Client, *mc)
+	if err != nil {
+		r.logger.Error(err, "resolve dependency endpoints", "name", mc.Name, "namespace", mc.Namespace)
+	}
 
 	milvusCond, err := GetComponentConditionGetter().GetMilvusInstanceCondition(ctx, r.Client, *mc)
 	if err != nil {
 		return err
 	}
+	startupTimeout := checkStartupTimeout(*mc, &milvusCond, time.Now())
 	UpdateCondition(&mc.Status, milvusCond)
+	err = syncRestoreCondition(ctx, r.Client, mc)
+	if err != nil {
+		return errors.Wrap(err, "sync restore condition failed")
+	}
 	err = r.syncUpdatedCondition(ctx, mc)
 	if err != nil {
 		return errors.Wrap(err, "handle terminating pods failed")
 	}
+	updateRolloutPausedCondition(mc)
+	err = r.rollbackStalledUpgrade(ctx, mc, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "rollback stalled upgrade failed")
+	}
 
 	// set current milvus version annotation based on Spec when milvus is ready and updated
 	if IsMilvusConditionTrueByType(mc.Status.Conditions, v1beta1.MilvusReady) &&
 		IsMilvusConditionTrueByType(mc.Status.Conditions, v1beta1.MilvusUpdated) {
+		lastImage := mc.Status.CurrentImage
 		mc.Status.CurrentImage = mc.Spec.Com.Image
-		mc.Status.CurrentVersion = mc.Spec.Com.Version
+		mc.Status.CurrentVersion = mc.Spec.GetMilvusVersion()
+		markPostUpgradeActions(mc, lastImage)
 	}
 
 	statusInfo := MilvusHealthStatusInfo{
 		LastState:  mc.Status.Status,
-		IsStopping: mc.Spec.IsStopping(),
+		IsStopping: mc.IsStopping(),
 		IsHealthy:  milvusCond.Status == corev1.ConditionTrue,
+		// a timed out startup fails loudly instead of staying pending
+		IsStartupTimeout: startupTimeout,
+	}
+	if !r.checkHealthGate(ctx, mc, statusInfo) {
+		statusInfo.IsHealthy = false
 	}
 	mc.Status.Status = statusInfo.GetMilvusHealthStatus()
+	err = r.runPendingPostUpgradeActions(ctx, mc)
+	if err != nil {
+		// not fatal, retry in next sync
+		r.logger.Error(err, "run post upgrade actions failed", "namespace", mc.Namespace, "name", mc.Name)
+	}
 	if IsEqual(beginStatus, &mc.Status) {
 		return nil
 	}
@@ -350,16 +484,47 @@ func (r *MilvusStatusSyncer) UpdateIngressStatus(ctx context.Context, mc *v1beta
 	if ingress == nil {
 		return nil
 	}
-	key := client.ObjectKeyFromObject(mc)
-	key.Name += "-milvus"
-	status, err := getIngressStatus(ctx, r.Client, key)
+	status, err := getIngressStatus(ctx, r.Client, NamespacedName(mc.Namespace, getIngressName(mc.Name)))
 	if err != nil {
 		return errors.Wrap(err, "get ingress status failed")
 	}
+	if ingress.RESTful != nil {
+		restfulStatus, err := getIngressStatus(ctx, r.Client, NamespacedName(mc.Namespace, getRESTfulIngressName(mc.Name)))
+		if err != nil {
+			return errors.Wrap(err, "get restful ingress status failed")
+		}
+		mergeIngressStatus(status, *restfulStatus)
+	}
 	mc.Status.IngressStatus = *status
 	return nil
 }
 
+// mergeIngressStatus appends the load balancer ingresses of other which are not in status
+func mergeIngressStatus(status *networkv1.IngressStatus, other networkv1.IngressStatus) {
+	for _, lbIngress := range other.LoadBalancer.Ingress {
+		found := false
+		for _, existing := range status.LoadBalancer.Ingress {
+			if IsEqual(existing, lbIngress) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			status.LoadBalancer.Ingress = append(status.LoadBalancer.Ingress, lbIngress)
+		}
+	}
+}
+
+// updateTLSCertificateCondition sets the TLSCertificateValid condition only when TLS is configured for the ingress
+func (r *MilvusStatusSyncer) updateTLSCertificateCondition(ctx context.Context, mc *v1beta1.Milvus) {
+	ingress := mc.Spec.GetServiceComponent().Ingress
+	if ingress == nil || len(ingress.TLSSecretRefs) == 0 {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.TLSCertificateValid})
+		return
+	}
+	UpdateCondition(&mc.Status, GetTLSCertificateCondition(ctx, r.Client, mc.Namespace, *ingress, time.Now()))
+}
+
 func getIngressStatus(ctx context.Context, client client.Client, key client.ObjectKey) (*networkv1.IngressStatus, error) {
 	ingress := &networkv1.Ingress{}
 	err := client.Get(ctx, key, ingress)
@@ -416,9 +581,15 @@ func (r *MilvusStatusSyncer) GetMsgStreamCondition(
 				Message: err.Error(),
 			}, nil
 		}
-		kafkaConf.BrokerList = mc.Spec.Dep.Kafka.BrokerList
+		brokerList, err := ResolveKafkaBrokerList(ctx, r.Client, mc)
+		if err != nil {
+			return newErrMsgStreamCondResult(v1beta1.ReasonSecretErr, err.Error()), nil
+		}
+		kafkaConf.BrokerList = brokerList
 		getter = wrapKafkaConditonGetter(ctx, r.logger, mc.Spec.Dep.Kafka, *kafkaConf)
-		eps = mc.Spec.Dep.Kafka.BrokerList
+		eps = brokerList
+	case v1beta1.MsgStreamTypeWoodPecker:
+		return r.getWoodPeckerCondition(ctx, mc)
 	default:
 		// default built-in mqs, assume ok
 		return msgStreamReadyCondition, nil
@@ -426,10 +597,38 @@ func (r *MilvusStatusSyncer) GetMsgStreamCondition(
 	return GetCondition(getter, eps), nil
 }
 
+// getWoodPeckerCondition returns the condition of woodpecker.
+// woodpecker is embedded in milvus, it's ready when its storage backend is ready
+func (r *MilvusStatusSyncer) getWoodPeckerCondition(ctx context.Context, mc v1beta1.Milvus) (v1beta1.MilvusCondition, error) {
+	storageType, _ := util.GetStringValue(mc.Spec.Conf.Data, "woodpecker", "storage", "type")
+	if storageType == WoodPeckerStorageTypeLocal {
+		return msgStreamReadyCondition, nil
+	}
+	storageCond, err := r.GetMinioCondition(ctx, mc)
+	if err != nil {
+		return v1beta1.MilvusCondition{}, err
+	}
+	if storageCond.Status == corev1.ConditionTrue {
+		return msgStreamReadyCondition, nil
+	}
+	return v1beta1.MilvusCondition{
+		Type:    v1beta1.MsgStreamReady,
+		Status:  storageCond.Status,
+		Reason:  v1beta1.ReasonMsgStreamNotReady,
+		Message: fmt.Sprintf("woodpecker storage not ready: %s", storageCond.Message),
+	}, nil
+}
+
 // TODO: rename as GetStorageCondition
 func (r *MilvusStatusSyncer) GetMinioCondition(
 	ctx context.Context, mc v1beta1.Milvus) (v1beta1.MilvusCondition, error) {
-	info := StorageConditionInfo{
+	info := newStorageConditionInfo(mc)
+	getter := wrapMinioConditionGetter(ctx, r.logger, r.Client, info)
+	return GetCondition(getter, []string{mc.Spec.Dep.Storage.Endpoint}), nil
+}
+
+func newStorageConditionInfo(mc v1beta1.Milvus) StorageConditionInfo {
+	return StorageConditionInfo{
 		Namespace:      mc.Namespace,
 		Bucket:         GetMinioBucket(mc.Spec.Conf.Data),
 		Storage:        mc.Spec.Dep.Storage,
@@ -438,9 +637,30 @@ func (r *MilvusStatusSyncer) GetMinioCondition(
 		IAMEndpoint:    GetMinioIAMEndpoint(mc.Spec.Conf.Data),
 		StorageAccount: GetAzureStorageAccount(mc.Spec.Conf.Data),
 		UseVirtualHost: ShouldUseVirtualHost(mc.Spec.Conf.Data),
+		Region:         GetMinioRegion(mc.Spec.Conf.Data),
 	}
-	getter := wrapMinioConditionGetter(ctx, r.logger, r.Client, info)
-	return GetCondition(getter, []string{mc.Spec.Dep.Storage.Endpoint}), nil
+}
+
+// updateStorageCapacityCondition sets the StorageCapacityWarning condition only when the capacity warning threshold is configured
+// and the bucket usage exceeds it. The existing condition is kept if the usage can't be fetched
+func (r *MilvusStatusSyncer) updateStorageCapacityCondition(ctx context.Context, mc *v1beta1.Milvus) {
+	threshold := mc.Spec.Dep.Storage.CapacityWarningThreshold
+	// only MinIO reports the bucket usage
+	storageType := mc.Spec.Dep.Storage.Type
+	if threshold == nil || (storageType != "" && storageType != v1beta1.StorageTypeMinIO) {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.StorageCapacityWarning})
+		return
+	}
+	cond, err := GetStorageCapacityCondition(ctx, r.Client, newStorageConditionInfo(*mc), *threshold)
+	if err != nil {
+		r.logger.Error(err, "check storage capacity failed", "milvus", mc.Name)
+		return
+	}
+	if cond == nil {
+		RemoveConditions(&mc.Status, []v1beta1.MilvusConditionType{v1beta1.StorageCapacityWarning})
+		return
+	}
+	UpdateCondition(&mc.Status, *cond)
 }
 
 func (r *MilvusStatusSyncer) GetEtcdCondition(ctx context.Context, mc v1beta1.Milvus) (v1beta1.MilvusCondition, error) {
@@ -448,6 +668,10 @@ func (r *MilvusStatusSyncer) GetEtcdCondition(ctx context.Context, mc v1beta1.Mi
 	return GetCondition(getter, mc.Spec.Dep.Etcd.Endpoints), nil
 }
 
+func (r *MilvusStatusSyncer) GetDependencyConflictCondition(ctx context.Context, mc v1beta1.Milvus) (v1beta1.MilvusCondition, error) {
+	return GetDependencyConflictCondition(ctx, r.Client, mc)
+}
+
 type componentsDeployStatusUpdater interface {
 	Update(ctx context.Context, mc *v1beta1.Milvus) error
 }
@@ -476,9 +700,9 @@ func (r *componentsDeployStatusUpdaterImpl) Update(ctx context.Context, mc *v1be
 		mc.Status.ComponentsDeployStatus = make(map[string]v1beta1.ComponentDeployStatus)
 	}
 	componentDeploy := makeComponentDeploymentMap(*mc, deployList.Items)
-	allComponents := GetComponentsBySpec(mc.Spec)
+	allComponents := GetDeploymentComponentsBySpec(mc.Spec)
 	for _, component := range allComponents {
-		deployment := componentDeploy[component.Name]
+		deployment := componentDeploy[component.GetName()]
 		if deployment == nil {
 			continue
 		}
@@ -486,19 +710,58 @@ func (r *componentsDeployStatusUpdaterImpl) Update(ctx context.Context, mc *v1be
 			Generation: deployment.Generation,
 			Status:     deployment.Status,
 		}
+		if !deployment.CreationTimestamp.IsZero() {
+			status.CreationTimestamp = deployment.CreationTimestamp.DeepCopy()
+		}
 		containerIdx := GetContainerIndex(deployment.Spec.Template.Spec.Containers, component.Name)
 		if containerIdx >= 0 {
 			status.Image = deployment.Spec.Template.Spec.Containers[containerIdx].Image
 		}
-		mc.Status.ComponentsDeployStatus[component.Name] = status
+		mc.Status.ComponentsDeployStatus[component.GetName()] = status
+	}
+	// the deployments of removed components are still in use during migrations
+	if mc.IsChangingMode() {
+		return nil
+	}
+	expectedComponents := sets.New(ExpectedComponents(*mc)...)
+	for name := range mc.Status.ComponentsDeployStatus {
+		if !expectedComponents.Has(name) {
+			delete(mc.Status.ComponentsDeployStatus, name)
+		}
 	}
 	return nil
 }
 
+// GetDependencyEndpoints returns the endpoints of the dependencies in spec,
+// which are defaulted to the in-cluster service address for managed ones.
+// the kafka brokers are read from the secret if it's configured, msgStream is left empty if it fails
+func GetDependencyEndpoints(ctx context.Context, cli client.Client, mc v1beta1.Milvus) (*v1beta1.DependencyEndpoints, error) {
+	dep := mc.Spec.Dep
+	ret := &v1beta1.DependencyEndpoints{
+		Etcd:    dep.Etcd.Endpoints,
+		Storage: dep.Storage.Endpoint,
+	}
+
+	switch dep.MsgStreamType {
+	case v1beta1.MsgStreamTypeKafka:
+		brokerList, err := ResolveKafkaBrokerList(ctx, cli, mc)
+		if err != nil {
+			return ret, err
+		}
+		ret.MsgStream = brokerList
+	case v1beta1.MsgStreamTypePulsar:
+		if dep.Pulsar.Endpoint != "" {
+			ret.MsgStream = []string{dep.Pulsar.Endpoint}
+		}
+	}
+	return ret, nil
+}
+
 type MilvusHealthStatusInfo struct {
-	LastState  v1beta1.MilvusHealthStatus
-	IsStopping bool
-	IsHealthy  bool
+	LastState        v1beta1.MilvusHealthStatus
+	IsStopping       bool
+	IsHealthy        bool
+	IsStartupTimeout bool
 }
 
 func (m MilvusHealthStatusInfo) GetMilvusHealthStatus() v1beta1.MilvusHealthStatus {
@@ -510,7 +773,8 @@ func (m MilvusHealthStatusInfo) GetMilvusHealthStatus() v1beta1.MilvusHealthStat
 		return v1beta1.StatusHealthy
 	}
 	// if !m.IsStopping && !m.IsHealthy
-	if m.LastState == v1beta1.StatusHealthy ||
+	if m.IsStartupTimeout ||
+		m.LastState == v1beta1.StatusHealthy ||
 		m.LastState == v1beta1.StatusUnhealthy {
 		return v1beta1.StatusUnhealthy
 	}
@@ -518,21 +782,60 @@ func (m MilvusHealthStatusInfo) GetMilvusHealthStatus() v1beta1.MilvusHealthStat
 	return v1beta1.StatusPending
 }
 
+// GetRollingUpdateProgress returns the percent of the replicas updated to the target image across the components.
+// it returns 100 only when all the components are updated to the target image and complete
+func GetRollingUpdateProgress(m *v1beta1.Milvus) int {
+	var total, updated int32
+	allUpdated := true
+	for _, component := range GetDeploymentComponentsBySpec(m.Spec) {
+		componentStatus, ok := m.Status.ComponentsDeployStatus[component.GetName()]
+		isTargetImage := ok && v1beta1.IsSameImage(componentStatus.Image, getComponentTargetImage(m.Spec, component))
+		deployState := componentStatus.GetState()
+		if !isTargetImage || (deployState != v1beta1.DeploymentComplete && deployState != v1beta1.DeploymentPaused) {
+			allUpdated = false
+		}
+		total += componentStatus.Status.Replicas
+		if isTargetImage {
+			updated += componentStatus.Status.UpdatedReplicas
+		}
+	}
+	if allUpdated {
+		return 100
+	}
+	if total < 1 {
+		return 0
+	}
+	return min(int(updated*100/total), 99)
+}
+
 func GetMilvusUpdatedCondition(m *v1beta1.Milvus) v1beta1.MilvusCondition {
-	components := GetComponentsBySpec(m.Spec)
+	components := GetDeploymentComponentsBySpec(m.Spec)
 	status := m.Status.ComponentsDeployStatus
 	var updatingComponent []string
+	// pendingComponent are the updating ones & the ones not running the target image, listed in the message
+	var pendingComponent []string
 	var isUpdatingImage bool
+	var isImageOutdated bool
 	for _, component := range components {
-		componentStatus := status[component.Name]
+		componentStatus := status[component.GetName()]
+		targetImage := getComponentTargetImage(m.Spec, component)
+		// the deployments being created always use the target image
+		isTargetImage := v1beta1.IsSameImage(componentStatus.Image, targetImage)
+		if componentStatus.Image != "" && !isTargetImage {
+			isImageOutdated = true
+		}
 		deployState := componentStatus.GetState()
-		if deployState != v1beta1.DeploymentComplete && deployState != v1beta1.DeploymentPaused {
-			updatingComponent = append(updatingComponent, component.Name)
-		} else if v1beta1.Labels().IsComponentRolling(*m, component.Name) {
-			updatingComponent = append(updatingComponent, component.Name)
+		isUpdating := deployState != v1beta1.DeploymentComplete && deployState != v1beta1.DeploymentPaused ||
+			v1beta1.Labels().IsComponentRolling(*m, component.Name)
+		if isUpdating {
+			updatingComponent = append(updatingComponent, component.GetName())
+		}
+		if isUpdating || !isTargetImage {
+			pendingComponent = append(pendingComponent,
+				fmt.Sprintf("%s(%s->%s)", component.GetName(), componentStatus.Image, targetImage))
 		}
 		if m.IsRollingUpdateEnabled() &&
-			componentStatus.Image != m.Spec.Com.Image {
+			!v1beta1.IsSameImage(componentStatus.Image, m.Spec.Com.Image) {
 			isUpdatingImage = true
 		}
 	}
@@ -541,17 +844,20 @@ func GetMilvusUpdatedCondition(m *v1beta1.Milvus) v1beta1.MilvusCondition {
 	var msg string
 	var updated = corev1.ConditionFalse
 	switch {
+	case isImageOutdated && !isUpdateWindowOpen(m.Spec.Com.UpdateWindow, scheduleNow()):
+		reason = v1beta1.ReasonWaitingForUpdateWindow
+		msg = fmt.Sprintf("Milvus components[%s] are waiting for the update window", strings.Join(pendingComponent, ","))
 	case isUpdatingImage &&
 		m.Spec.Com.ImageUpdateMode == v1beta1.ImageUpdateModeRollingUpgrade:
 		reason = v1beta1.ReasonMilvusUpgradingImage
-		msg = fmt.Sprintf("Milvus is performing rolling upgrade pending components[%s]", strings.Join(updatingComponent, ","))
+		msg = fmt.Sprintf("Milvus is performing rolling upgrade pending components[%s]", strings.Join(pendingComponent, ","))
 	case isUpdatingImage &&
 		m.Spec.Com.ImageUpdateMode == v1beta1.ImageUpdateModeRollingDowngrade:
 		reason = v1beta1.ReasonMilvusDowngradingImage
-		msg = fmt.Sprintf("{Milvus is performing rolling downgrade, pending components[%s]}", strings.Join(updatingComponent, ","))
+		msg = fmt.Sprintf("{Milvus is performing rolling downgrade, pending components[%s]}", strings.Join(pendingComponent, ","))
 	case len(updatingComponent) > 0: // updating
 		reason = v1beta1.ReasonMilvusComponentsUpdating
-		msg = fmt.Sprintf("Milvus components[%s] are updating", strings.Join(updatingComponent, ","))
+		msg = fmt.Sprintf("Milvus components[%s] are updating", strings.Join(pendingComponent, ","))
 	default:
 		reason = v1beta1.ReasonMilvusComponentsUpdated
 		msg = "Milvus components are all updated"
@@ -564,3 +870,15 @@ func GetMilvusUpdatedCondition(m *v1beta1.Milvus) v1beta1.MilvusCondition {
 		Message: msg,
 	}
 }
+
+// getComponentTargetImage returns the image the component should finally run with
+func getComponentTargetImage(spec v1beta1.MilvusSpec, component MilvusComponent) string {
+	componentField := reflect.ValueOf(spec.Com).FieldByName(component.FieldName)
+	if componentField.IsValid() && !componentField.IsNil() {
+		image := component.GetComponentSpec(spec).Image
+		if len(image) > 0 {
+			return image
+		}
+	}
+	return spec.Com.Image
+}
@@ -3,19 +3,24 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/prashantv/gostub"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkv1 "k8s.io/api/networking/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	runtimectrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
@@ -66,6 +71,30 @@ func TestMilvusStatusSyncer_UpdateIngressStatus(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "host1", milvus.Status.IngressStatus.LoadBalancer.Ingress[0].Hostname)
 	})
+	t.Run("restful ingress status aggregated", func(t *testing.T) {
+		milvus.Spec.Com.Standalone.Ingress = &v1beta1.MilvusIngress{
+			RESTful: &v1beta1.MilvusIngressBackend{},
+		}
+		mockCli.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: milvus.Namespace, Name: getIngressName(milvus.Name)}, gomock.Any()).
+			Do(func(_, _ interface{}, obj *networkv1.Ingress, opts ...any) {
+				obj.Status.LoadBalancer.Ingress = []networkv1.IngressLoadBalancerIngress{
+					{Hostname: "host1"},
+				}
+			}).Return(nil)
+		mockCli.EXPECT().Get(gomock.Any(), client.ObjectKey{Namespace: milvus.Namespace, Name: getRESTfulIngressName(milvus.Name)}, gomock.Any()).
+			Do(func(_, _ interface{}, obj *networkv1.Ingress, opts ...any) {
+				obj.Status.LoadBalancer.Ingress = []networkv1.IngressLoadBalancerIngress{
+					{Hostname: "host1"},
+					{Hostname: "host2"},
+				}
+			}).Return(nil)
+		err := s.UpdateIngressStatus(ctx, &milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, []networkv1.IngressLoadBalancerIngress{
+			{Hostname: "host1"},
+			{Hostname: "host2"},
+		}, milvus.Status.IngressStatus.LoadBalancer.Ingress)
+	})
 }
 
 func init() {
@@ -120,6 +149,32 @@ func TestMilvusStatusSyncer_GetDependencyCondition(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, corev1.ConditionTrue, ret.Status)
 	})
+	t.Run("GetMsgStreamCondition_woodpecker_local", func(t *testing.T) {
+		defer ctrl.Finish()
+		milvus := *milvus.DeepCopy()
+		milvus.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeWoodPecker
+		milvus.Spec.Conf.Data = map[string]interface{}{
+			"woodpecker": map[string]interface{}{
+				"storage": map[string]interface{}{
+					"type": "local",
+				},
+			},
+		}
+		ret, err := s.GetMsgStreamCondition(ctx, milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.ConditionTrue, ret.Status)
+	})
+	t.Run("GetMsgStreamCondition_woodpecker_object_storage", func(t *testing.T) {
+		defer ctrl.Finish()
+		milvus := *milvus.DeepCopy()
+		milvus.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeWoodPecker
+		mockCli.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("test"))
+		ret, err := s.GetMsgStreamCondition(ctx, milvus)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.MsgStreamReady, ret.Type)
+		assert.Equal(t, corev1.ConditionFalse, ret.Status)
+		assert.Equal(t, v1beta1.ReasonMsgStreamNotReady, ret.Reason)
+	})
 	t.Run("GetMsgStreamCondition_custom", func(t *testing.T) {
 		defer ctrl.Finish()
 		milvus.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeCustom
@@ -263,7 +318,7 @@ func TestStatusSyncer_UpdateStatusRoutine(t *testing.T) {
 	// get condition failed
 	mockRunner := NewMockGroupRunner(ctrl)
 	defaultGroupRunner = mockRunner
-	mockRunner.EXPECT().RunWithResult(gomock.Len(3), gomock.Any(), gomock.Any()).
+	mockRunner.EXPECT().RunWithResult(gomock.Len(4), gomock.Any(), gomock.Any()).
 		Return([]Result{
 			{Err: errors.New("test")},
 			{Err: errors.New("test")},
@@ -277,7 +332,7 @@ func TestStatusSyncer_UpdateStatusRoutine(t *testing.T) {
 	m.Spec.GetServiceComponent().Ingress = &v1beta1.MilvusIngress{}
 	t.Run("update ingress status failed", func(t *testing.T) {
 		defer ctrl.Finish()
-		mockRunner.EXPECT().RunWithResult(gomock.Len(3), gomock.Any(), gomock.Any()).
+		mockRunner.EXPECT().RunWithResult(gomock.Len(4), gomock.Any(), gomock.Any()).
 			Return([]Result{
 				{Data: v1beta1.MilvusCondition{}},
 			})
@@ -292,7 +347,7 @@ func TestStatusSyncer_UpdateStatusRoutine(t *testing.T) {
 	s.deployStatusUpdater = mockDeployStatusUpdater
 	t.Run("update deployStatus failed", func(t *testing.T) {
 		defer ctrl.Finish()
-		mockRunner.EXPECT().RunWithResult(gomock.Len(3), gomock.Any(), gomock.Any()).
+		mockRunner.EXPECT().RunWithResult(gomock.Len(4), gomock.Any(), gomock.Any()).
 			Return([]Result{
 				{Data: v1beta1.MilvusCondition{}},
 			})
@@ -304,7 +359,7 @@ func TestStatusSyncer_UpdateStatusRoutine(t *testing.T) {
 	t.Run("update status healthy to unhealthy success", func(t *testing.T) {
 		defer ctrl.Finish()
 		mockDeployStatusUpdater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
-		mockRunner.EXPECT().RunWithResult(gomock.Len(3), gomock.Any(), gomock.Any()).
+		mockRunner.EXPECT().RunWithResult(gomock.Len(4), gomock.Any(), gomock.Any()).
 			Return([]Result{
 				{Data: v1beta1.MilvusCondition{}},
 			})
@@ -338,7 +393,7 @@ func TestStatusSyncer_UpdateStatusRoutine(t *testing.T) {
 	t.Run("update status creating", func(t *testing.T) {
 		defer ctrl.Finish()
 		mockDeployStatusUpdater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
-		mockRunner.EXPECT().RunWithResult(gomock.Len(3), gomock.Any(), gomock.Any()).
+		mockRunner.EXPECT().RunWithResult(gomock.Len(4), gomock.Any(), gomock.Any()).
 			Return([]Result{
 				{Data: v1beta1.MilvusCondition{}},
 			})
@@ -423,6 +478,46 @@ func Test_UpdateMetrics(t *testing.T) {
 		assert.Equal(t, 1, creatingCount)
 		assert.Equal(t, 1, deletingCount)
 	})
+
+	t.Run("dependency condition count", func(t *testing.T) {
+		defer ctrl.Finish()
+		mockCli.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_, listType interface{}, _ ...interface{}) {
+			list := listType.(*v1beta1.MilvusList)
+			etcdDown := v1beta1.Milvus{}
+			etcdDown.Status.Conditions = []v1beta1.MilvusCondition{
+				{Type: v1beta1.EtcdReady, Status: corev1.ConditionFalse},
+				{Type: v1beta1.StorageReady, Status: corev1.ConditionTrue},
+				{Type: v1beta1.MsgStreamReady, Status: corev1.ConditionTrue},
+			}
+			allReady := v1beta1.Milvus{}
+			allReady.Status.Conditions = []v1beta1.MilvusCondition{
+				{Type: v1beta1.EtcdReady, Status: corev1.ConditionTrue},
+				{Type: v1beta1.StorageReady, Status: corev1.ConditionTrue},
+				{Type: v1beta1.MsgStreamReady, Status: corev1.ConditionTrue},
+			}
+			msgStreamUnknown := v1beta1.Milvus{}
+			msgStreamUnknown.Status.Conditions = []v1beta1.MilvusCondition{
+				{Type: v1beta1.MsgStreamReady, Status: corev1.ConditionUnknown},
+			}
+			list.Items = []v1beta1.Milvus{
+				etcdDown,
+				etcdDown,
+				allReady,
+				msgStreamUnknown,
+			}
+		}).Return(nil)
+		err := s.updateMetrics()
+		assert.NoError(t, err)
+		getCount := func(conditionType v1beta1.MilvusConditionType, status corev1.ConditionStatus) float64 {
+			return testutil.ToFloat64(milvusDependencyConditionCountCollector.WithLabelValues(string(conditionType), string(status)))
+		}
+		assert.Equal(t, float64(2), getCount(v1beta1.EtcdReady, corev1.ConditionFalse))
+		assert.Equal(t, float64(1), getCount(v1beta1.EtcdReady, corev1.ConditionTrue))
+		assert.Equal(t, float64(3), getCount(v1beta1.StorageReady, corev1.ConditionTrue))
+		assert.Equal(t, float64(0), getCount(v1beta1.StorageReady, corev1.ConditionFalse))
+		assert.Equal(t, float64(3), getCount(v1beta1.MsgStreamReady, corev1.ConditionTrue))
+		assert.Equal(t, float64(1), getCount(v1beta1.MsgStreamReady, corev1.ConditionUnknown))
+	})
 }
 
 func TestComponentsDeployStatusUpdaterImpl_Update(t *testing.T) {
@@ -458,6 +553,7 @@ func TestComponentsDeployStatusUpdaterImpl_Update(t *testing.T) {
 			deploy.Labels = map[string]string{
 				AppLabelComponent: StandaloneName,
 			}
+			deploy.CreationTimestamp = metav1.NewTime(time.Unix(1000, 0))
 			err := runtimectrl.SetControllerReference(m1, &deploy, scheme)
 			assert.NoError(t, err)
 			list.Items = []appsv1.Deployment{
@@ -467,6 +563,7 @@ func TestComponentsDeployStatusUpdaterImpl_Update(t *testing.T) {
 		err := r.Update(ctx, m1)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(m1.Status.ComponentsDeployStatus))
+		assert.Equal(t, time.Unix(1000, 0), m1.Status.ComponentsDeployStatus[StandaloneName].CreationTimestamp.Time)
 	})
 
 	t.Run("cluster success", func(t *testing.T) {
@@ -514,6 +611,20 @@ func TestComponentsDeployStatusUpdaterImpl_Update(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 3, len(m1.Status.ComponentsDeployStatus))
 	})
+
+	t.Run("prune status of unexpected components", func(t *testing.T) {
+		m1 := m.DeepCopy()
+		m1.Default()
+		m1.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			StandaloneName: {},
+			ProxyName:      {},
+		}
+		mockCli.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		err := r.Update(ctx, m1)
+		assert.NoError(t, err)
+		assert.Len(t, m1.Status.ComponentsDeployStatus, 1)
+		assert.Contains(t, m1.Status.ComponentsDeployStatus, StandaloneName)
+	})
 }
 
 func TestMilvusHealthStatusInfo_GetMilvusHealthStatus(t *testing.T) {
@@ -542,6 +653,16 @@ func TestMilvusHealthStatusInfo_GetMilvusHealthStatus(t *testing.T) {
 		assert.Equal(t, v1beta1.StatusStopped, m.GetMilvusHealthStatus())
 	})
 
+	t.Run("pending to unhealthy for startup timeout", func(t *testing.T) {
+		m := MilvusHealthStatusInfo{}
+		m.LastState = v1beta1.StatusPending
+		m.IsStartupTimeout = true
+		assert.Equal(t, v1beta1.StatusUnhealthy, m.GetMilvusHealthStatus())
+		// stopped anyway
+		m.IsStopping = true
+		assert.Equal(t, v1beta1.StatusStopped, m.GetMilvusHealthStatus())
+	})
+
 	t.Run("healthy to other", func(t *testing.T) {
 		m := MilvusHealthStatusInfo{}
 		m.LastState = v1beta1.StatusHealthy
@@ -579,6 +700,54 @@ func TestMilvusHealthStatusInfo_GetMilvusHealthStatus(t *testing.T) {
 	})
 }
 
+func TestGetRollingUpdateProgress(t *testing.T) {
+	m := &v1beta1.Milvus{}
+	m.Spec.Mode = v1beta1.MilvusModeCluster
+	m.Spec.Com.Image = "milvusdb/milvus:v2.5.1"
+	m.Spec.Com.MixCoord = &v1beta1.MilvusMixCoord{}
+	m.Default()
+	const oldImage = "milvusdb/milvus:v2.5.0"
+	newStatus := func(image string, replicas, updatedReplicas int32) v1beta1.ComponentDeployStatus {
+		status := readyDeployStatus
+		status.Replicas = replicas
+		status.UpdatedReplicas = updatedReplicas
+		return v1beta1.ComponentDeployStatus{Generation: 1, Image: image, Status: status}
+	}
+
+	t.Run("no deploy status", func(t *testing.T) {
+		assert.Equal(t, 0, GetRollingUpdateProgress(m))
+	})
+
+	t.Run("mid rollout", func(t *testing.T) {
+		querynodeStatus := newStatus(m.Spec.Com.Image, 2, 1)
+		querynodeStatus.Status.Conditions = nil
+		m.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			MixCoordName:   newStatus(m.Spec.Com.Image, 2, 2),
+			ProxyName:      newStatus(m.Spec.Com.Image, 2, 2),
+			QueryNodeName:  querynodeStatus,
+			DataNodeName:   newStatus(oldImage, 2, 2),
+			IndexNodeName:  newStatus(oldImage, 2, 2),
+			StandaloneName: newStatus(m.Spec.Com.Image, 0, 0),
+		}
+		assert.Equal(t, 50, GetRollingUpdateProgress(m))
+	})
+
+	t.Run("all replicas updated but not ready", func(t *testing.T) {
+		for name := range m.Status.ComponentsDeployStatus {
+			m.Status.ComponentsDeployStatus[name] = newStatus(m.Spec.Com.Image, 2, 2)
+		}
+		notReady := newStatus(m.Spec.Com.Image, 2, 2)
+		notReady.Status.Conditions = nil
+		m.Status.ComponentsDeployStatus[QueryNodeName] = notReady
+		assert.Equal(t, 99, GetRollingUpdateProgress(m))
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		m.Status.ComponentsDeployStatus[QueryNodeName] = newStatus(m.Spec.Com.Image, 2, 2)
+		assert.Equal(t, 100, GetRollingUpdateProgress(m))
+	})
+}
+
 func TestGetMilvusUpdatedCondition(t *testing.T) {
 
 	t.Run("creating", func(t *testing.T) {
@@ -603,6 +772,66 @@ func TestGetMilvusUpdatedCondition(t *testing.T) {
 		assert.Equal(t, corev1.ConditionTrue, cond.Status)
 	})
 
+	t.Run("digest pinned image", func(t *testing.T) {
+		digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		m := &v1beta1.Milvus{}
+		m.Default()
+		m.Spec.Com.EnableRollingUpdate = util.BoolPtr(true)
+		m.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingUpgrade
+		m.Spec.Com.Image = "milvusdb/milvus:v2.5.10@" + digest
+		m.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			StandaloneName: {
+				Generation: 1,
+				Image:      "milvusdb/milvus:v2.5.10",
+				Status:     readyDeployStatus,
+			},
+		}
+		cond := GetMilvusUpdatedCondition(m)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonMilvusUpgradingImage, cond.Reason)
+
+		// same digest with another tag
+		m.Status.ComponentsDeployStatus[StandaloneName] = v1beta1.ComponentDeployStatus{
+			Generation: 1,
+			Image:      "milvusdb/milvus@" + digest,
+			Status:     readyDeployStatus,
+		}
+		cond = GetMilvusUpdatedCondition(m)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+
+		// another digest
+		m.Status.ComponentsDeployStatus[StandaloneName] = v1beta1.ComponentDeployStatus{
+			Generation: 1,
+			Image:      "milvusdb/milvus:v2.5.10@sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+			Status:     readyDeployStatus,
+		}
+		cond = GetMilvusUpdatedCondition(m)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	})
+
+	t.Run("waiting for update window", func(t *testing.T) {
+		bak := scheduleNow
+		defer func() { scheduleNow = bak }()
+		m := &v1beta1.Milvus{}
+		m.Default()
+		m.Spec.Com.UpdateWindow = &v1beta1.UpdateWindow{Start: "0 2 * * *", End: "0 4 * * *"}
+		m.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			StandaloneName: {
+				Generation: 1,
+				Image:      "milvusdb/milvus:old",
+				Status:     readyDeployStatus,
+			},
+		}
+		scheduleNow = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+		cond := GetMilvusUpdatedCondition(m)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonWaitingForUpdateWindow, cond.Reason)
+
+		scheduleNow = func() time.Time { return time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC) }
+		cond = GetMilvusUpdatedCondition(m)
+		assert.NotEqual(t, v1beta1.ReasonWaitingForUpdateWindow, cond.Reason)
+	})
+
 	t.Run("standalone 2 deploy mode: old deployment scaling down", func(t *testing.T) {
 		m := &v1beta1.Milvus{}
 		m.Default()
@@ -654,4 +883,309 @@ func TestGetMilvusUpdatedCondition(t *testing.T) {
 		assert.Equal(t, v1beta1.ReasonMilvusDowngradingImage, cond.Reason)
 		assert.Contains(t, cond.Message, ProxyName)
 	})
+
+	t.Run("cluster upgrade lists all pending components with images", func(t *testing.T) {
+		m := &v1beta1.Milvus{}
+		m.Spec.Mode = v1beta1.MilvusModeCluster
+		m.Spec.Com.EnableRollingUpdate = util.BoolPtr(true)
+		m.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingUpgrade
+		m.Default()
+		oldImage := "milvusdb/milvus:old"
+		m.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{}
+		for _, component := range GetComponentsBySpec(m.Spec) {
+			m.Status.ComponentsDeployStatus[component.Name] = v1beta1.ComponentDeployStatus{
+				Generation: 1,
+				Image:      oldImage,
+				Status:     readyDeployStatus,
+			}
+		}
+		m.Status.ComponentsDeployStatus[ProxyName] = v1beta1.ComponentDeployStatus{
+			Generation: 1,
+			Image:      m.Spec.Com.Image,
+			Status:     readyDeployStatus,
+		}
+		cond := GetMilvusUpdatedCondition(m)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonMilvusUpgradingImage, cond.Reason)
+		assert.Contains(t, cond.Message, fmt.Sprintf("%s(%s->%s)", MixCoordName, oldImage, m.Spec.Com.Image))
+		assert.Contains(t, cond.Message, fmt.Sprintf("%s(%s->%s)", DataNodeName, oldImage, m.Spec.Com.Image))
+		assert.Contains(t, cond.Message, fmt.Sprintf("%s(%s->%s)", QueryNodeName, oldImage, m.Spec.Com.Image))
+		assert.NotContains(t, cond.Message, ProxyName)
+	})
+}
+
+func TestGetDependencyEndpoints(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kafka"},
+		Data:       map[string][]byte{"brokers": []byte("kafka1:9092,kafka2:9092")},
+	}
+	cli := fake.NewClientBuilder().WithObjects(secret).Build()
+	mc := v1beta1.Milvus{}
+	mc.Name = "mc"
+	mc.Namespace = "ns"
+
+	t.Run("in-cluster dependencies by defaulted endpoints", func(t *testing.T) {
+		m := *mc.DeepCopy()
+		m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+		m.Default()
+		ret, err := GetDependencyEndpoints(ctx, cli, m)
+		assert.NoError(t, err)
+		assert.Contains(t, ret.Etcd, "mc-etcd-0.mc-etcd-headless.ns:2379")
+		assert.Equal(t, "mc-minio.ns:9000", ret.Storage)
+		assert.Equal(t, []string{"mc-pulsar-proxy.ns:6650"}, ret.MsgStream)
+
+		m = *mc.DeepCopy()
+		m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeKafka
+		m.Default()
+		ret, err = GetDependencyEndpoints(ctx, cli, m)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mc-kafka.ns:9092"}, ret.MsgStream)
+
+		m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeRocksMQ
+		ret, err = GetDependencyEndpoints(ctx, cli, m)
+		assert.NoError(t, err)
+		assert.Empty(t, ret.MsgStream)
+	})
+
+	t.Run("external dependencies", func(t *testing.T) {
+		m := *mc.DeepCopy()
+		m.Spec.Dep.Etcd.External = true
+		m.Spec.Dep.Etcd.Endpoints = []string{"etcd1:2379", "etcd2:2379"}
+		m.Spec.Dep.Storage.External = true
+		m.Spec.Dep.Storage.Endpoint = "s3.amazonaws.com:443"
+		m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypePulsar
+		m.Spec.Dep.Pulsar.External = true
+		m.Spec.Dep.Pulsar.Endpoint = "pulsar:6650"
+		ret, err := GetDependencyEndpoints(ctx, cli, m)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"etcd1:2379", "etcd2:2379"}, ret.Etcd)
+		assert.Equal(t, "s3.amazonaws.com:443", ret.Storage)
+		assert.Equal(t, []string{"pulsar:6650"}, ret.MsgStream)
+
+		m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeKafka
+		m.Spec.Dep.Kafka.External = true
+		m.Spec.Dep.Kafka.BrokerList = []string{"kafka1:9092"}
+		ret, err = GetDependencyEndpoints(ctx, cli, m)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kafka1:9092"}, ret.MsgStream)
+	})
+
+	t.Run("kafka brokers from secret", func(t *testing.T) {
+		m := *mc.DeepCopy()
+		m.Spec.Dep.MsgStreamType = v1beta1.MsgStreamTypeKafka
+		m.Spec.Dep.Kafka.External = true
+		m.Spec.Dep.Kafka.BrokerListFromSecret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "kafka"},
+			Key:                  "brokers",
+		}
+		ret, err := GetDependencyEndpoints(ctx, cli, m)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kafka1:9092", "kafka2:9092"}, ret.MsgStream)
+
+		m.Spec.Dep.Kafka.BrokerListFromSecret.Name = "notfound"
+		ret, err = GetDependencyEndpoints(ctx, cli, m)
+		assert.Error(t, err)
+		assert.Empty(t, ret.MsgStream)
+	})
+}
+
+func TestMilvusStatusSyncer_updateTLSCertificateCondition(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: env.Inst.Namespace, Name: "tls"},
+		Data:       map[string][]byte{corev1.TLSCertKey: newTestCertPEM(t, time.Now().Add(365*24*time.Hour))},
+	}
+	s := NewMilvusStatusSyncer(ctx, fake.NewClientBuilder().WithObjects(secret).Build(), logf.Log.WithName("test"))
+	mc := env.Inst.DeepCopy()
+
+	t.Run("tls not configured", func(t *testing.T) {
+		UpdateCondition(&mc.Status, v1beta1.MilvusCondition{Type: v1beta1.TLSCertificateValid, Status: corev1.ConditionFalse})
+		s.updateTLSCertificateCondition(ctx, mc)
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.TLSCertificateValid))
+	})
+
+	t.Run("tls configured", func(t *testing.T) {
+		mc.Spec.GetServiceComponent().Ingress = &v1beta1.MilvusIngress{
+			TLSSecretRefs: map[string][]string{"tls": {"host1"}},
+		}
+		s.updateTLSCertificateCondition(ctx, mc)
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.TLSCertificateValid)
+		assert.NotNil(t, cond)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	})
+}
+
+func TestMilvusStatusSyncer_updateStorageCapacityCondition(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	defer env.checkMocks()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: env.Inst.Namespace, Name: "storage-secret"},
+		Data: map[string][]byte{
+			AccessKey: []byte("accessKeyID"),
+			SecretKey: []byte("secretAccessKey"),
+		},
+	}
+	s := NewMilvusStatusSyncer(ctx, fake.NewClientBuilder().WithObjects(secret).Build(), logf.Log.WithName("test"))
+	mc := env.Inst.DeepCopy()
+	mc.Spec.Dep.Storage.SecretRef = "storage-secret"
+	var usage uint64 = 2 * 1024 * 1024 * 1024
+	stubs := gostub.Stub(&getStorageBucketUsage, func(external.CheckMinIOArgs) (uint64, error) {
+		return usage, nil
+	})
+	defer stubs.Reset()
+
+	t.Run("threshold not configured", func(t *testing.T) {
+		s.updateStorageCapacityCondition(ctx, mc)
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.StorageCapacityWarning))
+	})
+
+	threshold := resource.MustParse("1Gi")
+	mc.Spec.Dep.Storage.CapacityWarningThreshold = &threshold
+	t.Run("over threshold", func(t *testing.T) {
+		s.updateStorageCapacityCondition(ctx, mc)
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.StorageCapacityWarning)
+		assert.NotNil(t, cond)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	})
+
+	t.Run("under threshold", func(t *testing.T) {
+		usage = 1024
+		s.updateStorageCapacityCondition(ctx, mc)
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.StorageCapacityWarning))
+	})
+
+	t.Run("storage type not supported", func(t *testing.T) {
+		usage = 2 * 1024 * 1024 * 1024
+		mc.Spec.Dep.Storage.Type = v1beta1.StorageTypeS3
+		stubs.Stub(&getStorageBucketUsage, func(external.CheckMinIOArgs) (uint64, error) {
+			t.Error("bucket usage shouldn't be queried")
+			return 0, external.ErrBucketUsageNotSupported
+		})
+		s.updateStorageCapacityCondition(ctx, mc)
+		assert.Nil(t, GetMilvusConditionByType(mc.Status.Conditions, v1beta1.StorageCapacityWarning))
+	})
+}
+
+func TestMilvusStatusSyncer_ListMilvusStatusSummaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCli := NewMockK8sClient(ctrl)
+	ctx := context.Background()
+	s := NewMilvusStatusSyncer(ctx, mockCli, logf.Log.WithName("test"))
+
+	t.Run("list failed", func(t *testing.T) {
+		mockCli.EXPECT().List(gomock.Any(), gomock.Any()).Return(errors.New("test"))
+		_, err := s.ListMilvusStatusSummaries(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("mixed healthy & unhealthy", func(t *testing.T) {
+		healthy := v1beta1.Milvus{}
+		healthy.Namespace = "ns1"
+		healthy.Name = "healthy"
+		healthy.Status.Status = v1beta1.StatusHealthy
+		healthy.Status.Conditions = []v1beta1.MilvusCondition{
+			{Type: v1beta1.MilvusReady, Status: corev1.ConditionTrue, Reason: v1beta1.ReasonMilvusHealthy},
+		}
+		healthy.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			"proxy":     {Status: appsv1.DeploymentStatus{ReadyReplicas: 2}},
+			"querynode": {Status: appsv1.DeploymentStatus{ReadyReplicas: 3}},
+		}
+		unhealthy := v1beta1.Milvus{}
+		unhealthy.Namespace = "ns2"
+		unhealthy.Name = "unhealthy"
+		unhealthy.Status.Status = v1beta1.StatusUnhealthy
+		unhealthy.Status.Conditions = []v1beta1.MilvusCondition{
+			{Type: v1beta1.MilvusReady, Status: corev1.ConditionFalse, Reason: v1beta1.ReasonImagePullFailed},
+		}
+		unhealthy.Status.ComponentsDeployStatus = map[string]v1beta1.ComponentDeployStatus{
+			"standalone": {Status: appsv1.DeploymentStatus{ReadyReplicas: 0}},
+		}
+		pending := v1beta1.Milvus{}
+		pending.Namespace = "ns2"
+		pending.Name = "pending"
+		pending.Status.Status = v1beta1.StatusPending
+
+		mockCli.EXPECT().List(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list *v1beta1.MilvusList, opts ...any) error {
+				list.Items = []v1beta1.Milvus{healthy, unhealthy, pending}
+				return nil
+			})
+		summaries, err := s.ListMilvusStatusSummaries(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []MilvusStatusSummary{
+			{Namespace: "ns1", Name: "healthy", Status: v1beta1.StatusHealthy, Reason: v1beta1.ReasonMilvusHealthy, ReadyReplicas: 5},
+			{Namespace: "ns2", Name: "unhealthy", Status: v1beta1.StatusUnhealthy, Reason: v1beta1.ReasonImagePullFailed},
+			{Namespace: "ns2", Name: "pending", Status: v1beta1.StatusPending},
+		}, summaries)
+	})
+}
+
+func TestMilvusStatusSyncer_updateGenerationObservedCondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCli := NewMockK8sClient(ctrl)
+	mockStatusCli := NewMockK8sStatusClient(ctrl)
+	ctx := context.Background()
+	s := NewMilvusStatusSyncer(ctx, mockCli, logf.Log.WithName("test"))
+
+	mc := &v1beta1.Milvus{}
+	mc.Generation = 3
+	mc.Status.Status = v1beta1.StatusHealthy
+	mc.Status.ObservedGeneration = 2
+
+	t.Run("lagging generation flagged", func(t *testing.T) {
+		mockCli.EXPECT().Status().Return(mockStatusCli)
+		mockStatusCli.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		err := s.UpdateStatusRoutine(ctx, mc)
+		assert.NoError(t, err)
+		cond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.GenerationObserved)
+		assert.NotNil(t, cond)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, v1beta1.ReasonGenerationLagging, cond.Reason)
+		assert.Contains(t, cond.Message, "observedGeneration[2]")
+
+		// not updated again if not changed
+		err = s.UpdateStatusRoutine(ctx, mc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("observed", func(t *testing.T) {
+		mc.Status.ObservedGeneration = 3
+		cond := GetGenerationObservedCondition(*mc)
+		assert.Equal(t, corev1.ConditionTrue, cond.Status)
+		assert.Equal(t, v1beta1.ReasonGenerationObserved, cond.Reason)
+	})
+}
+
+func Test_observeDependencyProbeRetry(t *testing.T) {
+	stubs := gostub.Stub(&util.BackoffObserver, observeDependencyProbeRetry)
+	defer stubs.Reset()
+	successAfterRetry := dependencyProbeRetryCounter.WithLabelValues("etcd", util.BackoffOutcomeSuccessAfterRetry)
+	exhausted := dependencyProbeRetryCounter.WithLabelValues("minio", util.BackoffOutcomeExhausted)
+	successBefore := testutil.ToFloat64(successAfterRetry)
+	exhaustedBefore := testutil.ToFloat64(exhausted)
+
+	attempts := 0
+	err := util.DoWithBackoff("checkEtcd", func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, successBefore+1, testutil.ToFloat64(successAfterRetry))
+
+	err = util.DoWithBackoff("checkMinIO", func() error {
+		return errors.New("connection refused")
+	}, 3, 0)
+	assert.Error(t, err)
+	assert.Equal(t, exhaustedBefore+1, testutil.ToFloat64(exhausted))
+	assert.Equal(t, successBefore+1, testutil.ToFloat64(successAfterRetry))
 }
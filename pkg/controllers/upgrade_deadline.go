@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+// isUpgradeStalled returns true if the rolling upgrade makes no progress beyond the spec.components.upgradeDeadlineSeconds.
+// the MilvusUpdated condition's message lists the pending components, so its transition time is reset whenever the upgrade progresses.
+// a paused rollout is never stalled
+func isUpgradeStalled(mc v1beta1.Milvus, now time.Time) bool {
+	deadlineSeconds := mc.Spec.Com.UpgradeDeadlineSeconds
+	if deadlineSeconds == nil ||
+		mc.Spec.Com.ImageUpdateMode != v1beta1.ImageUpdateModeRollingUpgrade ||
+		isRolloutPaused(mc) {
+		return false
+	}
+	if mc.Status.CurrentImage == "" ||
+		mc.Status.CurrentImage == mc.Spec.Com.Image {
+		return false
+	}
+	updatedCond := GetMilvusConditionByType(mc.Status.Conditions, v1beta1.MilvusUpdated)
+	if updatedCond == nil ||
+		updatedCond.Status == corev1.ConditionTrue ||
+		updatedCond.Reason != v1beta1.ReasonMilvusUpgradingImage ||
+		updatedCond.LastTransitionTime == nil {
+		return false
+	}
+	deadline := time.Duration(*deadlineSeconds) * time.Second
+	// the time paused doesn't count
+	since := updatedCond.LastTransitionTime.Time
+	if resumed := getRolloutResumedTime(mc); resumed.After(since) {
+		since = resumed
+	}
+	return now.Sub(since) > deadline
+}
+
+// rollbackStalledUpgrade rolls back to the status.currentImage in rolling downgrade mode if the upgrade is stalled
+func (r *MilvusStatusSyncer) rollbackStalledUpgrade(ctx context.Context, mc *v1beta1.Milvus, now time.Time) error {
+	if !isUpgradeStalled(*mc, now) {
+		return nil
+	}
+	stalledImage := mc.Spec.Com.Image
+	// update on a copy, so that the status being synced is not overwritten by the response
+	rollback := mc.DeepCopy()
+	rollback.Spec.Com.Image = mc.Status.CurrentImage
+	rollback.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingDowngrade
+	err := r.Update(ctx, rollback)
+	if err != nil {
+		return errors.Wrap(err, "update milvus to rollback stalled upgrade")
+	}
+	mc.ObjectMeta = rollback.ObjectMeta
+	mc.Spec = rollback.Spec
+
+	msg := fmt.Sprintf("upgrade to image[%s] stalled beyond %ds, rollback to image[%s]",
+		stalledImage, *mc.Spec.Com.UpgradeDeadlineSeconds, mc.Spec.Com.Image)
+	r.logger.Info(msg, "namespace", mc.Namespace, "name", mc.Name)
+	if r.eventRecorder != nil {
+		r.eventRecorder.Event(mc, corev1.EventTypeWarning, v1beta1.ReasonMilvusUpgradeRollback, msg)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
+)
+
+func TestMilvusStatusSyncer_rollbackStalledUpgrade(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	upgradingSince := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	newUpgradingMilvus := func() *v1beta1.Milvus {
+		mc := &v1beta1.Milvus{}
+		mc.Namespace = "ns"
+		mc.Name = "mc"
+		mc.Spec.Com.Image = "milvus:v2"
+		mc.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingUpgrade
+		mc.Spec.Com.UpgradeDeadlineSeconds = ptr.To(int32(300))
+		mc.Status.CurrentImage = "milvus:v1"
+		mc.Status.Conditions = []v1beta1.MilvusCondition{
+			{
+				Type:               v1beta1.MilvusUpdated,
+				Status:             corev1.ConditionFalse,
+				Reason:             v1beta1.ReasonMilvusUpgradingImage,
+				LastTransitionTime: &upgradingSince,
+			},
+		}
+		return mc
+	}
+
+	newSyncer := func(mc *v1beta1.Milvus) (*MilvusStatusSyncer, *record.FakeRecorder, client.Client) {
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc).Build()
+		recorder := record.NewFakeRecorder(10)
+		s := NewMilvusStatusSyncer(ctx, cli, logf.Log.WithName("test"))
+		s.eventRecorder = recorder
+		return s, recorder, cli
+	}
+
+	t.Run("stalled upgrade rolled back", func(t *testing.T) {
+		mc := newUpgradingMilvus()
+		s, recorder, cli := newSyncer(mc)
+		err := s.rollbackStalledUpgrade(ctx, mc, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvus:v1", mc.Spec.Com.Image)
+		assert.Equal(t, v1beta1.ImageUpdateModeRollingDowngrade, mc.Spec.Com.ImageUpdateMode)
+		// status being synced is kept
+		assert.Equal(t, v1beta1.ReasonMilvusUpgradingImage, mc.Status.Conditions[0].Reason)
+
+		updated := &v1beta1.Milvus{}
+		err = cli.Get(ctx, client.ObjectKeyFromObject(mc), updated)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvus:v1", updated.Spec.Com.Image)
+		assert.Equal(t, v1beta1.ImageUpdateModeRollingDowngrade, updated.Spec.Com.ImageUpdateMode)
+
+		assert.Len(t, recorder.Events, 1)
+		event := <-recorder.Events
+		assert.Contains(t, event, corev1.EventTypeWarning)
+		assert.Contains(t, event, v1beta1.ReasonMilvusUpgradeRollback)
+	})
+
+	t.Run("progressing upgrade not rolled back", func(t *testing.T) {
+		mc := newUpgradingMilvus()
+		progressedAt := metav1.NewTime(now.Add(-time.Minute))
+		mc.Status.Conditions[0].LastTransitionTime = &progressedAt
+		s, recorder, _ := newSyncer(mc)
+		err := s.rollbackStalledUpgrade(ctx, mc, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "milvus:v2", mc.Spec.Com.Image)
+		assert.Equal(t, v1beta1.ImageUpdateModeRollingUpgrade, mc.Spec.Com.ImageUpdateMode)
+		assert.Len(t, recorder.Events, 0)
+	})
+}
+
+func TestIsUpgradeStalled(t *testing.T) {
+	now := time.Now()
+	upgradingSince := metav1.NewTime(now.Add(-10 * time.Minute))
+	mc := v1beta1.Milvus{}
+	mc.Spec.Com.Image = "milvus:v2"
+	mc.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingUpgrade
+	mc.Status.CurrentImage = "milvus:v1"
+	mc.Status.Conditions = []v1beta1.MilvusCondition{
+		{
+			Type:               v1beta1.MilvusUpdated,
+			Status:             corev1.ConditionFalse,
+			Reason:             v1beta1.ReasonMilvusUpgradingImage,
+			LastTransitionTime: &upgradingSince,
+		},
+	}
+
+	t.Run("deadline not set", func(t *testing.T) {
+		assert.False(t, isUpgradeStalled(mc, now))
+	})
+
+	mc.Spec.Com.UpgradeDeadlineSeconds = ptr.To(int32(300))
+	t.Run("stalled", func(t *testing.T) {
+		assert.True(t, isUpgradeStalled(mc, now))
+	})
+
+	t.Run("not rolling upgrade", func(t *testing.T) {
+		downgrade := *mc.DeepCopy()
+		downgrade.Spec.Com.ImageUpdateMode = v1beta1.ImageUpdateModeRollingDowngrade
+		assert.False(t, isUpgradeStalled(downgrade, now))
+	})
+
+	t.Run("image not changed", func(t *testing.T) {
+		sameImage := *mc.DeepCopy()
+		sameImage.Status.CurrentImage = sameImage.Spec.Com.Image
+		assert.False(t, isUpgradeStalled(sameImage, now))
+	})
+
+	t.Run("updating not by image", func(t *testing.T) {
+		updating := *mc.DeepCopy()
+		updating.Status.Conditions[0].Reason = v1beta1.ReasonMilvusComponentsUpdating
+		assert.False(t, isUpgradeStalled(updating, now))
+	})
+
+	t.Run("rollout paused", func(t *testing.T) {
+		paused := *mc.DeepCopy()
+		paused.Annotations = map[string]string{PauseRolloutAnnotation: "true"}
+		assert.False(t, isUpgradeStalled(paused, now))
+	})
+
+	t.Run("rollout resumed within deadline", func(t *testing.T) {
+		resumed := *mc.DeepCopy()
+		resumedAt := metav1.NewTime(now.Add(-time.Minute))
+		resumed.Status.Conditions = append(resumed.Status.Conditions, v1beta1.MilvusCondition{
+			Type:               v1beta1.RolloutPaused,
+			Status:             corev1.ConditionFalse,
+			Reason:             v1beta1.ReasonRolloutResumed,
+			LastTransitionTime: &resumedAt,
+		})
+		assert.False(t, isUpgradeStalled(resumed, now))
+	})
+}
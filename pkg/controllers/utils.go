@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -178,6 +179,18 @@ func MergeEnvVar(src, dst []corev1.EnvVar) []corev1.EnvVar {
 	return merged
 }
 
+func hasEnvVar(envs []corev1.EnvVar, name string) bool {
+	return slices.ContainsFunc(envs, func(envVar corev1.EnvVar) bool {
+		return envVar.Name == name
+	})
+}
+
+func removeEnvVar(envs []corev1.EnvVar, name string) []corev1.EnvVar {
+	return slices.DeleteFunc(envs, func(envVar corev1.EnvVar) bool {
+		return envVar.Name == name
+	})
+}
+
 // GetContainerIndex returns index of container @name in @containers, -1 if not found
 func GetContainerIndex(containers []corev1.Container, name string) int {
 	for i, c := range containers {
@@ -389,6 +402,10 @@ func GetMinioSecure(conf map[string]interface{}) bool {
 	return false
 }
 
+func GetMinioRegion(conf map[string]interface{}) string {
+	return GetStringValueWithDefault(conf, "", "minio", "region")
+}
+
 func ShouldUseVirtualHost(conf map[string]interface{}) bool {
 	fields := []string{"minio", "useVirtualHost"}
 	useVirtualHost, exist := util.GetBoolValue(conf, fields...)
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// WebhookCertCheckName is the name of the readyz check on the webhook serving certificate
+const WebhookCertCheckName = "webhook-cert"
+
+// webhookCertChecker checks the serving certificate of the webhook server is present & valid,
+// so that the operator is not ready while the admission silently fails
+type webhookCertChecker struct {
+	certFile string
+	keyFile  string
+	now      func() time.Time
+}
+
+// newWebhookCertChecker returns the checker of the certificate files the webhook server serves with
+func newWebhookCertChecker(server webhook.Server) *webhookCertChecker {
+	opts := webhook.Options{}
+	if defaultServer, ok := server.(*webhook.DefaultServer); ok {
+		opts = defaultServer.Options
+	}
+	// same defaults as the webhook server
+	certDir := opts.CertDir
+	if certDir == "" {
+		certDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
+	certName := opts.CertName
+	if certName == "" {
+		certName = "tls.crt"
+	}
+	keyName := opts.KeyName
+	if keyName == "" {
+		keyName = "tls.key"
+	}
+	return &webhookCertChecker{
+		certFile: filepath.Join(certDir, certName),
+		keyFile:  filepath.Join(certDir, keyName),
+		now:      time.Now,
+	}
+}
+
+// Check implements healthz.Checker, it records the result in the webhook cert metrics
+func (c *webhookCertChecker) Check(_ *http.Request) error {
+	notAfter, err := c.check()
+	if err != nil {
+		webhookCertReadyGauge.Set(0)
+		return err
+	}
+	webhookCertReadyGauge.Set(1)
+	webhookCertExpiryGauge.Set(float64(notAfter.Unix()))
+	return nil
+}
+
+// check returns the expiry time of the certificate, or error if it's missing or not valid now
+func (c *webhookCertChecker) check() (time.Time, error) {
+	pair, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "load webhook serving cert")
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parse webhook serving cert")
+	}
+	now := c.now()
+	if now.Before(cert.NotBefore) {
+		return time.Time{}, errors.Errorf("webhook serving cert not valid before %s", cert.NotBefore)
+	}
+	if now.After(cert.NotAfter) {
+		return time.Time{}, errors.Errorf("webhook serving cert expired at %s", cert.NotAfter)
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func writeTestWebhookCert(t *testing.T, dir string, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	assert.NoError(t, err)
+}
+
+func TestWebhookCertChecker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	certDir := t.TempDir()
+	checker := newWebhookCertChecker(webhook.NewServer(webhook.Options{CertDir: certDir}))
+	checker.now = func() time.Time { return now }
+
+	t.Run("missing cert not ready", func(t *testing.T) {
+		err := checker.Check(nil)
+		assert.Error(t, err)
+		assert.Equal(t, float64(0), testutil.ToFloat64(webhookCertReadyGauge))
+	})
+
+	notAfter := now.Add(30 * 24 * time.Hour)
+	writeTestWebhookCert(t, certDir, notAfter)
+	t.Run("valid cert ready", func(t *testing.T) {
+		err := checker.Check(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(webhookCertReadyGauge))
+		assert.Equal(t, float64(notAfter.Unix()), testutil.ToFloat64(webhookCertExpiryGauge))
+	})
+
+	t.Run("expired cert not ready", func(t *testing.T) {
+		checker.now = func() time.Time { return notAfter.Add(time.Hour) }
+		defer func() { checker.now = func() time.Time { return now } }()
+		err := checker.Check(nil)
+		assert.ErrorContains(t, err, "expired")
+		assert.Equal(t, float64(0), testutil.ToFloat64(webhookCertReadyGauge))
+	})
+
+	t.Run("default cert paths", func(t *testing.T) {
+		checker := newWebhookCertChecker(nil)
+		assert.Equal(t, filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs", "tls.crt"), checker.certFile)
+		assert.Equal(t, filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs", "tls.key"), checker.keyFile)
+	})
+}
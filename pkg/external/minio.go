@@ -29,6 +29,7 @@ type CheckMinIOArgs struct {
 	UseIAM         bool
 	IAMEndpoint    string
 	UseVirtualHost bool
+	Region         string
 	// SSL Configuration
 	CACertificate      []byte
 	InsecureSkipVerify bool
@@ -48,39 +49,10 @@ func CheckMinIO(args CheckMinIOArgs) error {
 				// minio client cannot recognize aws endpoints with :443
 				endpoint = strings.TrimSuffix(endpoint, ":443")
 			}
-			bucketLookup := minio.BucketLookupPath
-			if args.UseVirtualHost {
-				bucketLookup = minio.BucketLookupDNS
-			}
-			
-			options := &minio.Options{
-				// GetBucketLocation will succeed as long as the bucket exists
-				Creds:        credentials.NewStaticV4(args.AK, args.SK, ""),
-				Secure:       args.UseSSL,
-				BucketLookup: bucketLookup,
-			}
-			
-			// Configure custom TLS if SSL is enabled and custom configuration is provided
-			if args.UseSSL && (len(args.CACertificate) > 0 || args.InsecureSkipVerify) {
-				tlsConfig := &tls.Config{
-					InsecureSkipVerify: args.InsecureSkipVerify,
-				}
-				
-				// Add custom CA certificate if provided
-				if len(args.CACertificate) > 0 {
-					caCertPool := x509.NewCertPool()
-					if !caCertPool.AppendCertsFromPEM(args.CACertificate) {
-						return errors.New("failed to parse CA certificate")
-					}
-					tlsConfig.RootCAs = caCertPool
-				}
-				
-				transport := &http.Transport{
-					TLSClientConfig: tlsConfig,
-				}
-				options.Transport = transport
+			options, err := newS3Options(args)
+			if err != nil {
+				return err
 			}
-			
 			cli, err := minio.New(endpoint, options)
 			if err != nil {
 				return err
@@ -99,33 +71,11 @@ func CheckMinIO(args CheckMinIOArgs) error {
 			return nil
 		default:
 			// default to minio
-			// Create MinIO admin client with SSL configuration
-			mcli, err := madmin.New(args.Endpoint, args.AK, args.SK, args.UseSSL)
+			mcli, err := newMinIOAdminClient(args)
 			if err != nil {
 				return err
 			}
-			
-			// Configure custom TLS if SSL is enabled and custom configuration is provided
-			if args.UseSSL && (len(args.CACertificate) > 0 || args.InsecureSkipVerify) {
-				tlsConfig := &tls.Config{
-					InsecureSkipVerify: args.InsecureSkipVerify,
-				}
-				
-				// Add custom CA certificate if provided
-				if len(args.CACertificate) > 0 {
-					caCertPool := x509.NewCertPool()
-					if !caCertPool.AppendCertsFromPEM(args.CACertificate) {
-						return errors.New("failed to parse CA certificate")
-					}
-					tlsConfig.RootCAs = caCertPool
-				}
-				
-				transport := &http.Transport{
-					TLSClientConfig: tlsConfig,
-				}
-				mcli.SetCustomTransport(transport)
-			}
-			
+
 			st, err := mcli.ServerInfo(ctx)
 			if err != nil {
 				return err
@@ -136,6 +86,94 @@ func CheckMinIO(args CheckMinIOArgs) error {
 	return util.DoWithBackoff("checkMinIO", checkMinio, util.DefaultMaxRetry, util.DefaultBackOffInterval)
 }
 
+// newS3Options returns the options of the S3 client with the region, bucket lookup style & SSL configuration
+func newS3Options(args CheckMinIOArgs) (*minio.Options, error) {
+	bucketLookup := minio.BucketLookupPath
+	if args.UseVirtualHost {
+		bucketLookup = minio.BucketLookupDNS
+	}
+
+	options := &minio.Options{
+		// GetBucketLocation will succeed as long as the bucket exists
+		Creds:        credentials.NewStaticV4(args.AK, args.SK, ""),
+		Secure:       args.UseSSL,
+		BucketLookup: bucketLookup,
+		Region:       args.Region,
+	}
+
+	// Configure custom TLS if SSL is enabled and custom configuration is provided
+	if args.UseSSL && (len(args.CACertificate) > 0 || args.InsecureSkipVerify) {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: args.InsecureSkipVerify,
+		}
+
+		// Add custom CA certificate if provided
+		if len(args.CACertificate) > 0 {
+			caCertPool := x509.NewCertPool()
+			if !caCertPool.AppendCertsFromPEM(args.CACertificate) {
+				return nil, errors.New("failed to parse CA certificate")
+			}
+			tlsConfig.RootCAs = caCertPool
+		}
+
+		options.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+	return options, nil
+}
+
+// newMinIOAdminClient creates the MinIO admin client with SSL configuration
+func newMinIOAdminClient(args CheckMinIOArgs) (*madmin.AdminClient, error) {
+	mcli, err := madmin.New(args.Endpoint, args.AK, args.SK, args.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure custom TLS if SSL is enabled and custom configuration is provided
+	if args.UseSSL && (len(args.CACertificate) > 0 || args.InsecureSkipVerify) {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: args.InsecureSkipVerify,
+		}
+
+		// Add custom CA certificate if provided
+		if len(args.CACertificate) > 0 {
+			caCertPool := x509.NewCertPool()
+			if !caCertPool.AppendCertsFromPEM(args.CACertificate) {
+				return nil, errors.New("failed to parse CA certificate")
+			}
+			tlsConfig.RootCAs = caCertPool
+		}
+
+		transport := &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+		mcli.SetCustomTransport(transport)
+	}
+	return mcli, nil
+}
+
+// ErrBucketUsageNotSupported is returned when the storage type doesn't report the bucket usage
+var ErrBucketUsageNotSupported = errors.New("bucket usage is only supported by MinIO")
+
+// GetMinIOBucketUsage returns the size in bytes of the bucket by the MinIO admin data usage api
+func GetMinIOBucketUsage(args CheckMinIOArgs) (uint64, error) {
+	if args.Type != "" && args.Type != v1beta1.StorageTypeMinIO {
+		return 0, ErrBucketUsageNotSupported
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DependencyCheckTimeout)
+	defer cancel()
+	mcli, err := newMinIOAdminClient(args)
+	if err != nil {
+		return 0, err
+	}
+	usage, err := mcli.DataUsageInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return usage.BucketsUsage[args.Bucket].Size, nil
+}
+
 func isHealthyByServerInfo(st madmin.InfoMessage) error {
 	for _, server := range st.Servers {
 		if server.State == "ok" || server.State == "online" {
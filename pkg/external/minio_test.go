@@ -5,6 +5,7 @@ import (
 	"time"
 
 	madmin "github.com/minio/madmin-go"
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/zilliztech/milvus-operator/apis/milvus.io/v1beta1"
@@ -138,3 +139,31 @@ func TestIsHealthyByServerInfo(t *testing.T) {
 	err = isHealthyByServerInfo(st)
 	assert.NoError(t, err)
 }
+
+func TestNewS3Options(t *testing.T) {
+	options, err := newS3Options(CheckMinIOArgs{
+		Type:   v1beta1.StorageTypeS3,
+		Region: "us-west-2",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", options.Region)
+	assert.Equal(t, minio.BucketLookupPath, options.BucketLookup)
+	assert.Nil(t, options.Transport)
+
+	options, err = newS3Options(CheckMinIOArgs{
+		Type:               v1beta1.StorageTypeS3,
+		UseVirtualHost:     true,
+		UseSSL:             true,
+		InsecureSkipVerify: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "", options.Region)
+	assert.Equal(t, minio.BucketLookupDNS, options.BucketLookup)
+	assert.NotNil(t, options.Transport)
+
+	_, err = newS3Options(CheckMinIOArgs{
+		UseSSL:        true,
+		CACertificate: []byte("bad cert"),
+	})
+	assert.Error(t, err)
+}
@@ -53,6 +53,11 @@ func (d *LocalClient) GetValues(cfg *action.Configuration, releaseName string) (
 	return vals, nil
 }
 
+// GetRelease returns the latest release, it returns driver.ErrReleaseNotFound if it's not installed
+func (d *LocalClient) GetRelease(cfg *action.Configuration, releaseName string) (*release.Release, error) {
+	return action.NewGet(cfg).Run(releaseName)
+}
+
 func (d *LocalClient) ReleaseExist(cfg *action.Configuration, releaseName string) (bool, error) {
 	histClient := action.NewHistory(cfg)
 	histClient.Max = 1
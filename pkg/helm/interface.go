@@ -11,6 +11,7 @@ import (
 type Client interface {
 	GetStatus(cfg *action.Configuration, releaseName string) (release.Status, error)
 	GetValues(cfg *action.Configuration, releaseName string) (map[string]interface{}, error)
+	GetRelease(cfg *action.Configuration, releaseName string) (*release.Release, error)
 	ReleaseExist(cfg *action.Configuration, releaseName string) (bool, error)
 	Upgrade(cfg *action.Configuration, request ChartRequest) error
 	Update(cfg *action.Configuration, request ChartRequest) error
@@ -34,6 +35,10 @@ func GetValues(cfg *action.Configuration, releaseName string) (map[string]interf
 	return defaultClient.GetValues(cfg, releaseName)
 }
 
+func GetRelease(cfg *action.Configuration, releaseName string) (*release.Release, error) {
+	return defaultClient.GetRelease(cfg, releaseName)
+}
+
 func ReleaseExist(cfg *action.Configuration, releaseName string) (bool, error) {
 	return defaultClient.ReleaseExist(cfg, releaseName)
 }
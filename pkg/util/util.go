@@ -94,6 +94,15 @@ func MergeValues(origin, patch map[string]interface{}) {
 	}
 }
 
+// ReplaceValues replaces the value at the fields path in origin with the one in patch, if it exists in patch
+func ReplaceValues(origin, patch map[string]interface{}, fields ...string) {
+	v, found, err := unstructured.NestedFieldNoCopy(patch, fields...)
+	if err != nil || !found {
+		return
+	}
+	SetValue(origin, v, fields...)
+}
+
 func GetHostPort(endpoint string) (string, int32) {
 	host, port, err := net.SplitHostPort(endpoint)
 	if err != nil {
@@ -186,15 +195,28 @@ func SetLogger(l logr.Logger) {
 	logger = l
 }
 
+// outcomes of DoWithBackoff reported to BackoffObserver
+const (
+	BackoffOutcomeSuccessAfterRetry = "success_after_retry"
+	BackoffOutcomeExhausted         = "exhausted"
+)
+
+// BackoffObserver is called when DoWithBackoff has retried, so that the retries can be recorded in metrics
+var BackoffObserver = func(name, outcome string) {}
+
 func DoWithBackoff(name string, fn func() error, maxRetry int, backOff time.Duration) error {
 	var err error
 	for i := 0; i < maxRetry; i++ {
 		err = fn()
 		if err == nil {
+			if i > 0 {
+				BackoffObserver(name, BackoffOutcomeSuccessAfterRetry)
+			}
 			return nil
 		}
 		logger.Info("dowithbackoff failed", "func", name, "retry", i, "err", err)
 		time.Sleep(backOff)
 	}
+	BackoffObserver(name, BackoffOutcomeExhausted)
 	return errors.Wrapf(err, "dowithbackoff[%s] failed after %d retries", name, maxRetry)
 }
@@ -165,6 +165,31 @@ func TestMergeValues(t *testing.T) {
 	}
 }
 
+func TestReplaceValues(t *testing.T) {
+	origin := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": "1", "d": "2"},
+			"e": "3",
+		},
+	}
+	patch := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": "4"},
+		},
+	}
+	ReplaceValues(origin, patch, "a", "b")
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": "4"},
+			"e": "3",
+		},
+	}, origin)
+
+	// not in patch
+	ReplaceValues(origin, patch, "a", "e")
+	assert.Equal(t, "3", origin["a"].(map[string]interface{})["e"])
+}
+
 func TestGetHostPort(t *testing.T) {
 	endPoint := "host:8080"
 	host, port := GetHostPort(endPoint)
@@ -323,4 +348,17 @@ func Test_DoWithBackoff(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 3, i)
 	})
+
+	t.Run("observer called with outcome", func(t *testing.T) {
+		var outcomes []string
+		bak := BackoffObserver
+		defer func() { BackoffObserver = bak }()
+		BackoffObserver = func(name, outcome string) {
+			outcomes = append(outcomes, name+":"+outcome)
+		}
+		_ = DoWithBackoff("test", func() error { return nil }, 3, 0)
+		assert.Empty(t, outcomes)
+		_ = DoWithBackoff("test", func() error { return errors.New("test error") }, 2, 0)
+		assert.Equal(t, []string{"test:" + BackoffOutcomeExhausted}, outcomes)
+	})
 }